@@ -2,10 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"example-api-template/internal/config"
 	"example-api-template/internal/repository"
@@ -14,29 +22,58 @@ import (
 	"example-api-template/internal/usecase"
 	"example-api-template/pkg/database"
 	"example-api-template/pkg/logger"
+	"example-api-template/pkg/metrics"
+	"example-api-template/pkg/tracing"
 
 	"go.uber.org/zap"
 )
 
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run parses command line flags and runs the consumer, returning the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("consumer", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	validateOnly := flags.Bool("validate-config", false, "validate the configuration, print a redacted summary and exit")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+
+	if *validateOnly {
+		return printConfigSummary(cfg, stdout, stderr)
 	}
 
 	// Initialize logger
 	appLogger, err := logger.New(&cfg.Logger)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "Failed to initialize logger: %v\n", err)
+		return 1
 	}
 	defer appLogger.Close()
 
 	// Set global logger
 	logger.SetGlobal(appLogger)
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing, cfg.App.Version)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize tracing", zap.Error(err))
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			appLogger.Error("Failed to shut down tracing", zap.Error(err))
+		}
+	}()
+
 	appLogger.Info("Starting message queue consumer",
 		zap.String("name", cfg.App.Name+"-consumer"),
 		zap.String("version", cfg.App.Version),
@@ -49,6 +86,17 @@ func main() {
 		appLogger.Fatal("Failed to initialize consumer dependencies", zap.Error(err))
 	}
 
+	// Serve Prometheus metrics before consuming, so a port that can't be bound stops the
+	// consumer instead of leaving it running unobserved
+	var metricsServer *http.Server
+	var metricsErr <-chan error
+	if deps.Metrics != nil {
+		metricsServer, metricsErr, err = startMetricsServer(cfg.MessageQueue.MetricsPort, deps.Metrics, appLogger)
+		if err != nil {
+			appLogger.Fatal("Failed to serve consumer metrics", zap.Error(err))
+		}
+	}
+
 	// Start consumer
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -59,23 +107,38 @@ func main() {
 
 	appLogger.Info("Message queue consumer started successfully")
 
-	// Wait for interrupt signal to gracefully shutdown the consumer
+	// Wait for interrupt signal, or the metrics server failing, to shut down the consumer
+	exitCode := 0
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-	<-quit
+	select {
+	case <-quit:
+	case err := <-metricsErr:
+		appLogger.Error("Metrics server failed", zap.Error(err))
+		exitCode = 1
+	}
 
 	appLogger.Info("Shutting down consumer...")
 
 	// Cancel context to stop consumer
 	cancel()
 
-	// Stop consumer gracefully
+	// Stop consumer gracefully, letting in-flight messages finish
 	if err := deps.Consumer.Stop(); err != nil {
 		appLogger.Error("Failed to stop consumer gracefully", zap.Error(err))
 	} else {
 		appLogger.Info("Consumer stopped gracefully")
 	}
 
+	// Stop serving metrics
+	if metricsServer != nil {
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			appLogger.Error("Failed to stop metrics server", zap.Error(err))
+		}
+		cancelShutdown()
+	}
+
 	// Close database connection
 	if deps.DBConn != nil {
 		if err := deps.DBConn.Close(); err != nil {
@@ -86,6 +149,50 @@ func main() {
 	}
 
 	appLogger.Info("Consumer shutdown complete")
+
+	return exitCode
+}
+
+// printConfigSummary writes a redacted summary of a validated configuration
+func printConfigSummary(cfg *config.Config, stdout, stderr io.Writer) int {
+	fmt.Fprintln(stdout, "Configuration is valid")
+	if err := cfg.WriteSummary(stdout); err != nil {
+		fmt.Fprintf(stderr, "Failed to write configuration summary: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// startMetricsServer serves m on /metrics at port in the background. Binding the port fails
+// straight away; a later serving failure is sent on the returned channel.
+func startMetricsServer(port int, m *metrics.ConsumerMetrics, logger *logger.Logger) (*http.Server, <-chan error, error) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	server := &http.Server{
+		Addr:              net.JoinHostPort("", strconv.Itoa(port)),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to listen on %s: %w", server.Addr, err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+	logger.Info("Serving consumer metrics", zap.String("address", listener.Addr().String()))
+
+	return server, errCh, nil
+}
+
+// metricsNamespace converts the application name into a valid Prometheus namespace
+func metricsNamespace(appName string) string {
+	return strings.ReplaceAll(appName, "-", "_")
 }
 
 // ConsumerDependencies holds all dependencies needed for the consumer
@@ -96,6 +203,7 @@ type ConsumerDependencies struct {
 	UseCase     usecase.ExampleUseCase
 	Consumer    mq.ExampleConsumer
 	DBConn      *database.PostgreSQLConnection // Optional, only for PostgreSQL
+	Metrics     *metrics.ConsumerMetrics       // Optional, only when a metrics port is set
 }
 
 // initializeConsumerDependencies initializes all dependencies needed for the consumer
@@ -124,22 +232,23 @@ func initializeConsumerDependencies(cfg *config.Config, logger *logger.Logger) (
 				repo = repository.NewInMemoryExampleRepository()
 			} else {
 				// Create PostgreSQL repository
-				pgRepo := repository.NewPostgreSQLExampleRepository(dbConn.DB)
+				pgRepo := repository.NewPostgreSQLExampleRepository(dbConn.DB,
+					repository.WithTablePrefix(cfg.Database.TablePrefix),
+					repository.WithSchema(cfg.Database.Schema),
+				)
 
-				// Run migrations (consumer might start before server)
-				if err := pgRepo.AutoMigrate(); err != nil {
-					logger.Error("Database migration failed, falling back to in-memory repository", zap.Error(err))
+				// Run migrations (consumer might start before server). Falling back to memory
+				// would hide a broken schema, so a failure stops the consumer.
+				if err := database.MigrateSchema(&cfg.Database, pgRepo, "examples", logger); err != nil {
 					dbConn.Close()
-					dbConn = nil
-					repo = repository.NewInMemoryExampleRepository()
-				} else {
-					repo = pgRepo
-					logger.Info("Using PostgreSQL repository for consumer",
-						zap.String("host", cfg.Database.Host),
-						zap.Int("port", cfg.Database.Port),
-						zap.String("database", cfg.Database.Name),
-					)
+					return nil, fmt.Errorf("database migration failed: %w", err)
 				}
+				repo = pgRepo
+				logger.Info("Using PostgreSQL repository for consumer",
+					zap.String("host", cfg.Database.Host),
+					zap.Int("port", cfg.Database.Port),
+					zap.String("database", cfg.Database.Name),
+				)
 			}
 		}
 	default:
@@ -158,16 +267,33 @@ func initializeConsumerDependencies(cfg *config.Config, logger *logger.Logger) (
 		)
 		logger.Info("Using mock external API for consumer")
 	} else {
-		// In a real application, you would initialize the actual external API client here
-		externalAPI = repository.NewMockExternalExampleAPI(false, 100)
-		logger.Warn("Real external API not implemented, using mock for consumer")
+		externalAPI = repository.NewHTTPExternalExampleAPI(&cfg.ExternalAPI)
+		logger.Info("Using external API for consumer", zap.String("base_url", cfg.ExternalAPI.BaseURL))
 	}
+	externalAPI = repository.NewRetryingExternalExampleAPI(externalAPI, cfg.ExternalAPI.RetryAttempts, cfg.ExternalAPI.RetryDelay)
 
 	// Initialize service
-	svc := service.NewExampleService(repo, logger.Logger)
+	svc := service.NewExampleService(repo, logger.Logger, service.BusinessRules{
+		ProfanityWords:   cfg.BusinessRules.ProfanityWords,
+		CorporateDomains: cfg.BusinessRules.CorporateDomains,
+		VIPDomains:       cfg.BusinessRules.VIPDomains,
+		CorporateMinAge:  cfg.BusinessRules.CorporateMinAge,
+		VIPMinAge:        cfg.BusinessRules.VIPMinAge,
+	})
 
 	// Initialize use case
-	uc := usecase.NewExampleUseCase(svc, externalAPI, logger.Logger)
+	ucOpts := []usecase.Option{usecase.WithEnrichmentConcurrency(cfg.ExternalAPI.EnrichmentConcurrency)}
+	if breaker := cfg.ExternalAPI.CircuitBreaker; breaker.Enabled {
+		ucOpts = append(ucOpts, usecase.WithCircuitBreaker(breaker.FailureThreshold, breaker.OpenDuration))
+	}
+	uc := usecase.NewExampleUseCaseWithConfig(svc, externalAPI, logger.Logger,
+		usecase.UseCaseConfig{Timeout: cfg.ExternalAPI.Timeout}, ucOpts...)
+
+	// Initialize consumer metrics
+	var consumerMetrics *metrics.ConsumerMetrics
+	if cfg.MessageQueue.MetricsPort > 0 {
+		consumerMetrics = metrics.NewConsumer(metricsNamespace(cfg.App.Name))
+	}
 
 	// Initialize message queue consumer
 	var consumer mq.ExampleConsumer
@@ -178,30 +304,18 @@ func initializeConsumerDependencies(cfg *config.Config, logger *logger.Logger) (
 		consumer = mq.NewMockConsumer(eventHandler, logger.Logger)
 		logger.Info("Using mock message queue consumer")
 	} else {
-		// Use real RabbitMQ implementation
+		// Use the configured broker
 		if !cfg.MessageQueue.EnableConsumer {
 			return nil, fmt.Errorf("consumer is disabled in configuration")
 		}
 
-		consumerConfig := &mq.RabbitMQConsumerConfig{
-			URL:           cfg.MessageQueue.URL,
-			ExchangeName:  cfg.MessageQueue.ExchangeName,
-			QueueName:     cfg.MessageQueue.QueueName,
-			RoutingKeys:   cfg.MessageQueue.RoutingKeys,
-			Durable:       cfg.MessageQueue.Durable,
-			AutoDelete:    cfg.MessageQueue.AutoDelete,
-			Exclusive:     cfg.MessageQueue.Exclusive,
-			NoWait:        cfg.MessageQueue.NoWait,
-			PrefetchCount: cfg.MessageQueue.PrefetchCount,
-		}
-
 		eventHandler := mq.NewDefaultExampleEventHandler(uc, logger.Logger)
 		var err error
-		consumer, err = mq.NewRabbitMQConsumer(consumerConfig, eventHandler, logger.Logger)
+		consumer, err = newConsumer(cfg, eventHandler, consumerMetrics, logger)
 		if err != nil {
-			return nil, fmt.Errorf("failed to initialize RabbitMQ consumer: %w", err)
+			return nil, err
 		}
-		logger.Info("Using RabbitMQ consumer")
+		logger.Info("Using message queue consumer", zap.String("driver", cfg.MessageQueue.Driver))
 	}
 
 	return &ConsumerDependencies{
@@ -211,6 +325,7 @@ func initializeConsumerDependencies(cfg *config.Config, logger *logger.Logger) (
 		UseCase:     uc,
 		Consumer:    consumer,
 		DBConn:      dbConn,
+		Metrics:     consumerMetrics,
 	}, nil
 }
 
@@ -222,3 +337,50 @@ func init() {
 		os.Exit(0)
 	}
 }
+
+// newConsumer connects the consumer for the configured message queue driver
+func newConsumer(cfg *config.Config, handler mq.ExampleEventHandler, consumerMetrics *metrics.ConsumerMetrics, logger *logger.Logger) (mq.ExampleConsumer, error) {
+	switch cfg.MessageQueue.Driver {
+	case "nats":
+		consumer, err := mq.NewNATSConsumer(&mq.NATSConsumerConfig{
+			URL:               cfg.MessageQueue.URL,
+			StreamName:        cfg.MessageQueue.StreamName,
+			SubjectPrefix:     cfg.MessageQueue.RoutingPrefix,
+			DurableName:       cfg.MessageQueue.QueueName,
+			Durable:           cfg.MessageQueue.Durable,
+			MaxAckPending:     cfg.MessageQueue.PrefetchCount,
+			MaxRetries:        cfg.MessageQueue.MaxRetries,
+			RetryBackoff:      cfg.MessageQueue.RetryBackoff,
+			ReconnectInterval: cfg.MessageQueue.ReconnectInterval,
+			Metrics:           consumerMetrics,
+		}, handler, logger.Logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize NATS consumer: %w", err)
+		}
+		return consumer, nil
+	default:
+		consumer, err := mq.NewRabbitMQConsumer(&mq.RabbitMQConsumerConfig{
+			URL:                cfg.MessageQueue.URL,
+			ExchangeName:       cfg.MessageQueue.ExchangeName,
+			QueueName:          cfg.MessageQueue.QueueName,
+			RoutingKeys:        cfg.MessageQueue.RoutingKeys,
+			Durable:            cfg.MessageQueue.Durable,
+			AutoDelete:         cfg.MessageQueue.AutoDelete,
+			Exclusive:          cfg.MessageQueue.Exclusive,
+			NoWait:             cfg.MessageQueue.NoWait,
+			PrefetchCount:      cfg.MessageQueue.PrefetchCount,
+			Concurrency:        cfg.MessageQueue.Concurrency,
+			DrainTimeout:       cfg.MessageQueue.DrainTimeout,
+			ReconnectInterval:  cfg.MessageQueue.ReconnectInterval,
+			DeadLetterExchange: cfg.MessageQueue.DeadLetterExchange,
+			DeadLetterQueue:    cfg.MessageQueue.DeadLetterQueue,
+			MaxRetries:         cfg.MessageQueue.MaxRetries,
+			RetryBackoff:       cfg.MessageQueue.RetryBackoff,
+			Metrics:            consumerMetrics,
+		}, handler, logger.Logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize RabbitMQ consumer: %w", err)
+		}
+		return consumer, nil
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"testing"
@@ -50,6 +51,7 @@ func TestConsumerDependencyInitialization(t *testing.T) {
 	assert.NotNil(t, deps.Service)
 	assert.NotNil(t, deps.UseCase)
 	assert.NotNil(t, deps.Consumer)
+	assert.Nil(t, deps.Metrics, "metrics are disabled without a metrics port")
 }
 
 // TestConsumerDependencyInitializationWithRealMQ tests with real MQ config (but expects failure)
@@ -129,6 +131,41 @@ func TestHealthCheck(t *testing.T) {
 	assert.Equal(t, "true", os.Getenv("HEALTH_CHECK"))
 }
 
+// TestRunValidateConfig tests the -validate-config flag with valid and invalid configuration
+func TestRunValidateConfig(t *testing.T) {
+	t.Run("valid configuration", func(t *testing.T) {
+		t.Setenv("EXTERNAL_API_KEY", "api-key-secret")
+
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-validate-config"}, &stdout, &stderr)
+
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stdout.String(), "Configuration is valid")
+		assert.NotContains(t, stdout.String(), "api-key-secret")
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		t.Setenv("DB_TYPE", "postgres")
+		t.Setenv("DB_MAX_IDLE_CONNS", "50")
+
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-validate-config"}, &stdout, &stderr)
+
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), "database max idle connections must be between 0 and max connections")
+	})
+
+	t.Run("invalid metrics port", func(t *testing.T) {
+		t.Setenv("MQ_CONSUMER_METRICS_PORT", "70000")
+
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-validate-config"}, &stdout, &stderr)
+
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr.String(), "message queue consumer metrics port must be between 0 and 65535")
+	})
+}
+
 // TestConsumerStartStop tests consumer lifecycle with mock
 func TestConsumerStartStop(t *testing.T) {
 	cfg := &config.Config{
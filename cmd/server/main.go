@@ -2,22 +2,34 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
+	"example-api-template/internal/audit"
 	"example-api-template/internal/config"
+	"example-api-template/internal/domain"
+	"example-api-template/internal/errs"
+	"example-api-template/internal/outbox"
 	"example-api-template/internal/repository"
 	"example-api-template/internal/service"
 	httpTransport "example-api-template/internal/transport/http"
 	"example-api-template/internal/transport/mq"
 	"example-api-template/internal/usecase"
+	"example-api-template/pkg/buildinfo"
 	"example-api-template/pkg/database"
+	"example-api-template/pkg/health"
 	"example-api-template/pkg/i18n"
 	"example-api-template/pkg/logger"
+	"example-api-template/pkg/metrics"
+	"example-api-template/pkg/tracing"
 	"example-api-template/pkg/validator"
 
 	"github.com/labstack/echo/v4"
@@ -25,29 +37,66 @@ import (
 	"go.uber.org/zap"
 )
 
+// @title Example API
+// @version 1.0
+// @description Example service template: CRUD for examples with events published to a message queue.
+// @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT bearer token, as "Bearer <token>"; required on /api/v1 when authentication is enabled
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run parses command line flags and runs the server, returning the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	validateOnly := flags.Bool("validate-config", false, "validate the configuration, print a redacted summary and exit")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+
+	if *validateOnly {
+		return printConfigSummary(cfg, stdout, stderr)
 	}
 
 	// Initialize logger
 	appLogger, err := logger.New(&cfg.Logger)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(stderr, "Failed to initialize logger: %v\n", err)
+		return 1
 	}
 	defer appLogger.Close()
 
 	// Set global logger
 	logger.SetGlobal(appLogger)
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing, cfg.App.Version)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize tracing", zap.Error(err))
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			appLogger.Error("Failed to shut down tracing", zap.Error(err))
+		}
+	}()
+
 	appLogger.Info("Starting application",
 		zap.String("name", cfg.App.Name),
 		zap.String("version", cfg.App.Version),
 		zap.String("environment", cfg.App.Environment),
+		zap.String("git_commit", buildinfo.Commit()),
+		zap.String("build_time", buildinfo.Time()),
 	)
 
 	// Initialize dependencies
@@ -61,9 +110,35 @@ func main() {
 
 	// Register routes
 	deps.Handler.RegisterRoutes(e)
+	deps.Health.RegisterRoutes(e)
+	deps.Version.RegisterRoutes(e)
+
+	if cfg.Server.EnableDocs {
+		httpTransport.RegisterDocsRoutes(e)
+	}
+
+	// Admin routes change runtime behaviour, so they are only exposed behind their own Basic
+	// auth credentials; any API user holds a valid JWT, so it isn't enough on its own
+	if cfg.Server.AdminAuth.Enabled() {
+		deps.Admin.RegisterRoutes(e)
+	} else {
+		appLogger.Info("Admin endpoints disabled because no admin credentials are set")
+	}
 
 	// Start server
 	startServer(e, cfg, appLogger, deps)
+
+	return 0
+}
+
+// printConfigSummary writes a redacted summary of a validated configuration
+func printConfigSummary(cfg *config.Config, stdout, stderr io.Writer) int {
+	fmt.Fprintln(stdout, "Configuration is valid")
+	if err := cfg.WriteSummary(stdout); err != nil {
+		fmt.Fprintf(stderr, "Failed to write configuration summary: %v\n", err)
+		return 1
+	}
+	return 0
 }
 
 // Dependencies holds all application dependencies
@@ -74,13 +149,22 @@ type Dependencies struct {
 	UseCase     usecase.ExampleUseCase
 	Validator   validator.Validator
 	Handler     *httpTransport.ExampleHandler
+	Admin       *httpTransport.AdminHandler
+	Health      *httpTransport.HealthHandler
+	Version     *httpTransport.VersionHandler
 	Producer    mq.ExampleProducer
 	DBConn      *database.PostgreSQLConnection // Optional, only for PostgreSQL
 	Localizer   *i18n.Localizer                // i18n support
+	Metrics     *metrics.Metrics               // Optional, only when metrics are enabled
+	Outbox      *outbox.Publisher              // Optional, only for PostgreSQL with the outbox enabled
+	Background  *usecase.BackgroundTasks       // Notifications still running after their request
 }
 
 // initializeDependencies initializes all application dependencies
 func initializeDependencies(cfg *config.Config, logger *logger.Logger) (*Dependencies, error) {
+	// Link error responses to the error code documentation
+	errs.DocBaseURL = cfg.App.ErrorDocsURL
+
 	// Initialize i18n
 	i18nConfig := &i18n.Config{
 		DefaultLanguage: cfg.I18n.DefaultLanguage,
@@ -94,105 +178,86 @@ func initializeDependencies(cfg *config.Config, logger *logger.Logger) (*Depende
 	}
 
 	// Initialize validator
-	validator := validator.New()
+	validator := validator.New(validator.WithLocalizer(localizer))
 
-	// Initialize repository
-	var repo repository.ExampleRepository
-	var dbConn *database.PostgreSQLConnection
-	var dbErr error
-
-	switch cfg.Database.Type {
-	case "memory":
-		repo = repository.NewInMemoryExampleRepository()
-		logger.Info("Using in-memory repository")
-	case "postgres", "postgresql":
-		// Initialize PostgreSQL connection
-		dbConn, dbErr = database.NewPostgreSQLConnection(&cfg.Database, logger)
-		if dbErr != nil {
-			logger.Error("Failed to connect to PostgreSQL, falling back to in-memory repository", zap.Error(dbErr))
-			repo = repository.NewInMemoryExampleRepository()
-		} else {
-			// Run health check
-			if dbErr := dbConn.HealthCheck(); dbErr != nil {
-				logger.Error("PostgreSQL health check failed, falling back to in-memory repository", zap.Error(dbErr))
-				dbConn.Close()
-				dbConn = nil
-				repo = repository.NewInMemoryExampleRepository()
-			} else {
-				// Create PostgreSQL repository
-				pgRepo := repository.NewPostgreSQLExampleRepository(dbConn.DB)
-
-				// Run migrations
-				if dbErr := pgRepo.AutoMigrate(); dbErr != nil {
-					logger.Error("Database migration failed, falling back to in-memory repository", zap.Error(dbErr))
-					dbConn.Close()
-					dbConn = nil
-					repo = repository.NewInMemoryExampleRepository()
-				} else {
-					repo = pgRepo
-					logger.Info("Using PostgreSQL repository",
-						zap.String("host", cfg.Database.Host),
-						zap.Int("port", cfg.Database.Port),
-						zap.String("database", cfg.Database.Name),
-					)
-				}
-			}
-		}
-	default:
-		// Unsupported database type, fall back to in-memory
-		repo = repository.NewInMemoryExampleRepository()
-		logger.Warn("Unsupported database type, falling back to in-memory repository",
-			zap.String("type", cfg.Database.Type))
+	// Initialize repository; readiness waits for the schema to be migrated
+	migrations := health.NewGate("migrations")
+	store, err := initStorage(cfg, logger, migrations)
+	if err != nil {
+		return nil, err
 	}
+	repo, outboxRepo, transactor, dbConn, dbErr := store.repo, store.outboxRepo, store.transactor, store.dbConn, store.dbErr
 
 	// Initialize external API
 	var externalAPI repository.ExternalExampleAPI
+	var adminOpts []httpTransport.AdminHandlerOption
+	if store.schema != nil {
+		adminOpts = append(adminOpts, httpTransport.WithMigrationStatus(store.schema))
+	}
 	if cfg.ExternalAPI.EnableMock {
-		externalAPI = repository.NewMockExternalExampleAPI(
+		mockAPI := repository.NewMockExternalExampleAPI(
 			cfg.ExternalAPI.MockShouldFail,
 			cfg.ExternalAPI.MockDelay,
 		)
+		externalAPI = mockAPI
+		// Fault injection is for resilience testing and never exposed outside development
+		if cfg.IsDevelopment() {
+			adminOpts = append(adminOpts, httpTransport.WithFaultInjector(mockAPI))
+		}
 		logger.Info("Using mock external API")
 	} else {
-		// In a real application, you would initialize the actual external API client here
-		externalAPI = repository.NewMockExternalExampleAPI(false, 100*time.Millisecond)
-		logger.Warn("Real external API not implemented, using mock")
+		externalAPI = repository.NewHTTPExternalExampleAPI(&cfg.ExternalAPI)
+		logger.Info("Using external API", zap.String("base_url", cfg.ExternalAPI.BaseURL))
 	}
 
-	// Initialize service
-	svc := service.NewExampleService(repo, logger.Logger)
+	// Initialize metrics and instrument data access
+	var appMetrics *metrics.Metrics
+	if cfg.Server.EnableMetrics {
+		appMetrics = metrics.New(metricsNamespace(cfg.App.Name))
+		repo = repository.NewInstrumentedExampleRepository(repo, appMetrics)
+		externalAPI = repository.NewInstrumentedExternalExampleAPI(externalAPI, appMetrics)
+		if dbConn != nil {
+			dbConn.InstrumentQueries(appMetrics)
+		}
+		logger.Info("Prometheus metrics enabled")
+	}
 
-	// Initialize use case
-	uc := usecase.NewExampleUseCase(svc, externalAPI, logger.Logger)
+	// Retry transient external API failures; with metrics enabled every attempt is recorded
+	externalAPI = repository.NewRetryingExternalExampleAPI(externalAPI, cfg.ExternalAPI.RetryAttempts, cfg.ExternalAPI.RetryDelay)
 
-	// Initialize HTTP handler
-	handler := httpTransport.NewExampleHandler(uc, validator)
+	// Every layer clamps list page sizes to the same limits
+	pagination := domain.Pagination{DefaultLimit: cfg.Pagination.DefaultLimit, MaxLimit: cfg.Pagination.MaxLimit}
+
+	// Initialize service
+	rules := service.BusinessRules{
+		ProfanityWords:   cfg.BusinessRules.ProfanityWords,
+		CorporateDomains: cfg.BusinessRules.CorporateDomains,
+		VIPDomains:       cfg.BusinessRules.VIPDomains,
+		CorporateMinAge:  cfg.BusinessRules.CorporateMinAge,
+		VIPMinAge:        cfg.BusinessRules.VIPMinAge,
+	}
+	svc := service.NewExampleService(repo, logger.Logger, rules, service.WithPagination(pagination))
 
 	// Initialize message queue producer only (consumer runs separately)
 	var producer mq.ExampleProducer
+	var producerErr error
 
 	if cfg.MessageQueue.EnableMock {
 		// Use mock implementation
 		producer = mq.NewMockProducer(logger.Logger)
 		logger.Info("Using mock message queue producer")
 	} else {
-		// Use real RabbitMQ implementation
+		// Use the configured broker
 		if cfg.MessageQueue.EnableProducer {
-			producerConfig := &mq.RabbitMQProducerConfig{
-				URL:           cfg.MessageQueue.URL,
-				ExchangeName:  cfg.MessageQueue.ExchangeName,
-				RoutingPrefix: cfg.MessageQueue.RoutingPrefix,
-				Durable:       cfg.MessageQueue.Durable,
-				AutoDelete:    cfg.MessageQueue.AutoDelete,
-			}
-
-			var err error
-			producer, err = mq.NewRabbitMQProducer(producerConfig, logger.Logger)
-			if err != nil {
-				logger.Warn("Failed to initialize RabbitMQ producer, using mock", zap.Error(err))
+			producer, producerErr = newProducer(cfg, logger)
+			if producerErr != nil {
+				logger.Warn("Failed to initialize message queue producer, using mock",
+					zap.String("driver", cfg.MessageQueue.Driver),
+					zap.Error(producerErr),
+				)
 				producer = mq.NewMockProducer(logger.Logger)
 			} else {
-				logger.Info("Using RabbitMQ producer")
+				logger.Info("Using message queue producer", zap.String("driver", cfg.MessageQueue.Driver))
 			}
 		} else {
 			producer = mq.NewMockProducer(logger.Logger)
@@ -200,6 +265,98 @@ func initializeDependencies(cfg *config.Config, logger *logger.Logger) (*Depende
 		}
 	}
 
+	// Webhooks receive every event alongside the message queue
+	events := producer
+	if cfg.Webhook.Enabled() {
+		webhook, err := mq.NewWebhookProducer(&mq.WebhookProducerConfig{
+			URL:           cfg.Webhook.URL,
+			Secret:        cfg.Webhook.Secret,
+			Timeout:       cfg.Webhook.Timeout,
+			RetryAttempts: cfg.Webhook.PublishAttempts,
+			RetryBackoff:  cfg.Webhook.PublishBackoff,
+		}, logger.Logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize webhook producer: %w", err)
+		}
+		events = mq.NewFanOutProducer(producer, webhook)
+	}
+
+	// Events are recorded with each write and relayed by the outbox publisher when
+	// the repository supports it; otherwise the use case publishes after the write
+	var outboxPublisher *outbox.Publisher
+	if outboxRepo != nil {
+		outboxPublisher = outbox.NewPublisher(outboxRepo, events, logger.Logger,
+			cfg.MessageQueue.Outbox.PollInterval, cfg.MessageQueue.Outbox.BatchSize)
+		logger.Info("Publishing events through the transactional outbox")
+		adminOpts = append(adminOpts, httpTransport.WithEventReplayer(outboxPublisher))
+	}
+
+	// Initialize use case; an external API outage trips the circuit breaker
+	// instead of timing out every request
+	background := usecase.NewBackgroundTasks()
+	ucOpts := []usecase.Option{
+		usecase.WithEnrichmentConcurrency(cfg.ExternalAPI.EnrichmentConcurrency),
+		usecase.WithBackgroundTasks(background),
+	}
+	if outboxPublisher == nil {
+		ucOpts = append(ucOpts, usecase.WithEventPublisher(events))
+	}
+	if transactor != nil {
+		ucOpts = append(ucOpts, usecase.WithTransactor(transactor, func(txRepo repository.ExampleRepository) service.ExampleService {
+			return service.NewExampleService(txRepo, logger.Logger, rules, service.WithPagination(pagination))
+		}))
+	}
+	if !cfg.ExternalAPI.EnableEnrichment {
+		logger.Info("External API enrichment is disabled")
+		ucOpts = append(ucOpts, usecase.WithEnrichmentDisabled())
+	}
+	if breaker := cfg.ExternalAPI.CircuitBreaker; breaker.Enabled {
+		ucOpts = append(ucOpts, usecase.WithCircuitBreaker(breaker.FailureThreshold, breaker.OpenDuration))
+	}
+	if cfg.Audit.Enabled {
+		sink, err := newAuditSink(cfg, dbConn, logger)
+		if err != nil {
+			return nil, err
+		}
+		ucOpts = append(ucOpts, usecase.WithAuditor(audit.New(sink)))
+	}
+	uc := usecase.NewExampleUseCaseWithConfig(svc, externalAPI, logger.Logger,
+		usecase.UseCaseConfig{Timeout: cfg.ExternalAPI.Timeout, Pagination: pagination}, ucOpts...)
+
+	// Initialize HTTP handler
+	handlerOpts := []httpTransport.ExampleHandlerOption{
+		httpTransport.WithLocalizer(localizer),
+		httpTransport.WithIdempotency(httpTransport.NewInMemoryIdempotencyStore(), cfg.Server.IdempotencyTTL),
+		httpTransport.WithPagination(pagination),
+		httpTransport.WithStreamReadTimeout(cfg.Server.ReadTimeout),
+		httpTransport.WithStreamWriteTimeout(cfg.Server.WriteTimeout),
+	}
+	if cfg.Server.SchemaDir != "" {
+		schemas, err := loadRequestSchemas(cfg.Server.SchemaDir)
+		if err != nil {
+			return nil, err
+		}
+		handlerOpts = append(handlerOpts, httpTransport.WithJSONSchemas(schemas))
+	}
+	if cfg.Server.UniqueEmail {
+		handlerOpts = append(handlerOpts, httpTransport.WithUniqueEmail(repo))
+	}
+	handler := httpTransport.NewExampleHandler(uc, validator, handlerOpts...)
+	if cfg.Server.AdminAuth.Enabled() {
+		adminOpts = append(adminOpts, httpTransport.WithAdminMiddleware(
+			httpTransport.BasicAuthMiddleware(cfg.Server.AdminAuth.Username, cfg.Server.AdminAuth.Password)))
+	}
+	admin := httpTransport.NewAdminHandler(logger, adminOpts...)
+	checks, readiness := newHealthChecks(cfg, healthDeps{
+		dbConn:      dbConn,
+		dbErr:       dbErr,
+		migrations:  migrations,
+		externalAPI: externalAPI,
+		producer:    producer,
+		producerErr: producerErr,
+	})
+	healthHandler := httpTransport.NewHealthHandler(checks, readiness, cfg.App.Version)
+
 	return &Dependencies{
 		Repository:  repo,
 		ExternalAPI: externalAPI,
@@ -207,12 +364,252 @@ func initializeDependencies(cfg *config.Config, logger *logger.Logger) (*Depende
 		UseCase:     uc,
 		Validator:   validator,
 		Handler:     handler,
-		Producer:    producer,
+		Admin:       admin,
+		Health:      healthHandler,
+		Version:     httpTransport.NewVersionHandler(cfg.App),
+		Producer:    events,
 		DBConn:      dbConn,
 		Localizer:   localizer,
+		Metrics:     appMetrics,
+		Outbox:      outboxPublisher,
+		Background:  background,
 	}, nil
 }
 
+// newAuditSink creates the configured audit sink; the db sink needs PostgreSQL and falls
+// back to the log sink without it
+func newAuditSink(cfg *config.Config, dbConn *database.PostgreSQLConnection, logger *logger.Logger) (audit.Sink, error) {
+	if cfg.Audit.Sink == audit.SinkDB {
+		if dbConn != nil {
+			sink := audit.NewDBSink(dbConn.DB)
+			if err := database.MigrateSchema(&cfg.Database, sink, "audit_log", logger); err != nil {
+				return nil, fmt.Errorf("failed to migrate audit log: %w", err)
+			}
+			logger.Info("Auditing changes to the audit_log table")
+			return sink, nil
+		}
+		logger.Warn("Audit database sink needs PostgreSQL, logging audit entries instead")
+	}
+	logger.Info("Auditing changes to the log")
+	return audit.NewLogSink(logger.Logger.Named("audit")), nil
+}
+
+// storage is the repository picked at startup
+type storage struct {
+	repo       repository.ExampleRepository
+	outboxRepo repository.OutboxRepository           // Nil unless the outbox is enabled on PostgreSQL
+	transactor repository.Transactor                 // Nil unless the outbox is enabled on PostgreSQL
+	schema     httpTransport.MigrationStatusReporter // Nil for the in-memory repository
+	dbConn     *database.PostgreSQLConnection        // Nil for the in-memory repository
+	dbErr      error                                 // Why the configured database was replaced by the in-memory fallback
+}
+
+// initStorage opens the configured repository and opens migrations once the schema is ready.
+// When the database is unavailable, it fails unless Database.FallbackToMemory allows serving
+// from an empty in-memory repository, which readiness then reports as degraded.
+func initStorage(cfg *config.Config, logger *logger.Logger, migrations *health.Gate) (*storage, error) {
+	var store *storage
+	switch cfg.Database.Type {
+	case "memory":
+		repo, err := newMemoryRepository(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		migrations.Open()
+		logger.Info("Using in-memory repository")
+		return &storage{repo: repo}, nil
+	case "postgres", "postgresql":
+		dbConn, pgRepo, err := openPostgres(cfg, logger)
+		if err != nil {
+			store = &storage{dbErr: err}
+			break
+		}
+		store = &storage{repo: pgRepo, dbConn: dbConn, schema: pgRepo}
+		if cfg.MessageQueue.Outbox.Enabled {
+			store.outboxRepo = pgRepo
+			store.transactor = pgRepo
+		}
+		migrations.Open()
+		logger.Info("Using PostgreSQL repository",
+			zap.String("host", cfg.Database.Host),
+			zap.Int("port", cfg.Database.Port),
+			zap.String("database", cfg.Database.Name),
+		)
+		return store, nil
+	default:
+		store = &storage{dbErr: fmt.Errorf("unsupported database type %q", cfg.Database.Type)}
+	}
+
+	if !cfg.Database.FallbackToMemory {
+		return nil, fmt.Errorf("database unavailable: %w", store.dbErr)
+	}
+	logger.Error("Database unavailable, falling back to in-memory repository", zap.Error(store.dbErr))
+	store.repo = repository.NewInMemoryExampleRepository(statsOption(cfg))
+	migrations.Open()
+	return store, nil
+}
+
+// statsOption applies the configured age buckets to the repository stats,
+// keeping the built-in buckets when none are configured
+func statsOption(cfg *config.Config) repository.Option {
+	buckets := cfg.Stats.AgeBucketRanges()
+	if len(buckets) == 0 {
+		return repository.WithStatsConfig(repository.DefaultStatsConfig())
+	}
+	stats := repository.StatsConfig{AgeBuckets: make([]repository.AgeBucket, len(buckets))}
+	for i, bucket := range buckets {
+		stats.AgeBuckets[i] = repository.AgeBucket{Label: bucket.Label, Min: bucket.Min, Max: bucket.Max}
+	}
+	return repository.WithStatsConfig(stats)
+}
+
+// newMemoryRepository creates the in-memory repository, seeded from Database.SeedFile if set
+func newMemoryRepository(cfg *config.Config, logger *logger.Logger) (repository.ExampleRepository, error) {
+	if cfg.Database.SeedFile == "" {
+		return repository.NewInMemoryExampleRepository(statsOption(cfg)), nil
+	}
+
+	seed, err := repository.LoadSeedFile(cfg.Database.SeedFile)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := repository.NewInMemoryExampleRepositoryWithSeed(seed, statsOption(cfg))
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Seeded in-memory repository",
+		zap.String("file", cfg.Database.SeedFile),
+		zap.Int("examples", len(seed)),
+	)
+	return repo, nil
+}
+
+// openPostgres connects to PostgreSQL, checks the connection and migrates the schema
+func openPostgres(cfg *config.Config, logger *logger.Logger) (*database.PostgreSQLConnection, *repository.PostgreSQLExampleRepository, error) {
+	dbConn, err := database.NewPostgreSQLConnection(&cfg.Database, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := dbConn.HealthCheck(); err != nil {
+		dbConn.Close()
+		return nil, nil, fmt.Errorf("PostgreSQL health check failed: %w", err)
+	}
+
+	// A pool that fails to warm up still works, connecting on demand
+	if cfg.Database.WarmUp {
+		warmUpCtx, cancel := context.WithTimeout(context.Background(), cfg.Database.WarmUpTimeout)
+		_, err := dbConn.WarmUp(warmUpCtx)
+		cancel()
+		if err != nil {
+			logger.Warn("Database connection pool warm-up failed", zap.Error(err))
+		}
+	}
+
+	pgRepo := repository.NewPostgreSQLExampleRepository(dbConn.DB,
+		repository.WithTablePrefix(cfg.Database.TablePrefix),
+		repository.WithSchema(cfg.Database.Schema),
+		statsOption(cfg),
+	)
+	if cfg.MessageQueue.Outbox.Enabled {
+		pgRepo = pgRepo.WithOutbox()
+	}
+	if err := database.MigrateSchema(&cfg.Database, pgRepo, "examples", logger); err != nil {
+		dbConn.Close()
+		return nil, nil, fmt.Errorf("database migration failed: %w", err)
+	}
+	return dbConn, pgRepo, nil
+}
+
+// metricsNamespace converts the application name into a valid Prometheus namespace
+func metricsNamespace(appName string) string {
+	return strings.ReplaceAll(appName, "-", "_")
+}
+
+// healthDeps holds what the health checks probe, including startup failures that were
+// papered over with in-memory or mock fallbacks
+type healthDeps struct {
+	dbConn      *database.PostgreSQLConnection
+	dbErr       error
+	migrations  *health.Gate
+	externalAPI repository.ExternalExampleAPI
+	producer    mq.ExampleProducer
+	producerErr error
+}
+
+// newHealthChecks builds the full dependency health checks and the subset gating readiness:
+// the schema is migrated and the database and message queue are connected. A database
+// replaced by the in-memory fallback is reported as degraded rather than failing.
+func newHealthChecks(cfg *config.Config, deps healthDeps) (checks, readiness *health.Aggregator) {
+	dbCheck := health.NewCheck("database", func(ctx context.Context) error {
+		switch {
+		case deps.dbConn != nil:
+			return deps.dbConn.HealthCheck()
+		case deps.dbErr != nil:
+			return fmt.Errorf("%w: using in-memory fallback: %v", health.ErrDegraded, deps.dbErr)
+		default:
+			return nil
+		}
+	})
+
+	var mqCheck health.Checker
+	if conn, ok := deps.producer.(interface{ IsConnected() bool }); ok {
+		mqCheck = health.NewConnectionCheck("mq", conn.IsConnected)
+	} else {
+		mqCheck = health.NewCheck("mq", func(ctx context.Context) error {
+			if deps.producerErr != nil {
+				return fmt.Errorf("using mock fallback: %v", deps.producerErr)
+			}
+			return health.ErrNotConfigured // mock or disabled producer has nothing to probe
+		})
+	}
+
+	checks = health.NewAggregator(cfg.Server.HealthTimeout)
+	checks.Register(dbCheck, health.NewCheck("external_api", deps.externalAPI.Ping), mqCheck)
+
+	readiness = health.NewAggregator(cfg.Server.HealthTimeout)
+	readiness.Register(deps.migrations, dbCheck, mqCheck)
+
+	return checks, readiness
+}
+
+// loadRequestSchemas loads the JSON Schema of each example route that takes a body from dir
+func loadRequestSchemas(dir string) (*validator.JSONSchemaValidator, error) {
+	schemas := validator.NewJSONSchemaValidator()
+	for route, file := range httpTransport.ExampleRequestSchemas {
+		if err := schemas.AddSchemaFile(route, filepath.Join(dir, file)); err != nil {
+			return nil, fmt.Errorf("failed to load request schemas: %w", err)
+		}
+	}
+	return schemas, nil
+}
+
+// newProducer connects the producer for the configured message queue driver
+func newProducer(cfg *config.Config, logger *logger.Logger) (mq.ExampleProducer, error) {
+	switch cfg.MessageQueue.Driver {
+	case "nats":
+		return mq.NewNATSProducer(&mq.NATSProducerConfig{
+			URL:               cfg.MessageQueue.URL,
+			StreamName:        cfg.MessageQueue.StreamName,
+			SubjectPrefix:     cfg.MessageQueue.RoutingPrefix,
+			Durable:           cfg.MessageQueue.Durable,
+			ReconnectInterval: cfg.MessageQueue.ReconnectInterval,
+			RetryAttempts:     cfg.MessageQueue.PublishAttempts,
+			RetryBackoff:      cfg.MessageQueue.PublishBackoff,
+		}, logger.Logger)
+	default:
+		return mq.NewRabbitMQProducer(&mq.RabbitMQProducerConfig{
+			URL:           cfg.MessageQueue.URL,
+			ExchangeName:  cfg.MessageQueue.ExchangeName,
+			RoutingPrefix: cfg.MessageQueue.RoutingPrefix,
+			Durable:       cfg.MessageQueue.Durable,
+			AutoDelete:    cfg.MessageQueue.AutoDelete,
+			RetryAttempts: cfg.MessageQueue.PublishAttempts,
+			RetryBackoff:  cfg.MessageQueue.PublishBackoff,
+		}, logger.Logger)
+	}
+}
+
 // setupEcho configures the Echo web framework
 func setupEcho(cfg *config.Config, logger *logger.Logger, deps *Dependencies) *echo.Echo {
 	e := echo.New()
@@ -223,28 +620,75 @@ func setupEcho(cfg *config.Config, logger *logger.Logger, deps *Dependencies) *e
 
 	// Configure Echo
 	e.Debug = cfg.App.Debug
+	// Client IPs, used for rate limiting and logs, only come from forwarded headers set by trusted proxies
+	e.IPExtractor = httpTransport.TrustedProxyIPExtractor(cfg.Server.TrustedProxyRanges())
 
 	// Set custom error handler with i18n support
-	e.HTTPErrorHandler = httpTransport.ErrorHandlerMiddleware(deps.Localizer)
+	e.HTTPErrorHandler = httpTransport.ErrorHandlerMiddleware(deps.Localizer,
+		httpTransport.WithStackTraces(cfg.App.ExposeErrorStack))
 
 	// Middleware
 	e.Use(httpTransport.RequestIDMiddleware())
+	e.Use(httpTransport.TracingMiddleware(cfg.Tracing.ServiceName))
+	if deps.Metrics != nil {
+		e.Use(httpTransport.MetricsMiddleware(deps.Metrics))
+		var metricsAuth []echo.MiddlewareFunc
+		if cfg.Server.AdminAuth.Enabled() {
+			metricsAuth = append(metricsAuth, httpTransport.BasicAuthMiddleware(cfg.Server.AdminAuth.Username, cfg.Server.AdminAuth.Password))
+		}
+		e.GET("/metrics", echo.WrapHandler(deps.Metrics.Handler()), metricsAuth...)
+	}
 	e.Use(httpTransport.I18nMiddleware(deps.Localizer))
 	e.Use(createLoggingMiddleware(logger))
-	e.Use(middleware.Recover())
-	e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
-		Timeout: cfg.Server.ReadTimeout,
-	}))
+	e.Use(httpTransport.RecoverMiddleware(logger.Logger, deps.Metrics))
+	// Cancel the request context after the handler timeout so slow downstream calls
+	// give up; the use case's external API timeouts nest under this deadline. Streaming
+	// routes run for as long as the client keeps up, extending the connection deadlines per chunk.
+	e.Use(httpTransport.RequestTimeoutMiddleware(cfg.Server.HandlerTimeout,
+		httpTransport.WithTimeoutSkipper(httpTransport.IsStreamingRoute)))
 
 	// Security middleware
 	e.Use(httpTransport.InputSanitizationMiddleware())
-	e.Use(httpTransport.RequestSizeLimitMiddleware(1024 * 1024)) // 1MB limit
-	e.Use(httpTransport.IPRateLimitMiddleware(60))               // 60 requests per minute per IP
+	// Batch routes carry many examples per request, so they get a larger body limit
+	batchSizeLimits := []httpTransport.RequestSizeLimitOption{
+		httpTransport.WithRouteSizeLimit("/api/v1/examples/batch", cfg.Server.MaxBatchRequestBytes),
+		httpTransport.WithRouteSizeLimit("/api/v1/examples/import", cfg.Server.MaxBatchRequestBytes),
+	}
+	e.Use(httpTransport.RequestSizeLimitMiddleware(cfg.Server.MaxRequestBytes, batchSizeLimits...))
+	e.Use(httpTransport.RequestDecompressionMiddleware(cfg.Server.MaxRequestBytes, batchSizeLimits...)) // Same limits once decompressed
 
 	if cfg.Server.EnableCORS {
 		e.Use(httpTransport.CORSMiddleware())
 	}
 
+	// Authentication for the /api/v1 group; the health check and version stay public for probes
+	// and deployment checks, and admin routes with their own credentials skip it
+	if cfg.Auth.Enabled {
+		adminAuth := cfg.Server.AdminAuth.Enabled()
+		e.Use(httpTransport.JWTAuthMiddleware(cfg.Auth.Secret,
+			httpTransport.WithIssuer(cfg.Auth.Issuer),
+			httpTransport.WithSkipper(func(c echo.Context) bool {
+				return !strings.HasPrefix(c.Path(), "/api/v1/") || c.Path() == "/api/v1/health" || c.Path() == "/api/v1/version" ||
+					(adminAuth && strings.HasPrefix(c.Path(), "/api/v1/admin/"))
+			}),
+		))
+	}
+
+	// 60 requests per minute per IP; with the per-user limit below, authenticated users,
+	// whose user ID is set above, aren't held to the limit of a proxy they share
+	var ipLimitOpts []httpTransport.IPRateLimitOption
+	if cfg.RateLimit.Enabled {
+		ipLimitOpts = append(ipLimitOpts, httpTransport.WithUserRateLimit())
+	}
+	e.Use(httpTransport.IPRateLimitMiddleware(60, ipLimitOpts...))
+
+	// Limit each authenticated user, which needs the user ID set above; anonymous
+	// requests are limited by IP so clients sharing a proxy are told apart once signed in
+	if cfg.RateLimit.Enabled {
+		e.Use(httpTransport.UserRateLimitMiddleware(cfg.RateLimit.Requests,
+			httpTransport.WithRateLimitWindow(cfg.RateLimit.Window)))
+	}
+
 	// Security headers
 	e.Use(middleware.SecureWithConfig(middleware.SecureConfig{
 		XSSProtection:         "1; mode=block",
@@ -257,8 +701,9 @@ func setupEcho(cfg *config.Config, logger *logger.Logger, deps *Dependencies) *e
 	// Rate limiting (basic)
 	e.Use(middleware.RateLimiter(middleware.NewRateLimiterMemoryStore(20)))
 
-	// Compression
-	e.Use(middleware.Gzip())
+	// Compression; streaming routes skip it, since their handlers need the connection's own
+	// writer to extend its deadlines
+	e.Use(middleware.GzipWithConfig(middleware.GzipConfig{Skipper: httpTransport.IsStreamingRoute}))
 
 	return e
 }
@@ -307,6 +752,23 @@ func startServer(e *echo.Echo, cfg *config.Config, logger *logger.Logger, deps *
 		IdleTimeout:  cfg.Server.ReadTimeout * 2,
 	}
 
+	// Relay outbox events until shutdown
+	outboxCtx, stopOutbox := context.WithCancel(context.Background())
+	outboxDone := make(chan struct{})
+	go func() {
+		defer close(outboxDone)
+		if deps.Outbox != nil {
+			deps.Outbox.Run(outboxCtx)
+		}
+	}()
+
+	// Export connection pool statistics until shutdown
+	poolStatsCtx, stopPoolStats := context.WithCancel(context.Background())
+	defer stopPoolStats()
+	if deps.DBConn != nil && deps.Metrics != nil {
+		go deps.DBConn.ReportPoolStats(poolStatsCtx, deps.Metrics, cfg.Database.PoolStatsInterval)
+	}
+
 	// Start server in a goroutine
 	go func() {
 		logger.Info("Starting HTTP server",
@@ -327,31 +789,106 @@ func startServer(e *echo.Echo, cfg *config.Config, logger *logger.Logger, deps *
 
 	logger.Info("Shutting down server...")
 
-	// Close database connection
+	teardown := shutdownDeps{
+		server:     e,
+		background: deps.Background,
+		workers: closerFunc(func() error {
+			// Unpublished events stay in the outbox for the next start
+			stopOutbox()
+			stopPoolStats()
+			<-outboxDone
+			return nil
+		}),
+		producer: deps.Producer,
+		logger:   logger.Logger,
+	}
 	if deps.DBConn != nil {
-		if err := deps.DBConn.Close(); err != nil {
-			logger.Error("Failed to close database connection", zap.Error(err))
-		} else {
-			logger.Info("Database connection closed")
-		}
+		teardown.db = deps.DBConn
+	}
+	shutdown(teardown, cfg.Server.ShutdownTimeout)
+}
+
+// shutdowner drains work until ctx is done
+type shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// closerFunc adapts a function to io.Closer
+type closerFunc func() error
+
+// Close calls f
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// shutdownDeps holds what shutdown tears down, in the order it does so
+type shutdownDeps struct {
+	server     shutdowner // Stops accepting connections and drains in-flight requests
+	background shutdowner // Notifications started by those requests
+	workers    io.Closer  // Outbox relay and pool stats reporter
+	producer   io.Closer
+	db         io.Closer // Optional, only for PostgreSQL
+	logger     *zap.Logger
+}
+
+// shutdown tears deps down so nothing still running can use what is already closed: the
+// server is drained first, then background work, then the producer and finally the
+// database. The whole teardown is bounded by timeout; steps still running at the deadline
+// are abandoned and the remaining ones are given no time.
+func shutdown(deps shutdownDeps, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	logger := deps.logger
+
+	if err := deps.server.Shutdown(ctx); err != nil {
+		logger.Error("Server forced to shutdown", zap.Error(err))
+	} else {
+		logger.Info("Server exited gracefully")
+	}
+
+	// Any notifications still running at the deadline are cancelled
+	if err := deps.background.Shutdown(ctx); err != nil {
+		logger.Warn("Cancelled background tasks still running at shutdown", zap.Error(err))
+	} else {
+		logger.Info("Background tasks finished")
 	}
 
-	// Close message queue producer
-	if err := deps.Producer.Close(); err != nil {
+	if err := closeWithin(ctx, deps.workers); err != nil {
+		logger.Error("Failed to stop background workers", zap.Error(err))
+	} else {
+		logger.Info("Background workers stopped")
+	}
+
+	if err := closeWithin(ctx, deps.producer); err != nil {
 		logger.Error("Failed to close message queue producer", zap.Error(err))
 	} else {
 		logger.Info("Message queue producer closed")
 	}
 
-	// Create shutdown context with timeout
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
-	defer shutdownCancel()
-
-	// Shutdown server
-	if err := e.Shutdown(shutdownCtx); err != nil {
-		logger.Error("Server forced to shutdown", zap.Error(err))
+	if deps.db == nil {
+		return
+	}
+	if err := closeWithin(ctx, deps.db); err != nil {
+		logger.Error("Failed to close database connection", zap.Error(err))
 	} else {
-		logger.Info("Server exited gracefully")
+		logger.Info("Database connection closed")
+	}
+}
+
+// closeWithin closes c, giving up once ctx is done
+func closeWithin(ctx context.Context, c io.Closer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- c.Close() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
@@ -0,0 +1,167 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"example-api-template/pkg/ctxkeys"
+	"example-api-template/pkg/logger"
+)
+
+// Audited actions
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// systemUser is recorded when a change is made outside an authenticated request
+const systemUser = "system"
+
+// Entry is one audited change
+type Entry struct {
+	Action     string            `json:"action"`
+	ResourceID string            `json:"resource_id"`
+	UserID     string            `json:"user_id"`
+	RequestID  string            `json:"request_id,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Before     json.RawMessage   `json:"before,omitempty"` // Nil for creates
+	After      json.RawMessage   `json:"after,omitempty"`  // Nil for deletes
+	Changes    map[string]Change `json:"changes,omitempty"`
+}
+
+// Change is the old and new value of one field
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Sink stores audit entries
+type Sink interface {
+	Write(ctx context.Context, entry Entry) error
+}
+
+// Auditor records who changed what to a Sink
+type Auditor struct {
+	sink Sink
+	now  func() time.Time
+}
+
+// New creates an Auditor writing to sink
+func New(sink Sink) *Auditor {
+	return &Auditor{
+		sink: sink,
+		now:  time.Now,
+	}
+}
+
+// Audit records that the current user performed action on resourceID, changing it from before
+// to after. Either may be nil; both are stored as JSON along with the fields that differ.
+func (a *Auditor) Audit(ctx context.Context, action, resourceID string, before, after interface{}) error {
+	return a.record(ctx, action, resourceID, before, after, nil)
+}
+
+// AuditChanges is Audit for a change whose fields the caller already knows, such as from
+// domain.Example.Diff; only those fields, by JSON name, are recorded as changes, so ones
+// bumped on every write like timestamps are left out.
+func (a *Auditor) AuditChanges(ctx context.Context, action, resourceID string, before, after interface{}, fields []string) error {
+	if fields == nil {
+		fields = []string{} // No fields records no changes, unlike record's nil
+	}
+	return a.record(ctx, action, resourceID, before, after, fields)
+}
+
+// record writes an entry whose changes are limited to fields; nil compares every field
+func (a *Auditor) record(ctx context.Context, action, resourceID string, before, after interface{}, fields []string) error {
+	entry := Entry{
+		Action:     action,
+		ResourceID: resourceID,
+		UserID:     userID(ctx),
+		RequestID:  logger.RequestID(ctx),
+		Timestamp:  a.now().UTC(),
+	}
+
+	var beforeFields, afterFields map[string]interface{}
+	var err error
+	if entry.Before, beforeFields, err = snapshot(before); err != nil {
+		return fmt.Errorf("audit %s %s: before: %w", action, resourceID, err)
+	}
+	if entry.After, afterFields, err = snapshot(after); err != nil {
+		return fmt.Errorf("audit %s %s: after: %w", action, resourceID, err)
+	}
+	entry.Changes = diff(beforeFields, afterFields)
+	if fields != nil {
+		entry.Changes = only(entry.Changes, fields)
+	}
+
+	if err := a.sink.Write(ctx, entry); err != nil {
+		return fmt.Errorf("audit %s %s: %w", action, resourceID, err)
+	}
+	return nil
+}
+
+// snapshot encodes v as JSON and decodes it back into its fields, so values of any type can be
+// stored and compared field by field
+func snapshot(v interface{}) (json.RawMessage, map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, nil, fmt.Errorf("must encode as a JSON object: %w", err)
+	}
+	return data, fields, nil
+}
+
+// diff returns the fields whose values differ between before and after
+func diff(before, after map[string]interface{}) map[string]Change {
+	changes := make(map[string]Change)
+	for field, from := range before {
+		if to, ok := after[field]; !ok || !reflect.DeepEqual(from, to) {
+			changes[field] = Change{From: from, To: after[field]}
+		}
+	}
+	for field, to := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = Change{To: to}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// only returns the changes to fields
+func only(changes map[string]Change, fields []string) map[string]Change {
+	kept := make(map[string]Change)
+	for _, field := range fields {
+		if change, ok := changes[field]; ok {
+			kept[field] = change
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+// userID returns the authenticated user carried by ctx
+func userID(ctx context.Context) string {
+	if id := ctxkeys.UserID(ctx); id != "" {
+		return id
+	}
+	return systemUser
+}
@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"example-api-template/internal/domain"
+	"example-api-template/pkg/ctxkeys"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// memorySink keeps every entry written to it
+type memorySink struct {
+	entries []Entry
+	err     error
+}
+
+func (s *memorySink) Write(ctx context.Context, entry Entry) error {
+	s.entries = append(s.entries, entry)
+	return s.err
+}
+
+// userContext returns a context carrying an authenticated user and request ID
+func userContext() context.Context {
+	ctx := ctxkeys.WithUserID(context.Background(), "user-42")
+	return ctxkeys.WithRequestID(ctx, "req-123")
+}
+
+func newExample(t *testing.T, name string, age int) *domain.Example {
+	t.Helper()
+	example, err := domain.NewExample("ex_001", name, "john@example.com", age)
+	require.NoError(t, err)
+	return example
+}
+
+func TestAuditor(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("update records the diff and acting user", func(t *testing.T) {
+		sink := &memorySink{}
+		auditor := New(sink)
+		auditor.now = func() time.Time { return now }
+
+		before := newExample(t, "John Doe", 30)
+		after := *before
+		after.Name = "John Smith"
+		after.Age = 31
+
+		require.NoError(t, auditor.Audit(userContext(), ActionUpdate, before.ID, before, &after))
+
+		require.Len(t, sink.entries, 1)
+		entry := sink.entries[0]
+		assert.Equal(t, ActionUpdate, entry.Action)
+		assert.Equal(t, "ex_001", entry.ResourceID)
+		assert.Equal(t, "user-42", entry.UserID)
+		assert.Equal(t, "req-123", entry.RequestID)
+		assert.Equal(t, now, entry.Timestamp)
+		assert.Equal(t, map[string]Change{
+			"name": {From: "John Doe", To: "John Smith"},
+			"age":  {From: float64(30), To: float64(31)},
+		}, entry.Changes)
+		assert.JSONEq(t, mustJSON(t, before), string(entry.Before))
+		assert.JSONEq(t, mustJSON(t, &after), string(entry.After))
+	})
+
+	t.Run("create has no before and every field changes", func(t *testing.T) {
+		sink := &memorySink{}
+		example := newExample(t, "John Doe", 30)
+
+		require.NoError(t, New(sink).Audit(context.Background(), ActionCreate, example.ID, (*domain.Example)(nil), example))
+
+		entry := sink.entries[0]
+		assert.Equal(t, "system", entry.UserID)
+		assert.Empty(t, entry.RequestID)
+		assert.Nil(t, entry.Before)
+		assert.Equal(t, Change{To: "John Doe"}, entry.Changes["name"])
+	})
+
+	t.Run("changes are limited to the fields given", func(t *testing.T) {
+		sink := &memorySink{}
+		before := newExample(t, "John Doe", 30)
+		after := *before
+		after.Name = "John Smith"
+		after.Version++
+
+		require.NoError(t, New(sink).AuditChanges(context.Background(), ActionUpdate, before.ID, before, &after, []string{"name"}))
+
+		assert.Equal(t, map[string]Change{"name": {From: "John Doe", To: "John Smith"}}, sink.entries[0].Changes)
+	})
+
+	t.Run("sink failure is returned", func(t *testing.T) {
+		sink := &memorySink{err: errors.New("disk full")}
+
+		err := New(sink).Audit(context.Background(), ActionDelete, "ex_001", newExample(t, "John Doe", 30), nil)
+		assert.ErrorIs(t, err, sink.err)
+	})
+}
+
+func TestLogSink(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	auditor := New(NewLogSink(zap.New(core)))
+
+	before := newExample(t, "John Doe", 30)
+	after := *before
+	after.Age = 31
+	require.NoError(t, auditor.Audit(userContext(), ActionUpdate, before.ID, before, &after))
+
+	entries := logs.FilterMessage("Audit").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, ActionUpdate, fields["action"])
+	assert.Equal(t, "ex_001", fields["resource_id"])
+	assert.Equal(t, "user-42", fields["user_id"])
+	assert.Equal(t, "req-123", fields["request_id"])
+	assert.Equal(t, map[string]Change{"age": {From: float64(30), To: float64(31)}}, fields["changes"])
+}
+
+func TestDBSink(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "audit.db")), &gorm.Config{})
+	require.NoError(t, err)
+	sink := NewDBSink(db)
+	require.NoError(t, sink.AutoMigrate())
+
+	before := newExample(t, "John Doe", 30)
+	require.NoError(t, New(sink).Audit(userContext(), ActionDelete, before.ID, before, nil))
+
+	var records []Record
+	require.NoError(t, db.Find(&records).Error)
+	require.Len(t, records, 1)
+	assert.Equal(t, ActionDelete, records[0].Action)
+	assert.Equal(t, "user-42", records[0].UserID)
+	assert.Equal(t, "req-123", records[0].RequestID)
+	assert.JSONEq(t, mustJSON(t, before), records[0].Before)
+	assert.Empty(t, records[0].After)
+	assert.Contains(t, records[0].Changes, `"name":{"from":"John Doe","to":null}`)
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Sink types selectable in configuration
+const (
+	SinkLog = "log"
+	SinkDB  = "db"
+)
+
+// LogSink writes audit entries as structured log records
+type LogSink struct {
+	logger *zap.Logger
+}
+
+// NewLogSink creates a sink logging to logger
+func NewLogSink(logger *zap.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+// Write logs entry at info level
+func (s *LogSink) Write(ctx context.Context, entry Entry) error {
+	s.logger.Info("Audit",
+		zap.String("action", entry.Action),
+		zap.String("resource_id", entry.ResourceID),
+		zap.String("user_id", entry.UserID),
+		zap.String("request_id", entry.RequestID),
+		zap.Time("timestamp", entry.Timestamp),
+		zap.Reflect("before", entry.Before),
+		zap.Reflect("after", entry.After),
+		zap.Reflect("changes", entry.Changes),
+	)
+	return nil
+}
+
+// Record is an audit entry as stored in the audit_log table
+type Record struct {
+	ID         uint      `gorm:"primaryKey"`
+	Action     string    `gorm:"size:32;not null;index"`
+	ResourceID string    `gorm:"size:255;not null;index"`
+	UserID     string    `gorm:"size:255;not null;index"`
+	RequestID  string    `gorm:"size:255"`
+	Before     string    `gorm:"type:text"`
+	After      string    `gorm:"type:text"`
+	Changes    string    `gorm:"type:text"`
+	CreatedAt  time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for GORM
+func (Record) TableName() string {
+	return "audit_log"
+}
+
+// DBSink writes audit entries to the audit_log table
+type DBSink struct {
+	db *gorm.DB
+}
+
+// NewDBSink creates a sink storing entries in db
+func NewDBSink(db *gorm.DB) *DBSink {
+	return &DBSink{db: db}
+}
+
+// AutoMigrate creates or updates the audit_log table
+func (s *DBSink) AutoMigrate() error {
+	return s.db.AutoMigrate(&Record{})
+}
+
+// Write inserts entry into the audit_log table
+func (s *DBSink) Write(ctx context.Context, entry Entry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("encode changes: %w", err)
+	}
+
+	record := Record{
+		Action:     entry.Action,
+		ResourceID: entry.ResourceID,
+		UserID:     entry.UserID,
+		RequestID:  entry.RequestID,
+		Before:     string(entry.Before),
+		After:      string(entry.After),
+		Changes:    string(changes),
+		CreatedAt:  entry.Timestamp,
+	}
+	if err := s.db.WithContext(ctx).Create(&record).Error; err != nil {
+		return fmt.Errorf("save audit record: %w", err)
+	}
+	return nil
+}
@@ -1,10 +1,11 @@
 package domain
 
 import (
-	"errors"
 	"fmt"
-	"regexp"
+	"strings"
 	"time"
+
+	"example-api-template/pkg/validator"
 )
 
 // Example represents the core business entity
@@ -13,11 +14,27 @@ type Example struct {
 	Name      string    `json:"name" gorm:"size:255;not null;index"`
 	Email     string    `json:"email" gorm:"size:255;not null;unique;index"`
 	Age       int       `json:"age" gorm:"not null"`
+	Phone     string    `json:"phone,omitempty" gorm:"size:32"` // Optional, E.164; nullable so rows from before the column stay valid
 	CreatedAt time.Time `json:"created_at" gorm:"not null"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"not null"`
+	Version   int       `json:"version" gorm:"not null;default:1"` // Incremented by the repository on every update
 }
 
-// NewExample creates a new Example entity with validation
+// Age categories returned by AgeCategory
+const (
+	AgeCategoryMinor  = "minor"
+	AgeCategoryAdult  = "adult"
+	AgeCategorySenior = "senior"
+)
+
+// Ages at which the categories start
+const (
+	AdultAge  = 18
+	SeniorAge = 65
+)
+
+// NewExample creates a new Example entity with validation; invalid fields are reported as a *ValidationError.
+// The email is stored lowercased, so addresses differing only in case belong to the same example.
 func NewExample(id, name, email string, age int) (*Example, error) {
 	if err := validateExample(name, email, age); err != nil {
 		return nil, err
@@ -27,10 +44,11 @@ func NewExample(id, name, email string, age int) (*Example, error) {
 	return &Example{
 		ID:        id,
 		Name:      name,
-		Email:     email,
+		Email:     NormalizeEmail(email),
 		Age:       age,
 		CreatedAt: now,
 		UpdatedAt: now,
+		Version:   1,
 	}, nil
 }
 
@@ -39,49 +57,102 @@ func (Example) TableName() string {
 	return "examples"
 }
 
-// Update updates the example entity with validation
+// Update updates the example entity with validation; invalid fields are reported as a *ValidationError
 func (e *Example) Update(name, email string, age int) error {
 	if err := validateExample(name, email, age); err != nil {
 		return err
 	}
 
 	e.Name = name
-	e.Email = email
+	e.Email = NormalizeEmail(email)
 	e.Age = age
 	e.UpdatedAt = time.Now()
 	return nil
 }
 
-// validateExample validates the example fields
+// NormalizeEmail returns email in the form examples store it, lowercased
+func NormalizeEmail(email string) string {
+	return strings.ToLower(email)
+}
+
+// SetPhone sets the optional phone number, which must be in E.164 format such as
+// "+14155552671"; an empty phone removes it
+func (e *Example) SetPhone(phone string) error {
+	if phone != "" && !validator.IsValidPhone(phone) {
+		verr := &ValidationError{}
+		verr.add("phone", "phone must be in E.164 format")
+		return verr
+	}
+	e.Phone = phone
+	return nil
+}
+
+// validateExample validates the example fields, returning a *ValidationError listing every invalid one
 func validateExample(name, email string, age int) error {
+	verr := &ValidationError{}
+
 	if name == "" {
-		return errors.New("name cannot be empty")
-	}
-	if len(name) > 100 {
-		return errors.New("name cannot exceed 100 characters")
+		verr.add("name", "name cannot be empty")
+	} else if len(name) > 100 {
+		verr.add("name", "name cannot exceed 100 characters")
 	}
 
 	if email == "" {
-		return errors.New("email cannot be empty")
-	}
-	if !isValidEmail(email) {
-		return errors.New("invalid email format")
+		verr.add("email", "email cannot be empty")
+	} else if !validator.IsValidEmail(email) {
+		verr.add("email", "invalid email format")
 	}
 
 	if age < 0 {
-		return errors.New("age cannot be negative")
+		verr.add("age", "age cannot be negative")
+	} else if age > 150 {
+		verr.add("age", "age cannot exceed 150")
 	}
-	if age > 150 {
-		return errors.New("age cannot exceed 150")
+
+	return verr.errOrNil()
+}
+
+// Equal reports whether e and other hold the same data, ignoring the timestamps and
+// version, which change with every save
+func (e *Example) Equal(other *Example) bool {
+	if e == nil || other == nil {
+		return e == other
 	}
+	return len(e.Diff(other)) == 0
+}
 
-	return nil
+// Diff returns the fields whose value differs in other, keyed by their JSON name and
+// holding other's value. Like Equal, it ignores the timestamps and version.
+func (e *Example) Diff(other *Example) map[string]interface{} {
+	changes := make(map[string]interface{})
+	if e.ID != other.ID {
+		changes["id"] = other.ID
+	}
+	if e.Name != other.Name {
+		changes["name"] = other.Name
+	}
+	if e.Email != other.Email {
+		changes["email"] = other.Email
+	}
+	if e.Age != other.Age {
+		changes["age"] = other.Age
+	}
+	if e.Phone != other.Phone {
+		changes["phone"] = other.Phone
+	}
+	return changes
 }
 
-// isValidEmail validates email format using regex
-func isValidEmail(email string) bool {
-	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-	return emailRegex.MatchString(email)
+// AgeCategory classifies the example's age as minor, adult or senior
+func (e *Example) AgeCategory() string {
+	switch {
+	case e.Age < AdultAge:
+		return AgeCategoryMinor
+	case e.Age < SeniorAge:
+		return AgeCategoryAdult
+	default:
+		return AgeCategorySenior
+	}
 }
 
 // String returns a string representation of the Example
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -260,70 +261,58 @@ func TestValidateExample(t *testing.T) {
 	}
 }
 
-func TestIsValidEmail(t *testing.T) {
-	tests := []struct {
-		name  string
-		email string
-		want  bool
-	}{
-		{
-			name:  "valid email",
-			email: "test@example.com",
-			want:  true,
-		},
-		{
-			name:  "valid email with subdomain",
-			email: "user@mail.example.com",
-			want:  true,
-		},
-		{
-			name:  "valid email with numbers",
-			email: "user123@example.com",
-			want:  true,
-		},
-		{
-			name:  "valid email with dots",
-			email: "user.name@example.com",
-			want:  true,
-		},
-		{
-			name:  "invalid email no @",
-			email: "userexample.com",
-			want:  false,
-		},
-		{
-			name:  "invalid email no domain",
-			email: "user@",
-			want:  false,
-		},
-		{
-			name:  "invalid email no user",
-			email: "@example.com",
-			want:  false,
-		},
-		{
-			name:  "invalid email no TLD",
-			email: "user@example",
-			want:  false,
-		},
-		{
-			name:  "empty email",
-			email: "",
-			want:  false,
-		},
-		{
-			name:  "invalid email multiple @",
-			email: "user@@example.com",
-			want:  false,
-		},
-	}
+func TestValidateExample_FieldErrors(t *testing.T) {
+	_, err := NewExample("test-id", "", "not-an-email", 30)
+	require.Error(t, err)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := isValidEmail(tt.email)
-			assert.Equal(t, tt.want, got)
-		})
-	}
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, []FieldError{
+		{Field: "name", Reason: "name cannot be empty"},
+		{Field: "email", Reason: "invalid email format"},
+	}, verr.Fields)
+	assert.EqualError(t, err, "name cannot be empty; invalid email format")
+
+	// Wrapped errors still match
+	assert.ErrorIs(t, fmt.Errorf("create: %w", err), ErrValidation)
+
+	example, err := NewExample("test-id", "John Doe", "john@example.com", 30)
+	require.NoError(t, err)
+	err = example.Update("John Doe", "john@example.com", -1)
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, []FieldError{{Field: "age", Reason: "age cannot be negative"}}, verr.Fields)
+}
+
+func TestValidateExample_EmailFormats(t *testing.T) {
+	// Email format rules live in pkg/validator; the domain must accept and reject the same addresses
+	assert.NoError(t, validateExample("John Doe", "user.name+tag@sub.example.co.uk", 30))
+	assert.EqualError(t, validateExample("John Doe", "user@@x.com", 30), "invalid email format")
+}
+
+func TestExample_EmailLowercased(t *testing.T) {
+	example, err := NewExample("test-id", "John Doe", "John.Doe@Example.COM", 30)
+	require.NoError(t, err)
+	assert.Equal(t, "john.doe@example.com", example.Email)
+
+	require.NoError(t, example.Update("John Doe", "JANE@example.com", 30))
+	assert.Equal(t, "jane@example.com", example.Email)
+}
+
+func TestExample_SetPhone(t *testing.T) {
+	example, err := NewExample("test-id", "John Doe", "john@example.com", 30)
+	require.NoError(t, err)
+	assert.Empty(t, example.Phone)
+
+	require.NoError(t, example.SetPhone("+14155552671"))
+	assert.Equal(t, "+14155552671", example.Phone)
+
+	// An invalid number is rejected and the previous one is kept
+	assert.EqualError(t, example.SetPhone("12345"), "phone must be in E.164 format")
+	assert.Equal(t, "+14155552671", example.Phone)
+
+	// An empty phone removes it
+	require.NoError(t, example.SetPhone(""))
+	assert.Empty(t, example.Phone)
 }
 
 func TestExample_String(t *testing.T) {
@@ -351,6 +340,76 @@ func TestExample_CreatedAtUpdatedAt(t *testing.T) {
 	assert.True(t, example.UpdatedAt.After(example.CreatedAt))
 }
 
+func TestExample_AgeCategory(t *testing.T) {
+	tests := []struct {
+		age      int
+		expected string
+	}{
+		{0, AgeCategoryMinor},
+		{17, AgeCategoryMinor},
+		{18, AgeCategoryAdult},
+		{64, AgeCategoryAdult},
+		{65, AgeCategorySenior},
+		{150, AgeCategorySenior},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("age %d", tt.age), func(t *testing.T) {
+			example := &Example{Age: tt.age}
+			assert.Equal(t, tt.expected, example.AgeCategory())
+		})
+	}
+}
+
+func TestExample_EqualAndDiff(t *testing.T) {
+	newPair := func(t *testing.T) (*Example, *Example) {
+		before, err := NewExample("test-id", "John Doe", "john@example.com", 30)
+		require.NoError(t, err)
+		after := *before
+		return before, &after
+	}
+
+	t.Run("equal ignores timestamps and version", func(t *testing.T) {
+		before, after := newPair(t)
+		after.UpdatedAt = before.UpdatedAt.Add(time.Hour)
+		after.CreatedAt = before.CreatedAt.Add(-time.Hour)
+		after.Version = before.Version + 1
+
+		assert.True(t, before.Equal(after))
+		assert.Empty(t, before.Diff(after))
+	})
+
+	t.Run("single field diff", func(t *testing.T) {
+		before, after := newPair(t)
+		require.NoError(t, after.Update("John Doe", "john@example.com", 31))
+
+		assert.False(t, before.Equal(after))
+		assert.Equal(t, map[string]interface{}{"age": 31}, before.Diff(after))
+	})
+
+	t.Run("multi field diff", func(t *testing.T) {
+		before, after := newPair(t)
+		require.NoError(t, after.Update("Jane Doe", "jane@example.com", 30))
+		require.NoError(t, after.SetPhone("+14155552671"))
+
+		assert.False(t, before.Equal(after))
+		assert.Equal(t, map[string]interface{}{
+			"name":  "Jane Doe",
+			"email": "jane@example.com",
+			"phone": "+14155552671",
+		}, before.Diff(after))
+	})
+
+	t.Run("nil examples", func(t *testing.T) {
+		before, _ := newPair(t)
+		var missing *Example
+
+		assert.False(t, before.Equal(nil))
+		assert.False(t, missing.Equal(before))
+		assert.True(t, missing.Equal(nil))
+	})
+}
+
 // Benchmark tests
 func BenchmarkNewExample(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -366,12 +425,3 @@ func BenchmarkExample_Update(b *testing.B) {
 		_ = example.Update("Jane Doe", "jane@example.com", 25)
 	}
 }
-
-func BenchmarkIsValidEmail(b *testing.B) {
-	email := "test@example.com"
-
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		_ = isValidEmail(email)
-	}
-}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types recorded in the outbox
+const (
+	EventTypeExampleCreated = "example.created"
+	EventTypeExampleUpdated = "example.updated"
+	EventTypeExampleDeleted = "example.deleted"
+)
+
+// OutboxEvent is an example event stored in the same transaction as the write that
+// raised it, so it is published even if the process stops before it gets the chance
+type OutboxEvent struct {
+	ID          string     `json:"id" gorm:"primaryKey;size:255"`
+	Type        string     `json:"type" gorm:"size:255;not null"`
+	Payload     string     `json:"payload" gorm:"type:text;not null"` // The example as JSON
+	CreatedAt   time.Time  `json:"created_at" gorm:"not null;index"`
+	PublishedAt *time.Time `json:"published_at" gorm:"index"` // Nil until the event is published
+	// A publisher relaying the event holds it until ClaimedUntil, so replicas don't relay it twice
+	ClaimedBy    string     `json:"-" gorm:"size:255;index"`
+	ClaimedUntil *time.Time `json:"-"`
+}
+
+// NewOutboxEvent creates an unpublished event of eventType carrying example
+func NewOutboxEvent(eventType string, example *Example) (*OutboxEvent, error) {
+	payload, err := json.Marshal(example)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	return &OutboxEvent{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		Payload:   string(payload),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// TableName returns the table name for GORM
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// Example decodes the example the event carries
+func (e *OutboxEvent) Example() (*Example, error) {
+	var example Example
+	if err := json.Unmarshal([]byte(e.Payload), &example); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event %s: %w", e.Type, e.ID, err)
+	}
+	return &example, nil
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "fmt"
+
+// Pagination holds the page sizes of example listings
+type Pagination struct {
+	DefaultLimit int // Page size when none is requested
+	MaxLimit     int // Largest page size; larger requests are clamped to it
+}
+
+// DefaultPagination is used unless configuration sets other limits
+var DefaultPagination = Pagination{DefaultLimit: 10, MaxLimit: 100}
+
+// Validate checks that both limits are positive and the default doesn't exceed the max
+func (p Pagination) Validate() error {
+	if p.DefaultLimit <= 0 || p.MaxLimit <= 0 {
+		return fmt.Errorf("page limits must be positive, got default %d and max %d", p.DefaultLimit, p.MaxLimit)
+	}
+	if p.DefaultLimit > p.MaxLimit {
+		return fmt.Errorf("default page limit %d exceeds max page limit %d", p.DefaultLimit, p.MaxLimit)
+	}
+	return nil
+}
+
+// Limit returns the page size for a requested limit: DefaultLimit when none is
+// requested, clamped to MaxLimit. Invalid limits fall back to DefaultPagination.
+func (p Pagination) Limit(requested int) int {
+	if p.Validate() != nil {
+		p = DefaultPagination
+	}
+	if requested <= 0 {
+		return p.DefaultLimit
+	}
+	return min(requested, p.MaxLimit)
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagination(t *testing.T) {
+	pagination := Pagination{DefaultLimit: 20, MaxLimit: 50}
+
+	assert.Equal(t, 20, pagination.Limit(0))
+	assert.Equal(t, 20, pagination.Limit(-1))
+	assert.Equal(t, 30, pagination.Limit(30))
+	assert.Equal(t, 50, pagination.Limit(200))
+
+	assert.NoError(t, pagination.Validate())
+	assert.Error(t, Pagination{DefaultLimit: 60, MaxLimit: 50}.Validate())
+	assert.Error(t, Pagination{DefaultLimit: 0, MaxLimit: 50}.Validate())
+
+	// Invalid limits fall back to the defaults instead of returning empty pages
+	assert.Equal(t, DefaultPagination.DefaultLimit, Pagination{}.Limit(0))
+	assert.Equal(t, DefaultPagination.MaxLimit, Pagination{}.Limit(1000))
+}
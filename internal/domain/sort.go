@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SortField is a field examples can be listed by
+type SortField string
+
+const (
+	SortByCreatedAt SortField = "created_at"
+	SortByName      SortField = "name"
+	SortByAge       SortField = "age"
+)
+
+// ErrInvalidSort is returned for a sort on a field that is not whitelisted
+var ErrInvalidSort = errors.New("invalid sort")
+
+// ExampleSort orders a listing of examples
+type ExampleSort struct {
+	Field      SortField
+	Descending bool
+}
+
+// DefaultExampleSort lists the newest examples first
+var DefaultExampleSort = ExampleSort{Field: SortByCreatedAt, Descending: true}
+
+// ParseExampleSort parses a sort such as "name" or "-age", where a leading "-"
+// means descending. An empty value gives DefaultExampleSort.
+func ParseExampleSort(value string) (ExampleSort, error) {
+	if value == "" {
+		return DefaultExampleSort, nil
+	}
+
+	sort := ExampleSort{Field: SortField(strings.TrimPrefix(value, "-"))}
+	sort.Descending = len(sort.Field) < len(value)
+	if !sort.Valid() {
+		return ExampleSort{}, fmt.Errorf("%w: %q, must be one of %s", ErrInvalidSort, value, strings.Join(SortValues(), ", "))
+	}
+	return sort, nil
+}
+
+// SortValues lists every accepted sort value
+func SortValues() []string {
+	values := make([]string, 0, 6)
+	for _, field := range []SortField{SortByCreatedAt, SortByName, SortByAge} {
+		values = append(values, string(field), "-"+string(field))
+	}
+	return values
+}
+
+// Valid reports whether the sort uses a whitelisted field
+func (s ExampleSort) Valid() bool {
+	switch s.Field {
+	case SortByCreatedAt, SortByName, SortByAge:
+		return true
+	default:
+		return false
+	}
+}
+
+// String formats the sort the way ParseExampleSort accepts it
+func (s ExampleSort) String() string {
+	if s.Descending {
+		return "-" + string(s.Field)
+	}
+	return string(s.Field)
+}
+
+// Less reports whether a sorts before b, breaking ties by ID so the order is stable
+func (s ExampleSort) Less(a, b *Example) bool {
+	var cmp int
+	switch s.Field {
+	case SortByName:
+		cmp = strings.Compare(a.Name, b.Name)
+	case SortByAge:
+		cmp = a.Age - b.Age
+	default:
+		cmp = a.CreatedAt.Compare(b.CreatedAt)
+	}
+
+	if cmp == 0 {
+		return a.ID < b.ID
+	}
+	if s.Descending {
+		return cmp > 0
+	}
+	return cmp < 0
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExampleSort(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected ExampleSort
+	}{
+		{"", DefaultExampleSort},
+		{"created_at", ExampleSort{Field: SortByCreatedAt}},
+		{"-created_at", ExampleSort{Field: SortByCreatedAt, Descending: true}},
+		{"name", ExampleSort{Field: SortByName}},
+		{"-name", ExampleSort{Field: SortByName, Descending: true}},
+		{"age", ExampleSort{Field: SortByAge}},
+		{"-age", ExampleSort{Field: SortByAge, Descending: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			sort, err := ParseExampleSort(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sort)
+			if tt.value != "" {
+				assert.Equal(t, tt.value, sort.String())
+			}
+		})
+	}
+
+	for _, value := range []string{"email", "-", "--name", "name DESC", "name;DROP TABLE examples"} {
+		t.Run("rejects "+value, func(t *testing.T) {
+			_, err := ParseExampleSort(value)
+			assert.ErrorIs(t, err, ErrInvalidSort)
+		})
+	}
+}
+
+func TestExampleSortLess(t *testing.T) {
+	now := time.Now()
+	older := &Example{ID: "b", Name: "Zed", Age: 40, CreatedAt: now.Add(-time.Hour)}
+	newer := &Example{ID: "a", Name: "Amy", Age: 20, CreatedAt: now}
+	twin := &Example{ID: "c", Name: "Amy", Age: 20, CreatedAt: now}
+
+	assert.True(t, DefaultExampleSort.Less(newer, older))
+	assert.True(t, ExampleSort{Field: SortByCreatedAt}.Less(older, newer))
+	assert.True(t, ExampleSort{Field: SortByName}.Less(newer, older))
+	assert.True(t, ExampleSort{Field: SortByAge, Descending: true}.Less(older, newer))
+
+	// Ties fall back to ID in either direction
+	assert.True(t, ExampleSort{Field: SortByName}.Less(newer, twin))
+	assert.True(t, ExampleSort{Field: SortByName, Descending: true}.Less(newer, twin))
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrValidation matches every *ValidationError with errors.Is
+var ErrValidation = errors.New("validation failed")
+
+// FieldError describes why a single field is invalid
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+// ValidationError lists every invalid field of an entity
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error joins the reasons of all invalid fields
+func (e *ValidationError) Error() string {
+	reasons := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		reasons[i] = field.Reason
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// add records that field is invalid
+func (e *ValidationError) add(field, reason string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Reason: reason})
+}
+
+// errOrNil returns e, or a nil error when no field was recorded
+func (e *ValidationError) errOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
@@ -4,17 +4,26 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strings"
 
 	"example-api-template/pkg/i18n"
 )
 
+// DocBaseURL is where the error codes are documented; when set, each AppError links to
+// its code's section as DocBaseURL#code
+var DocBaseURL string
+
 type AppError struct {
 	Message      string
 	Code         ErrorCode
+	MachineCode  string // Upper-case Code that clients can switch on, e.g. EXAMPLE_NOT_FOUND
+	DocURL       string // Documentation of Code, empty unless DocBaseURL is set
 	Details      interface{}
 	HTTPStatus   int
 	TemplateData map[string]interface{}
 	Err          error
+
+	stack []uintptr // Where an internal or database error was created, see StackTrace
 }
 
 // Error implements error interface
@@ -46,11 +55,14 @@ func (e *AppError) Localize(localizer *i18n.Localizer, lang string) *AppError {
 	message := localizer.LocalizeError(lang, string(e.Code), e.TemplateData)
 	return &AppError{
 		Code:         e.Code,
+		MachineCode:  e.MachineCode,
+		DocURL:       e.DocURL,
 		Err:          e.Err,
 		Details:      e.Details,
 		TemplateData: e.TemplateData,
 		HTTPStatus:   e.HTTPStatus,
 		Message:      message,
+		stack:        e.stack,
 	}
 }
 
@@ -60,29 +72,35 @@ func (e *AppError) LocalizeWithContext(localizer *i18n.Localizer, ctx context.Co
 	return e.Localize(localizer, lang)
 }
 
-// New creates a simple AppError
+// New creates a simple AppError, taking its HTTP status from codeToStatus
 func New(code ErrorCode, err error, details interface{}) *AppError {
 	if err == nil {
 		err = errors.New(string(code))
 	}
-
-	return &AppError{
-		Code:       code,
-		Err:        err,
-		Details:    details,
-		HTTPStatus: getDefaultHTTPStatus(code),
-	}
+	return newAppError(code, err, details, nil)
 }
 
 // NewWithTemplate creates AppError พร้อม template data
 func NewWithTemplate(code ErrorCode, err error, details interface{}, templateData map[string]interface{}) *AppError {
-	return &AppError{
+	return newAppError(code, err, details, templateData)
+}
+
+// newAppError builds an AppError for code. Internal and database errors record the stack
+// of the caller of New or NewWithTemplate.
+func newAppError(code ErrorCode, err error, details interface{}, templateData map[string]interface{}) *AppError {
+	appErr := &AppError{
 		Code:         code,
+		MachineCode:  machineCode(code),
+		DocURL:       docURL(code),
 		Err:          err,
 		Details:      details,
 		HTTPStatus:   getDefaultHTTPStatus(code),
 		TemplateData: templateData,
 	}
+	if stackCodes[code] {
+		appErr.stack = callers(2) // Skip newAppError and New or NewWithTemplate
+	}
+	return appErr
 }
 
 // NewLocalized สร้างและ localize ทันที
@@ -97,34 +115,65 @@ func NewLocalizedWithContext(localizer *i18n.Localizer, ctx context.Context, cod
 	return appErr.LocalizeWithContext(localizer, ctx)
 }
 
-// Mapping ErrorCode → HTTP Status
+// codeToStatus maps every ErrorCode to the HTTP status it is reported with.
+// Add new codes here; codes missing from the map are reported as 500.
+var codeToStatus = map[ErrorCode]int{
+	ErrorCodeExampleNotFound:      http.StatusNotFound,
+	ErrorCodeExampleAlreadyExists: http.StatusConflict,
+	ErrorCodeVersionConflict:      http.StatusConflict,
+	ErrorCodeInvalidID:            http.StatusBadRequest,
+	ErrorCodeInvalidEmail:         http.StatusBadRequest,
+	ErrorCodeInvalidAge:           http.StatusBadRequest,
+	ErrorCodeInvalidPhone:         http.StatusBadRequest,
+	ErrorCodeInvalidName:          http.StatusBadRequest,
+	ErrorCodeInvalidInput:         http.StatusBadRequest,
+
+	ErrorCodeBusinessLogicFail:      http.StatusUnprocessableEntity,
+	ErrorCodeCorporateEmailUnderage: http.StatusUnprocessableEntity,
+	ErrorCodeVIPDomainUnderage:      http.StatusUnprocessableEntity,
+	ErrorCodeProfanityDetected:      http.StatusUnprocessableEntity,
+
+	ErrorCodeDatabaseError:        http.StatusInternalServerError,
+	ErrorCodeExternalAPIError:     http.StatusBadGateway,
+	ErrorCodeValidationError:      http.StatusInternalServerError,
+	ErrorCodeInternalError:        http.StatusInternalServerError,
+	ErrorCodeUnauthorized:         http.StatusUnauthorized,
+	ErrorCodeForbidden:            http.StatusForbidden,
+	ErrorCodeBadRequest:           http.StatusBadRequest,
+	ErrorCodeMethodNotAllowed:     http.StatusMethodNotAllowed,
+	ErrorCodeUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	ErrorCodeTooManyRequests:      http.StatusTooManyRequests,
+	ErrorCodeServiceUnavailable:   http.StatusServiceUnavailable,
+	ErrorCodeRequestTimeout:       http.StatusGatewayTimeout,
+	ErrorCodeRequestCanceled:      http.StatusRequestTimeout,
+
+	ErrorCodeInvalidRequest:   http.StatusBadRequest,
+	ErrorCodeValidationFailed: http.StatusBadRequest,
+
+	ErrorCodeIdempotencyKeyInProgress: http.StatusConflict,
+	ErrorCodeIdempotencyKeyReused:     http.StatusUnprocessableEntity,
+
+	ErrorCodeExampleIDRequired:    http.StatusBadRequest,
+	ErrorCodeExampleEmailRequired: http.StatusBadRequest,
+}
+
+// getDefaultHTTPStatus returns the HTTP status of code, or 500 for an unknown code
 func getDefaultHTTPStatus(code ErrorCode) int {
-	switch code {
-	case ErrorCodeExampleNotFound:
-		return http.StatusNotFound
-	case ErrorCodeExampleAlreadyExists:
-		return http.StatusConflict
-	case ErrorCodeInvalidID, ErrorCodeInvalidEmail, ErrorCodeInvalidAge, ErrorCodeInvalidName, ErrorCodeInvalidInput, ErrorCodeBadRequest, ErrorCodeInvalidRequest, ErrorCodeValidationFailed:
-		return http.StatusBadRequest
-	case ErrorCodeBusinessLogicFail, ErrorCodeCorporateEmailUnderage, ErrorCodeVIPDomainUnderage, ErrorCodeProfanityDetected:
-		return http.StatusUnprocessableEntity
-	case ErrorCodeUnauthorized:
-		return http.StatusUnauthorized
-	case ErrorCodeForbidden:
-		return http.StatusForbidden
-	case ErrorCodeMethodNotAllowed:
-		return http.StatusMethodNotAllowed
-	case ErrorCodeUnsupportedMediaType:
-		return http.StatusUnsupportedMediaType
-	case ErrorCodeTooManyRequests:
-		return http.StatusTooManyRequests
-	case ErrorCodeServiceUnavailable:
-		return http.StatusServiceUnavailable
-	case ErrorCodeExternalAPIError:
-		return http.StatusBadGateway
-	case ErrorCodeDatabaseError, ErrorCodeInternalError, ErrorCodeValidationError:
-		return http.StatusInternalServerError
-	default:
-		return http.StatusInternalServerError
+	if status, ok := codeToStatus[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// machineCode returns the upper-case form of code sent to clients
+func machineCode(code ErrorCode) string {
+	return strings.ToUpper(string(code))
+}
+
+// docURL links to the documentation of code, or returns "" when DocBaseURL is unset
+func docURL(code ErrorCode) string {
+	if DocBaseURL == "" {
+		return ""
 	}
+	return DocBaseURL + "#" + string(code)
 }
@@ -0,0 +1,125 @@
+package errs
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"example-api-template/pkg/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeToStatus(t *testing.T) {
+	tests := map[ErrorCode]int{
+		ErrorCodeExampleNotFound:      http.StatusNotFound,
+		ErrorCodeExampleAlreadyExists: http.StatusConflict,
+		ErrorCodeVersionConflict:      http.StatusConflict,
+		ErrorCodeInvalidID:            http.StatusBadRequest,
+		ErrorCodeInvalidEmail:         http.StatusBadRequest,
+		ErrorCodeInvalidAge:           http.StatusBadRequest,
+		ErrorCodeInvalidPhone:         http.StatusBadRequest,
+		ErrorCodeInvalidName:          http.StatusBadRequest,
+		ErrorCodeInvalidInput:         http.StatusBadRequest,
+
+		ErrorCodeBusinessLogicFail:      http.StatusUnprocessableEntity,
+		ErrorCodeCorporateEmailUnderage: http.StatusUnprocessableEntity,
+		ErrorCodeVIPDomainUnderage:      http.StatusUnprocessableEntity,
+		ErrorCodeProfanityDetected:      http.StatusUnprocessableEntity,
+
+		ErrorCodeDatabaseError:        http.StatusInternalServerError,
+		ErrorCodeExternalAPIError:     http.StatusBadGateway,
+		ErrorCodeValidationError:      http.StatusInternalServerError,
+		ErrorCodeInternalError:        http.StatusInternalServerError,
+		ErrorCodeUnauthorized:         http.StatusUnauthorized,
+		ErrorCodeForbidden:            http.StatusForbidden,
+		ErrorCodeBadRequest:           http.StatusBadRequest,
+		ErrorCodeMethodNotAllowed:     http.StatusMethodNotAllowed,
+		ErrorCodeUnsupportedMediaType: http.StatusUnsupportedMediaType,
+		ErrorCodeTooManyRequests:      http.StatusTooManyRequests,
+		ErrorCodeServiceUnavailable:   http.StatusServiceUnavailable,
+		ErrorCodeRequestTimeout:       http.StatusGatewayTimeout,
+		ErrorCodeRequestCanceled:      http.StatusRequestTimeout,
+
+		ErrorCodeInvalidRequest:   http.StatusBadRequest,
+		ErrorCodeValidationFailed: http.StatusBadRequest,
+
+		ErrorCodeIdempotencyKeyInProgress: http.StatusConflict,
+		ErrorCodeIdempotencyKeyReused:     http.StatusUnprocessableEntity,
+
+		ErrorCodeExampleIDRequired:    http.StatusBadRequest,
+		ErrorCodeExampleEmailRequired: http.StatusBadRequest,
+	}
+
+	// Every code in the table is covered above
+	assert.Len(t, codeToStatus, len(tests))
+
+	for code, status := range tests {
+		t.Run(string(code), func(t *testing.T) {
+			appErr := New(code, nil, nil)
+			assert.Equal(t, status, appErr.HTTPStatus)
+			assert.Equal(t, status, appErr.GetHTTPStatus())
+		})
+	}
+
+	t.Run("unknown code defaults to 500", func(t *testing.T) {
+		appErr := New(ErrorCode("no_such_code"), nil, nil)
+		assert.Equal(t, http.StatusInternalServerError, appErr.HTTPStatus)
+		assert.Equal(t, http.StatusInternalServerError, (&AppError{Code: "no_such_code"}).GetHTTPStatus())
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("sets the machine code", func(t *testing.T) {
+		cause := errors.New("record not found")
+		appErr := New(ErrorCodeExampleNotFound, cause, nil)
+
+		assert.Equal(t, "EXAMPLE_NOT_FOUND", appErr.MachineCode)
+		assert.Empty(t, appErr.DocURL)
+		assert.ErrorIs(t, appErr, cause)
+	})
+
+	t.Run("links to the docs when configured", func(t *testing.T) {
+		DocBaseURL = "https://docs.example.com/errors"
+		t.Cleanup(func() { DocBaseURL = "" })
+
+		appErr := New(ErrorCodeVersionConflict, nil, nil)
+		assert.Equal(t, "https://docs.example.com/errors#version_conflict", appErr.DocURL)
+
+		// Localizing keeps the fields clients match on
+		localizer, err := i18n.NewLocalizer(&i18n.Config{
+			DefaultLanguage: "en",
+			Languages:       []string{"en"},
+			TranslationDir:  "../../translations",
+		})
+		require.NoError(t, err)
+
+		localized := appErr.Localize(localizer, "en")
+		assert.Equal(t, "VERSION_CONFLICT", localized.MachineCode)
+		assert.Equal(t, appErr.DocURL, localized.DocURL)
+	})
+}
+
+func TestStackTrace(t *testing.T) {
+	t.Run("internal errors record where they were created", func(t *testing.T) {
+		for _, code := range []ErrorCode{ErrorCodeInternalError, ErrorCodeDatabaseError} {
+			appErr := New(code, errors.New("boom"), nil)
+
+			stack := appErr.StackTrace()
+			require.NotEmpty(t, stack)
+			assert.Contains(t, stack[0], "TestStackTrace")
+			assert.Contains(t, stack[0], "apperr_test.go")
+		}
+	})
+
+	t.Run("template errors record their caller too", func(t *testing.T) {
+		appErr := NewWithTemplate(ErrorCodeDatabaseError, errors.New("boom"), nil, nil)
+		require.NotEmpty(t, appErr.StackTrace())
+		assert.Contains(t, appErr.StackTrace()[0], "TestStackTrace")
+	})
+
+	t.Run("expected errors do not", func(t *testing.T) {
+		assert.Empty(t, New(ErrorCodeExampleNotFound, nil, nil).StackTrace())
+	})
+}
@@ -1,41 +1,52 @@
 package errs
 
+// ErrorCode identifies an application error. Codes are stable: clients match on them,
+// so rename or remove one only with a new API version. The HTTP status of each code
+// is listed in codeToStatus.
 type ErrorCode string
 
 const (
 	// Domain errors
-	ErrorCodeExampleNotFound      ErrorCode = "example_not_found"
-	ErrorCodeExampleAlreadyExists ErrorCode = "example_already_exists"
-	ErrorCodeInvalidID            ErrorCode = "invalid_id"
-	ErrorCodeInvalidEmail         ErrorCode = "invalid_email"
-	ErrorCodeInvalidAge           ErrorCode = "invalid_age"
-	ErrorCodeInvalidName          ErrorCode = "invalid_name"
-	ErrorCodeInvalidInput         ErrorCode = "invalid_input"
+	ErrorCodeExampleNotFound      ErrorCode = "example_not_found"      // 404: no example has the given ID or email
+	ErrorCodeExampleAlreadyExists ErrorCode = "example_already_exists" // 409: another example already uses the email
+	ErrorCodeVersionConflict      ErrorCode = "version_conflict"       // 409: the example changed since it was read
+	ErrorCodeInvalidID            ErrorCode = "invalid_id"             // 400: the ID is empty or malformed
+	ErrorCodeInvalidEmail         ErrorCode = "invalid_email"          // 400: the email is not a valid address
+	ErrorCodeInvalidAge           ErrorCode = "invalid_age"            // 400: the age is out of range
+	ErrorCodeInvalidPhone         ErrorCode = "invalid_phone"          // 400: the phone number is not in E.164 format
+	ErrorCodeInvalidName          ErrorCode = "invalid_name"           // 400: the name is empty or too long
+	ErrorCodeInvalidInput         ErrorCode = "invalid_input"          // 400: the input breaks a domain rule
 
 	// Business rule errors
-	ErrorCodeBusinessLogicFail      ErrorCode = "business_logic_fail"
-	ErrorCodeCorporateEmailUnderage ErrorCode = "corporate_email_underage"
-	ErrorCodeVIPDomainUnderage      ErrorCode = "vip_domain_underage"
-	ErrorCodeProfanityDetected      ErrorCode = "profanity_detected"
+	ErrorCodeBusinessLogicFail      ErrorCode = "business_logic_fail"      // 422: a business rule rejected the request
+	ErrorCodeCorporateEmailUnderage ErrorCode = "corporate_email_underage" // 422: corporate emails need a minimum age
+	ErrorCodeVIPDomainUnderage      ErrorCode = "vip_domain_underage"      // 422: VIP domains need a minimum age
+	ErrorCodeProfanityDetected      ErrorCode = "profanity_detected"       // 422: the name contains blocked words
 
 	// System errors
-	ErrorCodeDatabaseError        ErrorCode = "database_error"
-	ErrorCodeExternalAPIError     ErrorCode = "external_api_error"
-	ErrorCodeValidationError      ErrorCode = "validation_error"
-	ErrorCodeInternalError        ErrorCode = "internal_error"
-	ErrorCodeUnauthorized         ErrorCode = "unauthorized"
-	ErrorCodeForbidden            ErrorCode = "forbidden"
-	ErrorCodeBadRequest           ErrorCode = "bad_request"
-	ErrorCodeMethodNotAllowed     ErrorCode = "method_not_allowed"
-	ErrorCodeUnsupportedMediaType ErrorCode = "unsupported_media_type"
-	ErrorCodeTooManyRequests      ErrorCode = "too_many_requests"
-	ErrorCodeServiceUnavailable   ErrorCode = "service_unavailable"
+	ErrorCodeDatabaseError        ErrorCode = "database_error"         // 500: the repository failed
+	ErrorCodeExternalAPIError     ErrorCode = "external_api_error"     // 502: the external API failed
+	ErrorCodeValidationError      ErrorCode = "validation_error"       // 500: validation itself could not run
+	ErrorCodeInternalError        ErrorCode = "internal_error"         // 500: an unexpected failure
+	ErrorCodeUnauthorized         ErrorCode = "unauthorized"           // 401: missing or invalid credentials
+	ErrorCodeForbidden            ErrorCode = "forbidden"              // 403: the caller may not do this
+	ErrorCodeBadRequest           ErrorCode = "bad_request"            // 400: the request is malformed
+	ErrorCodeMethodNotAllowed     ErrorCode = "method_not_allowed"     // 405: the route does not accept the method
+	ErrorCodeUnsupportedMediaType ErrorCode = "unsupported_media_type" // 415: the body's type or encoding is not supported
+	ErrorCodeTooManyRequests      ErrorCode = "too_many_requests"      // 429: the caller hit a rate limit
+	ErrorCodeServiceUnavailable   ErrorCode = "service_unavailable"    // 503: a dependency is down
+	ErrorCodeRequestTimeout       ErrorCode = "request_timeout"        // 504: the request ran past its deadline
+	ErrorCodeRequestCanceled      ErrorCode = "request_canceled"       // 408: the request was cancelled before it finished
 
 	// Common errors
-	ErrorCodeInvalidRequest   ErrorCode = "invalid_request"
-	ErrorCodeValidationFailed ErrorCode = "validation_failed"
+	ErrorCodeInvalidRequest   ErrorCode = "invalid_request"   // 400: the body could not be bound
+	ErrorCodeValidationFailed ErrorCode = "validation_failed" // 400: one or more fields failed validation
+
+	// Idempotency errors
+	ErrorCodeIdempotencyKeyInProgress ErrorCode = "idempotency_key_in_progress" // 409: a request with the same Idempotency-Key is still running
+	ErrorCodeIdempotencyKeyReused     ErrorCode = "idempotency_key_reused"      // 422: the Idempotency-Key was used with a different request
 
 	// Example errors
-	ErrorCodeExampleIDRequired    ErrorCode = "example_id_required"
-	ErrorCodeExampleEmailRequired ErrorCode = "example_email_required"
+	ErrorCodeExampleIDRequired    ErrorCode = "example_id_required"    // 400: the route is missing the example ID
+	ErrorCodeExampleEmailRequired ErrorCode = "example_email_required" // 400: the route is missing the email
 )
@@ -0,0 +1,40 @@
+package errs
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// maxStackDepth bounds the number of frames recorded for an error
+const maxStackDepth = 32
+
+// stackCodes are the codes of unexpected failures, which record where they were created
+var stackCodes = map[ErrorCode]bool{
+	ErrorCodeInternalError: true,
+	ErrorCodeDatabaseError: true,
+}
+
+// callers records the stack of the function skip frames above its caller
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+2, pcs) // Skip runtime.Callers and callers itself
+	return pcs[:n]
+}
+
+// StackTrace returns the frames where the error was created, innermost first, formatted as
+// "function file:line". It is empty for codes that do not record a stack.
+func (e *AppError) StackTrace() []string {
+	if len(e.stack) == 0 {
+		return nil
+	}
+
+	var trace []string
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		trace = append(trace, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		if !more {
+			return trace
+		}
+	}
+}
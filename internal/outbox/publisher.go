@@ -0,0 +1,156 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"example-api-template/internal/domain"
+	"example-api-template/internal/repository"
+	"example-api-template/internal/usecase"
+	"example-api-template/pkg/ctxkeys"
+
+	"go.uber.org/zap"
+)
+
+// claimLease is how long a publisher holds the events it claimed. A publisher that stops
+// mid-batch leaves its events to the others once the lease runs out.
+const claimLease = time.Minute
+
+// Publisher relays events recorded in the outbox to the message queue. An event is
+// marked published only after the broker accepted it, so delivery is at least once.
+type Publisher struct {
+	store     repository.OutboxRepository
+	publisher usecase.EventPublisher
+	logger    *zap.Logger
+	interval  time.Duration
+	batchSize int
+}
+
+// NewPublisher creates a publisher that polls store every interval and publishes
+// up to batchSize events per poll
+func NewPublisher(store repository.OutboxRepository, publisher usecase.EventPublisher, logger *zap.Logger, interval time.Duration, batchSize int) *Publisher {
+	return &Publisher{
+		store:     store,
+		publisher: publisher,
+		logger:    logger.With(zap.String("component", "outbox_publisher")),
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run publishes pending events every interval until ctx is done
+func (p *Publisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.PublishPending(ctx); err != nil && ctx.Err() == nil {
+			p.logger.Warn("Failed to publish outbox events, retrying on the next poll", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending claims one batch of unpublished events, so other replicas skip them,
+// publishes them oldest first and returns how many were published. It stops at the first
+// failure so events keep their order, releasing the rest for the next poll.
+func (p *Publisher) PublishPending(ctx context.Context) (int, error) {
+	events, err := p.store.ClaimOutboxEvents(ctx, p.batchSize, claimLease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	for i, event := range events {
+		if err := p.publish(ctx, event); err != nil {
+			p.release(ctx, events[i:])
+			return i, fmt.Errorf("failed to publish outbox event %s: %w", event.ID, err)
+		}
+		if err := p.store.MarkOutboxEventPublished(ctx, event.ID); err != nil {
+			p.release(ctx, events[i:])
+			return i, fmt.Errorf("failed to mark outbox event %s published: %w", event.ID, err)
+		}
+	}
+
+	return len(events), nil
+}
+
+// Replay publishes the already published events matching filter again, oldest first, for
+// consumers that need to reprocess them, and returns how many were replayed. Their
+// published_at is left as it is. With dryRun nothing is published and the number of events
+// that would be replayed is returned. It stops at the first failure.
+func (p *Publisher) Replay(ctx context.Context, filter repository.OutboxEventFilter, dryRun bool) (int, error) {
+	if dryRun {
+		count, err := p.store.CountPublishedOutboxEvents(ctx, filter)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count outbox events: %w", err)
+		}
+		return count, nil
+	}
+
+	replayed := 0
+	for {
+		// Replaying doesn't change which events match, so offsets stay stable between batches
+		events, err := p.store.PublishedOutboxEvents(ctx, filter, p.batchSize, replayed)
+		if err != nil {
+			return replayed, fmt.Errorf("failed to load outbox events: %w", err)
+		}
+
+		for _, event := range events {
+			if err := p.publish(ctx, event); err != nil {
+				return replayed, fmt.Errorf("failed to replay outbox event %s: %w", event.ID, err)
+			}
+			replayed++
+		}
+
+		if len(events) < p.batchSize {
+			p.logger.Info("Replayed outbox events", zap.Int("count", replayed))
+			return replayed, nil
+		}
+	}
+}
+
+// release drops the claim on events that weren't published. If that fails too they are
+// claimed again once the lease runs out.
+func (p *Publisher) release(ctx context.Context, events []*domain.OutboxEvent) {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	if err := p.store.ReleaseOutboxEvents(context.WithoutCancel(ctx), ids); err != nil {
+		p.logger.Warn("Failed to release outbox events", zap.Int("count", len(ids)), zap.Error(err))
+	}
+}
+
+// publish sends a single event under the outbox event's ID, so a relay and any replay of
+// it carry the same message ID and consumers can deduplicate them. An event that can never
+// be published, because its type is unknown or its payload is corrupt, is logged and skipped
+// rather than blocking the rest.
+func (p *Publisher) publish(ctx context.Context, event *domain.OutboxEvent) error {
+	ctx = ctxkeys.WithEventID(ctx, event.ID)
+
+	example, err := event.Example()
+	if err != nil {
+		p.logger.Error("Skipping undecodable outbox event", zap.String("event_id", event.ID), zap.Error(err))
+		return nil
+	}
+
+	switch event.Type {
+	case domain.EventTypeExampleCreated:
+		return p.publisher.PublishExampleCreated(ctx, &usecase.ExampleWithMetadata{Example: example})
+	case domain.EventTypeExampleUpdated:
+		return p.publisher.PublishExampleUpdated(ctx, &usecase.ExampleWithMetadata{Example: example})
+	case domain.EventTypeExampleDeleted:
+		return p.publisher.PublishExampleDeleted(ctx, example.ID, example.Email, example.Name)
+	default:
+		p.logger.Error("Skipping outbox event of unknown type",
+			zap.String("event_id", event.ID),
+			zap.String("type", event.Type))
+		return nil
+	}
+}
@@ -0,0 +1,295 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"example-api-template/internal/domain"
+	"example-api-template/internal/repository"
+	"example-api-template/internal/usecase"
+	"example-api-template/pkg/ctxkeys"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// recordingPublisher records published events, and the event IDs they were published
+// under, and fails while err is set
+type recordingPublisher struct {
+	events   []string
+	eventIDs []string
+	err      error
+}
+
+func (p *recordingPublisher) record(ctx context.Context, eventType, id string) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, eventType+" "+id)
+	p.eventIDs = append(p.eventIDs, ctxkeys.EventID(ctx))
+	return nil
+}
+
+func (p *recordingPublisher) PublishExampleCreated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
+	return p.record(ctx, domain.EventTypeExampleCreated, example.ID)
+}
+
+func (p *recordingPublisher) PublishExampleUpdated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
+	return p.record(ctx, domain.EventTypeExampleUpdated, example.ID)
+}
+
+func (p *recordingPublisher) PublishExampleDeleted(ctx context.Context, exampleID, email, name string) error {
+	return p.record(ctx, domain.EventTypeExampleDeleted, exampleID)
+}
+
+// newTestRepository returns a migrated repository that records outbox events
+func newTestRepository(t *testing.T) *repository.PostgreSQLExampleRepository {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// Every connection to ":memory:" opens a fresh database, so keep to one
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	repo := repository.NewPostgreSQLExampleRepository(db).WithOutbox()
+	require.NoError(t, repo.AutoMigrate())
+	return repo
+}
+
+func createExample(t *testing.T, repo *repository.PostgreSQLExampleRepository, email string) *domain.Example {
+	example, err := domain.NewExample(uuid.New().String(), "John Doe", email, 30)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), example))
+	return example
+}
+
+func TestPublisher_PublishPending(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("publishes in order and marks events published", func(t *testing.T) {
+		repo := newTestRepository(t)
+		example := createExample(t, repo, "john@example.com")
+		require.NoError(t, repo.Update(ctx, example))
+		require.NoError(t, repo.Delete(ctx, example.ID))
+
+		events := &recordingPublisher{}
+		publisher := NewPublisher(repo, events, zap.NewNop(), time.Second, 10)
+
+		published, err := publisher.PublishPending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, published)
+		assert.Equal(t, []string{
+			"example.created " + example.ID,
+			"example.updated " + example.ID,
+			"example.deleted " + example.ID,
+		}, events.events)
+
+		// Each is published under its outbox event ID
+		saved, err := repo.PublishedOutboxEvents(ctx, repository.OutboxEventFilter{}, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, saved, 3)
+		assert.Equal(t, []string{saved[0].ID, saved[1].ID, saved[2].ID}, events.eventIDs)
+
+		// Nothing is published twice
+		published, err = publisher.PublishPending(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, published)
+		assert.Len(t, events.events, 3)
+	})
+
+	t.Run("publishes at most a batch per poll", func(t *testing.T) {
+		repo := newTestRepository(t)
+		createExample(t, repo, "john@example.com")
+		createExample(t, repo, "jane@example.com")
+		createExample(t, repo, "alice@example.com")
+
+		publisher := NewPublisher(repo, &recordingPublisher{}, zap.NewNop(), time.Second, 2)
+
+		published, err := publisher.PublishPending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, published)
+
+		published, err = publisher.PublishPending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, published)
+	})
+
+	t.Run("keeps events when the broker fails", func(t *testing.T) {
+		repo := newTestRepository(t)
+		example := createExample(t, repo, "john@example.com")
+
+		events := &recordingPublisher{err: errors.New("broker down")}
+		publisher := NewPublisher(repo, events, zap.NewNop(), time.Second, 10)
+
+		published, err := publisher.PublishPending(ctx)
+		assert.Error(t, err)
+		assert.Zero(t, published)
+
+		// The event goes out once the broker recovers
+		events.err = nil
+		published, err = publisher.PublishPending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, published)
+		assert.Equal(t, []string{"example.created " + example.ID}, events.events)
+	})
+
+	t.Run("skips events another publisher claimed", func(t *testing.T) {
+		repo := newTestRepository(t)
+		createExample(t, repo, "john@example.com")
+		createExample(t, repo, "jane@example.com")
+
+		claimed, err := repo.ClaimOutboxEvents(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+
+		events := &recordingPublisher{}
+		publisher := NewPublisher(repo, events, zap.NewNop(), time.Second, 10)
+
+		published, err := publisher.PublishPending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, published)
+		assert.NotContains(t, events.eventIDs, claimed[0].ID)
+	})
+
+	t.Run("skips events that can never be published", func(t *testing.T) {
+		repo := newTestRepository(t)
+		require.NoError(t, repo.SaveOutboxEvent(ctx, &domain.OutboxEvent{
+			ID: uuid.New().String(), Type: domain.EventTypeExampleCreated, Payload: "not json", CreatedAt: time.Now(),
+		}))
+		require.NoError(t, repo.SaveOutboxEvent(ctx, &domain.OutboxEvent{
+			ID: uuid.New().String(), Type: "example.unknown", Payload: "{}", CreatedAt: time.Now(),
+		}))
+
+		events := &recordingPublisher{}
+		publisher := NewPublisher(repo, events, zap.NewNop(), time.Second, 10)
+
+		published, err := publisher.PublishPending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, published)
+		assert.Empty(t, events.events)
+
+		remaining, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, remaining)
+	})
+}
+
+func TestPublisher_Run(t *testing.T) {
+	repo := newTestRepository(t)
+	example := createExample(t, repo, "john@example.com")
+
+	events := &recordingPublisher{}
+	publisher := NewPublisher(repo, events, zap.NewNop(), 10*time.Millisecond, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		publisher.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		remaining, err := repo.UnpublishedOutboxEvents(context.Background(), 10)
+		return err == nil && len(remaining) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+	assert.Equal(t, []string{"example.created " + example.ID}, events.events)
+}
+
+func TestPublisher_Replay(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// newRepository returns a repository holding a published created event for each of
+	// three examples an hour apart, and an unpublished updated event
+	newRepository := func(t *testing.T) (*repository.PostgreSQLExampleRepository, []*domain.OutboxEvent) {
+		repo := newTestRepository(t)
+		var events []*domain.OutboxEvent
+		for i, email := range []string{"john@example.com", "jane@example.com", "alice@example.com"} {
+			example, err := domain.NewExample(uuid.New().String(), "John Doe", email, 30)
+			require.NoError(t, err)
+			event, err := domain.NewOutboxEvent(domain.EventTypeExampleCreated, example)
+			require.NoError(t, err)
+			event.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+			require.NoError(t, repo.SaveOutboxEvent(ctx, event))
+			require.NoError(t, repo.MarkOutboxEventPublished(ctx, event.ID))
+			events = append(events, event)
+		}
+
+		example, err := events[0].Example()
+		require.NoError(t, err)
+		pending, err := domain.NewOutboxEvent(domain.EventTypeExampleUpdated, example)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveOutboxEvent(ctx, pending))
+		return repo, events
+	}
+
+	exampleID := func(t *testing.T, event *domain.OutboxEvent) string {
+		example, err := event.Example()
+		require.NoError(t, err)
+		return example.ID
+	}
+
+	t.Run("dry run counts without publishing", func(t *testing.T) {
+		repo, _ := newRepository(t)
+		events := &recordingPublisher{}
+		publisher := NewPublisher(repo, events, zap.NewNop(), time.Second, 10)
+
+		count, err := publisher.Replay(ctx, repository.OutboxEventFilter{From: base.Add(time.Hour)}, true)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Empty(t, events.events)
+	})
+
+	t.Run("replays published events in the time range", func(t *testing.T) {
+		repo, saved := newRepository(t)
+		events := &recordingPublisher{}
+		// A batch smaller than the matches makes the replay page through them
+		publisher := NewPublisher(repo, events, zap.NewNop(), time.Second, 1)
+
+		count, err := publisher.Replay(ctx, repository.OutboxEventFilter{From: base, To: base.Add(2 * time.Hour)}, false)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []string{
+			"example.created " + exampleID(t, saved[0]),
+			"example.created " + exampleID(t, saved[1]),
+		}, events.events)
+
+		// The unpublished event is left to the poller
+		remaining, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		assert.Len(t, remaining, 1)
+	})
+
+	t.Run("replays events by ID", func(t *testing.T) {
+		repo, saved := newRepository(t)
+		events := &recordingPublisher{}
+		publisher := NewPublisher(repo, events, zap.NewNop(), time.Second, 10)
+
+		count, err := publisher.Replay(ctx, repository.OutboxEventFilter{IDs: []string{saved[2].ID}}, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, []string{"example.created " + exampleID(t, saved[2])}, events.events)
+		assert.Equal(t, []string{saved[2].ID}, events.eventIDs)
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		repo, _ := newRepository(t)
+		events := &recordingPublisher{err: errors.New("broker down")}
+		publisher := NewPublisher(repo, events, zap.NewNop(), time.Second, 10)
+
+		count, err := publisher.Replay(ctx, repository.OutboxEventFilter{}, false)
+		assert.ErrorIs(t, err, events.err)
+		assert.Zero(t, count)
+	})
+}
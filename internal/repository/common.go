@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -12,15 +13,66 @@ var (
 	ErrExampleAlreadyExists = errors.New("example already exists")
 	ErrDatabaseConnection   = errors.New("database connection error")
 	ErrQueryTimeout         = errors.New("query timeout")
+	ErrQueryDeadline        = errors.New("query deadline exceeded")
+	ErrQueryCanceled        = errors.New("query canceled")
 	ErrInvalidQuery         = errors.New("invalid query")
 	ErrTransactionFailed    = errors.New("transaction failed")
+	ErrVersionConflict      = errors.New("example was modified concurrently")
+	ErrOutboxEventNotFound  = errors.New("outbox event not found")
 )
 
+// primaryReadsKey marks a context whose reads must go to the primary database
+type primaryReadsKey struct{}
+
+// WithPrimaryReads returns ctx whose reads the database repository sends to the primary
+// instead of a read replica. Use it for reads that feed a write, such as the version checked
+// before an update, so replica lag can't make them stale.
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+// primaryReads reports whether ctx asks for reads from the primary
+func primaryReads(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadsKey{}).(bool)
+	return primary
+}
+
+// updatableColumns are the only columns UpdateFields writes. id and created_at never change,
+// version is incremented by every update, and any other column is unknown.
+var updatableColumns = map[string]bool{
+	ColumnName:      true,
+	ColumnEmail:     true,
+	ColumnPhone:     true,
+	ColumnAge:       true,
+	ColumnUpdatedAt: true,
+}
+
+// checkUpdateFields rejects an UpdateFields call without an ID or fields, or writing a column
+// that isn't updatable
+func checkUpdateFields(id string, fields map[string]interface{}) error {
+	if id == "" {
+		return fmt.Errorf("%w: id cannot be empty", ErrInvalidQuery)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: no fields to update", ErrInvalidQuery)
+	}
+	for column := range fields {
+		if !updatableColumns[column] {
+			return fmt.Errorf("%w: column %s cannot be updated", ErrInvalidQuery, column)
+		}
+	}
+	return nil
+}
+
 func handleError(err error) error {
 	if err == nil {
 		return nil
 	}
 
+	if ctxErr := contextError(err); ctxErr != nil {
+		return ctxErr
+	}
+
 	if isRecordNotFoundError(err) {
 		return ErrExampleNotFound
 	}
@@ -46,6 +98,10 @@ func handleErrorWithContext(err error, operation string, resourceID string) erro
 		return nil
 	}
 
+	if ctxErr := contextError(err); ctxErr != nil {
+		return ctxErr
+	}
+
 	if isRecordNotFoundError(err) {
 		return ErrExampleNotFound
 	}
@@ -66,11 +122,26 @@ func handleErrorWithContext(err error, operation string, resourceID string) erro
 	return fmt.Errorf("%s failed for resource %s: %w", operation, resourceID, err)
 }
 
+// contextError maps a query that stopped because its context was cancelled or ran past its
+// deadline, keeping the context error in the chain. It returns nil for any other error.
+func contextError(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrQueryDeadline, err)
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("%w: %w", ErrQueryCanceled, err)
+	}
+	return nil
+}
+
 func isRecordNotFoundError(err error) bool {
 	return err == gorm.ErrRecordNotFound
 }
 
 func isDuplicateKeyError(err error) bool {
+	if err == nil {
+		return false
+	}
 	errStr := err.Error()
 	return contains(errStr, "duplicate key value violates unique constraint") ||
 		contains(errStr, "UNIQUE constraint failed") ||
@@ -3,7 +3,10 @@ package repository
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"example-api-template/internal/domain"
 )
@@ -14,27 +17,74 @@ const (
 	ErrTemplateEmail = "%w: email %s"
 )
 
+// StreamPageSize is how many examples StreamAll holds in memory at a time
+const StreamPageSize = 500
+
 // ExampleRepository defines the interface for example data access
 type ExampleRepository interface {
 	Create(ctx context.Context, example *domain.Example) error
 	GetByID(ctx context.Context, id string) (*domain.Example, error)
+	GetByIDs(ctx context.Context, ids []string) ([]*domain.Example, error)
 	GetByEmail(ctx context.Context, email string) (*domain.Example, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	Update(ctx context.Context, example *domain.Example) error
+	UpdateFields(ctx context.Context, id string, expectedVersion int, fields map[string]interface{}) error
 	Delete(ctx context.Context, id string) error
-	List(ctx context.Context, limit, offset int) ([]*domain.Example, error)
+	DeleteByIDs(ctx context.Context, ids []string) ([]string, error)
+	List(ctx context.Context, limit, offset int, sort domain.ExampleSort) ([]*domain.Example, error)
+	ListFiltered(ctx context.Context, filter ExampleFilter, limit, offset int, sort domain.ExampleSort) ([]*domain.Example, error)
 	Count(ctx context.Context) (int, error)
+	CountFiltered(ctx context.Context, filter ExampleFilter) (int, error)
+	StreamAll(ctx context.Context, fn func(*domain.Example) error) error
+}
+
+// Transactor runs fn in a database transaction, committing if fn returns nil and rolling
+// back otherwise (implemented by PostgreSQLExampleRepository)
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ExampleRepository) error) error
+}
+
+// ExampleFilter selects examples changed since a point in time, e.g. for clients syncing
+// changes. Both bounds are inclusive; zero fields match every example.
+type ExampleFilter struct {
+	CreatedSince time.Time // Examples created at or after CreatedSince
+	UpdatedSince time.Time // Examples updated at or after UpdatedSince
+}
+
+// IsZero reports whether f matches every example
+func (f ExampleFilter) IsZero() bool {
+	return f.CreatedSince.IsZero() && f.UpdatedSince.IsZero()
+}
+
+// Matches reports whether example is selected by f
+func (f ExampleFilter) Matches(example *domain.Example) bool {
+	if !f.CreatedSince.IsZero() && example.CreatedAt.Before(f.CreatedSince) {
+		return false
+	}
+	if !f.UpdatedSince.IsZero() && example.UpdatedAt.Before(f.UpdatedSince) {
+		return false
+	}
+	return true
+}
+
+// searchableValues mirrors searchableColumns for the in-memory repository
+var searchableValues = map[string]func(*domain.Example) string{
+	SearchFieldName:  func(example *domain.Example) string { return example.Name },
+	SearchFieldEmail: func(example *domain.Example) string { return example.Email },
 }
 
 // InMemoryExampleRepository is an in-memory implementation of ExampleRepository
 type InMemoryExampleRepository struct {
 	data  map[string]*domain.Example
 	mutex sync.RWMutex
+	stats StatsConfig
 }
 
 // NewInMemoryExampleRepository creates a new in-memory example repository
-func NewInMemoryExampleRepository() *InMemoryExampleRepository {
+func NewInMemoryExampleRepository(opts ...Option) *InMemoryExampleRepository {
 	return &InMemoryExampleRepository{
-		data: make(map[string]*domain.Example),
+		data:  make(map[string]*domain.Example),
+		stats: newOptions(opts).stats,
 	}
 }
 
@@ -50,11 +100,15 @@ func (r *InMemoryExampleRepository) Create(ctx context.Context, example *domain.
 
 	// Check if example with same email already exists
 	for _, existing := range r.data {
-		if existing.Email == example.Email {
+		if strings.EqualFold(existing.Email, example.Email) {
 			return fmt.Errorf(ErrTemplateEmail, ErrExampleAlreadyExists, example.Email)
 		}
 	}
 
+	if example.Version == 0 {
+		example.Version = 1
+	}
+
 	// Create a copy to avoid external modifications
 	exampleCopy := *example
 	r.data[example.ID] = &exampleCopy
@@ -76,13 +130,35 @@ func (r *InMemoryExampleRepository) GetByID(ctx context.Context, id string) (*do
 	return &exampleCopy, nil
 }
 
-// GetByEmail retrieves an example by email
+// GetByIDs retrieves the examples with the given IDs in the order requested, skipping missing IDs
+func (r *InMemoryExampleRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Example, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	examples := make([]*domain.Example, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		example, exists := r.data[id]
+		if !exists || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		// Return a copy to avoid external modifications
+		exampleCopy := *example
+		examples = append(examples, &exampleCopy)
+	}
+
+	return examples, nil
+}
+
+// GetByEmail retrieves an example by email, ignoring case
 func (r *InMemoryExampleRepository) GetByEmail(ctx context.Context, email string) (*domain.Example, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
 	for _, example := range r.data {
-		if example.Email == email {
+		if strings.EqualFold(example.Email, email) {
 			// Return a copy to avoid external modifications
 			exampleCopy := *example
 			return &exampleCopy, nil
@@ -92,7 +168,21 @@ func (r *InMemoryExampleRepository) GetByEmail(ctx context.Context, email string
 	return nil, fmt.Errorf(ErrTemplateEmail, ErrExampleNotFound, email)
 }
 
-// Update updates an existing example
+// ExistsByEmail reports whether an example has the given email, ignoring case
+func (r *InMemoryExampleRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, example := range r.data {
+		if strings.EqualFold(example.Email, email) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Update updates an existing example if its stored version still matches example.Version,
+// then increments the version
 func (r *InMemoryExampleRepository) Update(ctx context.Context, example *domain.Example) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
@@ -103,21 +193,91 @@ func (r *InMemoryExampleRepository) Update(ctx context.Context, example *domain.
 		return fmt.Errorf("%w: id %s", ErrExampleNotFound, example.ID)
 	}
 
+	// Reject writes based on a stale read
+	if existing.Version != example.Version {
+		return fmt.Errorf("%w: id %s, version %d", ErrVersionConflict, example.ID, example.Version)
+	}
+
 	// Check if email is being changed and conflicts with another example
 	if existing.Email != example.Email {
 		for id, other := range r.data {
-			if id != example.ID && other.Email == example.Email {
+			if id != example.ID && strings.EqualFold(other.Email, example.Email) {
 				return fmt.Errorf(ErrTemplateEmail, ErrExampleAlreadyExists, example.Email)
 			}
 		}
 	}
 
+	example.Version++
+
 	// Create a copy to avoid external modifications
 	exampleCopy := *example
 	r.data[example.ID] = &exampleCopy
 	return nil
 }
 
+// UpdateFields sets only the given columns of an example if its stored version still matches
+// expectedVersion, and increments its version
+func (r *InMemoryExampleRepository) UpdateFields(ctx context.Context, id string, expectedVersion int, fields map[string]interface{}) error {
+	if err := checkUpdateFields(id, fields); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.data[id]
+	if !exists {
+		return fmt.Errorf(ErrTemplateID, ErrExampleNotFound, id)
+	}
+	if existing.Version != expectedVersion {
+		return fmt.Errorf("%w: id %s, version %d", ErrVersionConflict, id, expectedVersion)
+	}
+
+	// Apply to a copy so a bad field leaves the stored example unchanged
+	updated := *existing
+	updated.UpdatedAt = time.Now()
+	for column, value := range fields {
+		if err := setExampleField(&updated, column, value); err != nil {
+			return err
+		}
+	}
+
+	if updated.Email != existing.Email {
+		for otherID, other := range r.data {
+			if otherID != id && strings.EqualFold(other.Email, updated.Email) {
+				return fmt.Errorf(ErrTemplateEmail, ErrExampleAlreadyExists, updated.Email)
+			}
+		}
+	}
+
+	updated.Version++
+	r.data[id] = &updated
+	return nil
+}
+
+// setExampleField sets the field of example stored in column
+func setExampleField(example *domain.Example, column string, value interface{}) error {
+	var ok bool
+	switch column {
+	case ColumnName:
+		example.Name, ok = value.(string)
+	case ColumnEmail:
+		example.Email, ok = value.(string)
+	case ColumnPhone:
+		example.Phone, ok = value.(string)
+	case ColumnAge:
+		example.Age, ok = value.(int)
+	case ColumnUpdatedAt:
+		example.UpdatedAt, ok = value.(time.Time)
+	default:
+		return fmt.Errorf("%w: unknown column %s", ErrInvalidQuery, column)
+	}
+	if !ok {
+		return fmt.Errorf("%w: invalid value %v for column %s", ErrInvalidQuery, value, column)
+	}
+	return nil
+}
+
 // Delete removes an example by ID
 func (r *InMemoryExampleRepository) Delete(ctx context.Context, id string) error {
 	r.mutex.Lock()
@@ -131,17 +291,181 @@ func (r *InMemoryExampleRepository) Delete(ctx context.Context, id string) error
 	return nil
 }
 
-// List retrieves a paginated list of examples
-func (r *InMemoryExampleRepository) List(ctx context.Context, limit, offset int) ([]*domain.Example, error) {
+// DeleteByIDs removes the examples with the given IDs and returns the IDs it deleted;
+// IDs with no example are skipped
+func (r *InMemoryExampleRepository) DeleteByIDs(ctx context.Context, ids []string) ([]string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var deleted []string
+	for _, id := range ids {
+		if _, exists := r.data[id]; exists {
+			delete(r.data, id)
+			deleted = append(deleted, id)
+		}
+	}
+	return deleted, nil
+}
+
+// List retrieves a sorted, paginated list of examples
+func (r *InMemoryExampleRepository) List(ctx context.Context, limit, offset int, order domain.ExampleSort) ([]*domain.Example, error) {
+	return r.ListFiltered(ctx, ExampleFilter{}, limit, offset, order)
+}
+
+// ListFiltered retrieves a sorted, paginated list of the examples selected by filter
+func (r *InMemoryExampleRepository) ListFiltered(ctx context.Context, filter ExampleFilter, limit, offset int, order domain.ExampleSort) ([]*domain.Example, error) {
+	if !order.Valid() {
+		return nil, fmt.Errorf("%w: sort %q", ErrInvalidQuery, order.String())
+	}
+
+	return r.find(order, limit, offset, filter.Matches), nil
+}
+
+// Count returns the total number of examples
+func (r *InMemoryExampleRepository) Count(ctx context.Context) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.data), nil
+}
+
+// CountFiltered returns the number of examples selected by filter
+func (r *InMemoryExampleRepository) CountFiltered(ctx context.Context, filter ExampleFilter) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	count := 0
+	for _, example := range r.data {
+		if filter.Matches(example) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// StreamAll calls fn for every example in ID order, copying a page at a time so fn runs
+// without the lock held. Examples deleted while streaming are skipped; fn's error stops the stream.
+func (r *InMemoryExampleRepository) StreamAll(ctx context.Context, fn func(*domain.Example) error) error {
+	r.mutex.RLock()
+	ids := make([]string, 0, len(r.data))
+	for id := range r.data {
+		ids = append(ids, id)
+	}
+	r.mutex.RUnlock()
+	sort.Strings(ids)
+
+	for start := 0; start < len(ids); start += StreamPageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		end := min(start+StreamPageSize, len(ids))
+		r.mutex.RLock()
+		page := make([]domain.Example, 0, end-start)
+		for _, id := range ids[start:end] {
+			if example, exists := r.data[id]; exists {
+				page = append(page, *example)
+			}
+		}
+		r.mutex.RUnlock()
+
+		for i := range page {
+			if err := fn(&page[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ListByAge retrieves the newest examples first whose age is within [minAge, maxAge]
+func (r *InMemoryExampleRepository) ListByAge(ctx context.Context, minAge, maxAge, limit, offset int) ([]*domain.Example, error) {
+	return r.find(domain.DefaultExampleSort, limit, offset, func(example *domain.Example) bool {
+		return example.Age >= minAge && example.Age <= maxAge
+	}), nil
+}
+
+// Search searches for examples by name or email (case-insensitive partial match)
+func (r *InMemoryExampleRepository) Search(ctx context.Context, query string, limit, offset int) ([]*domain.Example, error) {
+	return r.SearchAdvanced(ctx, query, DefaultSearchFields, limit, offset)
+}
+
+// SearchAdvanced searches the given fields for query (case-insensitive partial match),
+// returning examples that match in any of them. No fields means DefaultSearchFields.
+func (r *InMemoryExampleRepository) SearchAdvanced(ctx context.Context, query string, fields []string, limit, offset int) ([]*domain.Example, error) {
+	if len(fields) == 0 {
+		fields = DefaultSearchFields
+	}
+	values := make([]func(*domain.Example) string, len(fields))
+	for i, field := range fields {
+		value, ok := searchableValues[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot search field %q", ErrInvalidQuery, field)
+		}
+		values[i] = value
+	}
+
+	query = strings.ToLower(query)
+	return r.find(domain.DefaultExampleSort, limit, offset, func(example *domain.Example) bool {
+		for _, value := range values {
+			if strings.Contains(strings.ToLower(value(example)), query) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// GetStats returns statistics about examples, counting those created within recentWindow
+// as recent activity
+func (r *InMemoryExampleRepository) GetStats(ctx context.Context, recentWindow time.Duration) (*RepositoryStats, error) {
+	since, err := recentSince(recentWindow)
+	if err != nil {
+		return nil, err
+	}
+
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	// Convert map to slice for pagination
+	stats := &RepositoryStats{
+		TotalCount: int64(len(r.data)),
+	}
+
+	totalAge := 0
+	countsByAge := make(map[int]int64)
+	for _, example := range r.data {
+		totalAge += example.Age
+		countsByAge[example.Age]++
+		if example.CreatedAt.After(since) {
+			stats.RecentActivity++
+		}
+	}
+	if len(r.data) > 0 {
+		stats.AverageAge = float64(totalAge) / float64(len(r.data))
+	}
+	stats.AgeDistribution = r.stats.ageDistribution(countsByAge)
+
+	return stats, nil
+}
+
+// find returns copies of the examples matching match, sorted by order and paginated
+func (r *InMemoryExampleRepository) find(order domain.ExampleSort, limit, offset int, match func(*domain.Example) bool) []*domain.Example {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	// Convert map to slice for sorting and pagination
 	examples := make([]*domain.Example, 0, len(r.data))
 	for _, example := range r.data {
+		if !match(example) {
+			continue
+		}
 		exampleCopy := *example
 		examples = append(examples, &exampleCopy)
 	}
+	sort.Slice(examples, func(i, j int) bool {
+		return order.Less(examples[i], examples[j])
+	})
 
 	// Apply pagination
 	start := offset
@@ -155,16 +479,8 @@ func (r *InMemoryExampleRepository) List(ctx context.Context, limit, offset int)
 	}
 
 	if start >= end {
-		return []*domain.Example{}, nil
+		return []*domain.Example{}
 	}
 
-	return examples[start:end], nil
-}
-
-// Count returns the total number of examples
-func (r *InMemoryExampleRepository) Count(ctx context.Context) (int, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-
-	return len(r.data), nil
+	return examples[start:end]
 }
@@ -0,0 +1,349 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"example-api-template/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// InMemoryRepositoryTestSuite mirrors the PostgreSQL suite's query assertions against the in-memory repository
+type InMemoryRepositoryTestSuite struct {
+	suite.Suite
+	repository *InMemoryExampleRepository
+	ctx        context.Context
+}
+
+// SetupTest runs before each test
+func (suite *InMemoryRepositoryTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.repository = NewInMemoryExampleRepository()
+}
+
+// TestExistsByEmail tests the ExistsByEmail method
+func (suite *InMemoryRepositoryTestSuite) TestExistsByEmail() {
+	exists, err := suite.repository.ExistsByEmail(suite.ctx, "test@example.com")
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), exists)
+
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, suite.createValidExample()))
+
+	exists, err = suite.repository.ExistsByEmail(suite.ctx, "test@example.com")
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), exists)
+}
+
+// TestEmailIgnoresCase tests that emails differing only in case are the same email
+func (suite *InMemoryRepositoryTestSuite) TestEmailIgnoresCase() {
+	example := suite.createValidExample()
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+
+	mixedCase := suite.createValidExample()
+	mixedCase.Email = "Test@Example.COM"
+	assert.ErrorIs(suite.T(), suite.repository.Create(suite.ctx, mixedCase), ErrExampleAlreadyExists)
+
+	retrieved, err := suite.repository.GetByEmail(suite.ctx, "TEST@example.com")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), example.ID, retrieved.ID)
+
+	exists, err := suite.repository.ExistsByEmail(suite.ctx, "Test@Example.Com")
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), exists)
+}
+
+// TestUpdateFields tests that UpdateFields sets only the given fields
+func (suite *InMemoryRepositoryTestSuite) TestUpdateFields() {
+	example := suite.createValidExample()
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+
+	require.NoError(suite.T(), suite.repository.UpdateFields(suite.ctx, example.ID, 1, map[string]interface{}{ColumnAge: 40}))
+
+	retrieved, err := suite.repository.GetByID(suite.ctx, example.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 40, retrieved.Age)
+	assert.Equal(suite.T(), example.Name, retrieved.Name)
+	assert.Equal(suite.T(), example.Email, retrieved.Email)
+	assert.Equal(suite.T(), 2, retrieved.Version)
+
+	other := suite.createValidExample()
+	other.Email = "other@example.com"
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, other))
+	err = suite.repository.UpdateFields(suite.ctx, other.ID, 1, map[string]interface{}{ColumnEmail: example.Email})
+	assert.ErrorIs(suite.T(), err, ErrExampleAlreadyExists)
+
+	err = suite.repository.UpdateFields(suite.ctx, example.ID, 2, map[string]interface{}{ColumnAge: "forty"})
+	assert.ErrorIs(suite.T(), err, ErrInvalidQuery)
+	assert.ErrorIs(suite.T(), suite.repository.UpdateFields(suite.ctx, example.ID, 2, map[string]interface{}{"created_at": time.Now()}), ErrInvalidQuery)
+	assert.ErrorIs(suite.T(), suite.repository.UpdateFields(suite.ctx, "missing", 1, map[string]interface{}{ColumnAge: 1}), ErrExampleNotFound)
+
+	// A write based on a stale read is rejected
+	err = suite.repository.UpdateFields(suite.ctx, example.ID, 1, map[string]interface{}{ColumnAge: 50})
+	assert.ErrorIs(suite.T(), err, ErrVersionConflict)
+	retrieved, err = suite.repository.GetByID(suite.ctx, example.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 40, retrieved.Age)
+}
+
+// TestDeleteByIDs tests that DeleteByIDs removes the existing examples and skips missing IDs
+func (suite *InMemoryRepositoryTestSuite) TestDeleteByIDs() {
+	var ids []string
+	for i := 0; i < 3; i++ {
+		example := suite.createValidExample()
+		example.Email = fmt.Sprintf("test%d@example.com", i)
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+		ids = append(ids, example.ID)
+	}
+
+	deleted, err := suite.repository.DeleteByIDs(suite.ctx, []string{ids[0], "missing", ids[1]})
+	require.NoError(suite.T(), err)
+	assert.ElementsMatch(suite.T(), []string{ids[0], ids[1]}, deleted)
+
+	_, err = suite.repository.GetByID(suite.ctx, ids[0])
+	assert.ErrorIs(suite.T(), err, ErrExampleNotFound)
+	count, err := suite.repository.Count(suite.ctx)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, count)
+
+	deleted, err = suite.repository.DeleteByIDs(suite.ctx, []string{ids[0]})
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), deleted)
+
+	deleted, err = suite.repository.DeleteByIDs(suite.ctx, nil)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), deleted)
+}
+
+// TestListFiltered tests that the since filters keep examples at or after the bound
+func (suite *InMemoryRepositoryTestSuite) TestListFiltered() {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, name := range []string{"Old", "Boundary", "New"} {
+		example := suite.createValidExample()
+		example.Name = name
+		example.Email = fmt.Sprintf("user%d@example.com", i)
+		example.CreatedAt = base.Add(time.Duration(i-1) * time.Hour)
+		example.UpdatedAt = base.Add(time.Duration(1-i) * time.Hour)
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+	}
+
+	examples, err := suite.repository.ListFiltered(suite.ctx, ExampleFilter{CreatedSince: base}, 10, 0, domain.DefaultExampleSort)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), examples, 2)
+	assert.Equal(suite.T(), "New", examples[0].Name)
+	assert.Equal(suite.T(), "Boundary", examples[1].Name)
+
+	count, err := suite.repository.CountFiltered(suite.ctx, ExampleFilter{CreatedSince: base, UpdatedSince: base})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, count)
+
+	count, err = suite.repository.CountFiltered(suite.ctx, ExampleFilter{UpdatedSince: base.Add(time.Nanosecond)})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, count)
+}
+
+// TestListByAge tests the ListByAge method
+func (suite *InMemoryRepositoryTestSuite) TestListByAge() {
+	// Create examples with different ages
+	ages := []int{20, 25, 30, 35, 40}
+	for i, age := range ages {
+		example := suite.createValidExample()
+		example.Email = fmt.Sprintf("test%d@example.com", i)
+		example.Age = age
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+	}
+
+	// Test age range filter
+	examples, err := suite.repository.ListByAge(suite.ctx, 25, 35, 10, 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), examples, 3) // Ages 25, 30, 35
+
+	for _, example := range examples {
+		assert.GreaterOrEqual(suite.T(), example.Age, 25)
+		assert.LessOrEqual(suite.T(), example.Age, 35)
+	}
+
+	// Pagination applies after filtering
+	examples, err = suite.repository.ListByAge(suite.ctx, 25, 35, 2, 2)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), examples, 1)
+}
+
+// TestSearch tests the Search method
+func (suite *InMemoryRepositoryTestSuite) TestSearch() {
+	// Create examples with different names
+	names := []string{"John Doe", "Jane Smith", "John Johnson", "Alice Cooper"}
+	for i, name := range names {
+		example := suite.createValidExample()
+		example.Email = fmt.Sprintf("test%d@example.com", i)
+		example.Name = name
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+	}
+
+	// Test search by partial name
+	examples, err := suite.repository.Search(suite.ctx, "john", 10, 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), examples, 2) // John Doe, John Johnson
+
+	// Test case-insensitive search
+	examples, err = suite.repository.Search(suite.ctx, "JOHN", 10, 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), examples, 2)
+
+	// Test search with no results
+	examples, err = suite.repository.Search(suite.ctx, "nonexistent", 10, 0)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), examples)
+}
+
+// TestSearchByEmail tests that Search also matches on email
+func (suite *InMemoryRepositoryTestSuite) TestSearchByEmail() {
+	byName := suite.createValidExample()
+	byName.Name = "Acme Fan"
+	byName.Email = "fan@example.com"
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, byName))
+
+	byEmail := suite.createValidExample()
+	byEmail.Name = "Jane Smith"
+	byEmail.Email = "jane@ACME.io"
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, byEmail))
+
+	// Only the email contains "acme.io"
+	examples, err := suite.repository.Search(suite.ctx, "acme.io", 10, 0)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), examples, 1)
+	assert.Equal(suite.T(), byEmail.ID, examples[0].ID)
+
+	// "acme" matches one by name and the other by email
+	examples, err = suite.repository.Search(suite.ctx, "acme", 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), examples, 2)
+}
+
+// TestSearchAdvanced tests searching only the chosen fields
+func (suite *InMemoryRepositoryTestSuite) TestSearchAdvanced() {
+	example := suite.createValidExample()
+	example.Name = "Jane Smith"
+	example.Email = "jane@acme.io"
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+
+	examples, err := suite.repository.SearchAdvanced(suite.ctx, "acme", []string{SearchFieldEmail}, 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), examples, 1)
+
+	examples, err = suite.repository.SearchAdvanced(suite.ctx, "acme", []string{SearchFieldName}, 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), examples)
+
+	// No fields searches the defaults
+	examples, err = suite.repository.SearchAdvanced(suite.ctx, "ACME", nil, 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), examples, 1)
+
+	// Fields outside the whitelist are rejected
+	_, err = suite.repository.SearchAdvanced(suite.ctx, "acme", []string{"name) OR 1=1 --"}, 10, 0)
+	assert.ErrorIs(suite.T(), err, ErrInvalidQuery)
+}
+
+// TestGetStats tests the GetStats method
+func (suite *InMemoryRepositoryTestSuite) TestGetStats() {
+	// Test empty stats
+	stats, err := suite.repository.GetStats(suite.ctx, 0)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), stats.TotalCount)
+	assert.Equal(suite.T(), float64(0), stats.AverageAge)
+
+	// Create examples with different ages
+	ages := []int{17, 25, 35, 55, 70}
+	for i, age := range ages {
+		example := suite.createValidExample()
+		example.Email = fmt.Sprintf("test%d@example.com", i)
+		example.Age = age
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+	}
+
+	// One example is older than the 24 hour activity window
+	old := suite.createValidExample()
+	old.Email = "old@example.com"
+	old.Age = 30
+	old.CreatedAt = time.Now().Add(-48 * time.Hour)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, old))
+
+	// Test stats
+	stats, err = suite.repository.GetStats(suite.ctx, 0)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(6), stats.TotalCount)
+	assert.InDelta(suite.T(), 38.666, stats.AverageAge, 0.001) // (17+25+35+55+70+30)/6
+	assert.Equal(suite.T(), map[string]int64{
+		"under_18": 1,
+		"18_29":    1,
+		"30_49":    2,
+		"50_64":    1,
+		"65_plus":  1,
+	}, stats.AgeDistribution)
+	assert.Equal(suite.T(), int64(5), stats.RecentActivity)
+}
+
+// TestGetStatsRecentWindow tests that recent activity counts examples created within the window
+func (suite *InMemoryRepositoryTestSuite) TestGetStatsRecentWindow() {
+	for i, age := range []time.Duration{time.Hour, 30 * time.Hour, 60 * time.Hour, 100 * 24 * time.Hour} {
+		example := suite.createValidExample()
+		example.Email = fmt.Sprintf("test%d@example.com", i)
+		example.CreatedAt = time.Now().Add(-age)
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+	}
+
+	for _, tc := range []struct {
+		window time.Duration
+		recent int64
+	}{
+		{window: 0, recent: 1}, // DefaultRecentWindow
+		{window: 48 * time.Hour, recent: 2},
+		{window: 72 * time.Hour, recent: 3},
+		{window: 365 * 24 * time.Hour, recent: 3}, // Capped at MaxRecentWindow
+	} {
+		stats, err := suite.repository.GetStats(suite.ctx, tc.window)
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), tc.recent, stats.RecentActivity, "window %s", tc.window)
+		assert.Equal(suite.T(), int64(4), stats.TotalCount)
+	}
+
+	_, err := suite.repository.GetStats(suite.ctx, -time.Hour)
+	assert.ErrorIs(suite.T(), err, ErrInvalidQuery)
+}
+
+// TestGetStatsCustomAgeBuckets tests that the age distribution uses the configured buckets
+func (suite *InMemoryRepositoryTestSuite) TestGetStatsCustomAgeBuckets() {
+	repo := NewInMemoryExampleRepository(WithStatsConfig(customStatsConfig()))
+	for i, age := range []int{5, 12, 13, 19, 20, 64, 100} {
+		example := suite.createValidExample()
+		example.Email = fmt.Sprintf("test%d@example.com", i)
+		example.Age = age
+		require.NoError(suite.T(), repo.Create(suite.ctx, example))
+	}
+
+	stats, err := repo.GetStats(suite.ctx, 0)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), customStatsDistribution(), stats.AgeDistribution)
+	assert.Equal(suite.T(), int64(7), stats.TotalCount)
+}
+
+// createValidExample creates a valid example with a unique ID
+func (suite *InMemoryRepositoryTestSuite) createValidExample() *domain.Example {
+	example, _ := domain.NewExample(
+		uuid.New().String(),
+		"Test User",
+		"test@example.com",
+		25,
+	)
+	return example
+}
+
+func TestInMemoryRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(InMemoryRepositoryTestSuite))
+}
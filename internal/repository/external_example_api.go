@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"sync"
 	"time"
 )
 
@@ -34,35 +38,108 @@ type ExternalExampleAPI interface {
 
 	// NotifyExampleCreated sends notification about new example creation
 	NotifyExampleCreated(ctx context.Context, exampleID, email string) error
+
+	// Ping checks that the external API is reachable
+	Ping(ctx context.Context) error
+}
+
+// Mock method names accepted by SetMethodFailure
+const (
+	MockMethodGetExampleData       = "GetExampleData"
+	MockMethodValidateExample      = "ValidateExample"
+	MockMethodEnrichExample        = "EnrichExample"
+	MockMethodNotifyExampleCreated = "NotifyExampleCreated"
+	MockMethodPing                 = "Ping"
+)
+
+// mockMethods lists the methods whose failures can be overridden
+var mockMethods = map[string]bool{
+	MockMethodGetExampleData:       true,
+	MockMethodValidateExample:      true,
+	MockMethodEnrichExample:        true,
+	MockMethodNotifyExampleCreated: true,
+	MockMethodPing:                 true,
 }
 
-// MockExternalExampleAPI is a mock implementation for testing and development
+// MockFaults describes the delay and failures the mock external API simulates
+type MockFaults struct {
+	ShouldFail     bool            // Fail every call
+	Delay          time.Duration   // Wait before answering each call
+	FailureRate    float64         // Probability in [0, 1] that a call fails when ShouldFail is off
+	MethodFailures map[string]bool // Per-method overrides: true always fails, false never does
+}
+
+// Validate checks the failure rate and method names
+func (f MockFaults) Validate() error {
+	if f.FailureRate < 0 || f.FailureRate > 1 {
+		return fmt.Errorf("failure rate %v is outside [0, 1]", f.FailureRate)
+	}
+	if f.Delay < 0 {
+		return fmt.Errorf("delay %s is negative", f.Delay)
+	}
+	for method := range f.MethodFailures {
+		if !mockMethods[method] {
+			return fmt.Errorf("unknown mock method %q", method)
+		}
+	}
+	return nil
+}
+
+// MockExternalExampleAPI is a mock implementation for testing and development. Its faults can
+// be changed while it is in use, e.g. for resilience testing.
 type MockExternalExampleAPI struct {
-	shouldFail bool
-	delay      time.Duration
+	mu     sync.RWMutex
+	faults MockFaults
 }
 
 // NewMockExternalExampleAPI creates a new mock external API
 func NewMockExternalExampleAPI(shouldFail bool, delay time.Duration) *MockExternalExampleAPI {
 	return &MockExternalExampleAPI{
-		shouldFail: shouldFail,
-		delay:      delay,
+		faults: MockFaults{
+			ShouldFail:     shouldFail,
+			Delay:          delay,
+			MethodFailures: make(map[string]bool),
+		},
 	}
 }
 
-// GetExampleData returns mock external data
-func (m *MockExternalExampleAPI) GetExampleData(ctx context.Context, exampleID string) (*ExternalExampleData, error) {
+// simulate waits for the configured delay and reports whether method should fail
+func (m *MockExternalExampleAPI) simulate(ctx context.Context, method string) error {
+	m.mu.RLock()
+	delay := m.faults.Delay
+	fail := m.fails(method)
+	m.mu.RUnlock()
+
 	// Simulate delay
-	if m.delay > 0 {
+	if delay > 0 {
 		select {
-		case <-time.After(m.delay):
+		case <-time.After(delay):
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return ctx.Err()
 		}
 	}
 
-	if m.shouldFail {
-		return nil, ErrExternalAPIUnavailable
+	if fail {
+		return ErrExternalAPIUnavailable
+	}
+	return nil
+}
+
+// fails decides whether a call to method fails. Callers must hold m.mu.
+func (m *MockExternalExampleAPI) fails(method string) bool {
+	if fail, ok := m.faults.MethodFailures[method]; ok {
+		return fail
+	}
+	if m.faults.ShouldFail {
+		return true
+	}
+	return m.faults.FailureRate > 0 && rand.Float64() < m.faults.FailureRate
+}
+
+// GetExampleData returns mock external data
+func (m *MockExternalExampleAPI) GetExampleData(ctx context.Context, exampleID string) (*ExternalExampleData, error) {
+	if err := m.simulate(ctx, MockMethodGetExampleData); err != nil {
+		return nil, err
 	}
 
 	return &ExternalExampleData{
@@ -79,17 +156,8 @@ func (m *MockExternalExampleAPI) GetExampleData(ctx context.Context, exampleID s
 
 // ValidateExample validates example data against mock rules
 func (m *MockExternalExampleAPI) ValidateExample(ctx context.Context, name, email string, age int) (bool, error) {
-	// Simulate delay
-	if m.delay > 0 {
-		select {
-		case <-time.After(m.delay):
-		case <-ctx.Done():
-			return false, ctx.Err()
-		}
-	}
-
-	if m.shouldFail {
-		return false, ErrExternalAPIUnavailable
+	if err := m.simulate(ctx, MockMethodValidateExample); err != nil {
+		return false, err
 	}
 
 	// Mock validation rules
@@ -108,17 +176,8 @@ func (m *MockExternalExampleAPI) ValidateExample(ctx context.Context, name, emai
 
 // EnrichExample returns mock enrichment data
 func (m *MockExternalExampleAPI) EnrichExample(ctx context.Context, exampleID string) (map[string]interface{}, error) {
-	// Simulate delay
-	if m.delay > 0 {
-		select {
-		case <-time.After(m.delay):
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		}
-	}
-
-	if m.shouldFail {
-		return nil, ErrExternalAPIUnavailable
+	if err := m.simulate(ctx, MockMethodEnrichExample); err != nil {
+		return nil, err
 	}
 
 	return map[string]interface{}{
@@ -138,29 +197,76 @@ func (m *MockExternalExampleAPI) EnrichExample(ctx context.Context, exampleID st
 
 // NotifyExampleCreated sends mock notification
 func (m *MockExternalExampleAPI) NotifyExampleCreated(ctx context.Context, exampleID, email string) error {
-	// Simulate delay
-	if m.delay > 0 {
-		select {
-		case <-time.After(m.delay):
-		case <-ctx.Done():
-			return ctx.Err()
-		}
-	}
-
-	if m.shouldFail {
-		return ErrExternalAPIUnavailable
+	if err := m.simulate(ctx, MockMethodNotifyExampleCreated); err != nil {
+		return err
 	}
 
 	// Mock notification logic - in real implementation this would call external service
 	return nil
 }
 
+// Ping reports the mock as reachable unless it is configured to fail
+func (m *MockExternalExampleAPI) Ping(ctx context.Context) error {
+	return m.simulate(ctx, MockMethodPing)
+}
+
 // SetShouldFail configures the mock to simulate failures
 func (m *MockExternalExampleAPI) SetShouldFail(shouldFail bool) {
-	m.shouldFail = shouldFail
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.faults.ShouldFail = shouldFail
 }
 
 // SetDelay configures the mock to simulate network delays
 func (m *MockExternalExampleAPI) SetDelay(delay time.Duration) {
-	m.delay = delay
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.faults.Delay = delay
+}
+
+// SetFailureRate makes calls fail at random with the given probability, clamped to [0, 1]
+func (m *MockExternalExampleAPI) SetFailureRate(rate float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.faults.FailureRate = math.Min(math.Max(rate, 0), 1)
+}
+
+// SetMethodFailure makes every call to method fail, or none when shouldFail is false,
+// regardless of ShouldFail and FailureRate
+func (m *MockExternalExampleAPI) SetMethodFailure(method string, shouldFail bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.faults.MethodFailures[method] = shouldFail
+}
+
+// ClearMethodFailures removes every per-method override
+func (m *MockExternalExampleAPI) ClearMethodFailures() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.faults.MethodFailures = make(map[string]bool)
+}
+
+// Faults returns a copy of the current faults
+func (m *MockExternalExampleAPI) Faults() MockFaults {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	faults := m.faults
+	faults.MethodFailures = maps.Clone(m.faults.MethodFailures)
+	return faults
+}
+
+// SetFaults replaces every fault at once
+func (m *MockExternalExampleAPI) SetFaults(faults MockFaults) error {
+	if err := faults.Validate(); err != nil {
+		return err
+	}
+	faults.MethodFailures = maps.Clone(faults.MethodFailures)
+	if faults.MethodFailures == nil {
+		faults.MethodFailures = make(map[string]bool)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.faults = faults
+	return nil
 }
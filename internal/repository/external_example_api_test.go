@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockExternalExampleAPIFailureRate(t *testing.T) {
+	const calls = 1000
+
+	api := NewMockExternalExampleAPI(false, 0)
+	api.SetFailureRate(0.5)
+
+	failures := 0
+	for i := 0; i < calls; i++ {
+		if err := api.Ping(context.Background()); err != nil {
+			assert.ErrorIs(t, err, ErrExternalAPIUnavailable)
+			failures++
+		}
+	}
+
+	// Binomial(1000, 0.5) falls outside 400-600 with a probability far below 1e-9
+	assert.Greater(t, failures, 400)
+	assert.Less(t, failures, 600)
+
+	api.SetFailureRate(0)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, api.Ping(context.Background()))
+	}
+
+	// Rates outside [0, 1] are clamped
+	api.SetFailureRate(7)
+	assert.Equal(t, 1.0, api.Faults().FailureRate)
+	assert.Error(t, api.Ping(context.Background()))
+}
+
+func TestMockExternalExampleAPIToggles(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should fail can be flipped", func(t *testing.T) {
+		api := NewMockExternalExampleAPI(false, 0)
+		require.NoError(t, api.Ping(ctx))
+
+		api.SetShouldFail(true)
+		assert.ErrorIs(t, api.Ping(ctx), ErrExternalAPIUnavailable)
+		_, err := api.GetExampleData(ctx, "example-1")
+		assert.ErrorIs(t, err, ErrExternalAPIUnavailable)
+
+		api.SetShouldFail(false)
+		assert.NoError(t, api.Ping(ctx))
+	})
+
+	t.Run("delay respects the context", func(t *testing.T) {
+		api := NewMockExternalExampleAPI(false, 0)
+		api.SetDelay(time.Hour)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, api.Ping(timeoutCtx), context.DeadlineExceeded)
+	})
+
+	t.Run("method overrides win over the global settings", func(t *testing.T) {
+		api := NewMockExternalExampleAPI(true, 0)
+		api.SetMethodFailure(MockMethodPing, false)
+		api.SetMethodFailure(MockMethodNotifyExampleCreated, true)
+
+		assert.NoError(t, api.Ping(ctx))
+		_, err := api.EnrichExample(ctx, "example-1")
+		assert.ErrorIs(t, err, ErrExternalAPIUnavailable)
+
+		api.SetShouldFail(false)
+		assert.ErrorIs(t, api.NotifyExampleCreated(ctx, "example-1", "john@example.com"), ErrExternalAPIUnavailable)
+		valid, err := api.ValidateExample(ctx, "John Doe", "john@example.com", 30)
+		require.NoError(t, err)
+		assert.True(t, valid)
+
+		api.ClearMethodFailures()
+		assert.NoError(t, api.NotifyExampleCreated(ctx, "example-1", "john@example.com"))
+	})
+
+	t.Run("faults are replaced at once", func(t *testing.T) {
+		api := NewMockExternalExampleAPI(false, 0)
+		require.NoError(t, api.SetFaults(MockFaults{
+			FailureRate:    0.25,
+			Delay:          time.Millisecond,
+			MethodFailures: map[string]bool{MockMethodPing: true},
+		}))
+
+		faults := api.Faults()
+		assert.Equal(t, 0.25, faults.FailureRate)
+		assert.Equal(t, time.Millisecond, faults.Delay)
+		assert.Equal(t, map[string]bool{MockMethodPing: true}, faults.MethodFailures)
+		assert.Error(t, api.Ping(ctx))
+
+		assert.Error(t, api.SetFaults(MockFaults{FailureRate: 1.5}))
+		assert.Error(t, api.SetFaults(MockFaults{Delay: -time.Second}))
+		assert.Error(t, api.SetFaults(MockFaults{MethodFailures: map[string]bool{"Unknown": true}}))
+		assert.Equal(t, faults, api.Faults())
+	})
+
+	t.Run("toggling while calls are running is safe", func(t *testing.T) {
+		api := NewMockExternalExampleAPI(false, 0)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					_ = api.Ping(ctx)
+				}
+			}()
+		}
+		for i := 0; i < 100; i++ {
+			api.SetShouldFail(i%2 == 0)
+			api.SetFailureRate(float64(i%10) / 10)
+			api.SetMethodFailure(MockMethodPing, i%3 == 0)
+		}
+		wg.Wait()
+	})
+}
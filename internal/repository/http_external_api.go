@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"example-api-template/internal/config"
+)
+
+// HTTPExternalExampleAPI implements ExternalExampleAPI against a JSON HTTP API:
+//
+//	GET  {base}/examples/{id}/data          -> ExternalExampleData
+//	GET  {base}/examples/{id}/enrichment    -> object
+//	POST {base}/examples/validate           {name, email, age} -> {valid}
+//	POST {base}/examples/{id}/notifications {example_id, email, event}
+//	GET  {base}/health
+type HTTPExternalExampleAPI struct {
+	baseURL string
+	apiKey  string
+	headers map[string]string
+	client  *http.Client
+}
+
+// validateRequest is the body sent to the validation endpoint
+type validateRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Age   int    `json:"age"`
+}
+
+// validateResponse is the body returned by the validation endpoint
+type validateResponse struct {
+	Valid bool `json:"valid"`
+}
+
+// notificationRequest is the body sent to the notifications endpoint
+type notificationRequest struct {
+	ExampleID string `json:"example_id"`
+	Email     string `json:"email"`
+	Event     string `json:"event"`
+}
+
+// NewHTTPExternalExampleAPI creates an external API client; every call is bounded by cfg.Timeout
+func NewHTTPExternalExampleAPI(cfg *config.ExternalAPIConfig) *HTTPExternalExampleAPI {
+	return &HTTPExternalExampleAPI{
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:  cfg.APIKey,
+		headers: cfg.Headers,
+		client:  &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// GetExampleData fetches additional data for an example
+func (a *HTTPExternalExampleAPI) GetExampleData(ctx context.Context, exampleID string) (*ExternalExampleData, error) {
+	var data ExternalExampleData
+	if err := a.do(ctx, http.MethodGet, "/examples/"+url.PathEscape(exampleID)+"/data", nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// ValidateExample asks the external API whether an example is acceptable
+func (a *HTTPExternalExampleAPI) ValidateExample(ctx context.Context, name, email string, age int) (bool, error) {
+	var resp validateResponse
+	req := validateRequest{Name: name, Email: email, Age: age}
+	if err := a.do(ctx, http.MethodPost, "/examples/validate", req, &resp); err != nil {
+		return false, err
+	}
+	return resp.Valid, nil
+}
+
+// EnrichExample fetches enrichment data for an example
+func (a *HTTPExternalExampleAPI) EnrichExample(ctx context.Context, exampleID string) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	if err := a.do(ctx, http.MethodGet, "/examples/"+url.PathEscape(exampleID)+"/enrichment", nil, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// NotifyExampleCreated tells the external API about a new example
+func (a *HTTPExternalExampleAPI) NotifyExampleCreated(ctx context.Context, exampleID, email string) error {
+	req := notificationRequest{ExampleID: exampleID, Email: email, Event: "example.created"}
+	return a.do(ctx, http.MethodPost, "/examples/"+url.PathEscape(exampleID)+"/notifications", req, nil)
+}
+
+// Ping checks that the external API is reachable
+func (a *HTTPExternalExampleAPI) Ping(ctx context.Context) error {
+	return a.do(ctx, http.MethodGet, "/health", nil, nil)
+}
+
+// do sends a JSON request and decodes a JSON response into out, if given.
+// Timeouts map to ErrExternalAPITimeout, connection failures and 5xx/429
+// responses to ErrExternalAPIUnavailable, and undecodable bodies to ErrInvalidExternalData.
+func (a *HTTPExternalExampleAPI) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode external API request: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to build external API request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if a.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+a.apiKey)
+	}
+	for key, value := range a.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return classifyTransportError(ctx, method, path, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s %s returned %d", ErrExternalAPIUnavailable, method, path, resp.StatusCode)
+	case resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices:
+		return fmt.Errorf("external API %s %s returned %d", method, path, resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %s %s: %v", ErrInvalidExternalData, method, path, err)
+	}
+	return nil
+}
+
+// classifyTransportError maps a failed round trip to the external API errors.
+// A request cancelled by the caller keeps its context error.
+func classifyTransportError(ctx context.Context, method, path string, err error) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return ctx.Err()
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %s %s: %v", ErrExternalAPITimeout, method, path, err)
+	}
+	return fmt.Errorf("%w: %s %s: %v", ErrExternalAPIUnavailable, method, path, err)
+}
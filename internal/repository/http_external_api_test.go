@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example-api-template/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHTTPExternalAPI(t *testing.T, handler http.HandlerFunc, timeout time.Duration) *HTTPExternalExampleAPI {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewHTTPExternalExampleAPI(&config.ExternalAPIConfig{
+		BaseURL: server.URL + "/",
+		APIKey:  "secret",
+		Headers: map[string]string{"X-Client": "example-api"},
+		Timeout: timeout,
+	})
+}
+
+func TestHTTPExternalExampleAPI_Success(t *testing.T) {
+	ctx := context.Background()
+	lastModified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var notified notificationRequest
+	api := newTestHTTPExternalAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "example-api", r.Header.Get("X-Client"))
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /examples/ex_1/data":
+			json.NewEncoder(w).Encode(ExternalExampleData{
+				ExternalID:   "ext_ex_1",
+				Metadata:     map[string]string{"source": "crm"},
+				Score:        0.9,
+				LastModified: lastModified,
+			})
+		case "GET /examples/ex_1/enrichment":
+			w.Write([]byte(`{"risk_score": 0.1, "verification": "completed"}`))
+		case "POST /examples/validate":
+			var req validateRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			json.NewEncoder(w).Encode(validateResponse{Valid: req.Age >= 13})
+		case "POST /examples/ex_1/notifications":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&notified))
+			w.WriteHeader(http.StatusAccepted)
+		case "GET /health":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}, time.Second)
+
+	data, err := api.GetExampleData(ctx, "ex_1")
+	require.NoError(t, err)
+	assert.Equal(t, &ExternalExampleData{
+		ExternalID:   "ext_ex_1",
+		Metadata:     map[string]string{"source": "crm"},
+		Score:        0.9,
+		LastModified: lastModified,
+	}, data)
+
+	enrichment, err := api.EnrichExample(ctx, "ex_1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"risk_score": 0.1, "verification": "completed"}, enrichment)
+
+	valid, err := api.ValidateExample(ctx, "John Doe", "john@example.com", 30)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = api.ValidateExample(ctx, "Young Person", "young@example.com", 10)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	require.NoError(t, api.NotifyExampleCreated(ctx, "ex_1", "john@example.com"))
+	assert.Equal(t, notificationRequest{ExampleID: "ex_1", Email: "john@example.com", Event: "example.created"}, notified)
+
+	assert.NoError(t, api.Ping(ctx))
+}
+
+func TestHTTPExternalExampleAPI_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("5xx is unavailable", func(t *testing.T) {
+		api := newTestHTTPExternalAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}, time.Second)
+
+		_, err := api.GetExampleData(ctx, "ex_1")
+		assert.ErrorIs(t, err, ErrExternalAPIUnavailable)
+
+		_, err = api.ValidateExample(ctx, "John Doe", "john@example.com", 30)
+		assert.ErrorIs(t, err, ErrExternalAPIUnavailable)
+
+		assert.ErrorIs(t, api.NotifyExampleCreated(ctx, "ex_1", "john@example.com"), ErrExternalAPIUnavailable)
+	})
+
+	t.Run("slow response is a timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		api := newTestHTTPExternalAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}, 50*time.Millisecond)
+
+		_, err := api.EnrichExample(ctx, "ex_1")
+		assert.ErrorIs(t, err, ErrExternalAPITimeout)
+	})
+
+	t.Run("caller deadline is a timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		api := newTestHTTPExternalAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}, time.Second)
+
+		deadlineCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, err := api.GetExampleData(deadlineCtx, "ex_1")
+		assert.ErrorIs(t, err, ErrExternalAPITimeout)
+	})
+
+	t.Run("4xx is not retryable", func(t *testing.T) {
+		api := newTestHTTPExternalAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}, time.Second)
+
+		_, err := api.GetExampleData(ctx, "ex_1")
+		require.Error(t, err)
+		assert.False(t, isTransientExternalError(err))
+	})
+
+	t.Run("malformed body is invalid data", func(t *testing.T) {
+		api := newTestHTTPExternalAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`not json`))
+		}, time.Second)
+
+		_, err := api.GetExampleData(ctx, "ex_1")
+		assert.ErrorIs(t, err, ErrInvalidExternalData)
+	})
+
+	t.Run("unreachable server is unavailable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		api := NewHTTPExternalExampleAPI(&config.ExternalAPIConfig{BaseURL: server.URL, Timeout: time.Second})
+
+		assert.ErrorIs(t, api.Ping(ctx), ErrExternalAPIUnavailable)
+	})
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"example-api-template/internal/domain"
+	"example-api-template/pkg/metrics"
+)
+
+// InstrumentedExampleRepository decorates an ExampleRepository with Prometheus metrics
+type InstrumentedExampleRepository struct {
+	next    ExampleRepository
+	metrics *metrics.Metrics
+}
+
+// NewInstrumentedExampleRepository wraps a repository so every operation records its duration
+func NewInstrumentedExampleRepository(next ExampleRepository, m *metrics.Metrics) *InstrumentedExampleRepository {
+	return &InstrumentedExampleRepository{
+		next:    next,
+		metrics: m,
+	}
+}
+
+// Create records metrics around the wrapped Create
+func (r *InstrumentedExampleRepository) Create(ctx context.Context, example *domain.Example) error {
+	start := time.Now()
+	err := r.next.Create(ctx, example)
+	r.metrics.ObserveRepositoryOperation("create", start, err)
+	return err
+}
+
+// GetByID records metrics around the wrapped GetByID
+func (r *InstrumentedExampleRepository) GetByID(ctx context.Context, id string) (*domain.Example, error) {
+	start := time.Now()
+	example, err := r.next.GetByID(ctx, id)
+	r.metrics.ObserveRepositoryOperation("get_by_id", start, err)
+	return example, err
+}
+
+// GetByIDs records metrics around the wrapped GetByIDs
+func (r *InstrumentedExampleRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Example, error) {
+	start := time.Now()
+	examples, err := r.next.GetByIDs(ctx, ids)
+	r.metrics.ObserveRepositoryOperation("get_by_ids", start, err)
+	return examples, err
+}
+
+// GetByEmail records metrics around the wrapped GetByEmail
+func (r *InstrumentedExampleRepository) GetByEmail(ctx context.Context, email string) (*domain.Example, error) {
+	start := time.Now()
+	example, err := r.next.GetByEmail(ctx, email)
+	r.metrics.ObserveRepositoryOperation("get_by_email", start, err)
+	return example, err
+}
+
+// ExistsByEmail records metrics around the wrapped ExistsByEmail
+func (r *InstrumentedExampleRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	start := time.Now()
+	exists, err := r.next.ExistsByEmail(ctx, email)
+	r.metrics.ObserveRepositoryOperation("exists_by_email", start, err)
+	return exists, err
+}
+
+// Update records metrics around the wrapped Update
+func (r *InstrumentedExampleRepository) Update(ctx context.Context, example *domain.Example) error {
+	start := time.Now()
+	err := r.next.Update(ctx, example)
+	r.metrics.ObserveRepositoryOperation("update", start, err)
+	return err
+}
+
+// UpdateFields records metrics around the wrapped UpdateFields
+func (r *InstrumentedExampleRepository) UpdateFields(ctx context.Context, id string, expectedVersion int, fields map[string]interface{}) error {
+	start := time.Now()
+	err := r.next.UpdateFields(ctx, id, expectedVersion, fields)
+	r.metrics.ObserveRepositoryOperation("update_fields", start, err)
+	return err
+}
+
+// Delete records metrics around the wrapped Delete
+func (r *InstrumentedExampleRepository) Delete(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	r.metrics.ObserveRepositoryOperation("delete", start, err)
+	return err
+}
+
+// DeleteByIDs records metrics around the wrapped DeleteByIDs
+func (r *InstrumentedExampleRepository) DeleteByIDs(ctx context.Context, ids []string) ([]string, error) {
+	start := time.Now()
+	deleted, err := r.next.DeleteByIDs(ctx, ids)
+	r.metrics.ObserveRepositoryOperation("delete_by_ids", start, err)
+	return deleted, err
+}
+
+// List records metrics around the wrapped List
+func (r *InstrumentedExampleRepository) List(ctx context.Context, limit, offset int, sort domain.ExampleSort) ([]*domain.Example, error) {
+	start := time.Now()
+	examples, err := r.next.List(ctx, limit, offset, sort)
+	r.metrics.ObserveRepositoryOperation("list", start, err)
+	return examples, err
+}
+
+// ListFiltered records metrics around the wrapped ListFiltered
+func (r *InstrumentedExampleRepository) ListFiltered(ctx context.Context, filter ExampleFilter, limit, offset int, sort domain.ExampleSort) ([]*domain.Example, error) {
+	start := time.Now()
+	examples, err := r.next.ListFiltered(ctx, filter, limit, offset, sort)
+	r.metrics.ObserveRepositoryOperation("list_filtered", start, err)
+	return examples, err
+}
+
+// Count records metrics around the wrapped Count
+func (r *InstrumentedExampleRepository) Count(ctx context.Context) (int, error) {
+	start := time.Now()
+	count, err := r.next.Count(ctx)
+	r.metrics.ObserveRepositoryOperation("count", start, err)
+	return count, err
+}
+
+// CountFiltered records metrics around the wrapped CountFiltered
+func (r *InstrumentedExampleRepository) CountFiltered(ctx context.Context, filter ExampleFilter) (int, error) {
+	start := time.Now()
+	count, err := r.next.CountFiltered(ctx, filter)
+	r.metrics.ObserveRepositoryOperation("count_filtered", start, err)
+	return count, err
+}
+
+// StreamAll records metrics around the wrapped StreamAll, including the time spent in fn
+func (r *InstrumentedExampleRepository) StreamAll(ctx context.Context, fn func(*domain.Example) error) error {
+	start := time.Now()
+	err := r.next.StreamAll(ctx, fn)
+	r.metrics.ObserveRepositoryOperation("stream_all", start, err)
+	return err
+}
+
+// InstrumentedExternalExampleAPI decorates an ExternalExampleAPI with Prometheus metrics
+type InstrumentedExternalExampleAPI struct {
+	next    ExternalExampleAPI
+	metrics *metrics.Metrics
+}
+
+// NewInstrumentedExternalExampleAPI wraps an external API client so every call records its duration
+func NewInstrumentedExternalExampleAPI(next ExternalExampleAPI, m *metrics.Metrics) *InstrumentedExternalExampleAPI {
+	return &InstrumentedExternalExampleAPI{
+		next:    next,
+		metrics: m,
+	}
+}
+
+// GetExampleData records metrics around the wrapped GetExampleData
+func (a *InstrumentedExternalExampleAPI) GetExampleData(ctx context.Context, exampleID string) (*ExternalExampleData, error) {
+	start := time.Now()
+	data, err := a.next.GetExampleData(ctx, exampleID)
+	a.metrics.ObserveExternalAPICall("get_example_data", start, err)
+	return data, err
+}
+
+// ValidateExample records metrics around the wrapped ValidateExample
+func (a *InstrumentedExternalExampleAPI) ValidateExample(ctx context.Context, name, email string, age int) (bool, error) {
+	start := time.Now()
+	valid, err := a.next.ValidateExample(ctx, name, email, age)
+	a.metrics.ObserveExternalAPICall("validate_example", start, err)
+	return valid, err
+}
+
+// EnrichExample records metrics around the wrapped EnrichExample
+func (a *InstrumentedExternalExampleAPI) EnrichExample(ctx context.Context, exampleID string) (map[string]interface{}, error) {
+	start := time.Now()
+	data, err := a.next.EnrichExample(ctx, exampleID)
+	a.metrics.ObserveExternalAPICall("enrich_example", start, err)
+	return data, err
+}
+
+// NotifyExampleCreated records metrics around the wrapped NotifyExampleCreated
+func (a *InstrumentedExternalExampleAPI) NotifyExampleCreated(ctx context.Context, exampleID, email string) error {
+	start := time.Now()
+	err := a.next.NotifyExampleCreated(ctx, exampleID, email)
+	a.metrics.ObserveExternalAPICall("notify_example_created", start, err)
+	return err
+}
+
+// Ping records metrics around the wrapped Ping
+func (a *InstrumentedExternalExampleAPI) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := a.next.Ping(ctx)
+	a.metrics.ObserveExternalAPICall("ping", start, err)
+	return err
+}
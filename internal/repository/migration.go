@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"example-api-template/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// MigrationStatus describes how the examples table compares with the schema AutoMigrate creates
+type MigrationStatus struct {
+	Table          string
+	Exists         bool
+	MissingColumns []string
+	MissingIndexes []string
+}
+
+// UpToDate reports whether the table exists with every expected column and index
+func (s MigrationStatus) UpToDate() bool {
+	return s.Exists && len(s.MissingColumns) == 0 && len(s.MissingIndexes) == 0
+}
+
+// MigrationStatus inspects the examples table on the primary, e.g. to check a schema
+// migrated outside the application
+func (r *PostgreSQLExampleRepository) MigrationStatus(ctx context.Context) (MigrationStatus, error) {
+	status := MigrationStatus{Table: r.examplesTable}
+	db := r.db.WithContext(ctx).Clauses(dbresolver.Write)
+
+	// The migrator reports a failed query as a missing table, so make sure the database answers first
+	if err := db.Exec("SELECT 1").Error; err != nil {
+		return status, fmt.Errorf("failed to check migration status: %w", err)
+	}
+
+	migrator := db.Table(r.examplesTable).Migrator()
+	model := &domain.Example{}
+	if status.Exists = migrator.HasTable(model); !status.Exists {
+		return status, nil
+	}
+
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(model); err != nil {
+		return status, fmt.Errorf("failed to parse example model: %w", err)
+	}
+	for _, column := range stmt.Schema.DBNames {
+		if !migrator.HasColumn(model, column) {
+			status.MissingColumns = append(status.MissingColumns, column)
+		}
+	}
+	if index := r.emailIndex(); !migrator.HasIndex(model, index) {
+		status.MissingIndexes = append(status.MissingIndexes, index)
+	}
+	return status, nil
+}
+
+// emailIndex names the case-insensitive email index; indexes live in their table's schema,
+// so the name leaves the schema out
+func (r *PostgreSQLExampleRepository) emailIndex() string {
+	return "idx_" + r.examplesTable[strings.LastIndex(r.examplesTable, ".")+1:] + "_email_lower"
+}
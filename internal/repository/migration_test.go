@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestPostgreSQLRepositoryMigrationStatus(t *testing.T) {
+	ctx := context.Background()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	repo := NewPostgreSQLExampleRepository(db, WithTablePrefix("tenant1_"))
+
+	status, err := repo.MigrationStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "tenant1_examples", status.Table)
+	assert.False(t, status.Exists)
+	assert.False(t, status.UpToDate())
+
+	require.NoError(t, repo.AutoMigrate())
+	status, err = repo.MigrationStatus(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.UpToDate(), "missing columns %v, indexes %v", status.MissingColumns, status.MissingIndexes)
+
+	// A schema managed elsewhere that lags behind the model
+	require.NoError(t, db.Exec("DROP INDEX idx_tenant1_examples_email_lower").Error)
+	require.NoError(t, db.Exec("ALTER TABLE tenant1_examples DROP COLUMN phone").Error)
+	status, err = repo.MigrationStatus(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.Exists)
+	assert.False(t, status.UpToDate())
+	assert.Equal(t, []string{"phone"}, status.MissingColumns)
+	assert.Equal(t, []string{"idx_tenant1_examples_email_lower"}, status.MissingIndexes)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	_, err = repo.MigrationStatus(ctx)
+	assert.Error(t, err, "an unreachable database is an error, not a missing table")
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"example-api-template/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+// OutboxRepository stores example events until they have been published
+type OutboxRepository interface {
+	SaveOutboxEvent(ctx context.Context, event *domain.OutboxEvent) error
+	UnpublishedOutboxEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error)
+	ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error)
+	ReleaseOutboxEvents(ctx context.Context, ids []string) error
+	MarkOutboxEventPublished(ctx context.Context, id string) error
+	PublishedOutboxEvents(ctx context.Context, filter OutboxEventFilter, limit, offset int) ([]*domain.OutboxEvent, error)
+	CountPublishedOutboxEvents(ctx context.Context, filter OutboxEventFilter) (int, error)
+}
+
+// OutboxRecorder is a repository whose writes can record outbox events themselves
+type OutboxRecorder interface {
+	WithoutOutboxRecording() ExampleRepository
+}
+
+// OutboxEventFilter selects published events, e.g. to replay them. Zero fields match every event.
+type OutboxEventFilter struct {
+	From time.Time // Events created at or after From
+	To   time.Time // Events created before To
+	IDs  []string  // Events with one of these IDs
+}
+
+// SaveOutboxEvent stores an unpublished event
+func (r *PostgreSQLExampleRepository) SaveOutboxEvent(ctx context.Context, event *domain.OutboxEvent) error {
+	result := r.outboxEvents(ctx).Create(event)
+	return handleErrorWithContext(result.Error, "save outbox event", event.ID)
+}
+
+// UnpublishedOutboxEvents returns up to limit unpublished events, oldest first.
+// They are read from the primary so replica lag cannot hold events back.
+func (r *PostgreSQLExampleRepository) UnpublishedOutboxEvents(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	var events []*domain.OutboxEvent
+	result := r.outboxEvents(ctx).Clauses(dbresolver.Write).
+		Where("published_at IS NULL").
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&events)
+	if err := handleError(result.Error); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ClaimOutboxEvents claims up to limit unpublished events no other publisher holds, oldest
+// first, for lease and returns them. Events of a publisher that stopped before publishing
+// them can be claimed again once its lease runs out. On PostgreSQL the candidates are locked
+// with SKIP LOCKED, so concurrent claims pick different events instead of waiting.
+func (r *PostgreSQLExampleRepository) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+	now := time.Now()
+	claimedBy := uuid.New().String()
+	var events []*domain.OutboxEvent
+
+	err := r.db.WithContext(ctx).Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		txRepo := r.withTx(tx)
+
+		candidates := txRepo.outboxEvents(ctx).
+			Where("published_at IS NULL").
+			Where("(claimed_until IS NULL OR claimed_until < ?)", now).
+			Order("created_at ASC, id ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			candidates = candidates.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		var ids []string
+		if err := candidates.Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		// The lease is checked again in case another publisher claimed an event in between
+		err := txRepo.outboxEvents(ctx).
+			Where("id IN ?", ids).
+			Where("(claimed_until IS NULL OR claimed_until < ?)", now).
+			Updates(map[string]interface{}{"claimed_by": claimedBy, "claimed_until": now.Add(lease)}).Error
+		if err != nil {
+			return err
+		}
+
+		return txRepo.outboxEvents(ctx).
+			Where("claimed_by = ?", claimedBy).
+			Where("published_at IS NULL").
+			Order("created_at ASC, id ASC").
+			Find(&events).Error
+	})
+	if err := handleError(err); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ReleaseOutboxEvents drops the claim on the unpublished events with ids, so they can be
+// claimed again without waiting for the lease to run out
+func (r *PostgreSQLExampleRepository) ReleaseOutboxEvents(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	result := r.outboxEvents(ctx).
+		Where("id IN ?", ids).
+		Where("published_at IS NULL").
+		Updates(map[string]interface{}{"claimed_by": "", "claimed_until": nil})
+	return handleErrorWithContext(result.Error, "release outbox events", fmt.Sprint(ids))
+}
+
+// MarkOutboxEventPublished records that an event has been published
+func (r *PostgreSQLExampleRepository) MarkOutboxEventPublished(ctx context.Context, id string) error {
+	result := r.outboxEvents(ctx).Model(&domain.OutboxEvent{}).
+		Where(QueryByID, id).
+		Update("published_at", time.Now())
+	if err := handleErrorWithContext(result.Error, "mark outbox event published", id); err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf(ErrTemplateID, ErrOutboxEventNotFound, id)
+	}
+	return nil
+}
+
+// PublishedOutboxEvents returns up to limit published events matching filter, oldest first,
+// skipping the first offset
+func (r *PostgreSQLExampleRepository) PublishedOutboxEvents(ctx context.Context, filter OutboxEventFilter, limit, offset int) ([]*domain.OutboxEvent, error) {
+	var events []*domain.OutboxEvent
+	result := r.publishedOutboxEvents(ctx, filter).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events)
+	if err := handleError(result.Error); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// CountPublishedOutboxEvents returns how many published events match filter
+func (r *PostgreSQLExampleRepository) CountPublishedOutboxEvents(ctx context.Context, filter OutboxEventFilter) (int, error) {
+	var count int64
+	result := r.publishedOutboxEvents(ctx, filter).Count(&count)
+	if err := handleError(result.Error); err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// publishedOutboxEvents starts a query on the published events matching filter
+func (r *PostgreSQLExampleRepository) publishedOutboxEvents(ctx context.Context, filter OutboxEventFilter) *gorm.DB {
+	query := r.outboxEvents(ctx).Where("published_at IS NOT NULL")
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	return query
+}
+
+// outboxEvents starts a query on the outbox table
+func (r *PostgreSQLExampleRepository) outboxEvents(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Table(r.outboxTable)
+}
+
+// recordEvent runs write and, when the outbox is enabled, stores an eventType event
+// for the example it returns in the same transaction, so either both are saved or neither is
+func (r *PostgreSQLExampleRepository) recordEvent(ctx context.Context, eventType string, write func(*PostgreSQLExampleRepository) (*domain.Example, error)) error {
+	if !r.outbox {
+		_, err := write(r)
+		return err
+	}
+
+	return r.db.WithContext(ctx).Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		txRepo := r.withTx(tx)
+		example, err := write(txRepo)
+		if err != nil {
+			return err
+		}
+
+		event, err := domain.NewOutboxEvent(eventType, example)
+		if err != nil {
+			return err
+		}
+		return txRepo.SaveOutboxEvent(ctx, event)
+	})
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"example-api-template/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newOutboxTestRepository returns a migrated repository that records outbox events
+func newOutboxTestRepository(t *testing.T) *PostgreSQLExampleRepository {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// Every connection to ":memory:" opens a fresh database, so keep to one
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	repo := NewPostgreSQLExampleRepository(db).WithOutbox()
+	require.NoError(t, repo.AutoMigrate())
+	return repo
+}
+
+func newOutboxTestExample(email string) *domain.Example {
+	example, _ := domain.NewExample(uuid.New().String(), "John Doe", email, 30)
+	return example
+}
+
+func TestPostgreSQLRepositoryOutbox(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("records an event with every write", func(t *testing.T) {
+		repo := newOutboxTestRepository(t)
+
+		example := newOutboxTestExample("john@example.com")
+		require.NoError(t, repo.Create(ctx, example))
+		example.Name = "John Smith"
+		require.NoError(t, repo.Update(ctx, example))
+		require.NoError(t, repo.Delete(ctx, example.ID))
+
+		events, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.Equal(t, domain.EventTypeExampleCreated, events[0].Type)
+		assert.Equal(t, domain.EventTypeExampleUpdated, events[1].Type)
+		assert.Equal(t, domain.EventTypeExampleDeleted, events[2].Type)
+
+		// The deleted event still carries the example
+		deleted, err := events[2].Example()
+		require.NoError(t, err)
+		assert.Equal(t, example.ID, deleted.ID)
+		assert.Equal(t, "John Smith", deleted.Name)
+		assert.Equal(t, "john@example.com", deleted.Email)
+	})
+
+	t.Run("bulk delete records an event per removed example", func(t *testing.T) {
+		repo := newOutboxTestRepository(t)
+
+		john := newOutboxTestExample("john@example.com")
+		jane := newOutboxTestExample("jane@example.com")
+		require.NoError(t, repo.Create(ctx, john))
+		require.NoError(t, repo.Create(ctx, jane))
+
+		deleted, err := repo.DeleteByIDs(ctx, []string{john.ID, "missing", jane.ID})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{john.ID, jane.ID}, deleted)
+
+		events, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 4)
+		var deletedIDs []string
+		for _, event := range events[2:] {
+			assert.Equal(t, domain.EventTypeExampleDeleted, event.Type)
+			example, err := event.Example()
+			require.NoError(t, err)
+			deletedIDs = append(deletedIDs, example.ID)
+		}
+		assert.ElementsMatch(t, []string{john.ID, jane.ID}, deletedIDs)
+	})
+
+	t.Run("field update records the updated example", func(t *testing.T) {
+		repo := newOutboxTestRepository(t)
+
+		example := newOutboxTestExample("john@example.com")
+		require.NoError(t, repo.Create(ctx, example))
+		require.NoError(t, repo.UpdateFields(ctx, example.ID, 1, map[string]interface{}{ColumnAge: 31}))
+
+		events, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, domain.EventTypeExampleUpdated, events[1].Type)
+		updated, err := events[1].Example()
+		require.NoError(t, err)
+		assert.Equal(t, 31, updated.Age)
+		assert.Equal(t, "John Doe", updated.Name)
+		assert.Equal(t, 2, updated.Version)
+	})
+
+	t.Run("failed write records no event", func(t *testing.T) {
+		repo := newOutboxTestRepository(t)
+		require.NoError(t, repo.Create(ctx, newOutboxTestExample("john@example.com")))
+
+		err := repo.Create(ctx, newOutboxTestExample("john@example.com"))
+		assert.ErrorIs(t, err, ErrExampleAlreadyExists)
+		assert.ErrorIs(t, repo.Delete(ctx, "missing"), ErrExampleNotFound)
+
+		events, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+
+	t.Run("rolled back transaction persists no event", func(t *testing.T) {
+		repo := newOutboxTestRepository(t)
+
+		err := repo.Transaction(ctx, func(txRepo ExampleRepository) error {
+			if err := txRepo.Create(ctx, newOutboxTestExample("john@example.com")); err != nil {
+				return err
+			}
+			return errors.New("simulated error")
+		})
+		require.Error(t, err)
+
+		count, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		events, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("committed transaction records its events", func(t *testing.T) {
+		repo := newOutboxTestRepository(t)
+
+		err := repo.Transaction(ctx, func(txRepo ExampleRepository) error {
+			return txRepo.Create(ctx, newOutboxTestExample("john@example.com"))
+		})
+		require.NoError(t, err)
+
+		events, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, domain.EventTypeExampleCreated, events[0].Type)
+	})
+
+	t.Run("transaction without outbox recording records no event", func(t *testing.T) {
+		repo := newOutboxTestRepository(t)
+
+		err := repo.Transaction(ctx, func(txRepo ExampleRepository) error {
+			return txRepo.(OutboxRecorder).WithoutOutboxRecording().Create(ctx, newOutboxTestExample("john@example.com"))
+		})
+		require.NoError(t, err)
+
+		count, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		events, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("published events are not returned again", func(t *testing.T) {
+		repo := newOutboxTestRepository(t)
+		require.NoError(t, repo.Create(ctx, newOutboxTestExample("john@example.com")))
+		require.NoError(t, repo.Create(ctx, newOutboxTestExample("jane@example.com")))
+
+		events, err := repo.UnpublishedOutboxEvents(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.NoError(t, repo.MarkOutboxEventPublished(ctx, events[0].ID))
+
+		remaining, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+		assert.NotEqual(t, events[0].ID, remaining[0].ID)
+
+		assert.ErrorIs(t, repo.MarkOutboxEventPublished(ctx, "missing"), ErrOutboxEventNotFound)
+	})
+
+	t.Run("claimed events are skipped until released or the lease runs out", func(t *testing.T) {
+		repo := newOutboxTestRepository(t)
+		require.NoError(t, repo.Create(ctx, newOutboxTestExample("john@example.com")))
+		require.NoError(t, repo.Create(ctx, newOutboxTestExample("jane@example.com")))
+
+		first, err := repo.ClaimOutboxEvents(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, first, 1)
+
+		// Another publisher gets the other event only
+		second, err := repo.ClaimOutboxEvents(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, second, 1)
+		assert.NotEqual(t, first[0].ID, second[0].ID)
+
+		none, err := repo.ClaimOutboxEvents(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+
+		require.NoError(t, repo.ReleaseOutboxEvents(ctx, []string{first[0].ID}))
+		released, err := repo.ClaimOutboxEvents(ctx, 10, -time.Second)
+		require.NoError(t, err)
+		require.Len(t, released, 1)
+		assert.Equal(t, first[0].ID, released[0].ID)
+
+		// Its lease has already run out, so it can be claimed again
+		expired, err := repo.ClaimOutboxEvents(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, expired, 1)
+		assert.Equal(t, first[0].ID, expired[0].ID)
+
+		// Published events are never claimed
+		require.NoError(t, repo.MarkOutboxEventPublished(ctx, first[0].ID))
+		require.NoError(t, repo.ReleaseOutboxEvents(ctx, []string{first[0].ID, second[0].ID}))
+		claimed, err := repo.ClaimOutboxEvents(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, second[0].ID, claimed[0].ID)
+	})
+
+	t.Run("repository without outbox records nothing", func(t *testing.T) {
+		repo := newOutboxTestRepository(t)
+		plain := NewPostgreSQLExampleRepository(repo.db)
+		require.NoError(t, plain.Create(ctx, newOutboxTestExample("john@example.com")))
+
+		events, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+}
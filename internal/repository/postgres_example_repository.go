@@ -2,11 +2,15 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"example-api-template/internal/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
 )
 
 // RepositoryStats holds statistics about the repository
@@ -20,71 +24,407 @@ type RepositoryStats struct {
 // Constants for database queries
 const (
 	QueryByID        = "id = ?"
-	QueryByEmail     = "email = ?"
+	QueryByIDs       = "id IN ?"
+	QueryByEmail     = "LOWER(email) = ?"
+	QueryByVersion   = "version = ?"
+	QueryAfterID     = "id > ?"
+	QueryCreatedFrom = "created_at >= ?"
+	QueryUpdatedFrom = "updated_at >= ?"
+	OrderByID        = "id"
 	OrderByCreatedAt = "created_at DESC"
 )
 
-// PostgreSQLExampleRepository implements ExampleRepository using PostgreSQL
+// Columns of the examples table that UpdateFields writes
+const (
+	ColumnName      = "name"
+	ColumnEmail     = "email"
+	ColumnPhone     = "phone"
+	ColumnAge       = "age"
+	ColumnUpdatedAt = "updated_at"
+)
+
+// Fields that can be searched, mapped to their columns
+const (
+	SearchFieldName  = "name"
+	SearchFieldEmail = "email"
+)
+
+var searchableColumns = map[string]string{
+	SearchFieldName:  "name",
+	SearchFieldEmail: "email",
+}
+
+// DefaultSearchFields are searched when the caller does not choose any
+var DefaultSearchFields = []string{SearchFieldName, SearchFieldEmail}
+
+// PostgreSQLExampleRepository implements ExampleRepository using PostgreSQL.
+// When read replicas are registered on db, reads are served by a replica and
+// writes, migrations and transactions by the primary.
 type PostgreSQLExampleRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	outbox bool // Record an outbox event in the same transaction as every write
+	stats  StatsConfig
+
+	// The models name their tables, so a schema or prefix is applied per query with Table
+	examplesTable string
+	outboxTable   string
 }
 
 // NewPostgreSQLExampleRepository creates a new PostgreSQL repository
-func NewPostgreSQLExampleRepository(db *gorm.DB) *PostgreSQLExampleRepository {
+func NewPostgreSQLExampleRepository(db *gorm.DB, opts ...Option) *PostgreSQLExampleRepository {
+	o := newOptions(opts)
 	return &PostgreSQLExampleRepository{
-		db: db,
+		db:            db,
+		stats:         o.stats,
+		examplesTable: o.tableName(domain.Example{}.TableName()),
+		outboxTable:   o.tableName(domain.OutboxEvent{}.TableName()),
 	}
 }
 
 // AutoMigrate creates or updates the database schema
 func (r *PostgreSQLExampleRepository) AutoMigrate() error {
-	return r.db.AutoMigrate(&domain.Example{})
+	db := r.db.Clauses(dbresolver.Write)
+	if err := db.Table(r.examplesTable).AutoMigrate(&domain.Example{}); err != nil {
+		return err
+	}
+
+	// Emails are unique regardless of case. The index also serves the LOWER(email) lookups,
+	// and fails to build while rows differing only in email case remain.
+	index := fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s (LOWER(email))", r.emailIndex(), r.examplesTable)
+	if err := db.Exec(index).Error; err != nil {
+		if duplicates := r.caseDuplicateEmails(); len(duplicates) > 0 {
+			return fmt.Errorf("failed to create case-insensitive email index, emails stored in several cases must be merged first: %s: %w",
+				strings.Join(duplicates, ", "), err)
+		}
+		return fmt.Errorf("failed to create case-insensitive email index: %w", err)
+	}
+	return db.Table(r.outboxTable).AutoMigrate(&domain.OutboxEvent{})
+}
+
+// maxReportedDuplicates caps how many conflicting emails a failed migration lists
+const maxReportedDuplicates = 20
+
+// caseDuplicateEmails returns, lowercased, emails stored by more than one example in
+// different cases, which keep the case-insensitive email index from being built
+func (r *PostgreSQLExampleRepository) caseDuplicateEmails() []string {
+	var emails []string
+	err := r.db.Clauses(dbresolver.Write).Table(r.examplesTable).
+		Select("LOWER(email)").
+		Group("LOWER(email)").
+		Having("COUNT(*) > 1").
+		Order("LOWER(email)").
+		Limit(maxReportedDuplicates).
+		Pluck("LOWER(email)", &emails).Error
+	if err != nil {
+		return nil
+	}
+	return emails
+}
+
+// WithOutbox returns a repository that records an outbox event in the same
+// transaction as every create, update and delete
+func (r *PostgreSQLExampleRepository) WithOutbox() *PostgreSQLExampleRepository {
+	repo := *r
+	repo.outbox = true
+	return &repo
+}
+
+// examples starts a query on the examples table
+func (r *PostgreSQLExampleRepository) examples(ctx context.Context) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if primaryReads(ctx) {
+		db = db.Clauses(dbresolver.Write)
+	}
+	return db.Table(r.examplesTable)
+}
+
+// withTx returns a copy of the repository running its queries in tx
+func (r *PostgreSQLExampleRepository) withTx(tx *gorm.DB) *PostgreSQLExampleRepository {
+	repo := *r
+	repo.db = tx
+	return &repo
 }
 
 // Create creates a new example in the database
 func (r *PostgreSQLExampleRepository) Create(ctx context.Context, example *domain.Example) error {
-	result := r.db.WithContext(ctx).Create(example)
+	if example.Version == 0 {
+		example.Version = 1
+	}
 
-	return handleErrorWithContext(result.Error, "create example", example.ID)
+	return r.recordEvent(ctx, domain.EventTypeExampleCreated, func(repo *PostgreSQLExampleRepository) (*domain.Example, error) {
+		result := repo.examples(ctx).Create(example)
+		return example, handleErrorWithContext(result.Error, "create example", example.ID)
+	})
 }
 
 // GetByID retrieves an example by ID
 func (r *PostgreSQLExampleRepository) GetByID(ctx context.Context, id string) (*domain.Example, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: id cannot be empty", ErrInvalidQuery)
+	}
+
 	var example domain.Example
-	result := r.db.WithContext(ctx).First(&example, QueryByID, id)
+	result := r.examples(ctx).First(&example, QueryByID, id)
 	return &example, handleErrorWithContext(result.Error, "get example by ID", id)
 }
 
-// GetByEmail retrieves an example by email
+// GetByIDs retrieves the examples with the given IDs in a single query, in the order requested,
+// skipping missing IDs
+func (r *PostgreSQLExampleRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Example, error) {
+	if len(ids) == 0 {
+		return []*domain.Example{}, nil
+	}
+
+	var examples []domain.Example
+	result := r.examples(ctx).Where(QueryByIDs, ids).Find(&examples)
+	if err := handleError(result.Error); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*domain.Example, len(examples))
+	for i := range examples {
+		byID[examples[i].ID] = &examples[i]
+	}
+
+	resultExamples := make([]*domain.Example, 0, len(examples))
+	for _, id := range ids {
+		if example, ok := byID[id]; ok {
+			resultExamples = append(resultExamples, example)
+			delete(byID, id) // Requested twice, returned once
+		}
+	}
+
+	return resultExamples, nil
+}
+
+// GetByEmail retrieves an example by email, ignoring case
 func (r *PostgreSQLExampleRepository) GetByEmail(ctx context.Context, email string) (*domain.Example, error) {
+	if email == "" {
+		return nil, fmt.Errorf("%w: email cannot be empty", ErrInvalidQuery)
+	}
+
 	var example domain.Example
-	result := r.db.WithContext(ctx).First(&example, QueryByEmail, email)
+	result := r.examples(ctx).First(&example, QueryByEmail, domain.NormalizeEmail(email))
 	return &example, handleErrorWithContext(result.Error, "get example by email", email)
 }
 
-// Update updates an existing example
+// ExistsByEmail reports whether an example has the given email, ignoring case, without loading the row
+func (r *PostgreSQLExampleRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	var found []int
+	result := r.examples(ctx).Model(&domain.Example{}).
+		Select("1").
+		Where(QueryByEmail, domain.NormalizeEmail(email)).
+		Limit(1).
+		Find(&found)
+	if err := handleErrorWithContext(result.Error, "check example email", email); err != nil {
+		return false, err
+	}
+	return len(found) > 0, nil
+}
+
+// Update updates an existing example if its stored version still matches example.Version,
+// then increments the version. A mismatch means another writer got there first.
 func (r *PostgreSQLExampleRepository) Update(ctx context.Context, example *domain.Example) error {
+	return r.recordEvent(ctx, domain.EventTypeExampleUpdated, func(repo *PostgreSQLExampleRepository) (*domain.Example, error) {
+		return example, repo.update(ctx, example)
+	})
+}
+
+// update writes example if its stored version still matches example.Version
+func (r *PostgreSQLExampleRepository) update(ctx context.Context, example *domain.Example) error {
+	expectedVersion := example.Version
+	updatedAt := example.UpdatedAt
+
 	example.UpdatedAt = time.Now()
+	example.Version = expectedVersion + 1
 
-	result := r.db.WithContext(ctx).Model(&domain.Example{}).
+	// Write every column, since Updates skips zero values and a removed phone number must be saved
+	result := r.examples(ctx).Model(&domain.Example{}).
 		Where(QueryByID, example.ID).
+		Where(QueryByVersion, expectedVersion).
+		Select("*").Omit("created_at").
 		Updates(example)
+	if err := handleErrorWithContext(result.Error, "update example", example.ID); err != nil {
+		example.Version, example.UpdatedAt = expectedVersion, updatedAt
+		return err
+	}
+	if result.RowsAffected == 0 {
+		example.Version, example.UpdatedAt = expectedVersion, updatedAt
+		return r.versionConflict(ctx, "update example", example.ID, expectedVersion)
+	}
+
+	return nil
+}
+
+// versionConflict explains why a versioned write of the example with the given ID changed
+// no row: it is missing, or its stored version is no longer expectedVersion
+func (r *PostgreSQLExampleRepository) versionConflict(ctx context.Context, operation, id string, expectedVersion int) error {
+	// Ask the primary so replica lag cannot hide the row
+	var count int64
+	err := r.examples(ctx).Clauses(dbresolver.Write).Model(&domain.Example{}).Where(QueryByID, id).Count(&count).Error
+	if err := handleErrorWithContext(err, operation, id); err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrExampleNotFound
+	}
+	return fmt.Errorf("%w: id %s, version %d", ErrVersionConflict, id, expectedVersion)
+}
+
+// UpdateFields writes only the given columns of an example, leaving the others as stored,
+// if its stored version still matches expectedVersion, and increments its version.
+// updated_at is set to the current time unless fields sets it.
+func (r *PostgreSQLExampleRepository) UpdateFields(ctx context.Context, id string, expectedVersion int, fields map[string]interface{}) error {
+	if err := checkUpdateFields(id, fields); err != nil {
+		return err
+	}
+
+	return r.recordEvent(ctx, domain.EventTypeExampleUpdated, func(repo *PostgreSQLExampleRepository) (*domain.Example, error) {
+		if err := repo.updateFields(ctx, id, expectedVersion, fields); err != nil {
+			return nil, err
+		}
+		if !repo.outbox {
+			return nil, nil
+		}
+		// The updated event carries the whole example, so read it back inside the transaction
+		return repo.GetByID(ctx, id)
+	})
+}
 
-	return handleErrorWithContext(result.Error, "update example", example.ID)
+// updateFields writes fields to the example with the given ID if its stored version still matches expectedVersion
+func (r *PostgreSQLExampleRepository) updateFields(ctx context.Context, id string, expectedVersion int, fields map[string]interface{}) error {
+	values := make(map[string]interface{}, len(fields)+2)
+	values[ColumnUpdatedAt] = time.Now()
+	for column, value := range fields {
+		values[column] = value
+	}
+	values["version"] = gorm.Expr("version + 1")
+
+	result := r.examples(ctx).Where(QueryByID, id).Where(QueryByVersion, expectedVersion).Updates(values)
+	if err := handleErrorWithContext(result.Error, "update example fields", id); err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return r.versionConflict(ctx, "update example fields", id, expectedVersion)
+	}
+	return nil
 }
 
 // Delete deletes an example by ID
 func (r *PostgreSQLExampleRepository) Delete(ctx context.Context, id string) error {
-	result := r.db.WithContext(ctx).Delete(&domain.Example{}, QueryByID, id)
-	return handleErrorWithContext(result.Error, "delete example", id)
+	if id == "" {
+		return fmt.Errorf("%w: id cannot be empty", ErrInvalidQuery)
+	}
+
+	if !r.outbox {
+		return r.delete(ctx, id)
+	}
+
+	// The deleted event carries the example, so load it inside the transaction first
+	return r.recordEvent(ctx, domain.EventTypeExampleDeleted, func(repo *PostgreSQLExampleRepository) (*domain.Example, error) {
+		example, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return example, repo.delete(ctx, id)
+	})
 }
 
-// List retrieves a list of examples with pagination
-func (r *PostgreSQLExampleRepository) List(ctx context.Context, limit, offset int) ([]*domain.Example, error) {
+// DeleteByIDs removes the examples with the given IDs in one statement and returns the IDs
+// it deleted, as reported by the database; IDs with no example are skipped. With the outbox
+// enabled, a deleted event is stored for every removed example in the same transaction.
+func (r *PostgreSQLExampleRepository) DeleteByIDs(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if !r.outbox {
+		return r.deleteByIDs(ctx, ids)
+	}
+
+	// The deleted events carry the examples, so load them inside the transaction first
+	var deleted []string
+	err := r.db.WithContext(ctx).Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		txRepo := r.withTx(tx)
+		examples, err := txRepo.GetByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		if deleted, err = txRepo.deleteByIDs(ctx, ids); err != nil {
+			return err
+		}
+
+		removed := make(map[string]bool, len(deleted))
+		for _, id := range deleted {
+			removed[id] = true
+		}
+		for _, example := range examples {
+			if !removed[example.ID] {
+				continue
+			}
+			event, err := domain.NewOutboxEvent(domain.EventTypeExampleDeleted, example)
+			if err != nil {
+				return err
+			}
+			if err := txRepo.SaveOutboxEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// deleteByIDs removes the examples with the given IDs and returns the IDs the statement
+// deleted (DELETE ... RETURNING id). Like delete, it goes through GORM's Delete, which
+// soft-deletes instead should Example gain a gorm.DeletedAt field.
+func (r *PostgreSQLExampleRepository) deleteByIDs(ctx context.Context, ids []string) ([]string, error) {
+	var removed []domain.Example
+	result := r.examples(ctx).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Delete(&removed, QueryByIDs, ids)
+	if err := handleErrorWithContext(result.Error, "delete examples", strings.Join(ids, ",")); err != nil {
+		return nil, err
+	}
+
+	deleted := make([]string, len(removed))
+	for i, example := range removed {
+		deleted[i] = example.ID
+	}
+	return deleted, nil
+}
+
+// delete removes the example with the given ID
+func (r *PostgreSQLExampleRepository) delete(ctx context.Context, id string) error {
+	result := r.examples(ctx).Delete(&domain.Example{}, QueryByID, id)
+	if err := handleErrorWithContext(result.Error, "delete example", id); err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return ErrExampleNotFound
+	}
+
+	return nil
+}
+
+// List retrieves a sorted list of examples with pagination
+func (r *PostgreSQLExampleRepository) List(ctx context.Context, limit, offset int, sort domain.ExampleSort) ([]*domain.Example, error) {
+	return r.ListFiltered(ctx, ExampleFilter{}, limit, offset, sort)
+}
+
+// ListFiltered retrieves a sorted list of the examples selected by filter with pagination
+func (r *PostgreSQLExampleRepository) ListFiltered(ctx context.Context, filter ExampleFilter, limit, offset int, sort domain.ExampleSort) ([]*domain.Example, error) {
+	order, err := orderClause(sort)
+	if err != nil {
+		return nil, err
+	}
+
 	var examples []domain.Example
 
-	query := r.db.WithContext(ctx).
-		Order(OrderByCreatedAt).
+	query := r.filteredExamples(ctx, filter).
+		Order(order).
 		Limit(limit).
 		Offset(offset)
 
@@ -102,21 +442,65 @@ func (r *PostgreSQLExampleRepository) List(ctx context.Context, limit, offset in
 	return resultExamples, nil
 }
 
+// StreamAll calls fn for every example in ID order, fetching StreamPageSize rows per query
+// with keyset pagination so memory stays bounded however large the table is. fn's error stops the stream.
+func (r *PostgreSQLExampleRepository) StreamAll(ctx context.Context, fn func(*domain.Example) error) error {
+	lastID := ""
+	for {
+		var page []domain.Example
+		query := r.examples(ctx).Order(OrderByID).Limit(StreamPageSize)
+		if lastID != "" {
+			query = query.Where(QueryAfterID, lastID)
+		}
+		if err := handleError(query.Find(&page).Error); err != nil {
+			return err
+		}
+
+		for i := range page {
+			if err := fn(&page[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(page) < StreamPageSize {
+			return nil
+		}
+		lastID = page[len(page)-1].ID
+	}
+}
+
 // Count returns the total number of examples
 func (r *PostgreSQLExampleRepository) Count(ctx context.Context) (int, error) {
+	return r.CountFiltered(ctx, ExampleFilter{})
+}
+
+// CountFiltered returns the number of examples selected by filter
+func (r *PostgreSQLExampleRepository) CountFiltered(ctx context.Context, filter ExampleFilter) (int, error) {
 	var count int64
-	result := r.db.WithContext(ctx).Model(&domain.Example{}).Count(&count)
+	result := r.filteredExamples(ctx, filter).Model(&domain.Example{}).Count(&count)
 	if err := handleError(result.Error); err != nil {
 		return 0, err
 	}
 	return int(count), nil
 }
 
+// filteredExamples starts a query on the examples selected by filter
+func (r *PostgreSQLExampleRepository) filteredExamples(ctx context.Context, filter ExampleFilter) *gorm.DB {
+	query := r.examples(ctx)
+	if !filter.CreatedSince.IsZero() {
+		query = query.Where(QueryCreatedFrom, filter.CreatedSince.UTC())
+	}
+	if !filter.UpdatedSince.IsZero() {
+		query = query.Where(QueryUpdatedFrom, filter.UpdatedSince.UTC())
+	}
+	return query
+}
+
 // ListByAge retrieves examples filtered by age range
 func (r *PostgreSQLExampleRepository) ListByAge(ctx context.Context, minAge, maxAge, limit, offset int) ([]*domain.Example, error) {
 	var examples []domain.Example
 
-	query := r.db.WithContext(ctx).
+	query := r.examples(ctx).
 		Where("age >= ? AND age <= ?", minAge, maxAge).
 		Order(OrderByCreatedAt).
 		Limit(limit).
@@ -136,12 +520,34 @@ func (r *PostgreSQLExampleRepository) ListByAge(ctx context.Context, minAge, max
 	return resultExamples, nil
 }
 
-// Search searches for examples by name (case-insensitive partial match)
+// Search searches for examples by name or email (case-insensitive partial match)
 func (r *PostgreSQLExampleRepository) Search(ctx context.Context, query string, limit, offset int) ([]*domain.Example, error) {
+	return r.SearchAdvanced(ctx, query, DefaultSearchFields, limit, offset)
+}
+
+// SearchAdvanced searches the given fields for query (case-insensitive partial match),
+// returning examples that match in any of them. No fields means DefaultSearchFields.
+func (r *PostgreSQLExampleRepository) SearchAdvanced(ctx context.Context, query string, fields []string, limit, offset int) ([]*domain.Example, error) {
+	if len(fields) == 0 {
+		fields = DefaultSearchFields
+	}
+
+	// Only whitelisted columns are interpolated into the condition
+	conditions := make([]string, len(fields))
+	args := make([]interface{}, len(fields))
+	for i, field := range fields {
+		column, ok := searchableColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot search field %q", ErrInvalidQuery, field)
+		}
+		conditions[i] = "LOWER(" + column + `) LIKE LOWER(?) ESCAPE '\'`
+		args[i] = "%" + escapeLike(query) + "%"
+	}
+
 	var examples []domain.Example
 
-	searchQuery := r.db.WithContext(ctx).
-		Where("LOWER(name) LIKE LOWER(?)", "%"+query+"%").
+	searchQuery := r.examples(ctx).
+		Where(strings.Join(conditions, " OR "), args...).
 		Order(OrderByCreatedAt).
 		Limit(limit).
 		Offset(offset)
@@ -160,13 +566,27 @@ func (r *PostgreSQLExampleRepository) Search(ctx context.Context, query string,
 	return resultExamples, nil
 }
 
-// GetStats returns statistics about examples
-func (r *PostgreSQLExampleRepository) GetStats(ctx context.Context) (*RepositoryStats, error) {
+// likeEscaper escapes the LIKE wildcards and the escape character itself
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike makes s match literally in a LIKE pattern with ESCAPE '\'
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// GetStats returns statistics about examples, counting those created within recentWindow
+// as recent activity
+func (r *PostgreSQLExampleRepository) GetStats(ctx context.Context, recentWindow time.Duration) (*RepositoryStats, error) {
+	since, err := recentSince(recentWindow)
+	if err != nil {
+		return nil, err
+	}
+
 	var stats RepositoryStats
 
 	// Get total count
 	var totalCount int64
-	err := r.db.WithContext(ctx).Model(&domain.Example{}).Count(&totalCount).Error
+	err = r.examples(ctx).Model(&domain.Example{}).Count(&totalCount).Error
 	if err := handleError(err); err != nil {
 		return nil, err
 	}
@@ -175,7 +595,7 @@ func (r *PostgreSQLExampleRepository) GetStats(ctx context.Context) (*Repository
 
 	// Get average age
 	var avgAge *float64
-	err = r.db.WithContext(ctx).Model(&domain.Example{}).Select("AVG(age)").Scan(&avgAge).Error
+	err = r.examples(ctx).Model(&domain.Example{}).Select("AVG(age)").Scan(&avgAge).Error
 	if err := handleError(err); err != nil {
 		return nil, err
 	}
@@ -185,40 +605,31 @@ func (r *PostgreSQLExampleRepository) GetStats(ctx context.Context) (*Repository
 		stats.AverageAge = 0
 	}
 
-	// Get age distribution
-	type AgeGroup struct {
-		AgeRange string
-		Count    int64
-	}
-
-	var ageGroups []AgeGroup
-	err = r.db.WithContext(ctx).Model(&domain.Example{}).
-		Select(`
-			CASE 
-				WHEN age < 18 THEN 'under_18'
-				WHEN age >= 18 AND age < 30 THEN '18_29'
-				WHEN age >= 30 AND age < 50 THEN '30_49'
-				WHEN age >= 50 AND age < 65 THEN '50_64'
-				ELSE '65_plus'
-			END as age_range,
-			COUNT(*) as count
-		`).
-		Group("age_range").
-		Scan(&ageGroups).Error
+	// Get age distribution, bucketing the count of each age as configured
+	type AgeCount struct {
+		Age   int
+		Count int64
+	}
+
+	var ageCounts []AgeCount
+	err = r.examples(ctx).Model(&domain.Example{}).
+		Select("age, COUNT(*) as count").
+		Group("age").
+		Scan(&ageCounts).Error
 	if err := handleError(err); err != nil {
 		return nil, err
 	}
 
-	stats.AgeDistribution = make(map[string]int64)
-	for _, group := range ageGroups {
-		stats.AgeDistribution[group.AgeRange] = group.Count
+	countsByAge := make(map[int]int64, len(ageCounts))
+	for _, ageCount := range ageCounts {
+		countsByAge[ageCount.Age] = ageCount.Count
 	}
+	stats.AgeDistribution = r.stats.ageDistribution(countsByAge)
 
-	// Get recent activity (examples created in last 24 hours)
+	// Get recent activity (examples created within the window)
 	var recentCount int64
-	yesterday := time.Now().Add(-24 * time.Hour)
-	err = r.db.WithContext(ctx).Model(&domain.Example{}).
-		Where("created_at > ?", yesterday).
+	err = r.examples(ctx).Model(&domain.Example{}).
+		Where("created_at > ?", since).
 		Count(&recentCount).Error
 	if err := handleError(err); err != nil {
 		return nil, err
@@ -228,10 +639,34 @@ func (r *PostgreSQLExampleRepository) GetStats(ctx context.Context) (*Repository
 	return &stats, nil
 }
 
-// Transaction executes a function within a database transaction
+// orderClause builds an ORDER BY clause for sort. Only whitelisted columns are
+// interpolated, so a sort can never inject SQL; ties are broken by ID.
+func orderClause(sort domain.ExampleSort) (string, error) {
+	if !sort.Valid() {
+		return "", fmt.Errorf("%w: sort %q", ErrInvalidQuery, sort.String())
+	}
+
+	direction := "ASC"
+	if sort.Descending {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s %s, id ASC", sort.Field, direction), nil
+}
+
+// Transaction executes a function within a database transaction on the primary,
+// so reads inside fn see the transaction's own writes. The repository passed to fn is
+// also an OutboxRepository and records outbox events like r does; fn can turn that off
+// with WithoutOutboxRecording to save the events for its unit of work itself.
 func (r *PostgreSQLExampleRepository) Transaction(ctx context.Context, fn func(ExampleRepository) error) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		txRepo := &PostgreSQLExampleRepository{db: tx}
-		return fn(txRepo)
+	return r.db.WithContext(ctx).Clauses(dbresolver.Write).Transaction(func(tx *gorm.DB) error {
+		return fn(r.withTx(tx))
 	})
 }
+
+// WithoutOutboxRecording returns a repository whose writes don't record outbox events,
+// for callers saving the events themselves
+func (r *PostgreSQLExampleRepository) WithoutOutboxRecording() ExampleRepository {
+	repo := *r
+	repo.outbox = false
+	return &repo
+}
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"testing"
 	"time"
 
@@ -97,6 +100,38 @@ func (suite *PostgreSQLRepositoryTestSuite) TestCreateDuplicateEmail() {
 	assert.Equal(suite.T(), ErrExampleAlreadyExists, err)
 }
 
+// TestCreateDuplicateEmailDifferentCase tests that the lowered email index rejects addresses
+// differing only in case, even when they bypass the domain's normalization
+func (suite *PostgreSQLRepositoryTestSuite) TestCreateDuplicateEmailDifferentCase() {
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, suite.createValidExample()))
+
+	mixedCase := suite.createValidExample()
+	mixedCase.ID = uuid.New().String()
+	mixedCase.Email = "Test@Example.COM"
+
+	err := suite.repository.Create(suite.ctx, mixedCase)
+	assert.Equal(suite.T(), ErrExampleAlreadyExists, err)
+
+	count, err := suite.repository.Count(suite.ctx)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, count)
+}
+
+// TestEmailLookupIgnoresCase tests that GetByEmail and ExistsByEmail match any case
+func (suite *PostgreSQLRepositoryTestSuite) TestEmailLookupIgnoresCase() {
+	example := suite.createValidExample()
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+
+	retrieved, err := suite.repository.GetByEmail(suite.ctx, "TEST@example.com")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), example.ID, retrieved.ID)
+	assert.Equal(suite.T(), "test@example.com", retrieved.Email)
+
+	exists, err := suite.repository.ExistsByEmail(suite.ctx, "Test@Example.Com")
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), exists)
+}
+
 // TestGetByID tests the GetByID method
 func (suite *PostgreSQLRepositoryTestSuite) TestGetByID() {
 	// Test getting non-existent example
@@ -122,6 +157,57 @@ func (suite *PostgreSQLRepositoryTestSuite) TestGetByID() {
 	assert.Contains(suite.T(), err.Error(), "id cannot be empty")
 }
 
+// TestGetByIDs tests the GetByIDs method
+func (suite *PostgreSQLRepositoryTestSuite) TestGetByIDs() {
+	first := suite.createValidExample()
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, first))
+	second, _ := domain.NewExample(uuid.New().String(), "Other User", "other@example.com", 40)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, second))
+
+	// Results follow the requested order, skip missing IDs and collapse duplicates
+	examples, err := suite.repository.GetByIDs(suite.ctx, []string{second.ID, "missing", first.ID, second.ID})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), examples, 2)
+	assert.Equal(suite.T(), second.ID, examples[0].ID)
+	assert.Equal(suite.T(), first.ID, examples[1].ID)
+
+	examples, err = suite.repository.GetByIDs(suite.ctx, []string{"missing"})
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), examples)
+
+	examples, err = suite.repository.GetByIDs(suite.ctx, nil)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), examples)
+}
+
+// TestStreamAll tests that StreamAll visits every row across pages in ID order
+func (suite *PostgreSQLRepositoryTestSuite) TestStreamAll() {
+	total := StreamPageSize + 3
+	for i := 0; i < total; i++ {
+		example, _ := domain.NewExample(fmt.Sprintf("stream-%04d", i), "Stream User", fmt.Sprintf("stream%d@example.com", i), 30)
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+	}
+
+	var ids []string
+	err := suite.repository.StreamAll(suite.ctx, func(example *domain.Example) error {
+		ids = append(ids, example.ID)
+		return nil
+	})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), ids, total)
+	assert.True(suite.T(), sort.StringsAreSorted(ids))
+
+	// fn's error stops the stream
+	stop := errors.New("stop")
+	visited := 0
+	err = suite.repository.StreamAll(suite.ctx, func(*domain.Example) error {
+		visited++
+		return stop
+	})
+	assert.ErrorIs(suite.T(), err, stop)
+	assert.Equal(suite.T(), 1, visited)
+}
+
 // TestGetByEmail tests the GetByEmail method
 func (suite *PostgreSQLRepositoryTestSuite) TestGetByEmail() {
 	// Test getting non-existent example
@@ -147,6 +233,26 @@ func (suite *PostgreSQLRepositoryTestSuite) TestGetByEmail() {
 	assert.Contains(suite.T(), err.Error(), "email cannot be empty")
 }
 
+// TestExistsByEmail tests the ExistsByEmail method
+func (suite *PostgreSQLRepositoryTestSuite) TestExistsByEmail() {
+	exists, err := suite.repository.ExistsByEmail(suite.ctx, "test@example.com")
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), exists)
+
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, suite.createValidExample()))
+
+	exists, err = suite.repository.ExistsByEmail(suite.ctx, "test@example.com")
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), exists)
+
+	// A failed query is an error, not "no such email"
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(suite.T(), err)
+	exists, err = NewPostgreSQLExampleRepository(db).ExistsByEmail(suite.ctx, "test@example.com")
+	assert.Error(suite.T(), err)
+	assert.False(suite.T(), exists)
+}
+
 // TestUpdate tests the Update method
 func (suite *PostgreSQLRepositoryTestSuite) TestUpdate() {
 	// Create an example
@@ -173,6 +279,65 @@ func (suite *PostgreSQLRepositoryTestSuite) TestUpdate() {
 	assert.Equal(suite.T(), ErrExampleNotFound, err)
 }
 
+// TestUpdatePhone tests that a phone number can be set and removed, and that rows without one load
+func (suite *PostgreSQLRepositoryTestSuite) TestUpdatePhone() {
+	example := suite.createValidExample()
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+
+	// Rows written before the phone column existed hold NULL
+	require.NoError(suite.T(), suite.db.Exec("UPDATE examples SET phone = NULL WHERE id = ?", example.ID).Error)
+	retrieved, err := suite.repository.GetByID(suite.ctx, example.ID)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), retrieved.Phone)
+
+	require.NoError(suite.T(), retrieved.SetPhone("+14155552671"))
+	require.NoError(suite.T(), suite.repository.Update(suite.ctx, retrieved))
+	retrieved, err = suite.repository.GetByID(suite.ctx, example.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "+14155552671", retrieved.Phone)
+
+	// Removing the phone number must be saved even though it is a zero value
+	require.NoError(suite.T(), retrieved.SetPhone(""))
+	require.NoError(suite.T(), suite.repository.Update(suite.ctx, retrieved))
+	retrieved, err = suite.repository.GetByID(suite.ctx, example.ID)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), retrieved.Phone)
+}
+
+// TestUpdateStaleVersion tests that an update based on an outdated read is rejected
+func (suite *PostgreSQLRepositoryTestSuite) TestUpdateStaleVersion() {
+	example := suite.createValidExample()
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+	assert.Equal(suite.T(), 1, example.Version)
+
+	// Two writers read the same version
+	first, err := suite.repository.GetByID(suite.ctx, example.ID)
+	require.NoError(suite.T(), err)
+	second, err := suite.repository.GetByID(suite.ctx, example.ID)
+	require.NoError(suite.T(), err)
+
+	// The first write wins and bumps the version
+	first.Name = "First Writer"
+	require.NoError(suite.T(), suite.repository.Update(suite.ctx, first))
+	assert.Equal(suite.T(), 2, first.Version)
+
+	// The second write is based on version 1 and must not clobber the first
+	second.Name = "Second Writer"
+	err = suite.repository.Update(suite.ctx, second)
+	assert.ErrorIs(suite.T(), err, ErrVersionConflict)
+	assert.Equal(suite.T(), 1, second.Version)
+
+	retrieved, err := suite.repository.GetByID(suite.ctx, example.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "First Writer", retrieved.Name)
+	assert.Equal(suite.T(), 2, retrieved.Version)
+
+	// Retrying with the fresh version succeeds
+	retrieved.Name = "Second Writer"
+	require.NoError(suite.T(), suite.repository.Update(suite.ctx, retrieved))
+	assert.Equal(suite.T(), 3, retrieved.Version)
+}
+
 // TestUpdateDuplicateEmail tests updating with duplicate email
 func (suite *PostgreSQLRepositoryTestSuite) TestUpdateDuplicateEmail() {
 	// Create two examples
@@ -193,6 +358,49 @@ func (suite *PostgreSQLRepositoryTestSuite) TestUpdateDuplicateEmail() {
 	assert.Equal(suite.T(), ErrExampleAlreadyExists, err)
 }
 
+// TestUpdateFields tests that UpdateFields writes only the given columns
+func (suite *PostgreSQLRepositoryTestSuite) TestUpdateFields() {
+	example := suite.createValidExample()
+	require.NoError(suite.T(), example.SetPhone("+14155552671"))
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+
+	require.NoError(suite.T(), suite.repository.UpdateFields(suite.ctx, example.ID, 1, map[string]interface{}{ColumnAge: 40}))
+
+	retrieved, err := suite.repository.GetByID(suite.ctx, example.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 40, retrieved.Age)
+	assert.Equal(suite.T(), example.Name, retrieved.Name)
+	assert.Equal(suite.T(), example.Email, retrieved.Email)
+	assert.Equal(suite.T(), example.Phone, retrieved.Phone)
+	assert.Equal(suite.T(), 2, retrieved.Version)
+	assert.WithinDuration(suite.T(), example.CreatedAt, retrieved.CreatedAt, time.Second)
+	assert.False(suite.T(), retrieved.UpdatedAt.Before(example.UpdatedAt))
+
+	// A zero value is written rather than skipped
+	require.NoError(suite.T(), suite.repository.UpdateFields(suite.ctx, example.ID, 2, map[string]interface{}{ColumnPhone: ""}))
+	retrieved, err = suite.repository.GetByID(suite.ctx, example.ID)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), retrieved.Phone)
+	assert.Equal(suite.T(), 40, retrieved.Age)
+
+	for _, column := range []string{"id", "created_at", "version", "deleted_at", "unknown"} {
+		err = suite.repository.UpdateFields(suite.ctx, example.ID, 3, map[string]interface{}{column: "x"})
+		assert.ErrorIs(suite.T(), err, ErrInvalidQuery, column)
+	}
+	assert.ErrorIs(suite.T(), suite.repository.UpdateFields(suite.ctx, example.ID, 3, nil), ErrInvalidQuery)
+
+	err = suite.repository.UpdateFields(suite.ctx, "non-existent-id", 1, map[string]interface{}{ColumnAge: 40})
+	assert.ErrorIs(suite.T(), err, ErrExampleNotFound)
+
+	// A write based on a stale read is rejected and changes nothing
+	err = suite.repository.UpdateFields(suite.ctx, example.ID, 1, map[string]interface{}{ColumnAge: 50})
+	assert.ErrorIs(suite.T(), err, ErrVersionConflict)
+	retrieved, err = suite.repository.GetByID(suite.ctx, example.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 40, retrieved.Age)
+	assert.Equal(suite.T(), 3, retrieved.Version)
+}
+
 // TestDelete tests the Delete method
 func (suite *PostgreSQLRepositoryTestSuite) TestDelete() {
 	// Create an example
@@ -220,10 +428,39 @@ func (suite *PostgreSQLRepositoryTestSuite) TestDelete() {
 	assert.Contains(suite.T(), err.Error(), "id cannot be empty")
 }
 
+// TestDeleteByIDs tests that DeleteByIDs removes the existing examples and skips missing IDs
+func (suite *PostgreSQLRepositoryTestSuite) TestDeleteByIDs() {
+	var ids []string
+	for i := 0; i < 3; i++ {
+		example := suite.createValidExample()
+		example.Email = fmt.Sprintf("test%d@example.com", i)
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+		ids = append(ids, example.ID)
+	}
+
+	deleted, err := suite.repository.DeleteByIDs(suite.ctx, []string{ids[0], "missing", ids[1]})
+	require.NoError(suite.T(), err)
+	assert.ElementsMatch(suite.T(), []string{ids[0], ids[1]}, deleted)
+
+	_, err = suite.repository.GetByID(suite.ctx, ids[0])
+	assert.ErrorIs(suite.T(), err, ErrExampleNotFound)
+	count, err := suite.repository.Count(suite.ctx)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, count)
+
+	deleted, err = suite.repository.DeleteByIDs(suite.ctx, []string{ids[0]})
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), deleted)
+
+	deleted, err = suite.repository.DeleteByIDs(suite.ctx, nil)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), deleted)
+}
+
 // TestList tests the List method
 func (suite *PostgreSQLRepositoryTestSuite) TestList() {
 	// Test empty list
-	examples, err := suite.repository.List(suite.ctx, 10, 0)
+	examples, err := suite.repository.List(suite.ctx, 10, 0, domain.DefaultExampleSort)
 	assert.NoError(suite.T(), err)
 	assert.Empty(suite.T(), examples)
 
@@ -242,24 +479,87 @@ func (suite *PostgreSQLRepositoryTestSuite) TestList() {
 	}
 
 	// Test listing all examples
-	examples, err = suite.repository.List(suite.ctx, 10, 0)
+	examples, err = suite.repository.List(suite.ctx, 10, 0, domain.DefaultExampleSort)
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), examples, exampleCount)
 
 	// Test pagination
-	examples, err = suite.repository.List(suite.ctx, 2, 0)
+	examples, err = suite.repository.List(suite.ctx, 2, 0, domain.DefaultExampleSort)
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), examples, 2)
 
-	examples, err = suite.repository.List(suite.ctx, 2, 2)
+	examples, err = suite.repository.List(suite.ctx, 2, 2, domain.DefaultExampleSort)
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), examples, 2)
 
-	examples, err = suite.repository.List(suite.ctx, 2, 4)
+	examples, err = suite.repository.List(suite.ctx, 2, 4, domain.DefaultExampleSort)
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), examples, 1)
 }
 
+// TestListSorted tests ordering List by each whitelisted field in both directions
+func (suite *PostgreSQLRepositoryTestSuite) TestListSorted() {
+	for i, person := range []struct {
+		name string
+		age  int
+	}{{"Carol", 41}, {"alice", 29}, {"Bob", 35}} {
+		example, err := domain.NewExample(uuid.New().String(), person.name, fmt.Sprintf("sorted%d@example.com", i), person.age)
+		require.NoError(suite.T(), err)
+		example.CreatedAt = time.Now().Add(time.Duration(i) * time.Minute)
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+	}
+
+	assertListOrder(suite.T(), suite.repository, domain.DefaultExampleSort, "Bob", "alice", "Carol")
+	assertListOrder(suite.T(), suite.repository, domain.ExampleSort{Field: domain.SortByCreatedAt}, "Carol", "alice", "Bob")
+	assertListOrder(suite.T(), suite.repository, domain.ExampleSort{Field: domain.SortByName}, "Bob", "Carol", "alice")
+	assertListOrder(suite.T(), suite.repository, domain.ExampleSort{Field: domain.SortByName, Descending: true}, "alice", "Carol", "Bob")
+	assertListOrder(suite.T(), suite.repository, domain.ExampleSort{Field: domain.SortByAge}, "alice", "Bob", "Carol")
+	assertListOrder(suite.T(), suite.repository, domain.ExampleSort{Field: domain.SortByAge, Descending: true}, "Carol", "Bob", "alice")
+
+	// A field outside the whitelist never reaches SQL
+	_, err := suite.repository.List(suite.ctx, 10, 0, domain.ExampleSort{Field: "name; DROP TABLE examples"})
+	assert.ErrorIs(suite.T(), err, ErrInvalidQuery)
+}
+
+// TestListFiltered tests that the since filters keep examples at or after the bound
+func (suite *PostgreSQLRepositoryTestSuite) TestListFiltered() {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, name := range []string{"Old", "Boundary", "New"} {
+		example := suite.createValidExample()
+		example.Name = name
+		example.Email = fmt.Sprintf("user%d@example.com", i)
+		example.CreatedAt = base.Add(time.Duration(i-1) * time.Hour)
+		example.UpdatedAt = base.Add(time.Duration(1-i) * time.Hour)
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+	}
+
+	names := func(filter ExampleFilter) []string {
+		examples, err := suite.repository.ListFiltered(suite.ctx, filter, 10, 0, domain.DefaultExampleSort)
+		require.NoError(suite.T(), err)
+		count, err := suite.repository.CountFiltered(suite.ctx, filter)
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), len(examples), count)
+
+		var names []string
+		for _, example := range examples {
+			names = append(names, example.Name)
+		}
+		return names
+	}
+
+	assert.Equal(suite.T(), []string{"New", "Boundary"}, names(ExampleFilter{CreatedSince: base}))
+	assert.Equal(suite.T(), []string{"New"}, names(ExampleFilter{CreatedSince: base.Add(time.Nanosecond)}))
+	assert.Equal(suite.T(), []string{"Boundary", "Old"}, names(ExampleFilter{UpdatedSince: base}))
+	assert.Equal(suite.T(), []string{"Boundary"}, names(ExampleFilter{CreatedSince: base, UpdatedSince: base}))
+	assert.Len(suite.T(), names(ExampleFilter{}), 3)
+
+	// Pagination applies after filtering
+	examples, err := suite.repository.ListFiltered(suite.ctx, ExampleFilter{CreatedSince: base}, 1, 1, domain.DefaultExampleSort)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), examples, 1)
+	assert.Equal(suite.T(), "Boundary", examples[0].Name)
+}
+
 // TestCount tests the Count method
 func (suite *PostgreSQLRepositoryTestSuite) TestCount() {
 	// Test empty count
@@ -336,10 +636,86 @@ func (suite *PostgreSQLRepositoryTestSuite) TestSearch() {
 	assert.Empty(suite.T(), examples)
 }
 
+// TestSearchByEmail tests that Search also matches on email
+func (suite *PostgreSQLRepositoryTestSuite) TestSearchByEmail() {
+	byName := suite.createValidExample()
+	byName.ID = uuid.New().String()
+	byName.Name = "Acme Fan"
+	byName.Email = "fan@example.com"
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, byName))
+
+	byEmail := suite.createValidExample()
+	byEmail.ID = uuid.New().String()
+	byEmail.Name = "Jane Smith"
+	byEmail.Email = "jane@ACME.io"
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, byEmail))
+
+	// Only the email contains "acme.io"
+	examples, err := suite.repository.Search(suite.ctx, "acme.io", 10, 0)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), examples, 1)
+	assert.Equal(suite.T(), byEmail.ID, examples[0].ID)
+
+	// "acme" matches one by name and the other by email
+	examples, err = suite.repository.Search(suite.ctx, "acme", 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), examples, 2)
+}
+
+// TestSearchAdvanced tests searching only the chosen fields
+func (suite *PostgreSQLRepositoryTestSuite) TestSearchAdvanced() {
+	example := suite.createValidExample()
+	example.Name = "Jane Smith"
+	example.Email = "jane@acme.io"
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+
+	examples, err := suite.repository.SearchAdvanced(suite.ctx, "acme", []string{SearchFieldEmail}, 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), examples, 1)
+
+	examples, err = suite.repository.SearchAdvanced(suite.ctx, "acme", []string{SearchFieldName}, 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), examples)
+
+	// No fields searches the defaults
+	examples, err = suite.repository.SearchAdvanced(suite.ctx, "ACME", nil, 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), examples, 1)
+
+	// Columns outside the whitelist are rejected
+	_, err = suite.repository.SearchAdvanced(suite.ctx, "acme", []string{"name) OR 1=1 --"}, 10, 0)
+	assert.ErrorIs(suite.T(), err, ErrInvalidQuery)
+
+	// LIKE wildcards in the query match literally
+	underscored := suite.createValidExample()
+	underscored.Email = "jane_doe@acme.io"
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, underscored))
+	dotted := suite.createValidExample()
+	dotted.Email = "jane.doe@acme.io"
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, dotted))
+
+	examples, err = suite.repository.SearchAdvanced(suite.ctx, "jane_doe", []string{SearchFieldEmail}, 10, 0)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), examples, 1)
+	assert.Equal(suite.T(), underscored.ID, examples[0].ID)
+
+	for _, query := range []string{"_", "%", `\`} {
+		examples, err = suite.repository.SearchAdvanced(suite.ctx, query, []string{SearchFieldName}, 10, 0)
+		require.NoError(suite.T(), err)
+		assert.Empty(suite.T(), examples, query)
+	}
+}
+
+// TestEscapeLike tests that LIKE wildcards and the escape character are escaped
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\% a\_b c\\d`, escapeLike(`100% a_b c\d`))
+	assert.Equal(t, "plain", escapeLike("plain"))
+}
+
 // TestGetStats tests the GetStats method
 func (suite *PostgreSQLRepositoryTestSuite) TestGetStats() {
 	// Test empty stats
-	stats, err := suite.repository.GetStats(suite.ctx)
+	stats, err := suite.repository.GetStats(suite.ctx, 0)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), int64(0), stats.TotalCount)
 	assert.Equal(suite.T(), float64(0), stats.AverageAge)
@@ -356,11 +732,62 @@ func (suite *PostgreSQLRepositoryTestSuite) TestGetStats() {
 	}
 
 	// Test stats
-	stats, err = suite.repository.GetStats(suite.ctx)
+	stats, err = suite.repository.GetStats(suite.ctx, 0)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), int64(5), stats.TotalCount)
 	assert.Equal(suite.T(), float64(40.4), stats.AverageAge) // (17+25+35+55+70)/5 = 40.4
-	assert.NotNil(suite.T(), stats.AgeDistribution)
+	assert.Equal(suite.T(), map[string]int64{
+		"under_18": 1,
+		"18_29":    1,
+		"30_49":    1,
+		"50_64":    1,
+		"65_plus":  1,
+	}, stats.AgeDistribution)
+	assert.Equal(suite.T(), int64(5), stats.RecentActivity)
+}
+
+// TestGetStatsRecentWindow tests that recent activity counts examples created within the window
+func (suite *PostgreSQLRepositoryTestSuite) TestGetStatsRecentWindow() {
+	for i, age := range []time.Duration{time.Hour, 30 * time.Hour, 60 * time.Hour, 100 * 24 * time.Hour} {
+		example := suite.createValidExample()
+		example.Email = fmt.Sprintf("test%d@example.com", i)
+		example.CreatedAt = time.Now().Add(-age)
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+	}
+
+	for _, tc := range []struct {
+		window time.Duration
+		recent int64
+	}{
+		{window: 0, recent: 1}, // DefaultRecentWindow
+		{window: 48 * time.Hour, recent: 2},
+		{window: 72 * time.Hour, recent: 3},
+		{window: 365 * 24 * time.Hour, recent: 3}, // Capped at MaxRecentWindow
+	} {
+		stats, err := suite.repository.GetStats(suite.ctx, tc.window)
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), tc.recent, stats.RecentActivity, "window %s", tc.window)
+		assert.Equal(suite.T(), int64(4), stats.TotalCount)
+	}
+
+	_, err := suite.repository.GetStats(suite.ctx, -time.Hour)
+	assert.ErrorIs(suite.T(), err, ErrInvalidQuery)
+}
+
+// TestGetStatsCustomAgeBuckets tests that the age distribution uses the configured buckets
+func (suite *PostgreSQLRepositoryTestSuite) TestGetStatsCustomAgeBuckets() {
+	repo := NewPostgreSQLExampleRepository(suite.db, WithStatsConfig(customStatsConfig()))
+	for i, age := range []int{5, 12, 13, 19, 20, 64, 100} {
+		example := suite.createValidExample()
+		example.Email = fmt.Sprintf("test%d@example.com", i)
+		example.Age = age
+		require.NoError(suite.T(), repo.Create(suite.ctx, example))
+	}
+
+	stats, err := repo.GetStats(suite.ctx, 0)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), customStatsDistribution(), stats.AgeDistribution)
+	assert.Equal(suite.T(), int64(7), stats.TotalCount)
 }
 
 // TestTransaction tests the Transaction method
@@ -426,6 +853,29 @@ func (suite *PostgreSQLRepositoryTestSuite) TestDomainStructWithGORM() {
 	assert.Equal(suite.T(), now, example.UpdatedAt)
 }
 
+// TestCancelledContext tests that queries on a cancelled context return ErrQueryCanceled
+func (suite *PostgreSQLRepositoryTestSuite) TestCancelledContext() {
+	example := suite.createValidExample()
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, example))
+
+	ctx, cancel := context.WithCancel(suite.ctx)
+	cancel()
+
+	_, err := suite.repository.GetByID(ctx, example.ID)
+	assert.ErrorIs(suite.T(), err, ErrQueryCanceled)
+	assert.ErrorIs(suite.T(), err, context.Canceled)
+
+	_, err = suite.repository.List(ctx, 10, 0, domain.ExampleSort{Field: domain.SortByName})
+	assert.ErrorIs(suite.T(), err, ErrQueryCanceled)
+
+	ctx, cancel = context.WithDeadline(suite.ctx, time.Now().Add(-time.Second))
+	defer cancel()
+
+	_, err = suite.repository.Count(ctx)
+	assert.ErrorIs(suite.T(), err, ErrQueryDeadline)
+	assert.ErrorIs(suite.T(), err, context.DeadlineExceeded)
+}
+
 // TestIsDuplicateKeyError tests the isDuplicateKeyError function
 func (suite *PostgreSQLRepositoryTestSuite) TestIsDuplicateKeyError() {
 	assert.False(suite.T(), isDuplicateKeyError(nil))
@@ -446,11 +896,308 @@ func (suite *PostgreSQLRepositoryTestSuite) createValidExample() *domain.Example
 	return example
 }
 
+// assertListOrder lists every example with sort and checks the names come back in order
+func assertListOrder(t *testing.T, repo ExampleRepository, sort domain.ExampleSort, names ...string) {
+	t.Helper()
+
+	examples, err := repo.List(context.Background(), 10, 0, sort)
+	require.NoError(t, err)
+
+	got := make([]string, len(examples))
+	for i, example := range examples {
+		got[i] = example.Name
+	}
+	assert.Equal(t, names, got, "sort %s", sort)
+}
+
+// TestInMemoryRepositoryGetByIDs tests that the in-memory repository matches the database lookup
+func TestInMemoryRepositoryGetByIDs(t *testing.T) {
+	repo := NewInMemoryExampleRepository()
+	first, _ := domain.NewExample("first", "First User", "first@example.com", 25)
+	second, _ := domain.NewExample("second", "Second User", "second@example.com", 40)
+	require.NoError(t, repo.Create(context.Background(), first))
+	require.NoError(t, repo.Create(context.Background(), second))
+
+	examples, err := repo.GetByIDs(context.Background(), []string{"second", "missing", "first", "second"})
+	require.NoError(t, err)
+	require.Len(t, examples, 2)
+	assert.Equal(t, "second", examples[0].ID)
+	assert.Equal(t, "first", examples[1].ID)
+
+	// Results are copies
+	examples[0].Name = "Changed"
+	stored, err := repo.GetByID(context.Background(), "second")
+	require.NoError(t, err)
+	assert.Equal(t, "Second User", stored.Name)
+}
+
+// TestInMemoryRepositoryStreamAll tests that the in-memory repository streams copies in ID order
+func TestInMemoryRepositoryStreamAll(t *testing.T) {
+	repo := NewInMemoryExampleRepository()
+	total := StreamPageSize*2 + 1
+	for i := 0; i < total; i++ {
+		example, _ := domain.NewExample(fmt.Sprintf("stream-%04d", i), "Stream User", fmt.Sprintf("stream%d@example.com", i), 30)
+		require.NoError(t, repo.Create(context.Background(), example))
+	}
+
+	var ids []string
+	err := repo.StreamAll(context.Background(), func(example *domain.Example) error {
+		ids = append(ids, example.ID)
+		example.Name = "Changed"
+		// The lock is not held while fn runs, so it may write
+		if example.ID == "stream-0000" {
+			return repo.Delete(context.Background(), fmt.Sprintf("stream-%04d", total-1))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, ids, total-1, "examples deleted while streaming are skipped")
+	assert.True(t, sort.StringsAreSorted(ids))
+
+	stored, err := repo.GetByID(context.Background(), "stream-0001")
+	require.NoError(t, err)
+	assert.Equal(t, "Stream User", stored.Name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, repo.StreamAll(ctx, func(*domain.Example) error { return nil }), context.Canceled)
+}
+
+// TestInMemoryRepositoryListSorted tests that the in-memory repository sorts like the database
+func TestInMemoryRepositoryListSorted(t *testing.T) {
+	repo := NewInMemoryExampleRepository()
+	for i, person := range []struct {
+		name string
+		age  int
+	}{{"Carol", 41}, {"alice", 29}, {"Bob", 35}} {
+		example, err := domain.NewExample(uuid.New().String(), person.name, fmt.Sprintf("sorted%d@example.com", i), person.age)
+		require.NoError(t, err)
+		example.CreatedAt = time.Now().Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.Create(context.Background(), example))
+	}
+
+	assertListOrder(t, repo, domain.DefaultExampleSort, "Bob", "alice", "Carol")
+	assertListOrder(t, repo, domain.ExampleSort{Field: domain.SortByCreatedAt}, "Carol", "alice", "Bob")
+	assertListOrder(t, repo, domain.ExampleSort{Field: domain.SortByName}, "Bob", "Carol", "alice")
+	assertListOrder(t, repo, domain.ExampleSort{Field: domain.SortByName, Descending: true}, "alice", "Carol", "Bob")
+	assertListOrder(t, repo, domain.ExampleSort{Field: domain.SortByAge}, "alice", "Bob", "Carol")
+	assertListOrder(t, repo, domain.ExampleSort{Field: domain.SortByAge, Descending: true}, "Carol", "Bob", "alice")
+
+	_, err := repo.List(context.Background(), 10, 0, domain.ExampleSort{Field: "email"})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
 // TestPostgreSQLRepositoryTestSuite runs the test suite
 func TestPostgreSQLRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(PostgreSQLRepositoryTestSuite))
 }
 
+// TestPostgreSQLRepositoryReadReplicas uses two SQLite files as stand-ins for a
+// primary and a replica, seeding each with a different row so the row a query
+// finds shows which connection served it
+func TestPostgreSQLRepositoryReadReplicas(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	primaryPath := filepath.Join(dir, "primary.db")
+	replicaPath := filepath.Join(dir, "replica.db")
+
+	replicaDB, err := gorm.Open(sqlite.Open(replicaPath), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, replicaDB.AutoMigrate(&domain.Example{}))
+	onReplica, err := domain.NewExample(uuid.New().String(), "Replica User", "replica@example.com", 30)
+	require.NoError(t, err)
+	require.NoError(t, replicaDB.Create(onReplica).Error)
+	sqlDB, err := replicaDB.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	db, err := gorm.Open(sqlite.Open(primaryPath), &gorm.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	require.NoError(t, database.RegisterReplicas(db, sqlite.Open(replicaPath)))
+
+	repo := NewPostgreSQLExampleRepository(db)
+	require.NoError(t, repo.AutoMigrate())
+
+	onPrimary, err := domain.NewExample(uuid.New().String(), "Primary User", "primary@example.com", 40)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, onPrimary))
+
+	t.Run("reads use the replica", func(t *testing.T) {
+		found, err := repo.GetByID(ctx, onReplica.ID)
+		require.NoError(t, err)
+		assert.Equal(t, onReplica.Name, found.Name)
+
+		_, err = repo.GetByID(ctx, onPrimary.ID)
+		assert.ErrorIs(t, err, ErrExampleNotFound)
+
+		found, err = repo.GetByEmail(ctx, onReplica.Email)
+		require.NoError(t, err)
+		assert.Equal(t, onReplica.ID, found.ID)
+
+		list, err := repo.List(ctx, 10, 0, domain.DefaultExampleSort)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, onReplica.ID, list[0].ID)
+
+		results, err := repo.Search(ctx, "User", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, onReplica.ID, results[0].ID)
+
+		count, err := repo.Count(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		stats, err := repo.GetStats(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats.TotalCount)
+		assert.Equal(t, float64(onReplica.Age), stats.AverageAge)
+	})
+
+	t.Run("primary reads use the primary", func(t *testing.T) {
+		primaryCtx := WithPrimaryReads(ctx)
+		found, err := repo.GetByID(primaryCtx, onPrimary.ID)
+		require.NoError(t, err)
+		assert.Equal(t, onPrimary.Name, found.Name)
+
+		exists, err := repo.ExistsByEmail(primaryCtx, onReplica.Email)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("transactions use the primary", func(t *testing.T) {
+		err := repo.Transaction(ctx, func(txRepo ExampleRepository) error {
+			found, err := txRepo.GetByID(ctx, onPrimary.ID)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, onPrimary.Name, found.Name)
+
+			_, err = txRepo.GetByID(ctx, onReplica.ID)
+			assert.ErrorIs(t, err, ErrExampleNotFound)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("update conflicts are resolved against the primary", func(t *testing.T) {
+		stale := *onPrimary
+		stale.Version = onPrimary.Version + 5
+
+		err := repo.Update(ctx, &stale)
+		assert.ErrorIs(t, err, ErrVersionConflict)
+	})
+}
+
+// TestPostgreSQLRepositoryTableNaming checks that a prefixed or schema-qualified repository
+// keeps its rows apart from the default tables on the same database
+func TestPostgreSQLRepositoryTableNaming(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// exerciseCRUD runs every kind of query against repo, leaving one example behind
+	exerciseCRUD := func(t *testing.T, repo *PostgreSQLExampleRepository) {
+		example, err := domain.NewExample(uuid.New().String(), "Tenant User", "tenant@example.com", 30)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, example))
+
+		example.Name = "Tenant Admin"
+		require.NoError(t, repo.Update(ctx, example))
+		found, err := repo.GetByID(ctx, example.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Tenant Admin", found.Name)
+
+		results, err := repo.Search(ctx, "admin", 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+
+		stats, err := repo.GetStats(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats.TotalCount)
+
+		events, err := repo.UnpublishedOutboxEvents(ctx, 10)
+		require.NoError(t, err)
+		assert.Len(t, events, 2)
+
+		deleted, err := domain.NewExample(uuid.New().String(), "Deleted User", "deleted@example.com", 40)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, deleted))
+		require.NoError(t, repo.Delete(ctx, deleted.ID))
+	}
+
+	t.Run("prefix", func(t *testing.T) {
+		db, err := gorm.Open(sqlite.Open(filepath.Join(dir, "prefix.db")), &gorm.Config{})
+		require.NoError(t, err)
+
+		plain := NewPostgreSQLExampleRepository(db)
+		require.NoError(t, plain.AutoMigrate())
+		repo := NewPostgreSQLExampleRepository(db, WithTablePrefix("tenant1_")).WithOutbox()
+		require.NoError(t, repo.AutoMigrate())
+		assert.True(t, db.Migrator().HasTable("tenant1_examples"))
+		assert.True(t, db.Migrator().HasTable("tenant1_outbox_events"))
+
+		exerciseCRUD(t, repo)
+
+		count, err := plain.Count(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, count, "the default table is untouched")
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		// SQLite can't create indexes on attached databases, so the tenant database is migrated on its own
+		tenantPath := filepath.Join(dir, "tenant2.db")
+		tenantDB, err := gorm.Open(sqlite.Open(tenantPath), &gorm.Config{})
+		require.NoError(t, err)
+		require.NoError(t, NewPostgreSQLExampleRepository(tenantDB).AutoMigrate())
+		sqlDB, err := tenantDB.DB()
+		require.NoError(t, err)
+		require.NoError(t, sqlDB.Close())
+
+		db, err := gorm.Open(sqlite.Open(filepath.Join(dir, "main.db")), &gorm.Config{})
+		require.NoError(t, err)
+		sqlDB, err = db.DB()
+		require.NoError(t, err)
+		sqlDB.SetMaxOpenConns(1) // Attached databases belong to a single connection
+		require.NoError(t, db.Exec("ATTACH DATABASE ? AS tenant2", tenantPath).Error)
+
+		repo := NewPostgreSQLExampleRepository(db, WithSchema("tenant2")).WithOutbox()
+		assert.Equal(t, "tenant2.examples", repo.examplesTable)
+		exerciseCRUD(t, repo)
+
+		var count int64
+		require.NoError(t, db.Table("tenant2.examples").Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func TestPostgreSQLRepositoryAutoMigrateCaseDuplicateEmails(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	// Rows written before emails were unique regardless of case
+	require.NoError(t, db.AutoMigrate(&domain.Example{}))
+	for _, email := range []string{"John@Example.com", "john@example.com", "jane@example.com"} {
+		example, err := domain.NewExample(uuid.New().String(), "John Doe", "placeholder@example.com", 30)
+		require.NoError(t, err)
+		example.Email = email
+		require.NoError(t, db.Create(example).Error)
+	}
+
+	err = NewPostgreSQLExampleRepository(db).AutoMigrate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "john@example.com")
+	assert.NotContains(t, err.Error(), "jane@example.com")
+}
+
 // Integration tests that require a real PostgreSQL database
 func TestPostgreSQLIntegration(t *testing.T) {
 	if testing.Short() {
@@ -533,7 +1280,7 @@ func testRealPostgreSQLConnection(t *testing.T, dbURL string) {
 	assert.NoError(t, err)
 
 	// List
-	examples, err := repo.List(ctx, 10, 0)
+	examples, err := repo.List(ctx, 10, 0, domain.DefaultExampleSort)
 	assert.NoError(t, err)
 	assert.Len(t, examples, 1)
 
@@ -585,7 +1332,7 @@ func BenchmarkPostgreSQLRepository(b *testing.B) {
 	}
 
 	b.Run("GetByID", func(b *testing.B) {
-		examples, _ := repo.List(ctx, 100, 0)
+		examples, _ := repo.List(ctx, 100, 0, domain.DefaultExampleSort)
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
 			repo.GetByID(ctx, examples[i%len(examples)].ID)
@@ -595,7 +1342,7 @@ func BenchmarkPostgreSQLRepository(b *testing.B) {
 	b.Run("List", func(b *testing.B) {
 		b.ResetTimer()
 		for i := 0; i < b.N; i++ {
-			repo.List(ctx, 10, 0)
+			repo.List(ctx, 10, 0, domain.DefaultExampleSort)
 		}
 	})
 
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RetryingExternalExampleAPI decorates an ExternalExampleAPI, retrying reads and
+// validation on transient failures with exponential backoff. Notifications and
+// pings are passed through unchanged.
+type RetryingExternalExampleAPI struct {
+	next      ExternalExampleAPI
+	attempts  int           // Retries after the first call
+	baseDelay time.Duration // Wait before the first retry, doubled for each one after
+}
+
+// NewRetryingExternalExampleAPI wraps an external API client so transient failures
+// are retried up to attempts times, waiting baseDelay, 2*baseDelay, 4*baseDelay, ...
+func NewRetryingExternalExampleAPI(next ExternalExampleAPI, attempts int, baseDelay time.Duration) *RetryingExternalExampleAPI {
+	return &RetryingExternalExampleAPI{
+		next:      next,
+		attempts:  attempts,
+		baseDelay: baseDelay,
+	}
+}
+
+// GetExampleData retries the wrapped GetExampleData on transient failures
+func (a *RetryingExternalExampleAPI) GetExampleData(ctx context.Context, exampleID string) (*ExternalExampleData, error) {
+	var data *ExternalExampleData
+	err := a.retry(ctx, func() (err error) {
+		data, err = a.next.GetExampleData(ctx, exampleID)
+		return err
+	})
+	return data, err
+}
+
+// ValidateExample retries the wrapped ValidateExample on transient failures
+func (a *RetryingExternalExampleAPI) ValidateExample(ctx context.Context, name, email string, age int) (bool, error) {
+	var valid bool
+	err := a.retry(ctx, func() (err error) {
+		valid, err = a.next.ValidateExample(ctx, name, email, age)
+		return err
+	})
+	return valid, err
+}
+
+// EnrichExample retries the wrapped EnrichExample on transient failures
+func (a *RetryingExternalExampleAPI) EnrichExample(ctx context.Context, exampleID string) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	err := a.retry(ctx, func() (err error) {
+		data, err = a.next.EnrichExample(ctx, exampleID)
+		return err
+	})
+	return data, err
+}
+
+// NotifyExampleCreated calls the wrapped NotifyExampleCreated once
+func (a *RetryingExternalExampleAPI) NotifyExampleCreated(ctx context.Context, exampleID, email string) error {
+	return a.next.NotifyExampleCreated(ctx, exampleID, email)
+}
+
+// Ping calls the wrapped Ping once so health checks report the current state
+func (a *RetryingExternalExampleAPI) Ping(ctx context.Context) error {
+	return a.next.Ping(ctx)
+}
+
+// retry calls fn until it succeeds, fails with a non-transient error or runs out
+// of attempts. It also stops when ctx would expire before the next attempt, and
+// always returns the error from the last call to fn.
+func (a *RetryingExternalExampleAPI) retry(ctx context.Context, fn func() error) error {
+	delay := a.baseDelay
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= a.attempts || !isTransientExternalError(err) {
+			return err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// isTransientExternalError reports whether a failed external API call is worth retrying
+func isTransientExternalError(err error) bool {
+	return errors.Is(err, ErrExternalAPIUnavailable) || errors.Is(err, ErrExternalAPITimeout)
+}
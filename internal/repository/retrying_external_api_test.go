@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyExternalAPI fails with the queued errors before succeeding
+type flakyExternalAPI struct {
+	MockExternalExampleAPI
+	errs  []error
+	calls int
+}
+
+func (f *flakyExternalAPI) next() error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *flakyExternalAPI) GetExampleData(ctx context.Context, exampleID string) (*ExternalExampleData, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return &ExternalExampleData{ExternalID: "ext_" + exampleID}, nil
+}
+
+func (f *flakyExternalAPI) ValidateExample(ctx context.Context, name, email string, age int) (bool, error) {
+	if err := f.next(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (f *flakyExternalAPI) EnrichExample(ctx context.Context, exampleID string) (map[string]interface{}, error) {
+	if err := f.next(); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"external_id": "ext_" + exampleID}, nil
+}
+
+func (f *flakyExternalAPI) NotifyExampleCreated(ctx context.Context, exampleID, email string) error {
+	return f.next()
+}
+
+func TestRetryingExternalExampleAPI(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries transient failures until success", func(t *testing.T) {
+		api := &flakyExternalAPI{errs: []error{ErrExternalAPIUnavailable, ErrExternalAPITimeout}}
+		retrying := NewRetryingExternalExampleAPI(api, 3, time.Millisecond)
+
+		data, err := retrying.GetExampleData(ctx, "ex_1")
+		require.NoError(t, err)
+		assert.Equal(t, "ext_ex_1", data.ExternalID)
+		assert.Equal(t, 3, api.calls)
+	})
+
+	t.Run("retries validation and enrichment", func(t *testing.T) {
+		api := &flakyExternalAPI{errs: []error{ErrExternalAPIUnavailable, ErrExternalAPIUnavailable}}
+		retrying := NewRetryingExternalExampleAPI(api, 3, time.Millisecond)
+
+		valid, err := retrying.ValidateExample(ctx, "John Doe", "john@example.com", 30)
+		require.NoError(t, err)
+		assert.True(t, valid)
+		assert.Equal(t, 3, api.calls)
+
+		api.errs, api.calls = []error{ErrExternalAPITimeout}, 0
+		enrichment, err := retrying.EnrichExample(ctx, "ex_1")
+		require.NoError(t, err)
+		assert.Equal(t, "ext_ex_1", enrichment["external_id"])
+		assert.Equal(t, 2, api.calls)
+	})
+
+	t.Run("gives up after the configured attempts", func(t *testing.T) {
+		api := &flakyExternalAPI{errs: []error{
+			ErrExternalAPIUnavailable, ErrExternalAPIUnavailable, ErrExternalAPIUnavailable,
+		}}
+		retrying := NewRetryingExternalExampleAPI(api, 2, time.Millisecond)
+
+		_, err := retrying.GetExampleData(ctx, "ex_1")
+		assert.ErrorIs(t, err, ErrExternalAPIUnavailable)
+		assert.Equal(t, 3, api.calls)
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		api := &flakyExternalAPI{errs: []error{ErrInvalidExternalData}}
+		retrying := NewRetryingExternalExampleAPI(api, 3, time.Millisecond)
+
+		_, err := retrying.EnrichExample(ctx, "ex_1")
+		assert.ErrorIs(t, err, ErrInvalidExternalData)
+		assert.Equal(t, 1, api.calls)
+	})
+
+	t.Run("does not retry notifications", func(t *testing.T) {
+		api := &flakyExternalAPI{errs: []error{ErrExternalAPIUnavailable}}
+		retrying := NewRetryingExternalExampleAPI(api, 3, time.Millisecond)
+
+		err := retrying.NotifyExampleCreated(ctx, "ex_1", "john@example.com")
+		assert.ErrorIs(t, err, ErrExternalAPIUnavailable)
+		assert.Equal(t, 1, api.calls)
+	})
+
+	t.Run("stops when the deadline would pass before the next attempt", func(t *testing.T) {
+		api := &flakyExternalAPI{errs: []error{ErrExternalAPIUnavailable, ErrExternalAPIUnavailable}}
+		retrying := NewRetryingExternalExampleAPI(api, 3, time.Second)
+
+		deadlineCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := retrying.GetExampleData(deadlineCtx, "ex_1")
+		assert.ErrorIs(t, err, ErrExternalAPIUnavailable)
+		assert.Equal(t, 1, api.calls)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("backs off exponentially", func(t *testing.T) {
+		api := &flakyExternalAPI{errs: []error{ErrExternalAPIUnavailable, ErrExternalAPIUnavailable}}
+		retrying := NewRetryingExternalExampleAPI(api, 3, 20*time.Millisecond)
+
+		start := time.Now()
+		_, err := retrying.GetExampleData(ctx, "ex_1")
+		require.NoError(t, err)
+		// 20ms before the first retry plus 40ms before the second
+		assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+	})
+}
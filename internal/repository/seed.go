@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"example-api-template/internal/domain"
+)
+
+// SeedExample is one record of a seed file
+type SeedExample struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Age   int    `json:"age"`
+	Phone string `json:"phone,omitempty"`
+}
+
+// NewInMemoryExampleRepositoryWithSeed creates an in-memory example repository holding the given examples
+func NewInMemoryExampleRepositoryWithSeed(examples []*domain.Example, opts ...Option) (*InMemoryExampleRepository, error) {
+	repo := NewInMemoryExampleRepository(opts...)
+	for _, example := range examples {
+		if err := repo.Create(context.Background(), example); err != nil {
+			return nil, fmt.Errorf("seed example %s: %w", example.ID, err)
+		}
+	}
+	return repo, nil
+}
+
+// LoadSeedFile reads a JSON array of examples, validating each record through domain.NewExample
+func LoadSeedFile(path string) ([]*domain.Example, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read seed file: %w", err)
+	}
+
+	var records []SeedExample
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parse seed file %s: %w", path, err)
+	}
+
+	examples := make([]*domain.Example, 0, len(records))
+	for i, record := range records {
+		if record.ID == "" {
+			return nil, fmt.Errorf("seed file %s: record %d: id is required", path, i)
+		}
+
+		example, err := domain.NewExample(record.ID, record.Name, record.Email, record.Age)
+		if err != nil {
+			return nil, fmt.Errorf("seed file %s: record %d (%s): %w", path, i, record.ID, err)
+		}
+		if err := example.SetPhone(record.Phone); err != nil {
+			return nil, fmt.Errorf("seed file %s: record %d (%s): %w", path, i, record.ID, err)
+		}
+		examples = append(examples, example)
+	}
+
+	return examples, nil
+}
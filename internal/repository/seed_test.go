@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"example-api-template/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSeedFile writes content to a seed file in a temporary directory
+func writeSeedFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadSeedFile(t *testing.T) {
+	t.Run("valid file seeds the repository", func(t *testing.T) {
+		path := writeSeedFile(t, `[
+			{"id": "ex_001", "name": "Alice Smith", "email": "alice@example.com", "age": 25},
+			{"id": "ex_002", "name": "Bob Johnson", "email": "bob@example.com", "age": 35, "phone": "+14155552671"}
+		]`)
+
+		examples, err := LoadSeedFile(path)
+		require.NoError(t, err)
+		require.Len(t, examples, 2)
+		assert.Equal(t, "+14155552671", examples[1].Phone)
+
+		repo, err := NewInMemoryExampleRepositoryWithSeed(examples)
+		require.NoError(t, err)
+
+		count, err := repo.Count(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		example, err := repo.GetByEmail(context.Background(), "bob@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "ex_002", example.ID)
+		assert.Equal(t, 1, example.Version)
+	})
+
+	t.Run("malformed JSON is rejected", func(t *testing.T) {
+		path := writeSeedFile(t, `[{"id": "ex_001", "name": "Alice Smith"`)
+
+		_, err := LoadSeedFile(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parse seed file")
+	})
+
+	t.Run("invalid record names the record and field", func(t *testing.T) {
+		path := writeSeedFile(t, `[
+			{"id": "ex_001", "name": "Alice Smith", "email": "alice@example.com", "age": 25},
+			{"id": "ex_002", "name": "Bob Johnson", "email": "not-an-email", "age": 35}
+		]`)
+
+		_, err := LoadSeedFile(path)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		assert.Contains(t, err.Error(), "record 1 (ex_002)")
+		assert.Contains(t, err.Error(), "invalid email format")
+	})
+
+	t.Run("record without an id is rejected", func(t *testing.T) {
+		path := writeSeedFile(t, `[{"name": "Alice Smith", "email": "alice@example.com", "age": 25}]`)
+
+		_, err := LoadSeedFile(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "record 0: id is required")
+	})
+
+	t.Run("missing file is rejected", func(t *testing.T) {
+		_, err := LoadSeedFile(filepath.Join(t.TempDir(), "missing.json"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("duplicate emails fail seeding", func(t *testing.T) {
+		path := writeSeedFile(t, `[
+			{"id": "ex_001", "name": "Alice Smith", "email": "alice@example.com", "age": 25},
+			{"id": "ex_002", "name": "Alice Jones", "email": "alice@example.com", "age": 30}
+		]`)
+
+		examples, err := LoadSeedFile(path)
+		require.NoError(t, err)
+
+		_, err = NewInMemoryExampleRepositoryWithSeed(examples)
+		assert.ErrorIs(t, err, ErrExampleAlreadyExists)
+	})
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"example-api-template/internal/domain"
+)
+
+// AgeBucket is a labelled, inclusive age range counted by GetStats
+type AgeBucket struct {
+	Label string
+	Min   int
+	Max   int
+}
+
+// StatsConfig configures the statistics returned by GetStats
+type StatsConfig struct {
+	// AgeBuckets are matched in order and an age counts towards the first bucket containing it;
+	// ages outside every bucket are left out of the distribution
+	AgeBuckets []AgeBucket
+}
+
+// DefaultStatsConfig returns the age buckets GetStats reported before they became configurable
+func DefaultStatsConfig() StatsConfig {
+	return StatsConfig{
+		AgeBuckets: []AgeBucket{
+			{Label: "under_18", Min: math.MinInt, Max: domain.AdultAge - 1},
+			{Label: "18_29", Min: domain.AdultAge, Max: 29},
+			{Label: "30_49", Min: 30, Max: 49},
+			{Label: "50_64", Min: 50, Max: domain.SeniorAge - 1},
+			{Label: "65_plus", Min: domain.SeniorAge, Max: math.MaxInt},
+		},
+	}
+}
+
+// Validate checks that every bucket has a label and a non-empty range
+func (c StatsConfig) Validate() error {
+	for _, bucket := range c.AgeBuckets {
+		if bucket.Label == "" {
+			return fmt.Errorf("%w: age bucket %d-%d has no label", ErrInvalidQuery, bucket.Min, bucket.Max)
+		}
+		if bucket.Min > bucket.Max {
+			return fmt.Errorf("%w: age bucket %q has min %d above max %d", ErrInvalidQuery, bucket.Label, bucket.Min, bucket.Max)
+		}
+	}
+	return nil
+}
+
+// ageBucket returns the label of the first bucket containing age
+func (c StatsConfig) ageBucket(age int) (string, bool) {
+	for _, bucket := range c.AgeBuckets {
+		if age >= bucket.Min && age <= bucket.Max {
+			return bucket.Label, true
+		}
+	}
+	return "", false
+}
+
+// ageDistribution groups the number of examples of each age into the configured buckets
+func (c StatsConfig) ageDistribution(countsByAge map[int]int64) map[string]int64 {
+	distribution := make(map[string]int64)
+	for age, count := range countsByAge {
+		if label, ok := c.ageBucket(age); ok {
+			distribution[label] += count
+		}
+	}
+	return distribution
+}
+
+// Bounds of the window in which GetStats counts recently created examples
+const (
+	DefaultRecentWindow = 24 * time.Hour
+	MaxRecentWindow     = 90 * 24 * time.Hour
+)
+
+// recentSince returns when the recent activity window ending now starts. A zero window
+// uses DefaultRecentWindow and longer windows than MaxRecentWindow are capped.
+func recentSince(window time.Duration) (time.Time, error) {
+	switch {
+	case window < 0:
+		return time.Time{}, fmt.Errorf("%w: negative recent window %s", ErrInvalidQuery, window)
+	case window == 0:
+		window = DefaultRecentWindow
+	case window > MaxRecentWindow:
+		window = MaxRecentWindow
+	}
+	return time.Now().Add(-window), nil
+}
+
+// Option configures optional repository settings
+type Option func(*options)
+
+// options holds the settings shared by every repository implementation
+type options struct {
+	stats       StatsConfig
+	tablePrefix string // Prepended to table names by the database repository
+	schema      string // Qualifies table names in the database repository; empty uses the search path
+}
+
+// WithStatsConfig replaces DefaultStatsConfig for GetStats; GetStats does not
+// check cfg, so callers validate it once with StatsConfig.Validate
+func WithStatsConfig(cfg StatsConfig) Option {
+	return func(o *options) {
+		o.stats = cfg
+	}
+}
+
+// WithTablePrefix prepends prefix to the tables the database repository uses,
+// e.g. "tenant1_" stores examples in tenant1_examples
+func WithTablePrefix(prefix string) Option {
+	return func(o *options) {
+		o.tablePrefix = prefix
+	}
+}
+
+// WithSchema places the database repository's tables in schema, e.g. tenant1.examples.
+// The schema must already exist.
+func WithSchema(schema string) Option {
+	return func(o *options) {
+		o.schema = schema
+	}
+}
+
+// tableName applies the configured schema and prefix to name
+func (o options) tableName(name string) string {
+	name = o.tablePrefix + name
+	if o.schema != "" {
+		name = o.schema + "." + name
+	}
+	return name
+}
+
+// newOptions applies opts over the defaults
+func newOptions(opts []Option) options {
+	o := options{stats: DefaultStatsConfig()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
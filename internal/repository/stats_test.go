@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// customStatsConfig has overlapping buckets and a gap, so both repositories can be checked
+// against the same expected distribution
+func customStatsConfig() StatsConfig {
+	return StatsConfig{
+		AgeBuckets: []AgeBucket{
+			{Label: "child", Min: 0, Max: 12},
+			{Label: "teen", Min: 13, Max: 19},
+			{Label: "minor", Min: 0, Max: 17}, // Shadowed by child and teen
+			{Label: "adult", Min: 20, Max: 64},
+		},
+	}
+}
+
+// customStatsDistribution is the distribution of ages 5, 12, 13, 19, 20, 64 and 100 under customStatsConfig
+func customStatsDistribution() map[string]int64 {
+	return map[string]int64{
+		"child": 2,
+		"teen":  2,
+		"adult": 2,
+	}
+}
+
+func TestStatsConfigAgeDistribution(t *testing.T) {
+	cfg := customStatsConfig()
+
+	// 100 is outside every bucket and left out
+	distribution := cfg.ageDistribution(map[int]int64{5: 1, 12: 1, 13: 1, 19: 1, 20: 1, 64: 1, 100: 1})
+	assert.Equal(t, customStatsDistribution(), distribution)
+
+	// The defaults cover every age
+	distribution = DefaultStatsConfig().ageDistribution(map[int]int64{0: 1, 17: 2, 18: 3, 64: 4, 65: 5, 150: 6})
+	assert.Equal(t, map[string]int64{"under_18": 3, "18_29": 3, "50_64": 4, "65_plus": 11}, distribution)
+}
+
+func TestStatsConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultStatsConfig().Validate())
+	assert.NoError(t, StatsConfig{}.Validate())
+	assert.ErrorIs(t, StatsConfig{AgeBuckets: []AgeBucket{{Min: 0, Max: 1}}}.Validate(), ErrInvalidQuery)
+	assert.ErrorIs(t, StatsConfig{AgeBuckets: []AgeBucket{{Label: "x", Min: 2, Max: 1}}}.Validate(), ErrInvalidQuery)
+}
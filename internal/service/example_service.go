@@ -4,23 +4,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"example-api-template/internal/domain"
 	"example-api-template/internal/errs"
 	"example-api-template/internal/repository"
+	"example-api-template/pkg/logger"
+	"example-api-template/pkg/tracing"
+	"example-api-template/pkg/validator"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // Constants for validation and business rules
 const (
-	DefaultLimit    = 10
-	MaxLimit        = 100
+	MaxBatchIDs     = 100
 	MinAge          = 0
 	MaxAge          = 150
 	MinNameLen      = 1
 	MaxNameLen      = 100
-	CorporateMinAge = 18
+	CorporateMinAge = domain.AdultAge
 	VIPMinAge       = 21
 )
 
@@ -31,6 +35,8 @@ const (
 	ErrMsgExampleNotFoundLog = "Example not found"
 )
 
+var tracer = tracing.Tracer("example-api-template/internal/service")
+
 var (
 	ErrInvalidInput      = errors.New("invalid input")
 	ErrBusinessLogicFail = errors.New("business logic validation failed")
@@ -38,32 +44,90 @@ var (
 
 // ExampleService defines the interface for example business logic
 type ExampleService interface {
-	CreateExample(ctx context.Context, name, email string, age int) (*domain.Example, error)
+	CreateExample(ctx context.Context, name, email, phone string, age int) (*domain.Example, error)
 	GetExampleByID(ctx context.Context, id string) (*domain.Example, error)
+	GetExamplesByIDs(ctx context.Context, ids []string) ([]*domain.Example, []string, error)
 	GetExampleByEmail(ctx context.Context, email string) (*domain.Example, error)
-	UpdateExample(ctx context.Context, id, name, email string, age int) (*domain.Example, error)
+	UpdateExample(ctx context.Context, id, name, email, phone string, age, expectedVersion int) (*domain.Example, error)
+	PatchExample(ctx context.Context, id string, name, email, phone *string, age *int) (*domain.Example, error)
 	DeleteExample(ctx context.Context, id string) error
-	ListExamples(ctx context.Context, limit, offset int) ([]*domain.Example, int, error)
+	DeleteExamplesByIDs(ctx context.Context, ids []string) ([]*domain.Example, []string, error)
+	ListExamples(ctx context.Context, limit, offset int, sort domain.ExampleSort, filter repository.ExampleFilter) ([]*domain.Example, int, error)
+	ExportExamples(ctx context.Context, fn func(*domain.Example) error) error
 	ValidateExampleBusinessRules(ctx context.Context, name, email string, age int) error
 }
 
+// BusinessRules holds the tunable inputs of ValidateExampleBusinessRules
+type BusinessRules struct {
+	ProfanityWords   []string // Rejected anywhere in a name, case-insensitively
+	CorporateDomains []string // Email domains, including their subdomains, held to CorporateMinAge
+	VIPDomains       []string // Email domains, including their subdomains, held to VIPMinAge
+	CorporateMinAge  int
+	VIPMinAge        int
+}
+
+// DefaultBusinessRules returns the rules the service applied before they became configurable
+func DefaultBusinessRules() BusinessRules {
+	return BusinessRules{
+		ProfanityWords:   []string{"badword1", "badword2"},
+		CorporateDomains: []string{"corp.com", "enterprise.com"},
+		VIPDomains:       []string{"vip.com", "premium.com"},
+		CorporateMinAge:  CorporateMinAge,
+		VIPMinAge:        VIPMinAge,
+	}
+}
+
 // exampleService implements ExampleService
 type exampleService struct {
-	repo   repository.ExampleRepository
-	logger *zap.Logger
+	repo       repository.ExampleRepository
+	logger     *zap.Logger
+	rules      BusinessRules
+	moderator  ContentModerator
+	pagination domain.Pagination
+}
+
+// Option configures optional service settings
+type Option func(*exampleService)
+
+// WithPagination sets the default and max page sizes of ListExamples
+func WithPagination(pagination domain.Pagination) Option {
+	return func(s *exampleService) {
+		s.pagination = pagination
+	}
 }
 
-// NewExampleService creates a new example service
-func NewExampleService(repo repository.ExampleRepository, logger *zap.Logger) ExampleService {
-	return &exampleService{
-		repo:   repo,
-		logger: logger,
+// WithContentModerator checks names with moderator instead of the ProfanityWords of the
+// business rules, e.g. to use an external moderation service
+func WithContentModerator(moderator ContentModerator) Option {
+	return func(s *exampleService) {
+		s.moderator = moderator
 	}
 }
 
+// NewExampleService creates a new example service enforcing the given business rules
+func NewExampleService(repo repository.ExampleRepository, logger *zap.Logger, rules BusinessRules, opts ...Option) ExampleService {
+	rules.CorporateDomains = normalizeWords(rules.CorporateDomains, "@")
+	rules.VIPDomains = normalizeWords(rules.VIPDomains, "@")
+
+	s := &exampleService{
+		repo:       repo,
+		logger:     logger,
+		rules:      rules,
+		moderator:  NewWordListModerator(rules.ProfanityWords),
+		pagination: domain.DefaultPagination,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
 // CreateExample creates a new example with business logic validation
-func (s *exampleService) CreateExample(ctx context.Context, name, email string, age int) (*domain.Example, error) {
-	logger := s.logger.With(
+func (s *exampleService) CreateExample(ctx context.Context, name, email, phone string, age int) (*domain.Example, error) {
+	ctx, span := tracer.Start(ctx, "ExampleService.CreateExample")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, s.logger).With(
 		zap.String("layer", "Service"),
 		zap.String("operation", "CreateExample"),
 		zap.String("email", email),
@@ -72,30 +136,38 @@ func (s *exampleService) CreateExample(ctx context.Context, name, email string,
 	)
 
 	// Input validation
-	if err := s.validateInput(name, email, age); err != nil {
+	if err := s.validateInput(name, email, phone, age); err != nil {
 		return nil, err
 	}
 
 	// Business logic validation
 	if appErr := s.ValidateExampleBusinessRules(ctx, name, email, age); appErr != nil {
 		logger.Error("Business validation failed", zap.Error(appErr))
-		return nil, errs.New(errs.ErrorCodeBusinessLogicFail, appErr, nil)
+		return nil, businessRuleError(appErr)
 	}
 
-	// Generate ID (in real app, might use UUID)
-	id := generateExampleID(name, email)
+	// Generate ID
+	id := generateExampleID()
 
 	// Create domain entity
 	example, err := domain.NewExample(id, name, email, age)
+	if err == nil {
+		err = example.SetPhone(phone)
+	}
 	if err != nil {
 		logger.Error("Failed to create domain entity", zap.Error(err))
-		return nil, errs.New(errs.ErrorCodeInvalidInput, err, nil)
+		return nil, mapDomainError(err)
 	}
 
 	// Check if example with same email already exists
-	if _, err := s.repo.GetByEmail(ctx, email); err == nil {
+	exists, err := s.repo.ExistsByEmail(ctx, email)
+	if err != nil {
+		logger.Error("Failed to check email availability", zap.Error(err))
+		return nil, s.emailCheckError(err, email)
+	}
+	if exists {
 		logger.Error("Example with email already exists", zap.String("email", email))
-		return nil, errs.New(errs.ErrorCodeExampleAlreadyExists, err, map[string]interface{}{
+		return nil, errs.New(errs.ErrorCodeExampleAlreadyExists, fmt.Errorf(repository.ErrTemplateEmail, repository.ErrExampleAlreadyExists, email), map[string]interface{}{
 			"Email": email,
 		})
 	}
@@ -103,7 +175,7 @@ func (s *exampleService) CreateExample(ctx context.Context, name, email string,
 	// Save to repository
 	if err := s.repo.Create(ctx, example); err != nil {
 		logger.Error("Failed to save example", zap.Error(err))
-		if appErr := s.mapRepositoryError(err, "create example", example.ID); appErr != nil {
+		if appErr := s.mapRepositoryError(fmt.Errorf("failed to save example: %w", err), "create example", example.ID); appErr != nil {
 			return nil, appErr
 		}
 		return nil, errs.New(errs.ErrorCodeDatabaseError, err, nil)
@@ -115,7 +187,10 @@ func (s *exampleService) CreateExample(ctx context.Context, name, email string,
 
 // GetExampleByID retrieves an example by ID
 func (s *exampleService) GetExampleByID(ctx context.Context, id string) (*domain.Example, error) {
-	logger := s.logger.With(
+	ctx, span := tracer.Start(ctx, "ExampleService.GetExampleByID")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, s.logger).With(
 		zap.String("operation", "GetExampleByID"),
 		zap.String("id", id),
 	)
@@ -144,9 +219,62 @@ func (s *exampleService) GetExampleByID(ctx context.Context, id string) (*domain
 	return example, nil
 }
 
+// GetExamplesByIDs retrieves the examples with the given IDs in the order requested; IDs that
+// do not exist are returned as notFound instead of failing the call
+func (s *exampleService) GetExamplesByIDs(ctx context.Context, ids []string) ([]*domain.Example, []string, error) {
+	ctx, span := tracer.Start(ctx, "ExampleService.GetExamplesByIDs")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, s.logger).With(
+		zap.String("operation", "GetExamplesByIDs"),
+		zap.Int("ids", len(ids)),
+	)
+
+	if len(ids) > MaxBatchIDs {
+		return nil, nil, errs.New(errs.ErrorCodeInvalidInput, fmt.Errorf("%w: at most %d ids can be requested at once", ErrInvalidInput, MaxBatchIDs), map[string]interface{}{
+			"ids": len(ids),
+		})
+	}
+	for _, id := range ids {
+		if id == "" {
+			return nil, nil, errs.New(errs.ErrorCodeInvalidID, errors.New(ErrMsgIDCannotBeEmpty), nil)
+		}
+	}
+
+	examples, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		logger.Error("Failed to get examples", zap.Error(err))
+		if appErr := s.mapRepositoryError(err, "get examples by IDs", "batch"); appErr != nil {
+			return nil, nil, appErr
+		}
+		return nil, nil, errs.New(errs.ErrorCodeDatabaseError, err, nil)
+	}
+
+	found := make(map[string]bool, len(examples))
+	for _, example := range examples {
+		found[example.ID] = true
+	}
+	notFound := []string{}
+	for _, id := range ids {
+		if !found[id] {
+			found[id] = true // Report each missing ID once
+			notFound = append(notFound, id)
+		}
+	}
+
+	logger.Info("Examples retrieved successfully",
+		zap.Int("found", len(examples)),
+		zap.Int("not_found", len(notFound)),
+	)
+	return examples, notFound, nil
+}
+
 // GetExampleByEmail retrieves an example by email
 func (s *exampleService) GetExampleByEmail(ctx context.Context, email string) (*domain.Example, error) {
-	logger := s.logger.With(
+	ctx, span := tracer.Start(ctx, "ExampleService.GetExampleByEmail")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, s.logger).With(
 		zap.String("operation", "GetExampleByEmail"),
 		zap.String("email", email),
 	)
@@ -175,9 +303,12 @@ func (s *exampleService) GetExampleByEmail(ctx context.Context, email string) (*
 	return example, nil
 }
 
-// UpdateExample updates an existing example
-func (s *exampleService) UpdateExample(ctx context.Context, id, name, email string, age int) (*domain.Example, error) {
-	logger := s.logger.With(
+// UpdateExample updates an existing example; a non-zero expectedVersion must match the stored version
+func (s *exampleService) UpdateExample(ctx context.Context, id, name, email, phone string, age, expectedVersion int) (*domain.Example, error) {
+	ctx, span := tracer.Start(ctx, "ExampleService.UpdateExample")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, s.logger).With(
 		zap.String("operation", "UpdateExample"),
 		zap.String("id", id),
 		zap.String("email", email),
@@ -186,13 +317,13 @@ func (s *exampleService) UpdateExample(ctx context.Context, id, name, email stri
 	logger.Info("Updating example")
 
 	// Input validation
-	if err := s.validateUpdateInput(id, name, email, age); err != nil {
+	if err := s.validateUpdateInput(id, name, email, phone, age); err != nil {
 		return nil, err
 	}
 
 	// Business logic validation
 	if appErr := s.ValidateExampleBusinessRules(ctx, name, email, age); appErr != nil {
-		return nil, errs.New(errs.ErrorCodeBusinessLogicFail, appErr, nil)
+		return nil, businessRuleError(appErr)
 	}
 
 	// Get existing example
@@ -201,26 +332,159 @@ func (s *exampleService) UpdateExample(ctx context.Context, id, name, email stri
 		return nil, err
 	}
 
+	// Reject updates made against an out-of-date copy; the repository re-checks atomically on save
+	if expectedVersion != 0 && example.Version != expectedVersion {
+		logger.Warn("Example version mismatch",
+			zap.Int("expected_version", expectedVersion),
+			zap.Int("current_version", example.Version),
+		)
+		return nil, errs.New(errs.ErrorCodeVersionConflict, repository.ErrVersionConflict, map[string]interface{}{
+			"id":               id,
+			"expected_version": expectedVersion,
+			"current_version":  example.Version,
+		})
+	}
+
 	// Check email conflict
 	if err := s.checkEmailConflict(ctx, example, email, logger); err != nil {
 		return nil, err
 	}
 
 	// Update and save
-	return s.updateAndSaveExample(ctx, example, name, email, age, logger)
+	return s.updateAndSaveExample(ctx, example, name, email, phone, age, logger)
+}
+
+// PatchExample updates only the provided fields of an existing example; nil fields are left unchanged
+func (s *exampleService) PatchExample(ctx context.Context, id string, name, email, phone *string, age *int) (*domain.Example, error) {
+	ctx, span := tracer.Start(ctx, "ExampleService.PatchExample")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, s.logger).With(
+		zap.String("operation", "PatchExample"),
+		zap.String("id", id),
+	)
+
+	logger.Info("Patching example")
+
+	if id == "" {
+		return nil, errs.New(errs.ErrorCodeInvalidID, errors.New(ErrMsgIDCannotBeEmpty), nil)
+	}
+
+	// Input validation of the provided fields only
+	if name != nil {
+		if err := s.validateName(*name); err != nil {
+			return nil, err
+		}
+	}
+	if email != nil {
+		if err := s.validateEmail(*email); err != nil {
+			return nil, err
+		}
+	}
+	if phone != nil {
+		if err := s.validatePhone(*phone); err != nil {
+			return nil, err
+		}
+	}
+	if age != nil {
+		if err := s.validateAge(*age); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get existing example
+	example, err := s.getExistingExample(ctx, id, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty patch is a no-op
+	if name == nil && email == nil && phone == nil && age == nil {
+		logger.Info("Empty patch, example left unchanged")
+		return example, nil
+	}
+
+	// Merge the provided fields over the current values
+	newName, newEmail, newPhone, newAge := example.Name, example.Email, example.Phone, example.Age
+	if name != nil {
+		newName = *name
+	}
+	if email != nil {
+		newEmail = *email
+	}
+	if phone != nil {
+		newPhone = *phone
+	}
+	if age != nil {
+		newAge = *age
+	}
+
+	// Business rules span several fields, so they are checked on the merged result
+	if appErr := s.ValidateExampleBusinessRules(ctx, newName, newEmail, newAge); appErr != nil {
+		return nil, businessRuleError(appErr)
+	}
+
+	// Check email conflict
+	if err := s.checkEmailConflict(ctx, example, newEmail, logger); err != nil {
+		return nil, err
+	}
+
+	// Update the domain entity, then write only the provided fields
+	existing := *example
+	err = example.Update(newName, newEmail, newAge)
+	if err == nil {
+		err = example.SetPhone(newPhone)
+	}
+	if err != nil {
+		logger.Error("Failed to update domain entity", zap.Error(err))
+		return nil, mapDomainError(err)
+	}
+
+	// The patch repeats the current values, so there is nothing to write
+	if existing.Equal(example) {
+		logger.Info("Patch changes nothing, example left unchanged")
+		return &existing, nil
+	}
+
+	fields := map[string]interface{}{repository.ColumnUpdatedAt: example.UpdatedAt}
+	if name != nil {
+		fields[repository.ColumnName] = example.Name
+	}
+	if email != nil {
+		fields[repository.ColumnEmail] = example.Email
+	}
+	if phone != nil {
+		fields[repository.ColumnPhone] = example.Phone
+	}
+	if age != nil {
+		fields[repository.ColumnAge] = example.Age
+	}
+
+	if err := s.repo.UpdateFields(ctx, example.ID, existing.Version, fields); err != nil {
+		logger.Error("Failed to patch example", zap.Error(err))
+		if appErr := s.mapRepositoryError(err, "patch example", example.ID); appErr != nil {
+			return nil, appErr
+		}
+		return nil, errs.New(errs.ErrorCodeDatabaseError, err, nil)
+	}
+	example.Version++
+
+	logger.Info("Example patched successfully")
+	return example, nil
 }
 
 // validateUpdateInput validates input for update operation
-func (s *exampleService) validateUpdateInput(id, name, email string, age int) error {
+func (s *exampleService) validateUpdateInput(id, name, email, phone string, age int) error {
 	if id == "" {
 		return errs.New(errs.ErrorCodeInvalidID, errors.New(ErrMsgIDCannotBeEmpty), nil)
 	}
-	return s.validateInput(name, email, age)
+	return s.validateInput(name, email, phone, age)
 }
 
-// getExistingExample retrieves existing example with error handling
+// getExistingExample retrieves existing example with error handling. It reads from the
+// primary, since the version it returns is checked against the write that follows.
 func (s *exampleService) getExistingExample(ctx context.Context, id string, logger *zap.Logger) (*domain.Example, error) {
-	example, err := s.repo.GetByID(ctx, id)
+	example, err := s.repo.GetByID(repository.WithPrimaryReads(ctx), id)
 	if err != nil {
 		if appErr := s.mapRepositoryError(err, "get existing example for update", id); appErr != nil {
 			if errors.Is(err, repository.ErrExampleNotFound) {
@@ -240,23 +504,44 @@ func (s *exampleService) getExistingExample(ctx context.Context, id string, logg
 
 // checkEmailConflict checks if email is already in use by another example
 func (s *exampleService) checkEmailConflict(ctx context.Context, example *domain.Example, email string, logger *zap.Logger) error {
-	if example.Email != email {
-		if existing, err := s.repo.GetByEmail(ctx, email); err == nil && existing.ID != example.ID {
-			logger.Error("Email already in use by another example", zap.String("email", email))
-			return errs.New(errs.ErrorCodeExampleAlreadyExists, errors.New("email already in use"), map[string]interface{}{
-				"email": email,
-			})
-		}
+	// Emails are unique regardless of case, so keeping the example's own email in another case is no conflict
+	if strings.EqualFold(example.Email, email) {
+		return nil
+	}
+
+	// The example itself has another email, so any match is a different example
+	exists, err := s.repo.ExistsByEmail(ctx, email)
+	if err != nil {
+		logger.Error("Failed to check email availability", zap.Error(err))
+		return s.emailCheckError(err, email)
+	}
+	if exists {
+		logger.Error("Email already in use by another example", zap.String("email", email))
+		return errs.New(errs.ErrorCodeExampleAlreadyExists, fmt.Errorf("email %s is already in use", email), map[string]interface{}{
+			"email": email,
+		})
 	}
 	return nil
 }
 
 // updateAndSaveExample updates domain entity and saves to repository
-func (s *exampleService) updateAndSaveExample(ctx context.Context, example *domain.Example, name, email string, age int, logger *zap.Logger) (*domain.Example, error) {
+func (s *exampleService) updateAndSaveExample(ctx context.Context, example *domain.Example, name, email, phone string, age int, logger *zap.Logger) (*domain.Example, error) {
+	existing := *example
+
 	// Update the domain entity
-	if err := example.Update(name, email, age); err != nil {
+	err := example.Update(name, email, age)
+	if err == nil {
+		err = example.SetPhone(phone)
+	}
+	if err != nil {
 		logger.Error("Failed to update domain entity", zap.Error(err))
-		return nil, errs.New(errs.ErrorCodeInvalidInput, err, nil)
+		return nil, mapDomainError(err)
+	}
+
+	// Nothing changed, so there is nothing to write
+	if existing.Equal(example) {
+		logger.Info("Update changes nothing, example left unchanged")
+		return &existing, nil
 	}
 
 	// Save to repository
@@ -274,7 +559,10 @@ func (s *exampleService) updateAndSaveExample(ctx context.Context, example *doma
 
 // DeleteExample deletes an example by ID
 func (s *exampleService) DeleteExample(ctx context.Context, id string) error {
-	logger := s.logger.With(
+	ctx, span := tracer.Start(ctx, "ExampleService.DeleteExample")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, s.logger).With(
 		zap.String("operation", "DeleteExample"),
 		zap.String("id", id),
 	)
@@ -313,26 +601,100 @@ func (s *exampleService) DeleteExample(ctx context.Context, id string) error {
 	return nil
 }
 
-// ListExamples retrieves a paginated list of examples
-func (s *exampleService) ListExamples(ctx context.Context, limit, offset int) ([]*domain.Example, int, error) {
-	logger := s.logger.With(
+// DeleteExamplesByIDs deletes the examples with the given IDs in one statement, returning the
+// examples it deleted in the order requested; IDs that do not exist, or were deleted by
+// someone else in the meantime, are returned as notFound
+func (s *exampleService) DeleteExamplesByIDs(ctx context.Context, ids []string) ([]*domain.Example, []string, error) {
+	ctx, span := tracer.Start(ctx, "ExampleService.DeleteExamplesByIDs")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, s.logger).With(
+		zap.String("operation", "DeleteExamplesByIDs"),
+		zap.Int("ids", len(ids)),
+	)
+
+	// Load the examples first to tell which IDs are missing
+	examples, notFound, err := s.GetExamplesByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(examples) == 0 {
+		logger.Info("No examples to delete", zap.Int("not_found", len(notFound)))
+		return examples, notFound, nil
+	}
+
+	found := make([]string, len(examples))
+	for i, example := range examples {
+		found[i] = example.ID
+	}
+	deleted, err := s.repo.DeleteByIDs(ctx, found)
+	if err != nil {
+		logger.Error("Failed to delete examples", zap.Error(err))
+		if appErr := s.mapRepositoryError(err, "delete examples by IDs", "batch"); appErr != nil {
+			return nil, nil, appErr
+		}
+		return nil, nil, errs.New(errs.ErrorCodeDatabaseError, err, nil)
+	}
+	if len(deleted) < len(found) {
+		// The rest were deleted by someone else in between, so this request didn't find them
+		logger.Warn("Some examples were deleted concurrently", zap.Int("found", len(found)), zap.Int("deleted", len(deleted)))
+		removed := make(map[string]bool, len(deleted))
+		for _, id := range deleted {
+			removed[id] = true
+		}
+		kept := examples[:0]
+		for _, example := range examples {
+			if removed[example.ID] {
+				kept = append(kept, example)
+			} else {
+				notFound = append(notFound, example.ID)
+			}
+		}
+		examples = kept
+	}
+
+	logger.Info("Examples deleted successfully",
+		zap.Int("deleted", len(examples)),
+		zap.Int("not_found", len(notFound)),
+	)
+	return examples, notFound, nil
+}
+
+// ListExamples retrieves a sorted, paginated list of the examples selected by filter; a zero
+// sort lists the newest first
+func (s *exampleService) ListExamples(ctx context.Context, limit, offset int, sort domain.ExampleSort, filter repository.ExampleFilter) ([]*domain.Example, int, error) {
+	ctx, span := tracer.Start(ctx, "ExampleService.ListExamples")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, s.logger).With(
 		zap.String("operation", "ListExamples"),
 		zap.Int("limit", limit),
 		zap.Int("offset", offset),
+		zap.Stringer("sort", sort),
 	)
 
-	// Validate pagination parameters
-	if limit <= 0 {
-		limit = DefaultLimit
+	if sort == (domain.ExampleSort{}) {
+		sort = domain.DefaultExampleSort
 	}
-	if limit > MaxLimit {
-		limit = MaxLimit
+	if !sort.Valid() {
+		return nil, 0, errs.New(errs.ErrorCodeInvalidRequest, domain.ErrInvalidSort, map[string]string{
+			"sort": "must be one of " + strings.Join(domain.SortValues(), ", "),
+		})
 	}
+
+	// Validate pagination parameters
+	limit = s.pagination.Limit(limit)
 	if offset < 0 {
 		offset = 0
 	}
 
-	examples, err := s.repo.List(ctx, limit, offset)
+	var examples []*domain.Example
+	var err error
+	if filter.IsZero() {
+		examples, err = s.repo.List(ctx, limit, offset, sort)
+	} else {
+		examples, err = s.repo.ListFiltered(ctx, filter, limit, offset, sort)
+	}
 	if err != nil {
 		logger.Error("Failed to list examples", zap.Error(err))
 		if appErr := s.mapRepositoryError(err, "list examples", "pagination"); appErr != nil {
@@ -341,7 +703,12 @@ func (s *exampleService) ListExamples(ctx context.Context, limit, offset int) ([
 		return nil, 0, errs.New(errs.ErrorCodeDatabaseError, err, nil)
 	}
 
-	total, err := s.repo.Count(ctx)
+	var total int
+	if filter.IsZero() {
+		total, err = s.repo.Count(ctx)
+	} else {
+		total, err = s.repo.CountFiltered(ctx, filter)
+	}
 	if err != nil {
 		logger.Error("Failed to count examples", zap.Error(err))
 		if appErr := s.mapRepositoryError(err, "count examples", "pagination"); appErr != nil {
@@ -357,29 +724,77 @@ func (s *exampleService) ListExamples(ctx context.Context, limit, offset int) ([
 	return examples, total, nil
 }
 
+// ExportExamples calls fn for every example in ID order without loading them all at once.
+// An error returned by fn stops the export and is returned unchanged.
+func (s *exampleService) ExportExamples(ctx context.Context, fn func(*domain.Example) error) error {
+	ctx, span := tracer.Start(ctx, "ExampleService.ExportExamples")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, s.logger).With(
+		zap.String("operation", "ExportExamples"),
+	)
+
+	var fnErr error
+	count := 0
+	err := s.repo.StreamAll(ctx, func(example *domain.Example) error {
+		if fnErr = fn(example); fnErr != nil {
+			return fnErr
+		}
+		count++
+		return nil
+	})
+	if fnErr != nil {
+		logger.Warn("Export stopped by consumer", zap.Int("count", count), zap.Error(fnErr))
+		return fnErr
+	}
+	if err != nil {
+		logger.Error("Failed to export examples", zap.Int("count", count), zap.Error(err))
+		if appErr := s.mapRepositoryError(err, "export examples", "export"); appErr != nil {
+			return appErr
+		}
+		return errs.New(errs.ErrorCodeDatabaseError, err, nil)
+	}
+
+	logger.Info("Examples exported successfully", zap.Int("count", count))
+	return nil
+}
+
 // ValidateExampleBusinessRules validates business-specific rules
 func (s *exampleService) ValidateExampleBusinessRules(ctx context.Context, name, email string, age int) error {
 	// Business rule: No profanity in names
-	if containsProfanity(name) {
+	if err := s.moderator.CheckName(ctx, name); err != nil {
+		if !errors.Is(err, ErrContentRejected) {
+			return errs.New(errs.ErrorCodeValidationError, fmt.Errorf("failed to moderate name: %w", err), nil)
+		}
 		return errs.New(errs.ErrorCodeProfanityDetected, errors.New("name contains inappropriate content"), map[string]interface{}{
 			"name": name,
 		})
 	}
 
 	// Business rule: Corporate emails have different age restrictions
-	if isCorporateEmail(email) && age < CorporateMinAge {
-		return errs.New(errs.ErrorCodeCorporateEmailUnderage, errors.New("corporate accounts require minimum age of 18"), map[string]interface{}{
-			"email": email,
-			"age":   age,
-		})
+	if isCorporateEmail(email, s.rules.CorporateDomains) && age < s.rules.CorporateMinAge {
+		return errs.NewWithTemplate(errs.ErrorCodeCorporateEmailUnderage,
+			fmt.Errorf("corporate accounts require minimum age of %d", s.rules.CorporateMinAge),
+			map[string]interface{}{
+				"email":   email,
+				"age":     age,
+				"min_age": s.rules.CorporateMinAge,
+			},
+			map[string]interface{}{"Email": email, "Age": age, "MinAge": s.rules.CorporateMinAge},
+		)
 	}
 
 	// Business rule: VIP domains get special treatment
-	if isVIPDomain(email) && age < VIPMinAge {
-		return errs.New(errs.ErrorCodeVIPDomainUnderage, errors.New("VIP accounts require minimum age of 21"), map[string]interface{}{
-			"email": email,
-			"age":   age,
-		})
+	if isVIPDomain(email, s.rules.VIPDomains) && age < s.rules.VIPMinAge {
+		return errs.NewWithTemplate(errs.ErrorCodeVIPDomainUnderage,
+			fmt.Errorf("VIP accounts require minimum age of %d", s.rules.VIPMinAge),
+			map[string]interface{}{
+				"email":   email,
+				"age":     age,
+				"min_age": s.rules.VIPMinAge,
+			},
+			map[string]interface{}{"Email": email, "Age": age, "MinAge": s.rules.VIPMinAge},
+		)
 	}
 
 	return nil
@@ -387,6 +802,43 @@ func (s *exampleService) ValidateExampleBusinessRules(ctx context.Context, name,
 
 // Helper functions for business logic
 
+// businessRuleError maps a failed ValidateExampleBusinessRules to a business_logic_fail AppError,
+// except for a check that could not run, which is no verdict on the request
+func businessRuleError(err error) *errs.AppError {
+	var appErr *errs.AppError
+	if errors.As(err, &appErr) && appErr.Code == errs.ErrorCodeValidationError {
+		return appErr
+	}
+	return errs.New(errs.ErrorCodeBusinessLogicFail, fmt.Errorf("%w: %w", ErrBusinessLogicFail, err), nil)
+}
+
+// mapDomainError maps a domain validation error to a validation_failed AppError listing each invalid field
+func mapDomainError(err error) *errs.AppError {
+	var verr *domain.ValidationError
+	if !errors.As(err, &verr) {
+		return errs.New(errs.ErrorCodeInvalidInput, err, nil)
+	}
+
+	fields := make([]validator.ValidationFieldErrorDTO, len(verr.Fields))
+	for i, field := range verr.Fields {
+		fields[i] = validator.ValidationFieldErrorDTO{
+			Field:   field.Field,
+			Message: field.Reason,
+		}
+	}
+	return errs.New(errs.ErrorCodeValidationFailed, err, fields)
+}
+
+// emailCheckError maps a failed email availability check, which must not be read as the email being free
+func (s *exampleService) emailCheckError(err error, email string) *errs.AppError {
+	if appErr := s.mapRepositoryError(err, "check email availability", email); appErr != nil {
+		return appErr
+	}
+	return errs.New(errs.ErrorCodeDatabaseError, err, map[string]interface{}{
+		"email": email,
+	})
+}
+
 // mapRepositoryError maps repository errors to AppError
 func (s *exampleService) mapRepositoryError(err error, operation string, resourceID string) *errs.AppError {
 	if err == nil {
@@ -405,12 +857,27 @@ func (s *exampleService) mapRepositoryError(err error, operation string, resourc
 			"resource_id": resourceID,
 			"operation":   operation,
 		})
+	case errors.Is(err, repository.ErrVersionConflict):
+		return errs.New(errs.ErrorCodeVersionConflict, err, map[string]interface{}{
+			"resource_id": resourceID,
+			"operation":   operation,
+		})
 	case errors.Is(err, repository.ErrDatabaseConnection):
 		return errs.New(errs.ErrorCodeDatabaseError, err, map[string]interface{}{
 			"resource_id": resourceID,
 			"operation":   operation,
 			"error_type":  "connection",
 		})
+	case errors.Is(err, repository.ErrQueryDeadline):
+		return errs.New(errs.ErrorCodeRequestTimeout, err, map[string]interface{}{
+			"resource_id": resourceID,
+			"operation":   operation,
+		})
+	case errors.Is(err, repository.ErrQueryCanceled):
+		return errs.New(errs.ErrorCodeRequestCanceled, err, map[string]interface{}{
+			"resource_id": resourceID,
+			"operation":   operation,
+		})
 	case errors.Is(err, repository.ErrQueryTimeout):
 		return errs.New(errs.ErrorCodeDatabaseError, err, map[string]interface{}{
 			"resource_id": resourceID,
@@ -428,96 +895,121 @@ func (s *exampleService) mapRepositoryError(err error, operation string, resourc
 }
 
 // validateInput validates basic input parameters
-func (s *exampleService) validateInput(name, email string, age int) error {
-	// Validate name
+func (s *exampleService) validateInput(name, email, phone string, age int) error {
+	if err := s.validateName(name); err != nil {
+		return err
+	}
+	if err := s.validateEmail(email); err != nil {
+		return err
+	}
+	if err := s.validatePhone(phone); err != nil {
+		return err
+	}
+	return s.validateAge(age)
+}
+
+// validateName validates the example name
+func (s *exampleService) validateName(name string) error {
 	if name == "" {
-		return errs.New(errs.ErrorCodeInvalidName, errors.New("name cannot be empty"), nil)
+		return errs.New(errs.ErrorCodeInvalidName, fmt.Errorf("%w: name cannot be empty", ErrInvalidInput), nil)
 	}
 	if len(name) < MinNameLen || len(name) > MaxNameLen {
-		return errs.New(errs.ErrorCodeInvalidName, errors.New("name length must be between 1 and 100 characters"), map[string]interface{}{
+		return errs.New(errs.ErrorCodeInvalidName, fmt.Errorf("%w: name length must be between 1 and 100 characters", ErrInvalidInput), map[string]interface{}{
 			"name":   name,
 			"length": len(name),
 		})
 	}
+	return nil
+}
 
-	// Validate email
+// validateEmail validates the example email
+func (s *exampleService) validateEmail(email string) error {
 	if email == "" {
-		return errs.New(errs.ErrorCodeInvalidEmail, errors.New("email cannot be empty"), nil)
+		return errs.New(errs.ErrorCodeInvalidEmail, fmt.Errorf("%w: email cannot be empty", ErrInvalidInput), nil)
 	}
-	// Basic email format validation
-	if !isValidEmailFormat(email) {
-		return errs.New(errs.ErrorCodeInvalidEmail, errors.New("invalid email format"), map[string]interface{}{
+	// Email format validation shared with the domain and HTTP layers
+	if !validator.IsValidEmail(email) {
+		return errs.New(errs.ErrorCodeInvalidEmail, fmt.Errorf("%w: invalid email format", ErrInvalidInput), map[string]interface{}{
 			"email": email,
 		})
 	}
+	return nil
+}
 
-	// Validate age
+// validateAge validates the example age
+func (s *exampleService) validateAge(age int) error {
 	if age < MinAge || age > MaxAge {
-		return errs.New(errs.ErrorCodeInvalidAge, errors.New("age must be between 0 and 150"), map[string]interface{}{
+		return errs.New(errs.ErrorCodeInvalidAge, fmt.Errorf("%w: age must be between 0 and 150", ErrInvalidInput), map[string]interface{}{
 			"age": age,
 		})
 	}
-
 	return nil
 }
 
-// isValidEmailFormat performs basic email format validation
-func isValidEmailFormat(email string) bool {
-	// Basic email validation - check for @ and . in appropriate positions
-	if len(email) < 5 { // minimum: a@b.c
-		return false
-	}
-
-	atIndex := -1
-	dotIndex := -1
-
-	for i, char := range email {
-		if char == '@' {
-			if atIndex != -1 { // Multiple @ symbols
-				return false
-			}
-			atIndex = i
-		} else if char == '.' {
-			dotIndex = i
-		}
+// validatePhone validates the optional example phone number
+func (s *exampleService) validatePhone(phone string) error {
+	// Phone format validation shared with the domain and HTTP layers
+	if phone != "" && !validator.IsValidPhone(phone) {
+		return errs.New(errs.ErrorCodeInvalidPhone, fmt.Errorf("%w: phone must be in E.164 format", ErrInvalidInput), map[string]interface{}{
+			"phone": phone,
+		})
 	}
-
-	// Must have @ and . and @ must come before .
-	return atIndex > 0 && dotIndex > atIndex+1 && dotIndex < len(email)-1
+	return nil
 }
 
-func generateExampleID(name, email string) string {
-	// Simple ID generation - in real app, use UUID or similar
-	return fmt.Sprintf("ex_%s_%d", email[:3], len(name))
+// exampleIDPrefix marks example IDs so they are recognisable in logs and events
+const exampleIDPrefix = "ex_"
+
+// generateExampleID returns a new random example ID
+func generateExampleID() string {
+	return exampleIDPrefix + uuid.NewString()
 }
 
-func containsProfanity(name string) bool {
-	// Simple profanity check - in real app, use proper filter
-	profanity := []string{"badword1", "badword2"}
-	for _, word := range profanity {
-		if name == word {
+// containsProfanity reports whether any of words appears in name; words must already be lower case
+func containsProfanity(name string, words []string) bool {
+	lowered := strings.ToLower(name)
+	for _, word := range words {
+		if strings.Contains(lowered, word) {
 			return true
 		}
 	}
 	return false
 }
 
-func isCorporateEmail(email string) bool {
-	// Check if email belongs to corporate domain
-	corporateDomains := []string{"@corp.com", "@enterprise.com"}
-	for _, domain := range corporateDomains {
-		if len(email) >= len(domain) && email[len(email)-len(domain):] == domain {
-			return true
+// normalizeWords lower-cases and trims configured entries, strips prefix, and drops empty
+// entries that would otherwise match everything
+func normalizeWords(words []string, prefix string) []string {
+	normalized := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(word)), prefix)
+		if word != "" {
+			normalized = append(normalized, word)
 		}
 	}
-	return false
+	return normalized
 }
 
-func isVIPDomain(email string) bool {
-	// Check if email belongs to VIP domain
-	vipDomains := []string{"@vip.com", "@premium.com"}
-	for _, domain := range vipDomains {
-		if len(email) >= len(domain) && email[len(email)-len(domain):] == domain {
+// isCorporateEmail reports whether email belongs to one of the corporate domains
+func isCorporateEmail(email string, corporateDomains []string) bool {
+	return hasEmailDomain(email, corporateDomains)
+}
+
+// isVIPDomain reports whether email belongs to one of the VIP domains
+func isVIPDomain(email string, vipDomains []string) bool {
+	return hasEmailDomain(email, vipDomains)
+}
+
+// hasEmailDomain reports whether the domain of email equals, or is a subdomain of, one of
+// domains; domains must already be lower case without a leading "@"
+func hasEmailDomain(email string, domains []string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	emailDomain := strings.ToLower(email[at+1:])
+
+	for _, domain := range domains {
+		if emailDomain == domain || strings.HasSuffix(emailDomain, "."+domain) {
 			return true
 		}
 	}
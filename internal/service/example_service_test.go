@@ -2,13 +2,19 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"example-api-template/internal/domain"
+	"example-api-template/internal/errs"
 	"example-api-template/internal/repository"
+	"example-api-template/pkg/validator"
 	"example-api-template/tests/mocks"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -51,7 +57,7 @@ func TestNewExampleService(t *testing.T) {
 	mockRepo := &mocks.MockExampleRepository{}
 	logger := zap.NewNop()
 
-	service := NewExampleService(mockRepo, logger)
+	service := NewExampleService(mockRepo, logger, DefaultBusinessRules())
 
 	assert.NotNil(t, service)
 }
@@ -72,9 +78,8 @@ func TestExampleService_CreateExample(t *testing.T) {
 			inputEmail: "john@example.com",
 			inputAge:   30,
 			setupMock: func(m *mocks.MockExampleRepository) {
-				// GetByEmail should return not found (email not exists)
-				m.On("GetByEmail", mock.Anything, "john@example.com").
-					Return(nil, repository.ErrExampleNotFound)
+				// The email is not taken yet
+				m.On("ExistsByEmail", mock.Anything, "john@example.com").Return(false, nil)
 				// Create should succeed
 				m.On("Create", mock.Anything, mock.AnythingOfType("*domain.Example")).
 					Return(nil)
@@ -87,13 +92,34 @@ func TestExampleService_CreateExample(t *testing.T) {
 			inputEmail: "existing@example.com",
 			inputAge:   30,
 			setupMock: func(m *mocks.MockExampleRepository) {
-				existingExample := validExampleWithCustomData("existing-id", "Existing User", "existing@example.com", 25)
-				m.On("GetByEmail", mock.Anything, "existing@example.com").
-					Return(existingExample, nil)
+				m.On("ExistsByEmail", mock.Anything, "existing@example.com").Return(true, nil)
 			},
 			wantErr:     true,
 			errContains: "already exists",
 		},
+		{
+			name:       "successful creation with tagged multi-level domain email",
+			inputName:  "John Doe",
+			inputEmail: "user.name+tag@sub.example.co.uk",
+			inputAge:   30,
+			setupMock: func(m *mocks.MockExampleRepository) {
+				m.On("ExistsByEmail", mock.Anything, "user.name+tag@sub.example.co.uk").Return(false, nil)
+				m.On("Create", mock.Anything, mock.AnythingOfType("*domain.Example")).
+					Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:       "invalid email with double @",
+			inputName:  "John Doe",
+			inputEmail: "user@@x.com",
+			inputAge:   30,
+			setupMock: func(m *mocks.MockExampleRepository) {
+				// No mock calls expected as validation should fail first
+			},
+			wantErr:     true,
+			errContains: "invalid input",
+		},
 		{
 			name:       "invalid email format",
 			inputName:  "John Doe",
@@ -122,26 +148,37 @@ func TestExampleService_CreateExample(t *testing.T) {
 			inputEmail: "john@example.com",
 			inputAge:   30,
 			setupMock: func(m *mocks.MockExampleRepository) {
-				m.On("GetByEmail", mock.Anything, "john@example.com").
-					Return(nil, repository.ErrExampleNotFound)
+				m.On("ExistsByEmail", mock.Anything, "john@example.com").Return(false, nil)
 				m.On("Create", mock.Anything, mock.AnythingOfType("*domain.Example")).
 					Return(repository.ErrExampleAlreadyExists)
 			},
 			wantErr:     true,
 			errContains: "failed to save example",
 		},
+		{
+			name:       "email check fails",
+			inputName:  "John Doe",
+			inputEmail: "john@example.com",
+			inputAge:   30,
+			setupMock: func(m *mocks.MockExampleRepository) {
+				// A database error must not be read as the email being free, so Create is never called
+				m.On("ExistsByEmail", mock.Anything, "john@example.com").Return(false, repository.ErrDatabaseConnection)
+			},
+			wantErr:     true,
+			errContains: "database connection error",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mocks.MockExampleRepository{}
 			logger := zap.NewNop()
-			service := NewExampleService(mockRepo, logger)
+			service := NewExampleService(mockRepo, logger, DefaultBusinessRules())
 
 			tt.setupMock(mockRepo)
 
 			ctx := getTestContext()
-			result, err := service.CreateExample(ctx, tt.inputName, tt.inputEmail, tt.inputAge)
+			result, err := service.CreateExample(ctx, tt.inputName, tt.inputEmail, "", tt.inputAge)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -160,6 +197,49 @@ func TestExampleService_CreateExample(t *testing.T) {
 	}
 }
 
+func TestExampleService_CreateExample_EmailCheckErrorAborts(t *testing.T) {
+	mockRepo := &mocks.MockExampleRepository{}
+	service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+	mockRepo.On("ExistsByEmail", mock.Anything, "john@example.com").Return(false, repository.ErrQueryTimeout)
+
+	result, err := service.CreateExample(getTestContext(), "John Doe", "john@example.com", "", 30)
+	assert.Nil(t, result)
+
+	// A timeout says nothing about whether the email is taken, so creation must stop there
+	var appErr *errs.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, errs.ErrorCodeDatabaseError, appErr.Code)
+	assert.ErrorIs(t, err, repository.ErrQueryTimeout)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExampleService_ContextErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoErr  error
+		wantCode errs.ErrorCode
+	}{
+		{"deadline exceeded", fmt.Errorf("%w: %w", repository.ErrQueryDeadline, context.DeadlineExceeded), errs.ErrorCodeRequestTimeout},
+		{"canceled", fmt.Errorf("%w: %w", repository.ErrQueryCanceled, context.Canceled), errs.ErrorCodeRequestCanceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.MockExampleRepository{}
+			service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+			mockRepo.On("GetByID", mock.Anything, "test-id").Return(nil, tt.repoErr)
+
+			_, err := service.GetExampleByID(getTestContext(), "test-id")
+
+			var appErr *errs.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.wantCode, appErr.Code)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestExampleService_GetExampleByID(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -202,7 +282,7 @@ func TestExampleService_GetExampleByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mocks.MockExampleRepository{}
 			logger := zap.NewNop()
-			service := NewExampleService(mockRepo, logger)
+			service := NewExampleService(mockRepo, logger, DefaultBusinessRules())
 
 			tt.setupMock(mockRepo)
 
@@ -224,6 +304,48 @@ func TestExampleService_GetExampleByID(t *testing.T) {
 	}
 }
 
+func TestExampleService_GetExamplesByIDs(t *testing.T) {
+	t.Run("mixed found and not found", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+
+		ids := []string{"a", "missing", "b", "missing"}
+		found := []*domain.Example{
+			validExampleWithCustomData("a", "John Doe", "john@example.com", 30),
+			validExampleWithCustomData("b", "Jane Doe", "jane@example.com", 28),
+		}
+		mockRepo.On("GetByIDs", mock.Anything, ids).Return(found, nil)
+
+		examples, notFound, err := service.GetExamplesByIDs(getTestContext(), ids)
+		require.NoError(t, err)
+		assert.Equal(t, found, examples)
+		assert.Equal(t, []string{"missing"}, notFound)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("empty ID", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+
+		_, _, err := service.GetExamplesByIDs(getTestContext(), []string{"a", ""})
+		assert.ErrorContains(t, err, "id cannot be empty")
+		mockRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("too many IDs", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+
+		ids := make([]string, MaxBatchIDs+1)
+		for i := range ids {
+			ids[i] = uuid.NewString()
+		}
+		_, _, err := service.GetExamplesByIDs(getTestContext(), ids)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		mockRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+	})
+}
+
 func TestExampleService_UpdateExample(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -231,6 +353,7 @@ func TestExampleService_UpdateExample(t *testing.T) {
 		inputName   string
 		inputEmail  string
 		inputAge    int
+		version     int
 		setupMock   func(*mocks.MockExampleRepository)
 		wantErr     bool
 		errContains string
@@ -244,12 +367,23 @@ func TestExampleService_UpdateExample(t *testing.T) {
 			setupMock: func(m *mocks.MockExampleRepository) {
 				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
 				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
-				m.On("GetByEmail", mock.Anything, "updated@example.com").
-					Return(nil, repository.ErrExampleNotFound) // Email not in use
+				m.On("ExistsByEmail", mock.Anything, "updated@example.com").Return(false, nil) // Email not in use
 				m.On("Update", mock.Anything, mock.AnythingOfType("*domain.Example")).Return(nil)
 			},
 			wantErr: false,
 		},
+		{
+			name:       "unchanged values skip the write",
+			inputID:    "test-id",
+			inputName:  "Original Name",
+			inputEmail: "original@example.com",
+			inputAge:   30,
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+			},
+			wantErr: false,
+		},
 		{
 			name:       "example not found",
 			inputID:    "non-existent",
@@ -271,30 +405,91 @@ func TestExampleService_UpdateExample(t *testing.T) {
 			inputAge:   35,
 			setupMock: func(m *mocks.MockExampleRepository) {
 				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
-				other := validExampleWithCustomData("other-id", "Other User", "taken@example.com", 25)
 				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
-				m.On("GetByEmail", mock.Anything, "taken@example.com").Return(other, nil)
+				m.On("ExistsByEmail", mock.Anything, "taken@example.com").Return(true, nil)
 			},
 			wantErr:     true,
 			errContains: "email taken@example.com is already in use",
 		},
+		{
+			name:       "email check fails",
+			inputID:    "test-id",
+			inputName:  "Updated Name",
+			inputEmail: "updated@example.com",
+			inputAge:   35,
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+				m.On("ExistsByEmail", mock.Anything, "updated@example.com").Return(false, repository.ErrQueryTimeout)
+			},
+			wantErr:     true,
+			errContains: "query timeout",
+		},
+		{
+			name:       "matching expected version",
+			inputID:    "test-id",
+			inputName:  "Updated Name",
+			inputEmail: "original@example.com",
+			inputAge:   35,
+			version:    1,
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+				m.On("Update", mock.Anything, mock.MatchedBy(func(e *domain.Example) bool { return e.Version == 1 })).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:       "stale expected version",
+			inputID:    "test-id",
+			inputName:  "Updated Name",
+			inputEmail: "original@example.com",
+			inputAge:   35,
+			version:    2,
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+			},
+			wantErr:     true,
+			errContains: "modified concurrently",
+		},
+		{
+			name:       "concurrent write detected on save",
+			inputID:    "test-id",
+			inputName:  "Updated Name",
+			inputEmail: "original@example.com",
+			inputAge:   35,
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+				m.On("Update", mock.Anything, mock.AnythingOfType("*domain.Example")).
+					Return(repository.ErrVersionConflict)
+			},
+			wantErr:     true,
+			errContains: "modified concurrently",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mocks.MockExampleRepository{}
 			logger := zap.NewNop()
-			service := NewExampleService(mockRepo, logger)
+			service := NewExampleService(mockRepo, logger, DefaultBusinessRules())
 
 			tt.setupMock(mockRepo)
 
 			ctx := getTestContext()
-			result, err := service.UpdateExample(ctx, tt.inputID, tt.inputName, tt.inputEmail, tt.inputAge)
+			result, err := service.UpdateExample(ctx, tt.inputID, tt.inputName, tt.inputEmail, "", tt.inputAge, tt.version)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errContains)
 				assert.Nil(t, result)
+				if tt.errContains == "modified concurrently" {
+					var appErr *errs.AppError
+					require.ErrorAs(t, err, &appErr)
+					assert.Equal(t, errs.ErrorCodeVersionConflict, appErr.Code)
+				}
 			} else {
 				assert.NoError(t, err)
 				require.NotNil(t, result)
@@ -309,6 +504,153 @@ func TestExampleService_UpdateExample(t *testing.T) {
 	}
 }
 
+func TestExampleService_PatchExample(t *testing.T) {
+	stringPtr := func(v string) *string { return &v }
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name        string
+		inputName   *string
+		inputEmail  *string
+		inputAge    *int
+		setupMock   func(*mocks.MockExampleRepository)
+		wantErr     bool
+		errContains string
+		wantName    string
+		wantEmail   string
+		wantAge     int
+	}{
+		{
+			name:     "only age",
+			inputAge: intPtr(31),
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+				m.On("UpdateFields", mock.Anything, "test-id", 1, mock.MatchedBy(func(fields map[string]interface{}) bool {
+					return len(fields) == 2 && fields["age"] == 31 && fields["updated_at"] != nil
+				})).Return(nil)
+			},
+			wantName:  "Original Name",
+			wantEmail: "original@example.com",
+			wantAge:   31,
+		},
+		{
+			name:      "only name",
+			inputName: stringPtr("Renamed"),
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+				m.On("UpdateFields", mock.Anything, "test-id", 1, mock.MatchedBy(func(fields map[string]interface{}) bool {
+					return len(fields) == 2 && fields["name"] == "Renamed"
+				})).Return(nil)
+			},
+			wantName:  "Renamed",
+			wantEmail: "original@example.com",
+			wantAge:   30,
+		},
+		{
+			name: "empty patch is a no-op",
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+			},
+			wantName:  "Original Name",
+			wantEmail: "original@example.com",
+			wantAge:   30,
+		},
+		{
+			name:       "patch repeating current values is a no-op",
+			inputName:  stringPtr("Original Name"),
+			inputEmail: stringPtr("original@example.com"),
+			inputAge:   intPtr(30),
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+			},
+			wantName:  "Original Name",
+			wantEmail: "original@example.com",
+			wantAge:   30,
+		},
+		{
+			name:       "own email in another case is no conflict",
+			inputEmail: stringPtr("Original@Example.com"),
+			inputAge:   intPtr(31),
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+				m.On("UpdateFields", mock.Anything, "test-id", 1, mock.Anything).Return(nil)
+			},
+			wantName:  "Original Name",
+			wantEmail: "original@example.com",
+			wantAge:   31,
+		},
+		{
+			name:       "email already in use by another example",
+			inputEmail: stringPtr("taken@example.com"),
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+				m.On("ExistsByEmail", mock.Anything, "taken@example.com").Return(true, nil)
+			},
+			wantErr:     true,
+			errContains: "email taken@example.com is already in use",
+		},
+		{
+			name:     "concurrent write detected on patch",
+			inputAge: intPtr(31),
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@example.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+				m.On("UpdateFields", mock.Anything, "test-id", 1, mock.Anything).Return(repository.ErrVersionConflict)
+			},
+			wantErr:     true,
+			errContains: "modified concurrently",
+		},
+		{
+			name:        "invalid provided field",
+			inputAge:    intPtr(200),
+			setupMock:   func(m *mocks.MockExampleRepository) {},
+			wantErr:     true,
+			errContains: "age must be between 0 and 150",
+		},
+		{
+			name:     "business rules apply to merged values",
+			inputAge: intPtr(16),
+			setupMock: func(m *mocks.MockExampleRepository) {
+				existing := validExampleWithCustomData("test-id", "Original Name", "original@corp.com", 30)
+				m.On("GetByID", mock.Anything, "test-id").Return(existing, nil)
+			},
+			wantErr:     true,
+			errContains: "corporate accounts require minimum age of 18",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mocks.MockExampleRepository{}
+			service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+
+			tt.setupMock(mockRepo)
+
+			result, err := service.PatchExample(getTestContext(), "test-id", tt.inputName, tt.inputEmail, nil, tt.inputAge)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				require.NotNil(t, result)
+				assert.Equal(t, tt.wantName, result.Name)
+				assert.Equal(t, tt.wantEmail, result.Email)
+				assert.Equal(t, tt.wantAge, result.Age)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestExampleService_DeleteExample(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -352,7 +694,7 @@ func TestExampleService_DeleteExample(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mocks.MockExampleRepository{}
 			logger := zap.NewNop()
-			service := NewExampleService(mockRepo, logger)
+			service := NewExampleService(mockRepo, logger, DefaultBusinessRules())
 
 			tt.setupMock(mockRepo)
 
@@ -371,11 +713,77 @@ func TestExampleService_DeleteExample(t *testing.T) {
 	}
 }
 
+func TestExampleService_DeleteExamplesByIDs(t *testing.T) {
+	t.Run("deletes the found examples in one call", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+
+		ids := []string{"a", "missing", "b"}
+		found := []*domain.Example{
+			validExampleWithCustomData("a", "John Doe", "john@example.com", 30),
+			validExampleWithCustomData("b", "Jane Doe", "jane@example.com", 28),
+		}
+		mockRepo.On("GetByIDs", mock.Anything, ids).Return(found, nil)
+		mockRepo.On("DeleteByIDs", mock.Anything, []string{"a", "b"}).Return([]string{"a", "b"}, nil).Once()
+
+		deleted, notFound, err := service.DeleteExamplesByIDs(getTestContext(), ids)
+		require.NoError(t, err)
+		assert.Equal(t, found, deleted)
+		assert.Equal(t, []string{"missing"}, notFound)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("examples deleted concurrently are not found", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+
+		ids := []string{"a", "missing", "b"}
+		a := validExampleWithCustomData("a", "John Doe", "john@example.com", 30)
+		b := validExampleWithCustomData("b", "Jane Doe", "jane@example.com", 28)
+		mockRepo.On("GetByIDs", mock.Anything, ids).Return([]*domain.Example{a, b}, nil)
+		mockRepo.On("DeleteByIDs", mock.Anything, []string{"a", "b"}).Return([]string{"b"}, nil).Once()
+
+		deleted, notFound, err := service.DeleteExamplesByIDs(getTestContext(), ids)
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.Example{b}, deleted)
+		assert.Equal(t, []string{"missing", "a"}, notFound)
+	})
+
+	t.Run("nothing found deletes nothing", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+
+		mockRepo.On("GetByIDs", mock.Anything, []string{"missing"}).Return([]*domain.Example{}, nil)
+
+		deleted, notFound, err := service.DeleteExamplesByIDs(getTestContext(), []string{"missing"})
+		require.NoError(t, err)
+		assert.Empty(t, deleted)
+		assert.Equal(t, []string{"missing"}, notFound)
+		mockRepo.AssertNotCalled(t, "DeleteByIDs", mock.Anything, mock.Anything)
+	})
+
+	t.Run("delete failure is a database error", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+
+		found := []*domain.Example{validExampleWithCustomData("a", "John Doe", "john@example.com", 30)}
+		mockRepo.On("GetByIDs", mock.Anything, []string{"a"}).Return(found, nil)
+		mockRepo.On("DeleteByIDs", mock.Anything, []string{"a"}).Return(nil, repository.ErrDatabaseConnection)
+
+		_, _, err := service.DeleteExamplesByIDs(getTestContext(), []string{"a"})
+		require.Error(t, err)
+		var appErr *errs.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, errs.ErrorCodeDatabaseError, appErr.Code)
+	})
+}
+
 func TestExampleService_ListExamples(t *testing.T) {
 	tests := []struct {
 		name        string
 		inputLimit  int
 		inputOffset int
+		inputSort   domain.ExampleSort
 		setupMock   func(*mocks.MockExampleRepository)
 		wantErr     bool
 		errContains string
@@ -387,7 +795,7 @@ func TestExampleService_ListExamples(t *testing.T) {
 			inputOffset: 0,
 			setupMock: func(m *mocks.MockExampleRepository) {
 				examples := multipleValidExamples()[:3]
-				m.On("List", mock.Anything, 5, 0).Return(examples, nil)
+				m.On("List", mock.Anything, 5, 0, domain.DefaultExampleSort).Return(examples, nil)
 				m.On("Count", mock.Anything).Return(10, nil)
 			},
 			wantErr:     false,
@@ -399,7 +807,7 @@ func TestExampleService_ListExamples(t *testing.T) {
 			inputOffset: 0,
 			setupMock: func(m *mocks.MockExampleRepository) {
 				examples := multipleValidExamples()[:3]
-				m.On("List", mock.Anything, 10, 0).Return(examples, nil) // Default limit is 10
+				m.On("List", mock.Anything, 10, 0, domain.DefaultExampleSort).Return(examples, nil) // Default limit is 10
 				m.On("Count", mock.Anything).Return(10, nil)
 			},
 			wantErr:     false,
@@ -411,7 +819,7 @@ func TestExampleService_ListExamples(t *testing.T) {
 			inputOffset: 0,
 			setupMock: func(m *mocks.MockExampleRepository) {
 				examples := multipleValidExamples()[:3]
-				m.On("List", mock.Anything, 100, 0).Return(examples, nil) // Max limit is 100
+				m.On("List", mock.Anything, 100, 0, domain.DefaultExampleSort).Return(examples, nil) // Max limit is 100
 				m.On("Count", mock.Anything).Return(10, nil)
 			},
 			wantErr:     false,
@@ -423,24 +831,44 @@ func TestExampleService_ListExamples(t *testing.T) {
 			inputOffset: -5,
 			setupMock: func(m *mocks.MockExampleRepository) {
 				examples := multipleValidExamples()[:3]
-				m.On("List", mock.Anything, 10, 0).Return(examples, nil) // Offset becomes 0
+				m.On("List", mock.Anything, 10, 0, domain.DefaultExampleSort).Return(examples, nil) // Offset becomes 0
+				m.On("Count", mock.Anything).Return(10, nil)
+			},
+			wantErr:     false,
+			expectLimit: 10,
+		},
+		{
+			name:       "sort is passed to the repository",
+			inputLimit: 10,
+			inputSort:  domain.ExampleSort{Field: domain.SortByAge, Descending: true},
+			setupMock: func(m *mocks.MockExampleRepository) {
+				examples := multipleValidExamples()[:3]
+				m.On("List", mock.Anything, 10, 0, domain.ExampleSort{Field: domain.SortByAge, Descending: true}).Return(examples, nil)
 				m.On("Count", mock.Anything).Return(10, nil)
 			},
 			wantErr:     false,
 			expectLimit: 10,
 		},
+		{
+			name:        "unknown sort field is rejected",
+			inputLimit:  10,
+			inputSort:   domain.ExampleSort{Field: "password"},
+			setupMock:   func(m *mocks.MockExampleRepository) {},
+			wantErr:     true,
+			errContains: "invalid sort",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mocks.MockExampleRepository{}
 			logger := zap.NewNop()
-			service := NewExampleService(mockRepo, logger)
+			service := NewExampleService(mockRepo, logger, DefaultBusinessRules())
 
 			tt.setupMock(mockRepo)
 
 			ctx := getTestContext()
-			examples, total, err := service.ListExamples(ctx, tt.inputLimit, tt.inputOffset)
+			examples, total, err := service.ListExamples(ctx, tt.inputLimit, tt.inputOffset, tt.inputSort, repository.ExampleFilter{})
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -458,6 +886,88 @@ func TestExampleService_ListExamples(t *testing.T) {
 	}
 }
 
+func TestExampleService_ListExamplesConfigured(t *testing.T) {
+	pagination := domain.Pagination{DefaultLimit: 20, MaxLimit: 50}
+
+	t.Run("configured max clamps the limit", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules(), WithPagination(pagination))
+		mockRepo.On("List", mock.Anything, 50, 0, domain.DefaultExampleSort).Return(multipleValidExamples(), nil)
+		mockRepo.On("Count", mock.Anything).Return(3, nil)
+
+		_, _, err := service.ListExamples(getTestContext(), 200, 0, domain.ExampleSort{}, repository.ExampleFilter{})
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("configured default applies without a limit", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules(), WithPagination(pagination))
+		mockRepo.On("List", mock.Anything, 20, 0, domain.DefaultExampleSort).Return(multipleValidExamples(), nil)
+		mockRepo.On("Count", mock.Anything).Return(3, nil)
+
+		_, _, err := service.ListExamples(getTestContext(), 0, 0, domain.ExampleSort{}, repository.ExampleFilter{})
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("filter lists and counts the matching examples", func(t *testing.T) {
+		filter := repository.ExampleFilter{UpdatedSince: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+		mockRepo.On("ListFiltered", mock.Anything, filter, 10, 0, domain.DefaultExampleSort).Return(multipleValidExamples()[:1], nil)
+		mockRepo.On("CountFiltered", mock.Anything, filter).Return(1, nil)
+
+		examples, total, err := service.ListExamples(getTestContext(), 0, 0, domain.ExampleSort{}, filter)
+		require.NoError(t, err)
+		assert.Len(t, examples, 1)
+		assert.Equal(t, 1, total)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestExampleService_ExportExamples(t *testing.T) {
+	examples := []*domain.Example{
+		validExampleWithCustomData("a", "John Doe", "john@example.com", 30),
+		validExampleWithCustomData("b", "Jane Doe", "jane@example.com", 28),
+	}
+
+	t.Run("every example is passed to fn", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+		mockRepo.On("StreamAll", mock.Anything, mock.Anything).Return(examples, nil)
+
+		var exported []*domain.Example
+		err := service.ExportExamples(getTestContext(), func(example *domain.Example) error {
+			exported = append(exported, example)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, examples, exported)
+	})
+
+	t.Run("fn error is returned unchanged", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+		mockRepo.On("StreamAll", mock.Anything, mock.Anything).Return(examples, nil)
+
+		writeErr := errors.New("client went away")
+		err := service.ExportExamples(getTestContext(), func(*domain.Example) error { return writeErr })
+		assert.Equal(t, writeErr, err)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules())
+		mockRepo.On("StreamAll", mock.Anything, mock.Anything).Return(nil, repository.ErrDatabaseConnection)
+
+		err := service.ExportExamples(getTestContext(), func(*domain.Example) error { return nil })
+		var appErr *errs.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, errs.ErrorCodeDatabaseError, appErr.Code)
+	})
+}
+
 func TestExampleService_ValidateExampleBusinessRules(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -482,6 +992,22 @@ func TestExampleService_ValidateExampleBusinessRules(t *testing.T) {
 			wantErr:     true,
 			errContains: "inappropriate content",
 		},
+		{
+			name:        "name embeds profanity",
+			inputName:   "John badword1 Doe",
+			inputEmail:  "test@example.com",
+			inputAge:    25,
+			wantErr:     true,
+			errContains: "inappropriate content",
+		},
+		{
+			name:        "profanity matched case-insensitively",
+			inputName:   "John BadWord2",
+			inputEmail:  "test@example.com",
+			inputAge:    25,
+			wantErr:     true,
+			errContains: "inappropriate content",
+		},
 		{
 			name:        "corporate email underage",
 			inputName:   "Young User",
@@ -518,7 +1044,7 @@ func TestExampleService_ValidateExampleBusinessRules(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mocks.MockExampleRepository{}
 			logger := zap.NewNop()
-			service := NewExampleService(mockRepo, logger)
+			service := NewExampleService(mockRepo, logger, DefaultBusinessRules())
 
 			ctx := getTestContext()
 			err := service.ValidateExampleBusinessRules(ctx, tt.inputName, tt.inputEmail, tt.inputAge)
@@ -535,9 +1061,40 @@ func TestExampleService_ValidateExampleBusinessRules(t *testing.T) {
 
 // Helper function tests
 func TestGenerateExampleID(t *testing.T) {
-	id := generateExampleID("John Doe", "john@example.com")
-	expected := "ex_joh_8" // First 3 chars of email + length of name
-	assert.Equal(t, expected, id)
+	id := generateExampleID()
+	require.True(t, strings.HasPrefix(id, "ex_"), "id %q should have the ex_ prefix", id)
+
+	_, err := uuid.Parse(strings.TrimPrefix(id, "ex_"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, id, generateExampleID())
+}
+
+func TestMapDomainError(t *testing.T) {
+	_, err := domain.NewExample("test-id", "", "not-an-email", 30)
+	require.Error(t, err)
+
+	appErr := mapDomainError(err)
+	assert.Equal(t, errs.ErrorCodeValidationFailed, appErr.Code)
+	assert.ErrorIs(t, appErr, domain.ErrValidation)
+	assert.Equal(t, []validator.ValidationFieldErrorDTO{
+		{Field: "name", Message: "name cannot be empty"},
+		{Field: "email", Message: "invalid email format"},
+	}, appErr.Details)
+
+	// Anything else stays invalid_input
+	assert.Equal(t, errs.ErrorCodeInvalidInput, mapDomainError(errors.New("boom")).Code)
+}
+
+func TestExampleService_CreateExample_UniqueIDsForShortEmails(t *testing.T) {
+	service := NewExampleService(repository.NewInMemoryExampleRepository(), zap.NewNop(), DefaultBusinessRules())
+	ctx := getTestContext()
+
+	first, err := service.CreateExample(ctx, "John Doe", "jo@x.com", "", 30)
+	require.NoError(t, err)
+	second, err := service.CreateExample(ctx, "John Doe", "j@x.com", "", 30)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first.ID, second.ID)
 }
 
 func TestContainsProfanity(t *testing.T) {
@@ -548,18 +1105,135 @@ func TestContainsProfanity(t *testing.T) {
 	}{
 		{"clean name", "John Doe", false},
 		{"contains profanity", "badword1", true},
-		{"case sensitive", "BADWORD1", false},        // Function is case sensitive
-		{"partial match", "somebadword1text", false}, // Exact match only
+		{"case insensitive", "BADWORD1", true},
+		{"embedded word", "John badword2 Doe", true},
+		{"partial match", "somebadword1text", true},
 	}
 
+	words := normalizeWords(DefaultBusinessRules().ProfanityWords, "")
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := containsProfanity(tt.input)
+			got := containsProfanity(tt.input, words)
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
+func TestExampleService_ConfiguredProfanityWords(t *testing.T) {
+	rules := DefaultBusinessRules()
+	rules.ProfanityWords = []string{" Gadzooks ", ""}
+	service := NewExampleService(&mocks.MockExampleRepository{}, zap.NewNop(), rules)
+	ctx := getTestContext()
+
+	err := service.ValidateExampleBusinessRules(ctx, "Sir gadzooks Smith", "test@example.com", 25)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "inappropriate content")
+
+	// The default list is replaced, and blank entries do not match every name
+	assert.NoError(t, service.ValidateExampleBusinessRules(ctx, "badword1", "test@example.com", 25))
+	assert.NoError(t, service.ValidateExampleBusinessRules(ctx, "John Doe", "test@example.com", 25))
+}
+
+// phraseModerator is a ContentModerator rejecting names containing phrase, or failing with err
+type phraseModerator struct {
+	phrase string
+	err    error
+	names  []string
+}
+
+func (m *phraseModerator) CheckName(ctx context.Context, name string) error {
+	m.names = append(m.names, name)
+	if m.err != nil {
+		return m.err
+	}
+	if strings.Contains(name, m.phrase) {
+		return fmt.Errorf("%w: %q is not allowed", ErrContentRejected, m.phrase)
+	}
+	return nil
+}
+
+func TestExampleService_ContentModerator(t *testing.T) {
+	ctx := getTestContext()
+
+	t.Run("injected moderator replaces the word list", func(t *testing.T) {
+		moderator := &phraseModerator{phrase: "Forbidden Phrase"}
+		service := NewExampleService(&mocks.MockExampleRepository{}, zap.NewNop(), DefaultBusinessRules(), WithContentModerator(moderator))
+
+		err := service.ValidateExampleBusinessRules(ctx, "The Forbidden Phrase", "test@example.com", 25)
+		var appErr *errs.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, errs.ErrorCodeProfanityDetected, appErr.Code)
+
+		assert.NoError(t, service.ValidateExampleBusinessRules(ctx, "badword1", "test@example.com", 25))
+		assert.Equal(t, []string{"The Forbidden Phrase", "badword1"}, moderator.names)
+	})
+
+	t.Run("rejected name fails creation as a broken business rule", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules(), WithContentModerator(&phraseModerator{phrase: "Forbidden Phrase"}))
+
+		_, err := service.CreateExample(ctx, "The Forbidden Phrase", "test@example.com", "", 25)
+		var appErr *errs.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, errs.ErrorCodeBusinessLogicFail, appErr.Code)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unreachable moderator is not a broken rule", func(t *testing.T) {
+		mockRepo := &mocks.MockExampleRepository{}
+		service := NewExampleService(mockRepo, zap.NewNop(), DefaultBusinessRules(), WithContentModerator(&phraseModerator{err: errors.New("moderation service down")}))
+
+		_, err := service.CreateExample(ctx, "John Doe", "test@example.com", "", 25)
+		var appErr *errs.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, errs.ErrorCodeValidationError, appErr.Code)
+		assert.Contains(t, err.Error(), "moderation service down")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestExampleService_ConfiguredAgeThresholds(t *testing.T) {
+	rules := DefaultBusinessRules()
+	rules.CorporateDomains = []string{"@Acme.io"}
+	rules.VIPDomains = []string{"gold.example.com"}
+	rules.CorporateMinAge = 25
+	rules.VIPMinAge = 30
+	service := NewExampleService(&mocks.MockExampleRepository{}, zap.NewNop(), rules)
+	ctx := getTestContext()
+
+	tests := []struct {
+		name     string
+		email    string
+		age      int
+		wantCode errs.ErrorCode
+	}{
+		{name: "corporate below new cutoff", email: "dev@acme.io", age: 24, wantCode: errs.ErrorCodeCorporateEmailUnderage},
+		{name: "corporate at new cutoff", email: "dev@acme.io", age: 25},
+		{name: "corporate subdomain below cutoff", email: "dev@eu.ACME.io", age: 20, wantCode: errs.ErrorCodeCorporateEmailUnderage},
+		{name: "VIP below new cutoff", email: "vip@gold.example.com", age: 29, wantCode: errs.ErrorCodeVIPDomainUnderage},
+		{name: "VIP at new cutoff", email: "vip@gold.example.com", age: 30},
+		{name: "default corporate domain no longer listed", email: "young@corp.com", age: 16},
+		{name: "default VIP domain no longer listed", email: "young@vip.com", age: 19},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateExampleBusinessRules(ctx, "Jane Doe", tt.email, tt.age)
+			if tt.wantCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var appErr *errs.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, tt.wantCode, appErr.Code)
+		})
+	}
+
+	err := service.ValidateExampleBusinessRules(ctx, "Jane Doe", "dev@acme.io", 24)
+	assert.Contains(t, err.Error(), "minimum age of 25")
+}
+
 func TestIsCorporateEmail(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -570,11 +1244,15 @@ func TestIsCorporateEmail(t *testing.T) {
 		{"corporate email", "user@corp.com", true},
 		{"enterprise email", "user@enterprise.com", true},
 		{"partial match", "user@mycorp.com", false},
+		{"subdomain", "user@mail.corp.com", true},
+		{"case insensitive", "user@CORP.com", true},
+		{"domain as prefix only", "user@corp.com.evil.io", false},
 	}
 
+	domains := DefaultBusinessRules().CorporateDomains
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := isCorporateEmail(tt.email)
+			got := isCorporateEmail(tt.email, domains)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -590,11 +1268,13 @@ func TestIsVIPDomain(t *testing.T) {
 		{"VIP email", "user@vip.com", true},
 		{"premium email", "user@premium.com", true},
 		{"partial match", "user@myvip.com", false},
+		{"subdomain", "user@club.premium.com", true},
 	}
 
+	domains := DefaultBusinessRules().VIPDomains
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := isVIPDomain(tt.email)
+			got := isVIPDomain(tt.email, domains)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrContentRejected is returned, possibly wrapped, by a ContentModerator refusing content
+var ErrContentRejected = errors.New("content rejected")
+
+// ContentModerator decides whether user-supplied text may be stored, e.g. by checking a
+// local word list or calling an external moderation service
+type ContentModerator interface {
+	// CheckName returns an error wrapping ErrContentRejected when name is not allowed.
+	// Any other error means the name could not be checked.
+	CheckName(ctx context.Context, name string) error
+}
+
+// WordListModerator is the default ContentModerator, rejecting names containing any of
+// its words case-insensitively
+type WordListModerator struct {
+	words []string
+}
+
+// NewWordListModerator creates a moderator rejecting names that contain any of words
+func NewWordListModerator(words []string) *WordListModerator {
+	return &WordListModerator{words: normalizeWords(words, "")}
+}
+
+// CheckName rejects name if it contains one of the words
+func (m *WordListModerator) CheckName(ctx context.Context, name string) error {
+	if containsProfanity(name, m.words) {
+		return fmt.Errorf("%w: name contains inappropriate content", ErrContentRejected)
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"example-api-template/internal/errs"
+	"example-api-template/internal/repository"
+
+	"github.com/labstack/echo/v4"
+)
+
+// LogLevelController reads and changes the application log level at runtime
+type LogLevelController interface {
+	Level() string
+	SetLevel(level string) error
+}
+
+// LogLevelDTO represents the log level request and response body
+type LogLevelDTO struct {
+	Level string `json:"level"`
+}
+
+// FaultInjector reads and changes the faults simulated by the mock external API
+type FaultInjector interface {
+	Faults() repository.MockFaults
+	SetFaults(faults repository.MockFaults) error
+}
+
+// ExternalAPIFaultsDTO represents the mock external API faults request and response body
+type ExternalAPIFaultsDTO struct {
+	ShouldFail     bool            `json:"should_fail"`
+	Delay          string          `json:"delay" example:"100ms"`
+	FailureRate    float64         `json:"failure_rate" example:"0.5"`
+	MethodFailures map[string]bool `json:"method_failures,omitempty"`
+}
+
+// EventReplayer publishes past events from the outbox again
+type EventReplayer interface {
+	Replay(ctx context.Context, filter repository.OutboxEventFilter, dryRun bool) (int, error)
+}
+
+// MaxReplayEvents is the most events one replay request publishes, so it finishes within the
+// request timeout; a longer time range has to be replayed in parts
+const MaxReplayEvents = 500
+
+// ReplayEventsRequestDTO selects the events to replay; at least one of the criteria is required
+type ReplayEventsRequestDTO struct {
+	From     *time.Time `json:"from,omitempty" example:"2026-01-01T00:00:00Z"` // Events created at or after
+	To       *time.Time `json:"to,omitempty" example:"2026-01-02T00:00:00Z"`   // Events created before
+	EventIDs []string   `json:"event_ids,omitempty"`
+	DryRun   bool       `json:"dry_run"` // Only count the matching events
+}
+
+// ReplayEventsResponseDTO reports how many events were replayed, or would be with dry_run
+type ReplayEventsResponseDTO struct {
+	Count  int  `json:"count"`
+	DryRun bool `json:"dry_run"`
+}
+
+// MigrationStatusReporter compares the database schema with the one the application expects
+type MigrationStatusReporter interface {
+	MigrationStatus(ctx context.Context) (repository.MigrationStatus, error)
+}
+
+// MigrationStatusDTO reports whether the examples table matches the expected schema
+type MigrationStatusDTO struct {
+	Table          string   `json:"table" example:"examples"`
+	Exists         bool     `json:"exists"`
+	UpToDate       bool     `json:"up_to_date"`
+	MissingColumns []string `json:"missing_columns,omitempty"`
+	MissingIndexes []string `json:"missing_indexes,omitempty"`
+}
+
+// AdminHandler handles operational HTTP requests
+type AdminHandler struct {
+	logLevel   LogLevelController
+	faults     FaultInjector
+	replayer   EventReplayer
+	migrations MigrationStatusReporter
+	middleware []echo.MiddlewareFunc
+}
+
+// AdminHandlerOption configures an AdminHandler
+type AdminHandlerOption func(*AdminHandler)
+
+// WithFaultInjector exposes the mock external API faults; meant for development only
+func WithFaultInjector(faults FaultInjector) AdminHandlerOption {
+	return func(h *AdminHandler) {
+		h.faults = faults
+	}
+}
+
+// WithEventReplayer exposes replaying past events from the outbox
+func WithEventReplayer(replayer EventReplayer) AdminHandlerOption {
+	return func(h *AdminHandler) {
+		h.replayer = replayer
+	}
+}
+
+// WithMigrationStatus exposes whether the database schema matches the expected one
+func WithMigrationStatus(migrations MigrationStatusReporter) AdminHandlerOption {
+	return func(h *AdminHandler) {
+		h.migrations = migrations
+	}
+}
+
+// WithAdminMiddleware runs m on every admin route, e.g. to require separate credentials
+func WithAdminMiddleware(m ...echo.MiddlewareFunc) AdminHandlerOption {
+	return func(h *AdminHandler) {
+		h.middleware = append(h.middleware, m...)
+	}
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(logLevel LogLevelController, opts ...AdminHandlerOption) *AdminHandler {
+	h := &AdminHandler{
+		logLevel: logLevel,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// RegisterRoutes registers all admin routes
+func (h *AdminHandler) RegisterRoutes(e *echo.Echo) {
+	admin := e.Group("/api/v1/admin", h.middleware...)
+	admin.GET("/log-level", h.GetLogLevel)
+	admin.POST("/log-level", h.SetLogLevel)
+
+	if h.faults != nil {
+		admin.GET("/external-api/faults", h.GetExternalAPIFaults)
+		admin.PUT("/external-api/faults", h.SetExternalAPIFaults)
+	}
+
+	if h.replayer != nil {
+		admin.POST("/events/replay", h.ReplayEvents)
+	}
+
+	if h.migrations != nil {
+		admin.GET("/migrations/status", h.GetMigrationStatus)
+	}
+}
+
+// GetLogLevel returns the current log level
+// @Summary Get log level
+// @Description Get the current minimum log level
+// @Tags admin
+// @Produce json
+// @Success 200 {object} LogLevelDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Router /api/v1/admin/log-level [get]
+func (h *AdminHandler) GetLogLevel(c echo.Context) error {
+	return c.JSON(http.StatusOK, LogLevelDTO{Level: h.logLevel.Level()})
+}
+
+// SetLogLevel changes the log level without a restart
+// @Summary Set log level
+// @Description Change the minimum log level at runtime
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param level body LogLevelDTO true "New log level"
+// @Success 200 {object} LogLevelDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Router /api/v1/admin/log-level [post]
+func (h *AdminHandler) SetLogLevel(c echo.Context) error {
+	var req LogLevelDTO
+	if err := c.Bind(&req); err != nil {
+		return errs.New(errs.ErrorCodeInvalidRequest, err, nil)
+	}
+
+	if err := h.logLevel.SetLevel(req.Level); err != nil {
+		return errs.New(errs.ErrorCodeInvalidInput, err, map[string]string{"level": req.Level})
+	}
+
+	return c.JSON(http.StatusOK, LogLevelDTO{Level: h.logLevel.Level()})
+}
+
+// GetExternalAPIFaults returns the faults the mock external API simulates
+// @Summary Get mock external API faults
+// @Description Get the delay and failures simulated by the mock external API (development only)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} ExternalAPIFaultsDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Router /api/v1/admin/external-api/faults [get]
+func (h *AdminHandler) GetExternalAPIFaults(c echo.Context) error {
+	return c.JSON(http.StatusOK, toExternalAPIFaultsDTO(h.faults.Faults()))
+}
+
+// SetExternalAPIFaults replaces the faults the mock external API simulates
+// @Summary Set mock external API faults
+// @Description Replace the delay and failures simulated by the mock external API (development only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param faults body ExternalAPIFaultsDTO true "New faults"
+// @Success 200 {object} ExternalAPIFaultsDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Router /api/v1/admin/external-api/faults [put]
+func (h *AdminHandler) SetExternalAPIFaults(c echo.Context) error {
+	var req ExternalAPIFaultsDTO
+	if err := c.Bind(&req); err != nil {
+		return errs.New(errs.ErrorCodeInvalidRequest, err, nil)
+	}
+
+	faults, err := req.toMockFaults()
+	if err != nil {
+		return errs.New(errs.ErrorCodeInvalidInput, err, map[string]string{"delay": req.Delay})
+	}
+	if err := h.faults.SetFaults(faults); err != nil {
+		return errs.New(errs.ErrorCodeInvalidInput, err, nil)
+	}
+
+	return c.JSON(http.StatusOK, toExternalAPIFaultsDTO(h.faults.Faults()))
+}
+
+// ReplayEvents republishes past events from the outbox
+// @Summary Replay events
+// @Description Publish already published events from the outbox again, selected by creation time and/or ID, oldest first. With dry_run only the matching events are counted. A replay publishes at most 500 events; event_ids may hold up to 500 IDs and a time range matching more is rejected, so it has to be split.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body ReplayEventsRequestDTO true "Events to replay"
+// @Success 200 {object} ReplayEventsResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/v1/admin/events/replay [post]
+func (h *AdminHandler) ReplayEvents(c echo.Context) error {
+	var req ReplayEventsRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return errs.New(errs.ErrorCodeInvalidRequest, err, nil)
+	}
+
+	filter, err := req.toOutboxEventFilter()
+	if err != nil {
+		return errs.New(errs.ErrorCodeInvalidInput, err, nil)
+	}
+
+	// event_ids is capped on its own, so only a time range needs counting first
+	if !req.DryRun && (req.From != nil || req.To != nil) {
+		matches, err := h.replayer.Replay(c.Request().Context(), filter, true)
+		if err != nil {
+			return errs.New(errs.ErrorCodeInternalError, err, nil)
+		}
+		if matches > MaxReplayEvents {
+			return errs.New(errs.ErrorCodeInvalidInput,
+				fmt.Errorf("%d events match, more than the %d a replay can publish; narrow the time range", matches, MaxReplayEvents),
+				map[string]string{"matches": strconv.Itoa(matches), "max": strconv.Itoa(MaxReplayEvents)})
+		}
+	}
+
+	count, err := h.replayer.Replay(c.Request().Context(), filter, req.DryRun)
+	if err != nil {
+		return errs.New(errs.ErrorCodeInternalError, err, map[string]string{"replayed": strconv.Itoa(count)})
+	}
+
+	return c.JSON(http.StatusOK, ReplayEventsResponseDTO{Count: count, DryRun: req.DryRun})
+}
+
+// GetMigrationStatus reports whether the examples table matches the expected schema
+// @Summary Get migration status
+// @Description Report whether the examples table exists with every column and index the application expects, e.g. when migrations are run outside the application
+// @Tags admin
+// @Produce json
+// @Success 200 {object} MigrationStatusDTO
+// @Failure 401 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/v1/admin/migrations/status [get]
+func (h *AdminHandler) GetMigrationStatus(c echo.Context) error {
+	status, err := h.migrations.MigrationStatus(c.Request().Context())
+	if err != nil {
+		return errs.New(errs.ErrorCodeInternalError, err, nil)
+	}
+
+	return c.JSON(http.StatusOK, MigrationStatusDTO{
+		Table:          status.Table,
+		Exists:         status.Exists,
+		UpToDate:       status.UpToDate(),
+		MissingColumns: status.MissingColumns,
+		MissingIndexes: status.MissingIndexes,
+	})
+}
+
+// toOutboxEventFilter converts the DTO to a filter, refusing one that would match every event
+func (d ReplayEventsRequestDTO) toOutboxEventFilter() (repository.OutboxEventFilter, error) {
+	var filter repository.OutboxEventFilter
+	if d.From == nil && d.To == nil && len(d.EventIDs) == 0 {
+		return filter, errors.New("from, to or event_ids is required")
+	}
+	if len(d.EventIDs) > MaxReplayEvents {
+		return filter, fmt.Errorf("event_ids must not hold more than %d IDs", MaxReplayEvents)
+	}
+	if d.From != nil {
+		filter.From = *d.From
+	}
+	if d.To != nil {
+		filter.To = *d.To
+	}
+	if d.From != nil && d.To != nil && !d.From.Before(*d.To) {
+		return filter, errors.New("from must be before to")
+	}
+	filter.IDs = d.EventIDs
+	return filter, nil
+}
+
+// toMockFaults converts the DTO to mock faults; an empty delay means none
+func (d ExternalAPIFaultsDTO) toMockFaults() (repository.MockFaults, error) {
+	faults := repository.MockFaults{
+		ShouldFail:     d.ShouldFail,
+		FailureRate:    d.FailureRate,
+		MethodFailures: d.MethodFailures,
+	}
+	if d.Delay != "" {
+		delay, err := time.ParseDuration(d.Delay)
+		if err != nil {
+			return faults, fmt.Errorf("invalid delay: %w", err)
+		}
+		faults.Delay = delay
+	}
+	return faults, nil
+}
+
+// toExternalAPIFaultsDTO converts mock faults to the DTO
+func toExternalAPIFaultsDTO(faults repository.MockFaults) ExternalAPIFaultsDTO {
+	return ExternalAPIFaultsDTO{
+		ShouldFail:     faults.ShouldFail,
+		Delay:          faults.Delay.String(),
+		FailureRate:    faults.FailureRate,
+		MethodFailures: faults.MethodFailures,
+	}
+}
@@ -0,0 +1,361 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example-api-template/internal/repository"
+	"example-api-template/pkg/i18n"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLogLevel is an in-memory LogLevelController
+type fakeLogLevel struct {
+	level string
+}
+
+func (f *fakeLogLevel) Level() string {
+	return f.level
+}
+
+func (f *fakeLogLevel) SetLevel(level string) error {
+	switch level {
+	case "debug", "info", "warn", "error":
+		f.level = level
+		return nil
+	default:
+		return errors.New("invalid log level: " + level)
+	}
+}
+
+func TestAdminHandlerLogLevel(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	newServer := func() (*echo.Echo, *fakeLogLevel) {
+		level := &fakeLogLevel{level: "info"}
+		e := echo.New()
+		e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+		NewAdminHandler(level).RegisterRoutes(e)
+		return e, level
+	}
+
+	postLevel := func(e *echo.Echo, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/log-level", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("get returns current level", func(t *testing.T) {
+		e, _ := newServer()
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/log-level", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"level":"info"}`, rec.Body.String())
+	})
+
+	t.Run("post changes level", func(t *testing.T) {
+		e, level := newServer()
+
+		rec := postLevel(e, `{"level":"debug"}`)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"level":"debug"}`, rec.Body.String())
+		assert.Equal(t, "debug", level.level)
+	})
+
+	t.Run("post rejects unknown level", func(t *testing.T) {
+		e, level := newServer()
+
+		rec := postLevel(e, `{"level":"verbose"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "info", level.level)
+	})
+}
+
+func TestAdminHandlerExternalAPIFaults(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	newServer := func() (*echo.Echo, *repository.MockExternalExampleAPI) {
+		api := repository.NewMockExternalExampleAPI(false, 0)
+		e := echo.New()
+		e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+		NewAdminHandler(&fakeLogLevel{level: "info"}, WithFaultInjector(api)).RegisterRoutes(e)
+		return e, api
+	}
+
+	putFaults := func(e *echo.Echo, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/external-api/faults", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("routes are absent without a fault injector", func(t *testing.T) {
+		e := echo.New()
+		NewAdminHandler(&fakeLogLevel{level: "info"}).RegisterRoutes(e)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/external-api/faults", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("get returns current faults", func(t *testing.T) {
+		e, _ := newServer()
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/external-api/faults", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"should_fail":false,"delay":"0s","failure_rate":0}`, rec.Body.String())
+	})
+
+	t.Run("put replaces faults", func(t *testing.T) {
+		e, api := newServer()
+
+		rec := putFaults(e, `{"delay":"5ms","failure_rate":0.5,"method_failures":{"Ping":true}}`)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"should_fail":false,"delay":"5ms","failure_rate":0.5,"method_failures":{"Ping":true}}`, rec.Body.String())
+		faults := api.Faults()
+		assert.Equal(t, 5*time.Millisecond, faults.Delay)
+		assert.Equal(t, 0.5, faults.FailureRate)
+		assert.Equal(t, map[string]bool{repository.MockMethodPing: true}, faults.MethodFailures)
+	})
+
+	t.Run("put rejects invalid faults", func(t *testing.T) {
+		e, api := newServer()
+
+		for _, body := range []string{
+			`{"delay":"soon"}`,
+			`{"failure_rate":2}`,
+			`{"method_failures":{"Unknown":true}}`,
+		} {
+			rec := putFaults(e, body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+		}
+		assert.Equal(t, 0.0, api.Faults().FailureRate)
+	})
+}
+
+// fakeReplayer records the replays it is asked for; it matches count events
+type fakeReplayer struct {
+	count   int
+	err     error
+	filters []repository.OutboxEventFilter
+	dryRuns []bool
+}
+
+func (f *fakeReplayer) Replay(ctx context.Context, filter repository.OutboxEventFilter, dryRun bool) (int, error) {
+	f.filters = append(f.filters, filter)
+	f.dryRuns = append(f.dryRuns, dryRun)
+	if f.err != nil {
+		return 0, f.err
+	}
+	return f.count, nil
+}
+
+func TestAdminHandlerReplayEvents(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	newServer := func(replayer *fakeReplayer) *echo.Echo {
+		e := echo.New()
+		e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+		NewAdminHandler(&fakeLogLevel{level: "info"}, WithEventReplayer(replayer)).RegisterRoutes(e)
+		return e
+	}
+
+	postReplay := func(e *echo.Echo, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/events/replay", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("route is absent without a replayer", func(t *testing.T) {
+		e := echo.New()
+		NewAdminHandler(&fakeLogLevel{level: "info"}).RegisterRoutes(e)
+
+		rec := postReplay(e, `{"event_ids":["evt-1"]}`)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("dry run reports the matching count", func(t *testing.T) {
+		replayer := &fakeReplayer{count: 3}
+
+		rec := postReplay(newServer(replayer), `{"from":"2026-01-01T00:00:00Z","to":"2026-01-02T00:00:00Z","dry_run":true}`)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"count":3,"dry_run":true}`, rec.Body.String())
+		assert.Equal(t, []bool{true}, replayer.dryRuns)
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), replayer.filters[0].From.UTC())
+		assert.Equal(t, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), replayer.filters[0].To.UTC())
+	})
+
+	t.Run("replay by event IDs", func(t *testing.T) {
+		replayer := &fakeReplayer{count: 2}
+
+		rec := postReplay(newServer(replayer), `{"event_ids":["evt-1","evt-2"]}`)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"count":2,"dry_run":false}`, rec.Body.String())
+		assert.Equal(t, []bool{false}, replayer.dryRuns)
+		assert.Equal(t, []string{"evt-1", "evt-2"}, replayer.filters[0].IDs)
+	})
+
+	t.Run("replay by time range counts the matches first", func(t *testing.T) {
+		replayer := &fakeReplayer{count: 3}
+
+		rec := postReplay(newServer(replayer), `{"from":"2026-01-01T00:00:00Z"}`)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"count":3,"dry_run":false}`, rec.Body.String())
+		assert.Equal(t, []bool{true, false}, replayer.dryRuns)
+	})
+
+	t.Run("time range matching too many events is rejected", func(t *testing.T) {
+		replayer := &fakeReplayer{count: MaxReplayEvents + 1}
+
+		rec := postReplay(newServer(replayer), `{"from":"2026-01-01T00:00:00Z"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "narrow the time range")
+		assert.Equal(t, []bool{true}, replayer.dryRuns, "nothing is replayed")
+	})
+
+	t.Run("too many event IDs are rejected", func(t *testing.T) {
+		replayer := &fakeReplayer{}
+		ids := make([]string, MaxReplayEvents+1)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("evt-%d", i)
+		}
+		body, err := json.Marshal(ReplayEventsRequestDTO{EventIDs: ids})
+		require.NoError(t, err)
+
+		rec := postReplay(newServer(replayer), string(body))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Empty(t, replayer.filters)
+	})
+
+	t.Run("a filter is required", func(t *testing.T) {
+		replayer := &fakeReplayer{}
+
+		rec := postReplay(newServer(replayer), `{"dry_run":true}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Empty(t, replayer.filters)
+	})
+
+	t.Run("from must be before to", func(t *testing.T) {
+		replayer := &fakeReplayer{}
+
+		rec := postReplay(newServer(replayer), `{"from":"2026-01-02T00:00:00Z","to":"2026-01-01T00:00:00Z"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Empty(t, replayer.filters)
+	})
+
+	t.Run("replay failure is an internal error", func(t *testing.T) {
+		rec := postReplay(newServer(&fakeReplayer{err: errors.New("broker down")}), `{"event_ids":["evt-1"]}`)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+// fakeMigrations reports a fixed migration status
+type fakeMigrations struct {
+	status repository.MigrationStatus
+	err    error
+}
+
+func (f *fakeMigrations) MigrationStatus(ctx context.Context) (repository.MigrationStatus, error) {
+	return f.status, f.err
+}
+
+func TestAdminHandlerMigrationStatus(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	getStatus := func(opts ...AdminHandlerOption) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+		NewAdminHandler(&fakeLogLevel{level: "info"}, opts...).RegisterRoutes(e)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/migrations/status", nil))
+		return rec
+	}
+
+	t.Run("route is absent without a reporter", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, getStatus().Code)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		rec := getStatus(WithMigrationStatus(&fakeMigrations{status: repository.MigrationStatus{Table: "examples", Exists: true}}))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"table":"examples","exists":true,"up_to_date":true}`, rec.Body.String())
+	})
+
+	t.Run("missing table", func(t *testing.T) {
+		rec := getStatus(WithMigrationStatus(&fakeMigrations{status: repository.MigrationStatus{Table: "examples"}}))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"table":"examples","exists":false,"up_to_date":false}`, rec.Body.String())
+	})
+
+	t.Run("missing columns and indexes", func(t *testing.T) {
+		rec := getStatus(WithMigrationStatus(&fakeMigrations{status: repository.MigrationStatus{
+			Table:          "examples",
+			Exists:         true,
+			MissingColumns: []string{"phone"},
+			MissingIndexes: []string{"idx_examples_email_lower"},
+		}}))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"table":"examples","exists":true,"up_to_date":false,"missing_columns":["phone"],"missing_indexes":["idx_examples_email_lower"]}`, rec.Body.String())
+	})
+
+	t.Run("unreachable database is an internal error", func(t *testing.T) {
+		rec := getStatus(WithMigrationStatus(&fakeMigrations{err: errors.New("connection refused")}))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
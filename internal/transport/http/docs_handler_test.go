@@ -0,0 +1,47 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocsRoutes(t *testing.T) {
+	e := echo.New()
+	RegisterDocsRoutes(e)
+
+	t.Run("spec lists the API paths and models", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+
+		var spec struct {
+			Paths       map[string]map[string]json.RawMessage `json:"paths"`
+			Definitions map[string]json.RawMessage            `json:"definitions"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+
+		assert.Contains(t, spec.Paths["/api/v1/examples"], "get")
+		assert.Contains(t, spec.Paths["/api/v1/examples"], "post")
+		assert.Contains(t, spec.Paths["/api/v1/examples/{id}"], "get")
+		assert.Contains(t, spec.Paths["/api/v1/examples/{id}"], "put")
+		assert.Contains(t, spec.Paths["/api/v1/examples/{id}"], "delete")
+		assert.Contains(t, spec.Paths, "/api/v1/health")
+		assert.Contains(t, spec.Definitions, "http.CreateExampleRequestDTO")
+		assert.Contains(t, spec.Definitions, "http.ExampleResponseDTO")
+	})
+
+	t.Run("serves Swagger UI", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "/openapi.json")
+		assert.Equal(t, docsContentSecurityPolicy, rec.Header().Get(echo.HeaderContentSecurityPolicy))
+	})
+}
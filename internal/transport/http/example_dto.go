@@ -1,11 +1,14 @@
 package http
 
 import (
+	"encoding/xml"
+	"strconv"
 	"strings"
 	"time"
 
 	"example-api-template/internal/domain"
 	"example-api-template/internal/usecase"
+	"example-api-template/pkg/health"
 	"example-api-template/pkg/validator"
 )
 
@@ -13,74 +16,150 @@ import (
 type CreateExampleRequestDTO struct {
 	Name  string `json:"name" validate:"required,min=1,max=100"`
 	Email string `json:"email" validate:"required,email"`
-	Age   int    `json:"age" validate:"required,min=0,max=150"`
+	Phone string `json:"phone,omitempty" validate:"omitempty,phone" example:"+14155552671"`
+	Age   *int   `json:"age" validate:"required,min=0,max=150"` // A pointer, so a missing age fails required while 0 passes
 }
 
 // UpdateExampleRequestDTO represents the HTTP request for updating an example
 type UpdateExampleRequestDTO struct {
 	Name  string `json:"name" validate:"required,min=1,max=100"`
 	Email string `json:"email" validate:"required,email"`
-	Age   int    `json:"age" validate:"required,min=0,max=150"`
+	Phone string `json:"phone,omitempty" validate:"omitempty,phone" example:"+14155552671"` // Omitting it removes the phone number
+	Age   *int   `json:"age" validate:"required,min=0,max=150"`                             // A pointer, so a missing age fails required while 0 passes
+}
+
+// PatchExampleRequestDTO represents the HTTP request for partially updating an example
+type PatchExampleRequestDTO struct {
+	Name  *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Email *string `json:"email,omitempty" validate:"omitempty,email"`
+	Phone *string `json:"phone,omitempty" validate:"omitempty,phone|len=0" example:"+14155552671"` // An empty string removes the phone number
+	Age   *int    `json:"age,omitempty" validate:"omitempty,min=0,max=150"`
 }
 
 // ExampleResponseDTO represents the HTTP response for an example
 type ExampleResponseDTO struct {
-	ID           string                  `json:"id"`
-	Name         string                  `json:"name"`
-	Email        string                  `json:"email"`
-	Age          int                     `json:"age"`
-	CreatedAt    time.Time               `json:"created_at"`
-	UpdatedAt    time.Time               `json:"updated_at"`
-	ExternalData *ExternalExampleDataDTO `json:"external_data,omitempty"`
-	Enrichment   map[string]interface{}  `json:"enrichment,omitempty"`
+	ID               string                  `json:"id" xml:"id"`
+	Name             string                  `json:"name" xml:"name"`
+	Email            string                  `json:"email" xml:"email"`
+	Phone            string                  `json:"phone,omitempty" xml:"phone,omitempty"`
+	Age              int                     `json:"age" xml:"age"`
+	AgeCategory      string                  `json:"age_category" xml:"age_category"` // minor, adult or senior
+	CreatedAt        time.Time               `json:"created_at" xml:"created_at"`
+	UpdatedAt        time.Time               `json:"updated_at" xml:"updated_at"`
+	Version          int                     `json:"version" xml:"version"`
+	ExternalData     *ExternalExampleDataDTO `json:"external_data,omitempty" xml:"external_data,omitempty"`
+	Enrichment       map[string]interface{}  `json:"enrichment,omitempty" xml:"enrichment,omitempty"`
+	EnrichmentStatus string                  `json:"enrichment_status,omitempty" xml:"enrichment_status,omitempty"` // complete, partial, skipped or failed
+}
+
+// MarshalXML implements xml.Marshaler, encoding the enrichment map as entries
+func (dto ExampleResponseDTO) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "example"} // A Marshaler is otherwise named after its type
+	type plain ExampleResponseDTO
+	return e.EncodeElement(struct {
+		plain
+		Enrichment interface{} `xml:"enrichment,omitempty"`
+	}{plain(dto), xmlValue(dto.Enrichment)}, start)
 }
 
 // ExternalExampleDataDTO represents external API data in HTTP response
 type ExternalExampleDataDTO struct {
-	ExternalID   string            `json:"external_id"`
-	Metadata     map[string]string `json:"metadata"`
-	Score        float64           `json:"score"`
-	LastModified time.Time         `json:"last_modified"`
+	ExternalID   string            `json:"external_id" xml:"external_id"`
+	Metadata     map[string]string `json:"metadata" xml:"metadata"`
+	Score        float64           `json:"score" xml:"score"`
+	LastModified time.Time         `json:"last_modified" xml:"last_modified"`
+}
+
+// MarshalXML implements xml.Marshaler, encoding the metadata map as entries
+func (dto ExternalExampleDataDTO) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain ExternalExampleDataDTO
+	return e.EncodeElement(struct {
+		plain
+		Metadata interface{} `xml:"metadata"`
+	}{plain(dto), xmlValue(dto.Metadata)}, start)
 }
 
 // ListExamplesRequestDTO represents the HTTP request for listing examples
 type ListExamplesRequestDTO struct {
-	Limit  int `query:"limit" validate:"omitempty,min=1,max=100"`
-	Offset int `query:"offset" validate:"omitempty,min=0"`
+	Limit        int                `query:"limit" validate:"omitempty,min=1"` // Clamped to the max page size before validation
+	Offset       int                `query:"offset" validate:"omitempty,min=0"`
+	Sort         domain.ExampleSort `query:"-"` // Parsed from the sort query parameter
+	Include      usecase.Include    `query:"-"` // Parsed from the include query parameter
+	CreatedSince time.Time          `query:"-"` // Parsed from the created_since query parameter
+	UpdatedSince time.Time          `query:"-"` // Parsed from the updated_since query parameter
 }
 
 // ListExamplesResponseDTO represents the HTTP response for listing examples
 type ListExamplesResponseDTO struct {
-	Message    string                `json:"message,omitempty"`
-	Examples   []*ExampleResponseDTO `json:"examples"`
-	Total      int                   `json:"total"`
-	Limit      int                   `json:"limit"`
-	Offset     int                   `json:"offset"`
-	HasNext    bool                  `json:"has_next"`
-	HasPrev    bool                  `json:"has_prev"`
-	TotalPages int                   `json:"total_pages"`
+	XMLName    xml.Name              `json:"-" xml:"examples"`
+	Message    string                `json:"message,omitempty" xml:"message,omitempty"`
+	Examples   []*ExampleResponseDTO `json:"examples" xml:"example"`
+	Total      int                   `json:"total" xml:"total"`
+	Limit      int                   `json:"limit" xml:"limit"`
+	Offset     int                   `json:"offset" xml:"offset"`
+	HasNext    bool                  `json:"has_next" xml:"has_next"`
+	HasPrev    bool                  `json:"has_prev" xml:"has_prev"`
+	TotalPages int                   `json:"total_pages" xml:"total_pages"`
+}
+
+// BatchGetExamplesRequestDTO represents the HTTP request for getting several examples by ID
+type BatchGetExamplesRequestDTO struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100,dive,required"`
+}
+
+// BatchGetExamplesResponseDTO represents the HTTP response for getting several examples by ID
+type BatchGetExamplesResponseDTO struct {
+	XMLName  xml.Name              `json:"-" xml:"examples"`
+	Examples []*ExampleResponseDTO `json:"examples" xml:"example"`
+	NotFound []string              `json:"not_found" xml:"not_found>id"`
+}
+
+// BatchDeleteExamplesRequestDTO represents the HTTP request for deleting several examples by ID
+type BatchDeleteExamplesRequestDTO struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100,dive,required"`
+}
+
+// BatchDeleteExamplesResponseDTO represents the HTTP response for deleting several examples by ID
+type BatchDeleteExamplesResponseDTO struct {
+	XMLName  xml.Name `json:"-" xml:"deleted_examples"`
+	Deleted  []string `json:"deleted" xml:"deleted>id"`
+	NotFound []string `json:"not_found" xml:"not_found>id"`
 }
 
 // ErrorResponseDTO represents an error response
 type ErrorResponseDTO struct {
-	Error   string      `json:"error"`
-	Message string      `json:"message"`
-	Code    string      `json:"code,omitempty"`
-	Details interface{} `json:"details,omitempty"`
+	Error   string      `json:"error" xml:"error"`
+	Message string      `json:"message" xml:"message"`
+	Code    string      `json:"code,omitempty" xml:"code,omitempty"`
+	DocURL  string      `json:"doc_url,omitempty" xml:"doc_url,omitempty"` // Documentation of the error code, when configured
+	Details interface{} `json:"details,omitempty" xml:"details,omitempty"`
+	Stack   []string    `json:"stack,omitempty" xml:"stack>frame,omitempty"` // Where an internal error was created; development only
+}
+
+// MarshalXML implements xml.Marshaler, encoding map details as entries
+func (dto ErrorResponseDTO) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "error"} // A Marshaler is otherwise named after its type
+	type plain ErrorResponseDTO
+	return e.EncodeElement(struct {
+		plain
+		Details interface{} `xml:"details,omitempty"`
+	}{plain(dto), xmlValue(dto.Details)}, start)
 }
 
 // ValidationErrorResponseDTO represents a validation error response
 type ValidationErrorResponseDTO struct {
-	Error   string                              `json:"error"`
-	Message string                              `json:"message"`
-	Code    string                              `json:"code"`
-	Fields  []validator.ValidationFieldErrorDTO `json:"fields"`
+	XMLName xml.Name                            `json:"-" xml:"error"`
+	Error   string                              `json:"error" xml:"error"`
+	Message string                              `json:"message" xml:"message"`
+	Code    string                              `json:"code" xml:"code"`
+	Fields  []validator.ValidationFieldErrorDTO `json:"fields" xml:"fields>field"`
 }
 
 // SuccessResponseDTO represents a success response without data
 type SuccessResponseDTO struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	XMLName xml.Name `json:"-" xml:"result"`
+	Success bool     `json:"success" xml:"success"`
+	Message string   `json:"message" xml:"message"`
 }
 
 // HealthResponseDTO represents the health check response
@@ -89,6 +168,7 @@ type HealthResponseDTO struct {
 	Timestamp time.Time         `json:"timestamp"`
 	Version   string            `json:"version"`
 	Services  map[string]string `json:"services"`
+	Errors    map[string]string `json:"errors,omitempty"` // Failure reason for each unhealthy service
 }
 
 // Conversion functions from domain/usecase to DTO
@@ -98,7 +178,8 @@ func (dto *CreateExampleRequestDTO) ToCreateExampleRequest() usecase.CreateExamp
 	return usecase.CreateExampleRequest{
 		Name:  dto.Name,
 		Email: dto.Email,
-		Age:   dto.Age,
+		Phone: dto.Phone,
+		Age:   ageValue(dto.Age),
 	}
 }
 
@@ -107,19 +188,32 @@ func (dto *UpdateExampleRequestDTO) ToUpdateExampleRequest() usecase.UpdateExamp
 	return usecase.UpdateExampleRequest{
 		Name:  dto.Name,
 		Email: dto.Email,
-		Age:   dto.Age,
+		Phone: dto.Phone,
+		Age:   ageValue(dto.Age),
 	}
 }
 
-// ToListExamplesRequest converts DTO to usecase request
-func (dto *ListExamplesRequestDTO) ToListExamplesRequest() usecase.ListExamplesRequest {
-	limit := dto.Limit
-	if limit <= 0 {
-		limit = 10 // Default limit
+// ageValue returns the age a validated request carries; requests failing validation may leave it out
+func ageValue(age *int) int {
+	if age == nil {
+		return 0
 	}
-	if limit > 100 {
-		limit = 100 // Max limit
+	return *age
+}
+
+// ToPatchExampleRequest converts DTO to usecase request
+func (dto *PatchExampleRequestDTO) ToPatchExampleRequest() usecase.PatchExampleRequest {
+	return usecase.PatchExampleRequest{
+		Name:  dto.Name,
+		Email: dto.Email,
+		Phone: dto.Phone,
+		Age:   dto.Age,
 	}
+}
+
+// ToListExamplesRequest converts DTO to usecase request, applying the page sizes of pagination
+func (dto *ListExamplesRequestDTO) ToListExamplesRequest(pagination domain.Pagination) usecase.ListExamplesRequest {
+	limit := pagination.Limit(dto.Limit)
 
 	offset := dto.Offset
 	if offset < 0 {
@@ -127,20 +221,28 @@ func (dto *ListExamplesRequestDTO) ToListExamplesRequest() usecase.ListExamplesR
 	}
 
 	return usecase.ListExamplesRequest{
-		Limit:  limit,
-		Offset: offset,
+		Limit:        limit,
+		Offset:       offset,
+		Sort:         dto.Sort,
+		Include:      dto.Include,
+		CreatedSince: dto.CreatedSince,
+		UpdatedSince: dto.UpdatedSince,
 	}
 }
 
 // FromExampleWithMetadata converts usecase response to DTO
 func FromExampleWithMetadata(example *usecase.ExampleWithMetadata) *ExampleResponseDTO {
 	dto := &ExampleResponseDTO{
-		ID:        example.ID,
-		Name:      example.Name,
-		Email:     example.Email,
-		Age:       example.Age,
-		CreatedAt: example.CreatedAt,
-		UpdatedAt: example.UpdatedAt,
+		ID:               example.ID,
+		Name:             example.Name,
+		Email:            example.Email,
+		Phone:            example.Phone,
+		Age:              example.Age,
+		AgeCategory:      example.AgeCategory(),
+		CreatedAt:        example.CreatedAt,
+		UpdatedAt:        example.UpdatedAt,
+		Version:          example.Version,
+		EnrichmentStatus: string(example.EnrichmentStatus),
 	}
 
 	if example.ExternalData != nil {
@@ -162,15 +264,74 @@ func FromExampleWithMetadata(example *usecase.ExampleWithMetadata) *ExampleRespo
 // FromExample converts domain example to DTO (without external data)
 func FromExample(example *domain.Example) *ExampleResponseDTO {
 	return &ExampleResponseDTO{
-		ID:        example.ID,
-		Name:      example.Name,
-		Email:     example.Email,
-		Age:       example.Age,
-		CreatedAt: example.CreatedAt,
-		UpdatedAt: example.UpdatedAt,
+		ID:          example.ID,
+		Name:        example.Name,
+		Email:       example.Email,
+		Phone:       example.Phone,
+		Age:         example.Age,
+		AgeCategory: example.AgeCategory(),
+		CreatedAt:   example.CreatedAt,
+		UpdatedAt:   example.UpdatedAt,
+		Version:     example.Version,
+	}
+}
+
+// Import line statuses
+const (
+	ImportStatusCreated = "created"
+	ImportStatusFailed  = "failed"
+)
+
+// ImportLineResultDTO reports the outcome of one line of an NDJSON import
+type ImportLineResultDTO struct {
+	Line    int         `json:"line"`
+	Status  string      `json:"status" enums:"created,failed"`
+	ID      string      `json:"id,omitempty"`
+	Code    string      `json:"code,omitempty"`
+	Error   string      `json:"error,omitempty"`   // Localized message, as in error responses
+	Details interface{} `json:"details,omitempty"` // The error's details, such as the fields that failed validation
+}
+
+// ImportSummaryDTO counts the outcomes of an NDJSON import; it is the last line of the response
+type ImportSummaryDTO struct {
+	Summary ImportCountsDTO `json:"summary"`
+	Error   string          `json:"error,omitempty"` // Why the import stopped before the end of the body
+}
+
+// ImportCountsDTO holds the counts of an ImportSummaryDTO
+type ImportCountsDTO struct {
+	Total   int `json:"total"`
+	Created int `json:"created"`
+	Failed  int `json:"failed"`
+}
+
+// ExampleCSVHeader names the columns of ToCSVRecord
+var ExampleCSVHeader = []string{"id", "name", "email", "phone", "age", "created_at", "updated_at", "version"}
+
+// ToCSVRecord converts domain example to a CSV row in ExampleCSVHeader order. Text cells
+// are escaped with csvText so spreadsheets don't evaluate them as formulas.
+func ToCSVRecord(example *domain.Example) []string {
+	return []string{
+		csvText(example.ID),
+		csvText(example.Name),
+		csvText(example.Email),
+		csvText(example.Phone),
+		strconv.Itoa(example.Age),
+		example.CreatedAt.UTC().Format(time.RFC3339),
+		example.UpdatedAt.UTC().Format(time.RFC3339),
+		strconv.Itoa(example.Version),
 	}
 }
 
+// csvText prefixes value with a single quote if it starts with a character spreadsheets read
+// as the start of a formula, such as "=" or a phone number's "+", so it is shown as text
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
 // FromListExamplesResponse converts usecase response to DTO
 func FromListExamplesResponse(response *usecase.ListExamplesResponse) *ListExamplesResponseDTO {
 	examples := make([]*ExampleResponseDTO, len(response.Examples))
@@ -194,6 +355,39 @@ func FromListExamplesResponse(response *usecase.ListExamplesResponse) *ListExamp
 	}
 }
 
+// FromGetExamplesByIDsResponse converts usecase response to DTO
+func FromGetExamplesByIDsResponse(response *usecase.GetExamplesByIDsResponse) *BatchGetExamplesResponseDTO {
+	examples := make([]*ExampleResponseDTO, len(response.Examples))
+	for i, example := range response.Examples {
+		examples[i] = FromExampleWithMetadata(example)
+	}
+
+	notFound := response.NotFound
+	if notFound == nil {
+		notFound = []string{}
+	}
+
+	return &BatchGetExamplesResponseDTO{
+		Examples: examples,
+		NotFound: notFound,
+	}
+}
+
+// FromDeleteExamplesByIDsResponse converts usecase response to DTO
+func FromDeleteExamplesByIDsResponse(response *usecase.DeleteExamplesByIDsResponse) *BatchDeleteExamplesResponseDTO {
+	dto := &BatchDeleteExamplesResponseDTO{
+		Deleted:  response.Deleted,
+		NotFound: response.NotFound,
+	}
+	if dto.Deleted == nil {
+		dto.Deleted = []string{}
+	}
+	if dto.NotFound == nil {
+		dto.NotFound = []string{}
+	}
+	return dto
+}
+
 // NewErrorResponse creates a new error response
 func NewErrorResponse(code string, err error, message string, details interface{}) *ErrorResponseDTO {
 	return &ErrorResponseDTO{
@@ -222,12 +416,24 @@ func NewSuccessResponse(message string) *SuccessResponseDTO {
 	}
 }
 
-// NewHealthResponse creates a new health response
-func NewHealthResponse(version string, services map[string]string) *HealthResponseDTO {
-	return &HealthResponseDTO{
-		Status:    "healthy",
+// NewHealthResponse creates a new health response from a health report
+func NewHealthResponse(version string, report *health.Report) *HealthResponseDTO {
+	response := &HealthResponseDTO{
+		Status:    string(report.Status),
 		Timestamp: time.Now(),
 		Version:   version,
-		Services:  services,
+		Services:  make(map[string]string, len(report.Checks)),
 	}
+
+	for name, result := range report.Checks {
+		response.Services[name] = string(result.Status)
+		if result.Error != "" {
+			if response.Errors == nil {
+				response.Errors = make(map[string]string)
+			}
+			response.Errors[name] = result.Error
+		}
+	}
+
+	return response
 }
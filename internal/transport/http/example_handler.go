@@ -1,25 +1,37 @@
 package http
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"example-api-template/internal/domain"
 	"example-api-template/internal/errs"
 	"example-api-template/internal/usecase"
+	"example-api-template/pkg/i18n"
+	"example-api-template/pkg/logger"
 	"example-api-template/pkg/validator"
 
+	playground "github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
 )
 
 // Constants for validation and limits
 const (
-	DefaultLimit = 10
-	MaxLimit     = 100
-	MinAge       = 0
-	MaxAge       = 150
-	MinNameLen   = 1
-	MaxNameLen   = 100
+	MinAge     = 0
+	MaxAge     = 150
+	MinNameLen = 1
+	MaxNameLen = 100
 )
 
 // Error messages
@@ -27,39 +39,183 @@ const (
 	ErrMsgMissingID = "missing id"
 )
 
+// MIMEApplicationNDJSON is the content type of newline-delimited JSON imports and their results
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+// exportFlushRows is how many CSV rows are buffered before they are flushed to the client
+const exportFlushRows = 100
+
+// Default time each chunk of a streamed request may take to read, and of a streamed
+// response to write, unless WithStreamReadTimeout or WithStreamWriteTimeout set another
+const (
+	DefaultStreamReadTimeout  = 10 * time.Second
+	DefaultStreamWriteTimeout = 10 * time.Second
+)
+
+// StreamingRoutes are the example routes whose body can be of any length. They run without
+// the request timeout and response compression, so their handlers can extend the deadlines
+// of the connection as each chunk goes through.
+var StreamingRoutes = map[string]bool{
+	"/api/v1/examples/export.csv": true,
+	"/api/v1/examples/import":     true,
+}
+
+// IsStreamingRoute reports whether c matched one of StreamingRoutes; it is a middleware.Skipper
+func IsStreamingRoute(c echo.Context) bool {
+	return StreamingRoutes[c.Path()]
+}
+
 // ExampleHandler handles HTTP requests for examples
 type ExampleHandler struct {
 	useCase   usecase.ExampleUseCase
 	validator validator.Validator
+	localizer *i18n.Localizer // Optional; localizes response messages such as list summaries
+
+	idempotencyStore IdempotencyStore // Optional; makes creates with an Idempotency-Key safe to retry
+	idempotencyTTL   time.Duration
+
+	schemas *validator.JSONSchemaValidator // Optional; checks request bodies before binding
+
+	pagination domain.Pagination // Page sizes of the list endpoint
+
+	streamReadTimeout  time.Duration // Time each chunk of a streamed request may take to read
+	streamWriteTimeout time.Duration // Time each chunk of a streamed response may take to write
+}
+
+// ExampleHandlerOption configures an ExampleHandler
+type ExampleHandlerOption func(*ExampleHandler)
+
+// WithLocalizer localizes response messages in the request language
+func WithLocalizer(localizer *i18n.Localizer) ExampleHandlerOption {
+	return func(h *ExampleHandler) {
+		h.localizer = localizer
+	}
+}
+
+// WithIdempotency replays the stored response of creates repeated with the same
+// Idempotency-Key for ttl, instead of creating the example again
+func WithIdempotency(store IdempotencyStore, ttl time.Duration) ExampleHandlerOption {
+	return func(h *ExampleHandler) {
+		h.idempotencyStore = store
+		h.idempotencyTTL = ttl
+	}
+}
+
+// WithPagination sets the default and max page sizes of the list endpoint
+func WithPagination(pagination domain.Pagination) ExampleHandlerOption {
+	return func(h *ExampleHandler) {
+		h.pagination = pagination
+	}
+}
+
+// WithStreamReadTimeout sets how long each chunk of a streamed request, such as a line of an
+// import, may take to read. It replaces the server's read timeout on streaming routes.
+func WithStreamReadTimeout(timeout time.Duration) ExampleHandlerOption {
+	return func(h *ExampleHandler) {
+		h.streamReadTimeout = timeout
+	}
+}
+
+// WithStreamWriteTimeout sets how long each chunk of a streamed response, such as a batch of
+// exported rows, may take to write. It replaces the server's write timeout on streaming routes.
+func WithStreamWriteTimeout(timeout time.Duration) ExampleHandlerOption {
+	return func(h *ExampleHandler) {
+		h.streamWriteTimeout = timeout
+	}
+}
+
+// ExampleRequestSchemas names the JSON Schema file validating the body of each example route
+// that takes one, for WithJSONSchemas
+var ExampleRequestSchemas = map[string]string{
+	http.MethodPost + " /api/v1/examples":      "create_example.json",
+	http.MethodPut + " /api/v1/examples/:id":   "update_example.json",
+	http.MethodPatch + " /api/v1/examples/:id": "patch_example.json",
+}
+
+// WithJSONSchemas validates the bodies of the routes in ExampleRequestSchemas against the
+// schemas registered for them before binding
+func WithJSONSchemas(schemas *validator.JSONSchemaValidator) ExampleHandlerOption {
+	return func(h *ExampleHandler) {
+		h.schemas = schemas
+	}
+}
+
+// EmailChecker reports whether an example already uses an email
+type EmailChecker interface {
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+}
+
+// WithUniqueEmail fails validation of a CreateExampleRequestDTO whose email is already taken
+// with a unique_email field error, so clients get it alongside the other field errors instead
+// of a conflict once the rest is valid. The check is registered on the handler's validator.
+func WithUniqueEmail(checker EmailChecker) ExampleHandlerOption {
+	return func(h *ExampleHandler) {
+		h.validator.RegisterStructValidationCtx(uniqueEmailValidation(checker), CreateExampleRequestDTO{})
+	}
+}
+
+// uniqueEmailValidation returns a struct-level validation reporting a taken email. Lookup
+// failures pass, leaving the use case to report them.
+func uniqueEmailValidation(checker EmailChecker) playground.StructLevelFuncCtx {
+	return func(ctx context.Context, sl playground.StructLevel) {
+		req, ok := sl.Current().Interface().(CreateExampleRequestDTO)
+		if !ok || req.Email == "" {
+			return
+		}
+		if exists, err := checker.ExistsByEmail(ctx, req.Email); err == nil && exists {
+			sl.ReportError(req.Email, "email", "Email", "unique_email", "")
+		}
+	}
 }
 
 // NewExampleHandler creates a new example handler
 func NewExampleHandler(
 	useCase usecase.ExampleUseCase,
 	validator validator.Validator,
+	opts ...ExampleHandlerOption,
 ) *ExampleHandler {
-	return &ExampleHandler{
-		useCase:   useCase,
-		validator: validator,
+	h := &ExampleHandler{
+		useCase:    useCase,
+		validator:  validator,
+		pagination: domain.DefaultPagination,
+
+		streamReadTimeout:  DefaultStreamReadTimeout,
+		streamWriteTimeout: DefaultStreamWriteTimeout,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // RegisterRoutes registers all example routes
 func (h *ExampleHandler) RegisterRoutes(e *echo.Echo) {
 	api := e.Group("/api/v1")
 
+	var bodyMiddleware []echo.MiddlewareFunc
+	if h.schemas != nil {
+		bodyMiddleware = append(bodyMiddleware, JSONSchemaMiddleware(h.schemas))
+	}
+
+	createMiddleware := append([]echo.MiddlewareFunc(nil), bodyMiddleware...)
+	if h.idempotencyStore != nil {
+		createMiddleware = append(createMiddleware, IdempotencyMiddleware(h.idempotencyStore, h.idempotencyTTL))
+	}
+
 	// Example routes
 	examples := api.Group("/examples")
-	examples.POST("", h.CreateExample)
+	examples.POST("", h.CreateExample, createMiddleware...)
 	examples.GET("", h.ListExamples)
 	examples.GET("/:id", h.GetExample)
-	examples.PUT("/:id", h.UpdateExample)
+	examples.PUT("/:id", h.UpdateExample, bodyMiddleware...)
+	examples.PATCH("/:id", h.PatchExample, bodyMiddleware...)
 	examples.DELETE("/:id", h.DeleteExample)
 	examples.GET("/email/:email", h.GetExampleByEmail)
-	examples.POST("/validate", h.ValidateAndCreateExample)
-
-	// Health check
-	api.GET("/health", h.HealthCheck)
+	examples.POST("/validate", h.ValidateAndCreateExample, createMiddleware...)
+	examples.POST("/batch-get", h.BatchGetExamples)
+	examples.POST("/batch-delete", h.BatchDeleteExamples)
+	examples.GET("/export.csv", h.ExportExamplesCSV)
+	examples.POST("/import", h.ImportExamplesNDJSON)
 }
 
 // CreateExample creates a new example
@@ -67,10 +223,12 @@ func (h *ExampleHandler) RegisterRoutes(e *echo.Echo) {
 // @Description Create a new example with the provided data
 // @Tags examples
 // @Accept json
-// @Produce json
+// @Produce json,application/xml
 // @Param example body CreateExampleRequestDTO true "Example data"
+// @Param Idempotency-Key header string false "Replays the first response for retries with the same key and body"
 // @Success 201 {object} ExampleResponseDTO
 // @Failure 400 {object} ErrorResponseDTO
+// @Failure 409 {object} ErrorResponseDTO
 // @Failure 422 {object} ValidationErrorResponseDTO
 // @Failure 500 {object} ErrorResponseDTO
 // @Router /api/v1/examples [post]
@@ -81,7 +239,7 @@ func (h *ExampleHandler) CreateExample(c echo.Context) error {
 	}
 
 	// Validate request
-	if validationErrors, err := h.validator.ValidateStruct(&req); len(validationErrors) > 0 {
+	if validationErrors, err := h.validator.ValidateStructCtx(c.Request().Context(), &req); len(validationErrors) > 0 {
 		return errs.New(errs.ErrorCodeValidationFailed, err, validationErrors)
 	}
 
@@ -91,16 +249,18 @@ func (h *ExampleHandler) CreateExample(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusCreated, FromExampleWithMetadata(example))
+	return respond(c, http.StatusCreated, FromExampleWithMetadata(example))
 }
 
 // GetExample retrieves an example by ID
 // @Summary Get an example by ID
 // @Description Get an example by its ID
 // @Tags examples
-// @Produce json
+// @Produce json,application/xml
 // @Param id path string true "Example ID"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} ExampleResponseDTO
+// @Success 304 "Not modified"
 // @Failure 400 {object} ErrorResponseDTO
 // @Failure 404 {object} ErrorResponseDTO
 // @Failure 500 {object} ErrorResponseDTO
@@ -116,14 +276,21 @@ func (h *ExampleHandler) GetExample(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, FromExampleWithMetadata(example))
+	// Let clients revalidate cached copies without downloading the body again
+	etag := exampleETag(example.Version)
+	c.Response().Header().Set("ETag", etag)
+	if etagMatches(c.Request().Header.Get("If-None-Match"), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return respond(c, http.StatusOK, FromExampleWithMetadata(example))
 }
 
 // GetExampleByEmail retrieves an example by email
 // @Summary Get an example by email
 // @Description Get an example by its email address
 // @Tags examples
-// @Produce json
+// @Produce json,application/xml
 // @Param email path string true "Example email"
 // @Success 200 {object} ExampleResponseDTO
 // @Failure 400 {object} ErrorResponseDTO
@@ -141,7 +308,68 @@ func (h *ExampleHandler) GetExampleByEmail(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, FromExampleWithMetadata(example))
+	return respond(c, http.StatusOK, FromExampleWithMetadata(example))
+}
+
+// BatchGetExamples retrieves several examples by ID in one request
+// @Summary Get examples by IDs
+// @Description Get up to 100 examples by ID; IDs with no example are listed in not_found
+// @Tags examples
+// @Accept json
+// @Produce json,application/xml
+// @Param request body BatchGetExamplesRequestDTO true "Example IDs"
+// @Success 200 {object} BatchGetExamplesResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 422 {object} ValidationErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/v1/examples/batch-get [post]
+func (h *ExampleHandler) BatchGetExamples(c echo.Context) error {
+	var req BatchGetExamplesRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return errs.New(errs.ErrorCodeInvalidRequest, err, nil)
+	}
+
+	// Validate request
+	if validationErrors, err := h.validator.ValidateStructCtx(c.Request().Context(), &req); len(validationErrors) > 0 {
+		return errs.New(errs.ErrorCodeValidationFailed, err, validationErrors)
+	}
+
+	response, err := h.useCase.GetExamplesByIDs(c.Request().Context(), req.IDs)
+	if err != nil {
+		return err
+	}
+
+	return respond(c, http.StatusOK, FromGetExamplesByIDsResponse(response))
+}
+
+// BatchDeleteExamples deletes several examples by ID in one request
+// @Summary Delete examples by IDs
+// @Description Delete up to 100 examples by ID; IDs with no example are listed in not_found
+// @Tags examples
+// @Accept json
+// @Produce json,application/xml
+// @Param request body BatchDeleteExamplesRequestDTO true "Example IDs"
+// @Success 200 {object} BatchDeleteExamplesResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/v1/examples/batch-delete [post]
+func (h *ExampleHandler) BatchDeleteExamples(c echo.Context) error {
+	var req BatchDeleteExamplesRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return errs.New(errs.ErrorCodeInvalidRequest, err, nil)
+	}
+
+	// Validate request
+	if validationErrors, err := h.validator.ValidateStructCtx(c.Request().Context(), &req); len(validationErrors) > 0 {
+		return errs.New(errs.ErrorCodeValidationFailed, err, validationErrors)
+	}
+
+	response, err := h.useCase.DeleteExamplesByIDs(c.Request().Context(), req.IDs)
+	if err != nil {
+		return err
+	}
+
+	return respond(c, http.StatusOK, FromDeleteExamplesByIDsResponse(response))
 }
 
 // UpdateExample updates an existing example
@@ -149,12 +377,14 @@ func (h *ExampleHandler) GetExampleByEmail(c echo.Context) error {
 // @Description Update an existing example with the provided data
 // @Tags examples
 // @Accept json
-// @Produce json
+// @Produce json,application/xml
 // @Param id path string true "Example ID"
 // @Param example body UpdateExampleRequestDTO true "Updated example data"
+// @Param If-Match header string false "ETag of the version the update is based on, or its version field"
 // @Success 200 {object} ExampleResponseDTO
 // @Failure 400 {object} ErrorResponseDTO
 // @Failure 404 {object} ErrorResponseDTO
+// @Failure 409 {object} ErrorResponseDTO
 // @Failure 422 {object} ValidationErrorResponseDTO
 // @Failure 500 {object} ErrorResponseDTO
 // @Router /api/v1/examples/{id} [put]
@@ -164,29 +394,76 @@ func (h *ExampleHandler) UpdateExample(c echo.Context) error {
 		return errs.New(errs.ErrorCodeExampleIDRequired, errors.New(ErrMsgMissingID), nil)
 	}
 
+	expectedVersion, err := parseIfMatchVersion(c.Request().Header.Get("If-Match"))
+	if err != nil {
+		return errs.New(errs.ErrorCodeInvalidRequest, err, map[string]string{"header": "If-Match"})
+	}
+
 	var req UpdateExampleRequestDTO
 	if err := c.Bind(&req); err != nil {
 		return errs.New(errs.ErrorCodeInvalidRequest, err, nil)
 	}
 
 	// Validate request
-	if validationErrors, err := h.validator.ValidateStruct(&req); len(validationErrors) > 0 {
+	if validationErrors, err := h.validator.ValidateStructCtx(c.Request().Context(), &req); len(validationErrors) > 0 {
+		return errs.New(errs.ErrorCodeValidationFailed, err, validationErrors)
+	}
+
+	updateReq := req.ToUpdateExampleRequest()
+	updateReq.ExpectedVersion = expectedVersion
+
+	example, err := h.useCase.UpdateExample(c.Request().Context(), id, updateReq)
+	if err != nil {
+		return err
+	}
+
+	return respond(c, http.StatusOK, FromExampleWithMetadata(example))
+}
+
+// PatchExample partially updates an existing example
+// @Summary Partially update an example
+// @Description Update only the provided fields of an existing example; omitted fields are left unchanged
+// @Tags examples
+// @Accept json
+// @Produce json,application/xml
+// @Param id path string true "Example ID"
+// @Param example body PatchExampleRequestDTO true "Fields to update"
+// @Success 200 {object} ExampleResponseDTO
+// @Failure 400 {object} ErrorResponseDTO
+// @Failure 404 {object} ErrorResponseDTO
+// @Failure 409 {object} ErrorResponseDTO
+// @Failure 422 {object} ValidationErrorResponseDTO
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/v1/examples/{id} [patch]
+func (h *ExampleHandler) PatchExample(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return errs.New(errs.ErrorCodeExampleIDRequired, errors.New(ErrMsgMissingID), nil)
+	}
+
+	var req PatchExampleRequestDTO
+	if err := c.Bind(&req); err != nil {
+		return errs.New(errs.ErrorCodeInvalidRequest, err, nil)
+	}
+
+	// Validate request
+	if validationErrors, err := h.validator.ValidateStructCtx(c.Request().Context(), &req); len(validationErrors) > 0 {
 		return errs.New(errs.ErrorCodeValidationFailed, err, validationErrors)
 	}
 
-	example, err := h.useCase.UpdateExample(c.Request().Context(), id, req.ToUpdateExampleRequest())
+	example, err := h.useCase.PatchExample(c.Request().Context(), id, req.ToPatchExampleRequest())
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, FromExampleWithMetadata(example))
+	return respond(c, http.StatusOK, FromExampleWithMetadata(example))
 }
 
 // DeleteExample deletes an example
 // @Summary Delete an example
 // @Description Delete an example by its ID
 // @Tags examples
-// @Produce json
+// @Produce json,application/xml
 // @Param id path string true "Example ID"
 // @Success 200 {object} SuccessResponseDTO
 // @Failure 400 {object} ErrorResponseDTO
@@ -206,63 +483,338 @@ func (h *ExampleHandler) DeleteExample(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// parsePagination reads the limit and offset query parameters. A missing or zero limit
+// becomes the default page size and one above the max is clamped to it; values that aren't
+// non-negative integers are rejected with 400.
+func parsePagination(c echo.Context, pagination domain.Pagination) (limit, offset int, err error) {
+	if limit, err = parseNonNegativeQueryInt(c, "limit"); err != nil {
+		return 0, 0, err
+	}
+	if offset, err = parseNonNegativeQueryInt(c, "offset"); err != nil {
+		return 0, 0, err
+	}
+	return pagination.Limit(limit), offset, nil
+}
+
+// parseNonNegativeQueryInt parses the query parameter name, returning 0 when it is absent
+func parseNonNegativeQueryInt(c echo.Context, name string) (int, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, errs.New(errs.ErrorCodeInvalidRequest,
+			fmt.Errorf("invalid %s parameter %q", name, raw),
+			map[string]string{name: "must be a non-negative integer"})
+	}
+	return value, nil
+}
+
+// includeParts maps the values accepted by the include query parameter to the data they add
+var includeParts = map[string]usecase.Include{
+	"external":   usecase.IncludeExternal,
+	"enrichment": usecase.IncludeEnrichment,
+}
+
+// parseInclude reads the comma-separated include query parameter. Without it nothing is
+// included, so lists skip the external API.
+func parseInclude(c echo.Context) (usecase.Include, error) {
+	raw := c.QueryParam("include")
+	if raw == "" {
+		return usecase.IncludeNone, nil
+	}
+
+	include := usecase.IncludeNone
+	for _, name := range strings.Split(raw, ",") {
+		part, ok := includeParts[strings.TrimSpace(name)]
+		if !ok {
+			return usecase.IncludeNone, errs.New(errs.ErrorCodeInvalidRequest,
+				fmt.Errorf("invalid include value %q", name),
+				map[string]string{"include": "must be a comma-separated list of external, enrichment"})
+		}
+		include |= part
+	}
+	return include, nil
+}
+
+// parseQueryTime parses the RFC3339 query parameter name, returning the zero time when it is absent
+func parseQueryTime(c echo.Context, name string) (time.Time, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, errs.New(errs.ErrorCodeInvalidRequest,
+			fmt.Errorf("invalid %s parameter %q: %w", name, raw, err),
+			map[string]string{name: "must be an RFC3339 timestamp, e.g. 2026-01-02T15:04:05Z"})
+	}
+	return value, nil
+}
+
 // ListExamples retrieves a paginated list of examples
 // @Summary List examples
-// @Description Get a paginated list of examples
+// @Description Get a paginated list of examples. External data is only fetched for the parts named in include. created_since and updated_since keep examples created or updated at or after the given time, for clients syncing changes.
 // @Tags examples
-// @Produce json
-// @Param limit query int false "Number of examples to return (max 100)" default(10)
+// @Produce json,application/xml
+// @Param limit query int false "Number of examples to return (max 100 unless PAGINATION_MAX_LIMIT is set)" default(10)
 // @Param offset query int false "Number of examples to skip" default(0)
+// @Param sort query string false "Sort order; prefix with - for descending" Enums(created_at, -created_at, name, -name, age, -age) default(-created_at)
+// @Param include query string false "Comma-separated external data to include: external, enrichment"
+// @Param created_since query string false "Only examples created at or after this RFC3339 time" format(date-time)
+// @Param updated_since query string false "Only examples updated at or after this RFC3339 time" format(date-time)
 // @Success 200 {object} ListExamplesResponseDTO
+// @Header 200 {integer} X-Total-Count "Total number of examples"
+// @Header 200 {string} Link "RFC 5988 links to the first, prev, next and last pages"
 // @Failure 400 {object} ErrorResponseDTO
 // @Failure 500 {object} ErrorResponseDTO
 // @Router /api/v1/examples [get]
 func (h *ExampleHandler) ListExamples(c echo.Context) error {
 	var req ListExamplesRequestDTO
 
-	// Parse query parameters with proper error handling
-	if limitStr := c.QueryParam("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			req.Limit = limit
-		} else {
-			return errs.New(errs.ErrorCodeInvalidRequest,
-				errors.New("invalid limit parameter"),
-				map[string]string{"limit": "must be a valid integer"})
-		}
+	limit, offset, err := parsePagination(c, h.pagination)
+	if err != nil {
+		return err
 	}
+	req.Limit, req.Offset = limit, offset
 
-	if offsetStr := c.QueryParam("offset"); offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil {
-			req.Offset = offset
-		} else {
-			return errs.New(errs.ErrorCodeInvalidRequest,
-				errors.New("invalid offset parameter"),
-				map[string]string{"offset": "must be a valid integer"})
-		}
+	// Only whitelisted sort fields are accepted, so column names can't be injected
+	sort, err := domain.ParseExampleSort(c.QueryParam("sort"))
+	if err != nil {
+		return errs.New(errs.ErrorCodeInvalidRequest, err,
+			map[string]string{"sort": "must be one of " + strings.Join(domain.SortValues(), ", ")})
 	}
+	req.Sort = sort
 
-	// Set defaults if not provided
-	if req.Limit <= 0 {
-		req.Limit = DefaultLimit
+	if req.Include, err = parseInclude(c); err != nil {
+		return err
 	}
-	if req.Limit > MaxLimit {
-		req.Limit = MaxLimit
+	if req.CreatedSince, err = parseQueryTime(c, "created_since"); err != nil {
+		return err
 	}
-	if req.Offset < 0 {
-		req.Offset = 0
+	if req.UpdatedSince, err = parseQueryTime(c, "updated_since"); err != nil {
+		return err
 	}
 
 	// Validate request
-	if validationErrors, err := h.validator.ValidateStruct(&req); len(validationErrors) > 0 {
+	if validationErrors, err := h.validator.ValidateStructCtx(c.Request().Context(), &req); len(validationErrors) > 0 {
 		return errs.New(errs.ErrorCodeValidationFailed, err, validationErrors)
 	}
 
-	response, err := h.useCase.ListExamples(c.Request().Context(), req.ToListExamplesRequest())
+	response, err := h.useCase.ListExamples(c.Request().Context(), req.ToListExamplesRequest(h.pagination))
 	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, FromListExamplesResponse(response))
+	dto := FromListExamplesResponse(response)
+	if h.localizer != nil {
+		dto.Message = h.localizer.LocalizePlural(c.Request().Context(), "examples_found", response.Total, nil)
+	}
+
+	setPaginationHeaders(c, response.Total, response.Limit, response.Offset)
+	return respond(c, http.StatusOK, dto)
+}
+
+// ExportExamplesCSV streams every example as CSV
+// @Summary Export examples as CSV
+// @Description Download every example as a CSV file, streamed in ID order without external data
+// @Tags examples
+// @Produce text/csv
+// @Success 200 {file} file "CSV with a header row: id, name, email, phone, age, created_at, updated_at, version"
+// @Failure 500 {object} ErrorResponseDTO
+// @Router /api/v1/examples/export.csv [get]
+func (h *ExampleHandler) ExportExamplesCSV(c echo.Context) error {
+	res := c.Response()
+	flusher, _ := res.Writer.(http.Flusher)
+	writer := csv.NewWriter(res)
+
+	// The response starts with the first row, so a failure before it is still reported as an error
+	flush := func() error {
+		h.extendWriteDeadline(c)
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+	start := func() error {
+		h.extendWriteDeadline(c)
+		header := res.Header()
+		header.Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		header.Set(echo.HeaderContentDisposition, `attachment; filename="examples.csv"`)
+		res.WriteHeader(http.StatusOK)
+		return writer.Write(ExampleCSVHeader)
+	}
+
+	rows := 0
+	err := h.useCase.ExportExamples(c.Request().Context(), func(example *domain.Example) error {
+		if !res.Committed {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(ToCSVRecord(example)); err != nil {
+			return err
+		}
+		rows++
+		if rows%exportFlushRows == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !res.Committed {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+	return flush()
+}
+
+// extendReadDeadline gives the next chunk of a streamed request the stream read timeout, since
+// the server's read timeout would otherwise cut off a long upload. Like extendWriteDeadline it
+// leaves connections that can't set deadlines as they are.
+func (h *ExampleHandler) extendReadDeadline(c echo.Context) {
+	_ = http.NewResponseController(c.Response().Writer).SetReadDeadline(time.Now().Add(h.streamReadTimeout))
+}
+
+// extendWriteDeadline gives the next chunk of a streamed response the stream write timeout,
+// since the server's write timeout would otherwise cut off a long stream. Writers that can't
+// set deadlines, such as test recorders, are left as they are.
+func (h *ExampleHandler) extendWriteDeadline(c echo.Context) {
+	_ = http.NewResponseController(c.Response().Writer).SetWriteDeadline(time.Now().Add(h.streamWriteTimeout))
+}
+
+// ImportExamplesNDJSON creates one example per line of a newline-delimited JSON body
+// @Summary Import examples from NDJSON
+// @Description Create one example per line of the body, each shaped like CreateExampleRequestDTO. A failed line does not stop the import. The response streams one ImportLineResultDTO per non-blank line, then an ImportSummaryDTO, which is sent even when the import stops early and then carries the reason in error.
+// @Tags examples
+// @Accept application/x-ndjson
+// @Produce application/x-ndjson
+// @Param examples body CreateExampleRequestDTO true "One example per line"
+// @Success 200 {object} ImportLineResultDTO
+// @Failure 413 {object} ErrorResponseDTO
+// @Router /api/v1/examples/import [post]
+func (h *ExampleHandler) ImportExamplesNDJSON(c echo.Context) error {
+	ctx := c.Request().Context()
+	reader := bufio.NewReader(c.Request().Body)
+	res := c.Response()
+	encoder := json.NewEncoder(res)
+	flusher, _ := res.Writer.(http.Flusher)
+
+	// Each result is flushed so the client sees progress; the response starts with the first one
+	write := func(v interface{}) error {
+		h.extendWriteDeadline(c)
+		if !res.Committed {
+			res.Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+			res.WriteHeader(http.StatusOK)
+		}
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	summary := ImportSummaryDTO{}
+	counts := &summary.Summary
+	for line := 1; ; line++ {
+		// Stop reading once the client is gone, still reporting what was imported so far
+		if err := ctx.Err(); err != nil {
+			summary.Error = err.Error()
+			break
+		}
+
+		h.extendReadDeadline(c)
+		data, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			if !res.Committed {
+				return errs.New(errs.ErrorCodeInvalidRequest, readErr, nil)
+			}
+			counts.Total++
+			counts.Failed++
+			if err := write(h.importFailure(ctx, line, errs.New(errs.ErrorCodeInvalidRequest, readErr, nil))); err != nil {
+				return err
+			}
+			break
+		}
+
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			result := h.importLine(c, line, data)
+			counts.Total++
+			if result.Status == ImportStatusCreated {
+				counts.Created++
+			} else {
+				counts.Failed++
+			}
+			if err := write(result); err != nil {
+				return err
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	return write(summary)
+}
+
+// importLine decodes, validates and creates the example on one line of an import
+func (h *ExampleHandler) importLine(c echo.Context, line int, data []byte) ImportLineResultDTO {
+	var req CreateExampleRequestDTO
+	if err := json.Unmarshal(data, &req); err != nil {
+		return h.importFailure(c.Request().Context(), line, errs.New(errs.ErrorCodeInvalidRequest, err, nil))
+	}
+
+	if validationErrors, err := h.validator.ValidateStructCtx(c.Request().Context(), &req); len(validationErrors) > 0 {
+		return h.importFailure(c.Request().Context(), line, errs.New(errs.ErrorCodeValidationFailed, err, validationErrors))
+	}
+
+	example, err := h.useCase.CreateExample(c.Request().Context(), req.ToCreateExampleRequest())
+	if err != nil {
+		return h.importFailure(c.Request().Context(), line, err)
+	}
+
+	return ImportLineResultDTO{Line: line, Status: ImportStatusCreated, ID: example.ID}
+}
+
+// importFailure reports a failed import line with the machine code, localized message and
+// details the error handler would respond with. The underlying error may hold database or
+// driver text, so it is only logged.
+func (h *ExampleHandler) importFailure(ctx context.Context, line int, err error) ImportLineResultDTO {
+	var appErr *errs.AppError
+	if !errors.As(err, &appErr) {
+		appErr = errs.New(errs.ErrorCodeInternalError, err, nil)
+	}
+	logger.GetGlobal().WithContext(ctx).Warn("Import line failed",
+		zap.Int("line", line),
+		zap.String("code", string(appErr.Code)),
+		zap.Error(appErr.Err),
+	)
+
+	message := http.StatusText(appErr.GetHTTPStatus())
+	if h.localizer != nil {
+		message = appErr.LocalizeWithContext(h.localizer, ctx).Message
+	}
+	return ImportLineResultDTO{
+		Line:    line,
+		Status:  ImportStatusFailed,
+		Code:    appErr.MachineCode,
+		Error:   message,
+		Details: appErr.Details,
+	}
 }
 
 // ValidateAndCreateExample creates an example with external validation
@@ -270,10 +822,12 @@ func (h *ExampleHandler) ListExamples(c echo.Context) error {
 // @Description Create a new example with external API validation
 // @Tags examples
 // @Accept json
-// @Produce json
+// @Produce json,application/xml
 // @Param example body CreateExampleRequestDTO true "Example data"
+// @Param Idempotency-Key header string false "Replays the first response for retries with the same key and body"
 // @Success 201 {object} ExampleResponseDTO
 // @Failure 400 {object} ErrorResponseDTO
+// @Failure 409 {object} ErrorResponseDTO
 // @Failure 422 {object} ValidationErrorResponseDTO
 // @Failure 500 {object} ErrorResponseDTO
 // @Router /api/v1/examples/validate [post]
@@ -284,7 +838,7 @@ func (h *ExampleHandler) ValidateAndCreateExample(c echo.Context) error {
 	}
 
 	// Validate request
-	if validationErrors, err := h.validator.ValidateStruct(&req); len(validationErrors) > 0 {
+	if validationErrors, err := h.validator.ValidateStructCtx(c.Request().Context(), &req); len(validationErrors) > 0 {
 		return errs.New(errs.ErrorCodeValidationFailed, err, validationErrors)
 	}
 
@@ -293,23 +847,74 @@ func (h *ExampleHandler) ValidateAndCreateExample(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusCreated, FromExampleWithMetadata(example))
+	return respond(c, http.StatusCreated, FromExampleWithMetadata(example))
+}
+
+// setPaginationHeaders sets X-Total-Count and RFC 5988 Link headers for the first,
+// prev, next and last pages. Links keep the request's other query parameters.
+func setPaginationHeaders(c echo.Context, total, limit, offset int) {
+	header := c.Response().Header()
+	header.Set("X-Total-Count", strconv.Itoa(total))
+
+	pageURL := func(offset int) string {
+		u := *c.Request().URL
+		u.Scheme, u.Host = c.Scheme(), c.Request().Host
+		query := u.Query()
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(offset))
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = (total - 1) / limit * limit
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(0))}
+	if offset > 0 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(max(offset-limit, 0))))
+	}
+	if offset+limit < total {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(offset+limit)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(lastOffset)))
+	header.Set("Link", strings.Join(links, ", "))
+}
+
+// exampleETag builds a weak ETag from the example version, which the repository increments
+// on every update, so the ETag can be sent back in If-Match
+func exampleETag(version int) string {
+	return fmt.Sprintf(`W/"%d"`, version)
+}
+
+// etagMatches reports whether an If-None-Match header matches etag using weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
 }
 
-// HealthCheck returns the health status of the service
-// @Summary Health check
-// @Description Get the health status of the service
-// @Tags health
-// @Produce json
-// @Success 200 {object} HealthResponseDTO
-// @Router /api/v1/health [get]
-func (h *ExampleHandler) HealthCheck(c echo.Context) error {
-	services := map[string]string{
-		"database":     "healthy",
-		"external_api": "healthy",
-		"cache":        "not_configured",
+// parseIfMatchVersion reads the example version from an If-Match header holding the ETag
+// of a GET, such as W/"3", or the bare version; an empty header or "*" returns 0, which
+// skips the version check
+func parseIfMatchVersion(ifMatch string) (int, error) {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
 	}
 
-	response := NewHealthResponse("1.0.0", services)
-	return c.JSON(http.StatusOK, response)
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`))
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("If-Match must be a positive example version, got %q", ifMatch)
+	}
+	return version, nil
 }
@@ -0,0 +1,1300 @@
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"example-api-template/internal/domain"
+	"example-api-template/internal/errs"
+	"example-api-template/internal/repository"
+	"example-api-template/internal/service"
+	"example-api-template/internal/usecase"
+	"example-api-template/pkg/i18n"
+	"example-api-template/pkg/validator"
+	"example-api-template/tests/fixtures"
+	"example-api-template/tests/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestServer wires the example handler to an in-memory repository and mock external API
+func newTestServer(t *testing.T) (*echo.Echo, repository.ExampleRepository) {
+	t.Helper()
+
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	repo := repository.NewInMemoryExampleRepository()
+	svc := service.NewExampleService(repo, zap.NewNop(), service.DefaultBusinessRules())
+	uc := usecase.NewExampleUseCase(svc, repository.NewMockExternalExampleAPI(false, 0), zap.NewNop())
+
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+	e.Use(I18nMiddleware(localizer))
+	NewExampleHandler(uc, validator.New(validator.WithLocalizer(localizer)), WithLocalizer(localizer)).RegisterRoutes(e)
+
+	return e, repo
+}
+
+func TestExampleHandlerGetExampleETag(t *testing.T) {
+	e, repo := newTestServer(t)
+	example := fixtures.ValidExample()
+	require.NoError(t, repo.Create(context.Background(), example))
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples/"+example.ID, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Contains(t, first.Body.String(), example.ID)
+
+	t.Run("matching ETag returns 304 with empty body", func(t *testing.T) {
+		rec := get(etag)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+		assert.Equal(t, etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("ETag in a list matches", func(t *testing.T) {
+		rec := get(`"other", ` + etag)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+
+	t.Run("stale ETag returns the example", func(t *testing.T) {
+		rec := get(`W/"stale"`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("ETag changes after an update", func(t *testing.T) {
+		example.UpdatedAt = example.UpdatedAt.Add(time.Second)
+		require.NoError(t, repo.Update(context.Background(), example))
+
+		rec := get(etag)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	})
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := exampleETag(3)
+
+	assert.True(t, etagMatches(etag, etag))
+	assert.True(t, etagMatches(`"3"`, etag))
+	assert.True(t, etagMatches("*", etag))
+	assert.True(t, etagMatches(`"a", W/"3"`, etag))
+	assert.False(t, etagMatches("", etag))
+	assert.False(t, etagMatches(`W/"4"`, etag))
+}
+
+func TestExampleHandlerPatchExample(t *testing.T) {
+	patch := func(e *echo.Echo, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/examples/"+id, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantName   string
+		wantAge    int
+	}{
+		{name: "only age", body: `{"age":45}`, wantStatus: http.StatusOK, wantName: "John Doe", wantAge: 45},
+		{name: "only name", body: `{"name":"Jane Doe"}`, wantStatus: http.StatusOK, wantName: "Jane Doe", wantAge: 30},
+		{name: "empty patch", body: `{}`, wantStatus: http.StatusOK, wantName: "John Doe", wantAge: 30},
+		{name: "invalid age", body: `{"age":200}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, repo := newTestServer(t)
+			example := fixtures.ValidExample()
+			require.NoError(t, repo.Create(context.Background(), example))
+
+			rec := patch(e, example.ID, tt.body)
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			stored, err := repo.GetByID(context.Background(), example.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, stored.Name)
+			assert.Equal(t, tt.wantAge, stored.Age)
+			assert.Equal(t, "john.doe@example.com", stored.Email)
+		})
+	}
+
+	t.Run("unknown example", func(t *testing.T) {
+		e, _ := newTestServer(t)
+
+		rec := patch(e, "missing", `{"age":40}`)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestExampleHandlerPhone(t *testing.T) {
+	e, _ := newTestServer(t)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/api/v1/examples", `{"name":"Jane Doe","email":"jane@example.com","phone":"12345","age":30}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"tag":"phone"`)
+
+	rec = send(http.MethodPost, "/api/v1/examples", `{"name":"Jane Doe","email":"jane@example.com","phone":"+14155552671","age":30}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created ExampleResponseDTO
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "+14155552671", created.Phone)
+
+	// An empty phone in a patch removes it
+	rec = send(http.MethodPatch, "/api/v1/examples/"+created.ID, `{"phone":""}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NotContains(t, rec.Body.String(), `"phone"`)
+}
+
+func TestExampleHandlerAgeBounds(t *testing.T) {
+	e, _ := newTestServer(t)
+
+	// Age 0 is a valid age, not a missing one
+	rec := fixtures.PostJSON(t, e, http.MethodPost, "/api/v1/examples", fixtures.ExampleJSON("Baby Doe", "baby@example.com", 0))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created ExampleResponseDTO
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, 0, created.Age)
+
+	rec = fixtures.PostJSON(t, e, http.MethodPut, "/api/v1/examples/"+created.ID, fixtures.ExampleJSON("Baby Doe", "baby@example.com", 0))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = fixtures.PostJSON(t, e, http.MethodPost, "/api/v1/examples", fixtures.ExampleJSON("Old Doe", "old@example.com", 151))
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"tag":"max"`)
+
+	rec = fixtures.PostJSON(t, e, http.MethodPost, "/api/v1/examples", `{"name":"Ageless Doe","email":"ageless@example.com"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"tag":"required","value":""`)
+
+	rec = fixtures.PostJSON(t, e, http.MethodPut, "/api/v1/examples/"+created.ID, `{"name":"Baby Doe","email":"baby@example.com"}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"tag":"required"`)
+}
+
+func TestExampleHandlerRequestFixtures(t *testing.T) {
+	e, _ := newTestServer(t)
+
+	rec := fixtures.PostJSON(t, e, http.MethodPost, "/api/v1/examples", fixtures.ValidCreateExampleJSON())
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created ExampleResponseDTO
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	for _, payload := range fixtures.InvalidCreateExamplePayloads() {
+		t.Run("create with "+payload.Name, func(t *testing.T) {
+			rec := fixtures.PostJSON(t, e, http.MethodPost, "/api/v1/examples", payload.Body)
+			require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), `"field":"`+payload.Field+`"`)
+		})
+	}
+
+	rec = fixtures.PostJSON(t, e, http.MethodPut, "/api/v1/examples/"+created.ID, fixtures.ValidUpdateExampleJSON())
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	for _, payload := range fixtures.InvalidUpdateExamplePayloads() {
+		t.Run("update with "+payload.Name, func(t *testing.T) {
+			rec := fixtures.PostJSON(t, e, http.MethodPut, "/api/v1/examples/"+created.ID, payload.Body)
+			require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), `"field":"`+payload.Field+`"`)
+		})
+	}
+
+	// Non-string bodies are marshalled
+	rec = fixtures.PostJSON(t, e, http.MethodPatch, "/api/v1/examples/"+created.ID, map[string]int{"age": 40})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"age":40`)
+}
+
+func TestExampleHandlerBatchGetExamples(t *testing.T) {
+	e, repo := newTestServer(t)
+	example := fixtures.ValidExample()
+	require.NoError(t, repo.Create(context.Background(), example))
+
+	batchGet := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/examples/batch-get", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("mixed found and not found", func(t *testing.T) {
+		rec := batchGet(fmt.Sprintf(`{"ids":["missing",%q]}`, example.ID))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var res BatchGetExamplesResponseDTO
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		require.Len(t, res.Examples, 1)
+		assert.Equal(t, example.ID, res.Examples[0].ID)
+		assert.Equal(t, []string{"missing"}, res.NotFound)
+	})
+
+	t.Run("all found", func(t *testing.T) {
+		rec := batchGet(fmt.Sprintf(`{"ids":[%q]}`, example.ID))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Contains(t, rec.Body.String(), `"not_found":[]`)
+	})
+
+	t.Run("no ids", func(t *testing.T) {
+		rec := batchGet(`{"ids":[]}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestExampleHandlerBatchDeleteExamples(t *testing.T) {
+	e, repo := newTestServer(t)
+
+	create := func(name string) string {
+		example := fixtures.ValidExample()
+		example.ID, example.Email = "example-"+name, name+"@example.com"
+		require.NoError(t, repo.Create(context.Background(), example))
+		return example.ID
+	}
+	batchDelete := func(ids ...string) BatchDeleteExamplesResponseDTO {
+		rec := fixtures.PostJSON(t, e, http.MethodPost, "/api/v1/examples/batch-delete", map[string][]string{"ids": ids})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var res BatchDeleteExamplesResponseDTO
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return res
+	}
+
+	t.Run("all found", func(t *testing.T) {
+		first, second := create("first"), create("second")
+
+		res := batchDelete(first, second)
+		assert.Equal(t, []string{first, second}, res.Deleted)
+		assert.Empty(t, res.NotFound)
+
+		_, err := repo.GetByID(context.Background(), first)
+		assert.ErrorIs(t, err, repository.ErrExampleNotFound)
+	})
+
+	t.Run("some missing", func(t *testing.T) {
+		id := create("third")
+
+		res := batchDelete("missing", id)
+		assert.Equal(t, []string{id}, res.Deleted)
+		assert.Equal(t, []string{"missing"}, res.NotFound)
+
+		// Already deleted IDs are reported as not found
+		res = batchDelete(id)
+		assert.Empty(t, res.Deleted)
+		assert.Equal(t, []string{id}, res.NotFound)
+	})
+
+	t.Run("no ids", func(t *testing.T) {
+		rec := fixtures.PostJSON(t, e, http.MethodPost, "/api/v1/examples/batch-delete", `{"ids":[]}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	})
+}
+
+// flushRecorder records the largest amount of body written between flushes, and the read
+// and write deadlines set through an http.ResponseController
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes        int
+	pending        int
+	maxUnflushed   int
+	readDeadlines  []time.Time
+	writeDeadlines []time.Time
+}
+
+func (r *flushRecorder) SetReadDeadline(deadline time.Time) error {
+	r.readDeadlines = append(r.readDeadlines, deadline)
+	return nil
+}
+
+func (r *flushRecorder) SetWriteDeadline(deadline time.Time) error {
+	r.writeDeadlines = append(r.writeDeadlines, deadline)
+	return nil
+}
+
+func (r *flushRecorder) Write(b []byte) (int, error) {
+	r.pending += len(b)
+	r.maxUnflushed = max(r.maxUnflushed, r.pending)
+	return r.ResponseRecorder.Write(b)
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes++
+	r.pending = 0
+	r.ResponseRecorder.Flush()
+}
+
+func TestExampleHandlerExportExamplesCSV(t *testing.T) {
+	t.Run("streams every example", func(t *testing.T) {
+		const total = 1000
+
+		e, repo := newTestServer(t)
+		for i := 0; i < total; i++ {
+			example := fixtures.ValidExampleWithCustomData(fmt.Sprintf("ex_%04d", i), "Export User", fmt.Sprintf("export%d@example.com", i), 30)
+			require.NoError(t, repo.Create(context.Background(), example))
+		}
+
+		rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/examples/export.csv", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, `attachment; filename="examples.csv"`, rec.Header().Get(echo.HeaderContentDisposition))
+
+		records, err := csv.NewReader(rec.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, total+1)
+		assert.Equal(t, ExampleCSVHeader, records[0])
+		assert.Equal(t, "ex_0000", records[1][0])
+		assert.Equal(t, "export0@example.com", records[1][2])
+
+		// Rows are flushed as they are written instead of buffered into one response
+		assert.GreaterOrEqual(t, rec.flushes, total/exportFlushRows)
+		assert.Less(t, rec.maxUnflushed, 32*1024)
+
+		// Each flushed batch gets a fresh write deadline
+		assert.GreaterOrEqual(t, len(rec.writeDeadlines), rec.flushes)
+		assert.WithinDuration(t, time.Now().Add(DefaultStreamWriteTimeout), rec.writeDeadlines[len(rec.writeDeadlines)-1], time.Second)
+	})
+
+	t.Run("cells that read as formulas are escaped", func(t *testing.T) {
+		e, repo := newTestServer(t)
+		example := fixtures.ValidExampleWithCustomData("ex_formula", "Formula User", "formula@example.com", 30)
+		require.NoError(t, example.SetPhone("+14155552671"))
+		example.Name = `=HYPERLINK("http://evil.example","x")`
+		example.Email = "@SUM(A1)@example.com"
+		require.NoError(t, repo.Create(context.Background(), example))
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/examples/export.csv", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		records, err := csv.NewReader(rec.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "ex_formula", records[1][0])
+		assert.Equal(t, `'=HYPERLINK("http://evil.example","x")`, records[1][1])
+		assert.Equal(t, "'@SUM(A1)@example.com", records[1][2])
+		assert.Equal(t, "'+14155552671", records[1][3])
+		assert.Equal(t, "30", records[1][4])
+	})
+
+	t.Run("empty export has only the header", func(t *testing.T) {
+		e, _ := newTestServer(t)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/examples/export.csv", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, strings.Join(ExampleCSVHeader, ",")+"\n", rec.Body.String())
+	})
+
+	t.Run("failure before the first row is an error response", func(t *testing.T) {
+		mockService := &mocks.MockExampleService{}
+		mockService.On("ExportExamples", mock.Anything, mock.Anything).
+			Return(nil, errs.New(errs.ErrorCodeDatabaseError, errors.New("connection refused"), nil))
+		uc := usecase.NewExampleUseCase(mockService, &mocks.MockExternalExampleAPI{}, zap.NewNop())
+		localizer, err := i18n.NewLocalizer(&i18n.Config{
+			DefaultLanguage: "en",
+			Languages:       []string{"en"},
+			TranslationDir:  "../../../translations",
+		})
+		require.NoError(t, err)
+
+		e := echo.New()
+		e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+		NewExampleHandler(uc, validator.New()).RegisterRoutes(e)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/examples/export.csv", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+	})
+}
+
+func TestExampleHandlerImportExamplesNDJSON(t *testing.T) {
+	importBody := func(e *echo.Echo, ctx context.Context, body string) *flushRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/examples/import", strings.NewReader(body)).WithContext(ctx)
+		req.Header.Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+		rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("keeps going past failed lines", func(t *testing.T) {
+		e, repo := newTestServer(t)
+
+		body := strings.Join([]string{
+			`{"name":"Alice Smith","email":"alice@example.com","age":25}`,
+			`{"name":"","email":"not-an-email","age":30}`,
+			``,
+			`{"name":"Broken", "email":`,
+			`{"name":"Bob Johnson","email":"bob@example.com","age":35}`,
+			`{"name":"Alice Again","email":"alice@example.com","age":40}`,
+		}, "\n")
+		rec := importBody(e, context.Background(), body)
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, MIMEApplicationNDJSON, rec.Header().Get(echo.HeaderContentType))
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		require.Len(t, lines, 6)
+
+		results := make([]ImportLineResultDTO, 5)
+		for i := range results {
+			require.NoError(t, json.Unmarshal([]byte(lines[i]), &results[i]))
+		}
+
+		assert.Equal(t, 1, results[0].Line)
+		assert.Equal(t, ImportStatusCreated, results[0].Status)
+		assert.NotEmpty(t, results[0].ID)
+
+		assert.Equal(t, 2, results[1].Line)
+		assert.Equal(t, ImportStatusFailed, results[1].Status)
+		assert.Equal(t, "VALIDATION_FAILED", results[1].Code)
+		assert.NotEmpty(t, results[1].Details)
+
+		// The blank line 3 is skipped but still counted
+		assert.Equal(t, 4, results[2].Line)
+		assert.Equal(t, ImportStatusFailed, results[2].Status)
+		assert.Equal(t, "INVALID_REQUEST", results[2].Code)
+		assert.NotEmpty(t, results[2].Error)
+
+		assert.Equal(t, 5, results[3].Line)
+		assert.Equal(t, ImportStatusCreated, results[3].Status)
+
+		assert.Equal(t, 6, results[4].Line)
+		assert.Equal(t, ImportStatusFailed, results[4].Status)
+		assert.Equal(t, "EXAMPLE_ALREADY_EXISTS", results[4].Code)
+
+		var summary ImportSummaryDTO
+		require.NoError(t, json.Unmarshal([]byte(lines[5]), &summary))
+		assert.Equal(t, ImportCountsDTO{Total: 5, Created: 2, Failed: 3}, summary.Summary)
+
+		count, err := repo.Count(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		// Every line read and every result written gets a fresh deadline
+		assert.Len(t, rec.readDeadlines, 6)
+		assert.Len(t, rec.writeDeadlines, 6)
+		assert.WithinDuration(t, time.Now().Add(DefaultStreamReadTimeout), rec.readDeadlines[5], time.Second)
+	})
+
+	t.Run("empty body reports an empty summary", func(t *testing.T) {
+		e, _ := newTestServer(t)
+
+		rec := importBody(e, context.Background(), "")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"summary":{"total":0,"created":0,"failed":0}}`, rec.Body.String())
+	})
+
+	t.Run("stops when the request context is done", func(t *testing.T) {
+		e, repo := newTestServer(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		rec := importBody(e, ctx, `{"name":"Alice Smith","email":"alice@example.com","age":25}`)
+
+		count, err := repo.Count(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		// The summary still ends the response, saying why the import stopped
+		assert.JSONEq(t, `{"summary":{"total":0,"created":0,"failed":0},"error":"context canceled"}`, rec.Body.String())
+	})
+}
+
+func TestExampleHandlerImportFailure(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+	h := &ExampleHandler{localizer: localizer}
+	driverErr := errors.New(`pq: duplicate key value violates unique constraint "examples_email_key"`)
+
+	t.Run("application errors report their public message", func(t *testing.T) {
+		result := h.importFailure(context.Background(), 3, errs.New(errs.ErrorCodeDatabaseError, driverErr, nil))
+
+		assert.Equal(t, 3, result.Line)
+		assert.Equal(t, ImportStatusFailed, result.Status)
+		assert.Equal(t, "DATABASE_ERROR", result.Code)
+		assert.NotEmpty(t, result.Error)
+		assert.NotContains(t, result.Error, "constraint")
+	})
+
+	t.Run("other errors are reported as internal", func(t *testing.T) {
+		result := h.importFailure(context.Background(), 1, driverErr)
+
+		assert.Equal(t, "INTERNAL_ERROR", result.Code)
+		assert.NotContains(t, result.Error, "constraint")
+	})
+
+	t.Run("without a localizer the status text is reported", func(t *testing.T) {
+		result := (&ExampleHandler{}).importFailure(context.Background(), 1, errs.New(errs.ErrorCodeDatabaseError, driverErr, nil))
+
+		assert.Equal(t, http.StatusText(http.StatusInternalServerError), result.Error)
+	})
+}
+
+func TestExampleHandlerUpdateExampleIfMatch(t *testing.T) {
+	body := `{"name":"Jane Doe","email":"john.doe@example.com","age":31}`
+	put := func(e *echo.Echo, id, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/examples/"+id, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name        string
+		ifMatch     string
+		wantStatus  int
+		wantVersion int
+	}{
+		{name: "no header updates unconditionally", wantStatus: http.StatusOK, wantVersion: 2},
+		{name: "wildcard updates unconditionally", ifMatch: "*", wantStatus: http.StatusOK, wantVersion: 2},
+		{name: "current version", ifMatch: `"1"`, wantStatus: http.StatusOK, wantVersion: 2},
+		{name: "current ETag", ifMatch: `W/"1"`, wantStatus: http.StatusOK, wantVersion: 2},
+		{name: "stale ETag", ifMatch: `W/"5"`, wantStatus: http.StatusConflict, wantVersion: 1},
+		{name: "stale version", ifMatch: `"5"`, wantStatus: http.StatusConflict, wantVersion: 1},
+		{name: "malformed header", ifMatch: `W/"abc"`, wantStatus: http.StatusBadRequest, wantVersion: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, repo := newTestServer(t)
+			example := fixtures.ValidExample()
+			require.NoError(t, repo.Create(context.Background(), example))
+
+			rec := put(e, example.ID, tt.ifMatch)
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+
+			stored, err := repo.GetByID(context.Background(), example.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, stored.Version)
+			if tt.wantStatus == http.StatusOK {
+				assert.Contains(t, rec.Body.String(), `"version":2`)
+			}
+		})
+	}
+}
+
+func TestExampleHandlerUpdateExampleWithETag(t *testing.T) {
+	e, repo := newTestServer(t)
+	example := fixtures.ValidExample()
+	require.NoError(t, repo.Create(context.Background(), example))
+
+	get := httptest.NewRecorder()
+	e.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/api/v1/examples/"+example.ID, nil))
+	require.Equal(t, http.StatusOK, get.Code)
+	etag := get.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	put := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/examples/"+example.ID,
+			strings.NewReader(`{"name":"Jane Doe","email":"john.doe@example.com","age":31}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("If-Match", etag)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := put()
+	require.Equal(t, http.StatusOK, first.Code, first.Body.String())
+	assert.Contains(t, first.Body.String(), `"version":2`)
+
+	// The ETag now names a stale version
+	second := put()
+	assert.Equal(t, http.StatusConflict, second.Code, second.Body.String())
+}
+
+func TestExampleHandlerListExamplesSort(t *testing.T) {
+	e, repo := newTestServer(t)
+	for i, person := range []struct {
+		name string
+		age  int
+	}{{"Carol Jones", 41}, {"Alice Smith", 29}, {"Bob Brown", 35}} {
+		example := fixtures.ValidExample()
+		example.ID = fmt.Sprintf("ex_sort_%d", i)
+		example.Name = person.name
+		example.Email = fmt.Sprintf("sort%d@example.com", i)
+		example.Age = person.age
+		require.NoError(t, repo.Create(context.Background(), example))
+	}
+
+	list := func(sort string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples?sort="+sort, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	names := func(rec *httptest.ResponseRecorder) []string {
+		var resp ListExamplesResponseDTO
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		got := make([]string, len(resp.Examples))
+		for i, example := range resp.Examples {
+			got[i] = example.Name
+		}
+		return got
+	}
+
+	tests := map[string][]string{
+		"name":  {"Alice Smith", "Bob Brown", "Carol Jones"},
+		"-name": {"Carol Jones", "Bob Brown", "Alice Smith"},
+		"age":   {"Alice Smith", "Bob Brown", "Carol Jones"},
+		"-age":  {"Carol Jones", "Bob Brown", "Alice Smith"},
+	}
+	for sort, expected := range tests {
+		t.Run(sort, func(t *testing.T) {
+			rec := list(sort)
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, expected, names(rec))
+		})
+	}
+
+	t.Run("unknown field is rejected", func(t *testing.T) {
+		rec := list("email")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "sort")
+	})
+}
+
+// parseLinkHeader maps each rel in an RFC 5988 Link header to its URL
+func parseLinkHeader(t *testing.T, header string) map[string]*url.URL {
+	t.Helper()
+
+	links := make(map[string]*url.URL)
+	for _, link := range strings.Split(header, ", ") {
+		target, params, ok := strings.Cut(link, "; ")
+		require.True(t, ok, "malformed link %q", link)
+		rel := strings.TrimSuffix(strings.TrimPrefix(params, `rel="`), `"`)
+
+		u, err := url.Parse(strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">"))
+		require.NoError(t, err)
+		links[rel] = u
+	}
+	return links
+}
+
+func TestExampleHandlerListExamplesPaginationHeaders(t *testing.T) {
+	e, repo := newTestServer(t)
+	for i := 0; i < 5; i++ {
+		example := fixtures.ValidExample()
+		example.ID = fmt.Sprintf("ex_page_%d", i)
+		example.Email = fmt.Sprintf("page%d@example.com", i)
+		require.NoError(t, repo.Create(context.Background(), example))
+	}
+
+	list := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples?"+query, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec
+	}
+
+	t.Run("middle page links to all four pages", func(t *testing.T) {
+		rec := list("limit=2&offset=2&sort=name")
+		assert.Equal(t, "5", rec.Header().Get("X-Total-Count"))
+
+		links := parseLinkHeader(t, rec.Header().Get("Link"))
+		require.Len(t, links, 4)
+		for rel, offset := range map[string]string{"first": "0", "prev": "0", "next": "4", "last": "4"} {
+			require.Contains(t, links, rel)
+			assert.Equal(t, "/api/v1/examples", links[rel].Path, rel)
+			assert.Equal(t, "2", links[rel].Query().Get("limit"), rel)
+			assert.Equal(t, offset, links[rel].Query().Get("offset"), rel)
+			assert.Equal(t, "name", links[rel].Query().Get("sort"), rel)
+		}
+
+		// The JSON body keeps its own pagination fields
+		var resp ListExamplesResponseDTO
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.True(t, resp.HasNext)
+		assert.True(t, resp.HasPrev)
+		assert.Equal(t, 3, resp.TotalPages)
+	})
+
+	t.Run("first page has no prev", func(t *testing.T) {
+		links := parseLinkHeader(t, list("limit=2").Header().Get("Link"))
+		assert.NotContains(t, links, "prev")
+		assert.Equal(t, "2", links["next"].Query().Get("offset"))
+		assert.Equal(t, "4", links["last"].Query().Get("offset"))
+	})
+
+	t.Run("last page has no next", func(t *testing.T) {
+		links := parseLinkHeader(t, list("limit=2&offset=4").Header().Get("Link"))
+		assert.NotContains(t, links, "next")
+		assert.Equal(t, "2", links["prev"].Query().Get("offset"))
+	})
+}
+
+func TestExampleHandlerListExamplesPaginationParams(t *testing.T) {
+	e, _ := newTestServer(t)
+
+	list := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples?"+query, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, tc := range []struct {
+		query string
+		param string
+	}{
+		{"limit=abc", "limit"},
+		{"limit=-1", "limit"},
+		{"offset=notanumber", "offset"},
+		{"offset=-5", "offset"},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			rec := list(tc.query)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.param)
+		})
+	}
+
+	t.Run("limit is defaulted and clamped", func(t *testing.T) {
+		for query, want := range map[string]int{"": domain.DefaultPagination.DefaultLimit, "limit=0": domain.DefaultPagination.DefaultLimit, "limit=1000": domain.DefaultPagination.MaxLimit} {
+			rec := list(query)
+			require.Equal(t, http.StatusOK, rec.Code, query)
+
+			var resp ListExamplesResponseDTO
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, want, resp.Limit, query)
+		}
+	})
+}
+
+func TestExampleHandlerListExamplesConfiguredPagination(t *testing.T) {
+	pagination := domain.Pagination{DefaultLimit: 20, MaxLimit: 50}
+	repo := repository.NewInMemoryExampleRepository()
+	svc := service.NewExampleService(repo, zap.NewNop(), service.DefaultBusinessRules(), service.WithPagination(pagination))
+	uc := usecase.NewExampleUseCaseWithConfig(svc, repository.NewMockExternalExampleAPI(false, 0), zap.NewNop(),
+		usecase.UseCaseConfig{Pagination: pagination})
+	e := echo.New()
+	NewExampleHandler(uc, validator.New(), WithPagination(pagination)).RegisterRoutes(e)
+
+	for query, want := range map[string]int{"limit=200": 50, "": 20} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples?"+query, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var resp ListExamplesResponseDTO
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, want, resp.Limit, query)
+		assert.Contains(t, rec.Header().Get("Link"), fmt.Sprintf("limit=%d", want), query)
+	}
+
+	dto := ListExamplesRequestDTO{Limit: 200}
+	assert.Equal(t, 50, dto.ToListExamplesRequest(pagination).Limit)
+}
+
+func TestExampleHandlerListExamplesSince(t *testing.T) {
+	e, repo := newTestServer(t)
+
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	old := fixtures.ValidExample()
+	old.CreatedAt, old.UpdatedAt = base.Add(-time.Hour), base.Add(-time.Hour)
+	recent := fixtures.ValidExample()
+	recent.ID, recent.Email = "example-2", "recent@example.com"
+	recent.CreatedAt, recent.UpdatedAt = base.Add(-time.Hour), base
+	require.NoError(t, repo.Create(context.Background(), old))
+	require.NoError(t, repo.Create(context.Background(), recent))
+
+	list := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples?"+query, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := list("updated_since=" + base.Format(time.RFC3339))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp ListExamplesResponseDTO
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Examples, 1)
+	assert.Equal(t, "example-2", resp.Examples[0].ID)
+	assert.Equal(t, 1, resp.Total)
+
+	rec = list("created_since=" + base.Format(time.RFC3339))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Examples)
+
+	for _, query := range []string{"created_since=yesterday", "updated_since=2026-01-01"} {
+		rec := list(query)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		assert.Contains(t, rec.Body.String(), strings.SplitN(query, "=", 2)[0], query)
+	}
+}
+
+func TestExampleHandlerListExamplesFilteredTotal(t *testing.T) {
+	e, repo := newTestServer(t)
+
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		example := fixtures.ValidExample()
+		example.ID, example.Email = fmt.Sprintf("example-%d", i), fmt.Sprintf("user%d@example.com", i)
+		example.CreatedAt, example.UpdatedAt = base.Add(-time.Hour), base.Add(-time.Hour)
+		if i < 3 {
+			example.UpdatedAt = base
+		}
+		require.NoError(t, repo.Create(context.Background(), example))
+	}
+
+	// The total counts the filtered set, not every example, even when a page holds fewer
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/examples?limit=2&updated_since="+base.Format(time.RFC3339), nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp ListExamplesResponseDTO
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp.Examples, 2)
+	assert.Equal(t, 3, resp.Total)
+}
+
+func TestExampleHandlerListExamplesInclude(t *testing.T) {
+	e, repo := newTestServer(t)
+	require.NoError(t, repo.Create(context.Background(), fixtures.ValidExample()))
+
+	list := func(query string) (*httptest.ResponseRecorder, ListExamplesResponseDTO) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples?"+query, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		var resp ListExamplesResponseDTO
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		}
+		return rec, resp
+	}
+
+	t.Run("bare by default", func(t *testing.T) {
+		_, resp := list("")
+		require.Len(t, resp.Examples, 1)
+		assert.Nil(t, resp.Examples[0].ExternalData)
+		assert.Nil(t, resp.Examples[0].Enrichment)
+	})
+
+	t.Run("includes the requested parts", func(t *testing.T) {
+		_, resp := list("include=external")
+		require.Len(t, resp.Examples, 1)
+		assert.NotNil(t, resp.Examples[0].ExternalData)
+		assert.Nil(t, resp.Examples[0].Enrichment)
+
+		_, resp = list("include=external,enrichment")
+		require.Len(t, resp.Examples, 1)
+		assert.NotNil(t, resp.Examples[0].ExternalData)
+		assert.NotNil(t, resp.Examples[0].Enrichment)
+	})
+
+	t.Run("unknown part is rejected", func(t *testing.T) {
+		rec, _ := list("include=external,bogus")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "include")
+	})
+}
+
+func TestExampleHandlerRequestTimeout(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	svc := &mocks.MockExampleService{}
+	externalAPI := &mocks.MockExternalExampleAPI{}
+	uc := usecase.NewExampleUseCase(svc, externalAPI, zap.NewNop())
+
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+	e.Use(RequestTimeoutMiddleware(50 * time.Millisecond))
+	NewExampleHandler(uc, validator.New()).RegisterRoutes(e)
+
+	get := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples/"+id, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("slow service is cancelled with 504", func(t *testing.T) {
+		cancelled := make(chan error, 1)
+		svc.On("GetExampleByID", mock.Anything, "ex_slow").Run(func(args mock.Arguments) {
+			ctx := args.Get(0).(context.Context)
+			select {
+			case <-ctx.Done():
+				cancelled <- ctx.Err()
+			case <-time.After(time.Second):
+				cancelled <- nil
+			}
+		}).Return(nil, context.DeadlineExceeded)
+
+		start := time.Now()
+		rec := get("ex_slow")
+		assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+		assert.Contains(t, rec.Body.String(), "REQUEST_TIMEOUT")
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.ErrorIs(t, <-cancelled, context.DeadlineExceeded)
+	})
+
+	t.Run("external calls nest under the request deadline", func(t *testing.T) {
+		example := fixtures.ValidExample()
+
+		var requestDeadline, externalDeadline time.Time
+		svc.On("GetExampleByID", mock.Anything, example.ID).Run(func(args mock.Arguments) {
+			requestDeadline, _ = args.Get(0).(context.Context).Deadline()
+		}).Return(example, nil)
+		externalAPI.On("GetExampleData", mock.Anything, example.ID).Run(func(args mock.Arguments) {
+			externalDeadline, _ = args.Get(0).(context.Context).Deadline()
+		}).Return(&repository.ExternalExampleData{ExternalID: "ext"}, nil)
+		externalAPI.On("EnrichExample", mock.Anything, example.ID).Return(map[string]interface{}{}, nil)
+
+		rec := get(example.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.False(t, requestDeadline.IsZero())
+		// The use case's own 30s external timeout is cut short by the request's deadline
+		assert.Equal(t, requestDeadline, externalDeadline)
+	})
+}
+
+func TestExampleHandlerContentNegotiation(t *testing.T) {
+	e, repo := newTestServer(t)
+	example := fixtures.ValidExample()
+	require.NoError(t, repo.Create(context.Background(), example))
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set(echo.HeaderAccept, accept)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("JSON by default and when requested", func(t *testing.T) {
+		for _, accept := range []string{"", "*/*", echo.MIMEApplicationJSON, "application/json, application/xml;q=0.9"} {
+			rec := get("/api/v1/examples/"+example.ID, accept)
+			require.Equal(t, http.StatusOK, rec.Code, accept)
+			assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON, accept)
+
+			var response ExampleResponseDTO
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response), accept)
+			assert.Equal(t, example.ID, response.ID)
+		}
+	})
+
+	t.Run("XML example", func(t *testing.T) {
+		rec := get("/api/v1/examples/"+example.ID, "application/xml")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationXML)
+
+		var response struct {
+			XMLName  xml.Name `xml:"example"`
+			ID       string   `xml:"id"`
+			Email    string   `xml:"email"`
+			Age      int      `xml:"age"`
+			Metadata []struct {
+				Key   string `xml:"key,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"external_data>metadata>entry"`
+		}
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, example.ID, response.ID)
+		assert.Equal(t, example.Email, response.Email)
+		assert.Equal(t, example.Age, response.Age)
+		assert.NotEmpty(t, response.Metadata)
+	})
+
+	t.Run("XML list", func(t *testing.T) {
+		rec := get("/api/v1/examples", "text/html, application/xml;q=0.9, */*;q=0.8")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response struct {
+			XMLName  xml.Name `xml:"examples"`
+			Total    int      `xml:"total"`
+			Examples []struct {
+				ID string `xml:"id"`
+			} `xml:"example"`
+		}
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 1, response.Total)
+		require.Len(t, response.Examples, 1)
+		assert.Equal(t, example.ID, response.Examples[0].ID)
+	})
+
+	t.Run("XML error", func(t *testing.T) {
+		rec := get("/api/v1/examples/missing", "application/xml")
+		require.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationXML)
+
+		var response struct {
+			XMLName xml.Name `xml:"error"`
+			Code    string   `xml:"code"`
+			Message string   `xml:"message"`
+			Details []struct {
+				Key   string `xml:"key,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"details>entry"`
+		}
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "EXAMPLE_NOT_FOUND", response.Code)
+		assert.NotEmpty(t, response.Message)
+		assert.NotEmpty(t, response.Details)
+	})
+
+	t.Run("JSON error", func(t *testing.T) {
+		rec := get("/api/v1/examples/missing", echo.MIMEApplicationJSON)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+
+		var response ErrorResponseDTO
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "EXAMPLE_NOT_FOUND", response.Code)
+		assert.Empty(t, response.DocURL)
+	})
+
+	t.Run("JSON error links to the docs", func(t *testing.T) {
+		errs.DocBaseURL = "https://docs.example.com/errors"
+		t.Cleanup(func() { errs.DocBaseURL = "" })
+
+		rec := get("/api/v1/examples/missing", echo.MIMEApplicationJSON)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+
+		var response ErrorResponseDTO
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "https://docs.example.com/errors#example_not_found", response.DocURL)
+	})
+}
+
+func TestPrefersXML(t *testing.T) {
+	assert.False(t, prefersXML(""))
+	assert.False(t, prefersXML("*/*"))
+	assert.False(t, prefersXML("application/json"))
+	assert.False(t, prefersXML("application/json, application/xml"))
+	assert.False(t, prefersXML("application/xml;q=0.5, application/json"))
+	assert.True(t, prefersXML("application/xml"))
+	assert.True(t, prefersXML("text/xml"))
+	assert.True(t, prefersXML("application/xml, */*;q=0.1"))
+	assert.True(t, prefersXML("application/json;q=0.5, application/xml"))
+}
+
+func TestExampleHandlerListExamplesMessage(t *testing.T) {
+	e, repo := newTestServer(t)
+	require.NoError(t, repo.Create(context.Background(), fixtures.ValidExample()))
+
+	list := func(lang string) ListExamplesResponseDTO {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples?lang="+lang, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response ListExamplesResponseDTO
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	assert.Equal(t, "1 example found", list("en").Message)
+	assert.Equal(t, "พบตัวอย่าง 1 รายการ", list("th").Message)
+
+	second := fixtures.ValidExample()
+	second.ID = "example-2"
+	second.Email = "second@example.com"
+	require.NoError(t, repo.Create(context.Background(), second))
+	assert.Equal(t, "2 examples found", list("en").Message)
+}
+
+func TestExampleHandlerValidationMessageLanguage(t *testing.T) {
+	e, _ := newTestServer(t)
+
+	create := func(lang string) string {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/examples", strings.NewReader(`{"name":"","email":"jane@example.com","age":30}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Accept-Language", lang)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		return rec.Body.String()
+	}
+
+	assert.Contains(t, create("en"), `"message":"name is required"`)
+	assert.Contains(t, create("th"), `"message":"name เป็นสิ่งจำเป็น"`)
+}
+
+func TestExampleHandlerJSONSchemas(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	schemas := validator.NewJSONSchemaValidator()
+	for route, file := range ExampleRequestSchemas {
+		require.NoError(t, schemas.AddSchemaFile(route, "../../../schemas/"+file))
+	}
+
+	repo := repository.NewInMemoryExampleRepository()
+	svc := service.NewExampleService(repo, zap.NewNop(), service.DefaultBusinessRules())
+	uc := usecase.NewExampleUseCase(svc, repository.NewMockExternalExampleAPI(false, 0), zap.NewNop())
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+	NewExampleHandler(uc, validator.New(), WithJSONSchemas(schemas)).RegisterRoutes(e)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("unknown property is rejected before binding", func(t *testing.T) {
+		rec := send(http.MethodPost, "/api/v1/examples", `{"name":"John Doe","email":"john@example.com","age":30,"role":"admin"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "additionalProperties")
+		assert.Contains(t, rec.Body.String(), "role")
+		count, err := repo.Count(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("wrong type is reported at its JSON pointer", func(t *testing.T) {
+		example := fixtures.ValidExample()
+		require.NoError(t, repo.Create(context.Background(), example))
+
+		rec := send(http.MethodPatch, "/api/v1/examples/"+example.ID, `{"age":"thirty"}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"/age"`)
+	})
+
+	t.Run("matching body passes", func(t *testing.T) {
+		rec := send(http.MethodPost, "/api/v1/examples", `{"name":"Jane Doe","email":"jane@example.com","age":28}`)
+
+		assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	})
+
+	t.Run("non-JSON body is rejected", func(t *testing.T) {
+		for _, contentType := range []string{echo.MIMEApplicationForm, echo.MIMEApplicationXML, ""} {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/examples",
+				strings.NewReader("name=Mallory&email=mallory@example.com&age=30&role=admin"))
+			if contentType != "" {
+				req.Header.Set(echo.HeaderContentType, contentType)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code, "content type %q", contentType)
+		}
+
+		_, err := repo.GetByEmail(context.Background(), "mallory@example.com")
+		assert.Error(t, err)
+	})
+}
+
+func TestExampleHandlerUniqueEmail(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	repo := repository.NewInMemoryExampleRepository()
+	require.NoError(t, repo.Create(context.Background(), fixtures.ValidExample()))
+	svc := service.NewExampleService(repo, zap.NewNop(), service.DefaultBusinessRules())
+	uc := usecase.NewExampleUseCase(svc, repository.NewMockExternalExampleAPI(false, 0), zap.NewNop())
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+	NewExampleHandler(uc, validator.New(), WithUniqueEmail(repo)).RegisterRoutes(e)
+
+	create := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/examples", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("taken email fails validation", func(t *testing.T) {
+		rec := create(`{"name":"John Smith","email":"john.doe@example.com","age":40}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "unique_email")
+		assert.Contains(t, rec.Body.String(), "email is already taken")
+	})
+
+	t.Run("taken email is reported with the other field errors", func(t *testing.T) {
+		rec := create(`{"name":"","email":"john.doe@example.com","age":40}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "unique_email")
+		assert.Contains(t, rec.Body.String(), "name is required")
+	})
+
+	t.Run("available email passes", func(t *testing.T) {
+		rec := create(`{"name":"Jane Doe","email":"jane.doe@example.com","age":28}`)
+
+		assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	})
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+
+	"example-api-template/pkg/health"
+
+	"github.com/labstack/echo/v4"
+)
+
+// LivenessResponseDTO represents the liveness probe response
+type LivenessResponseDTO struct {
+	Status string `json:"status"`
+}
+
+// HealthHandler reports the health of the service and its dependencies
+type HealthHandler struct {
+	checks    *health.Aggregator
+	readiness *health.Aggregator
+	version   string
+}
+
+// NewHealthHandler creates a new health handler. checks back the detailed health
+// report; readiness holds only what must be up before the service takes traffic.
+func NewHealthHandler(checks, readiness *health.Aggregator, version string) *HealthHandler {
+	return &HealthHandler{
+		checks:    checks,
+		readiness: readiness,
+		version:   version,
+	}
+}
+
+// RegisterRoutes registers all health routes; the probes live at the root for Kubernetes
+func (h *HealthHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/healthz", h.Liveness)
+	e.GET("/readyz", h.Readiness)
+	e.GET("/api/v1/health", h.HealthCheck)
+}
+
+// Liveness reports that the process is up and serving requests
+// @Summary Liveness probe
+// @Description Always succeeds while the process can serve HTTP
+// @Tags health
+// @Produce json
+// @Success 200 {object} LivenessResponseDTO
+// @Router /healthz [get]
+func (h *HealthHandler) Liveness(c echo.Context) error {
+	return c.JSON(http.StatusOK, LivenessResponseDTO{Status: "alive"})
+}
+
+// Readiness reports whether the service can take traffic
+// @Summary Readiness probe
+// @Description Succeeds once migrations have run and the database and message queue are connected; reports degraded while serving from the in-memory database fallback
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthResponseDTO
+// @Failure 503 {object} HealthResponseDTO
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(c echo.Context) error {
+	return h.respond(c, h.readiness)
+}
+
+// HealthCheck probes every dependency and returns their status
+// @Summary Health check
+// @Description Probe the service dependencies; any unhealthy dependency makes the service unhealthy
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthResponseDTO
+// @Failure 503 {object} HealthResponseDTO
+// @Router /api/v1/health [get]
+func (h *HealthHandler) HealthCheck(c echo.Context) error {
+	return h.respond(c, h.checks)
+}
+
+// respond runs checks and maps the report to 200 or 503
+func (h *HealthHandler) respond(c echo.Context, checks *health.Aggregator) error {
+	report := checks.Run(c.Request().Context())
+
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+
+	return c.JSON(status, NewHealthResponse(h.version, report))
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"example-api-template/pkg/health"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandlerHealthCheck(t *testing.T) {
+	passing := func(name string) health.Checker {
+		return health.NewCheck(name, func(ctx context.Context) error { return nil })
+	}
+
+	tests := []struct {
+		name         string
+		checks       []health.Checker
+		wantStatus   int
+		wantServices map[string]string
+		wantErrors   map[string]string
+	}{
+		{
+			name:         "all dependencies healthy",
+			checks:       []health.Checker{passing("database"), passing("external_api")},
+			wantStatus:   http.StatusOK,
+			wantServices: map[string]string{"database": "healthy", "external_api": "healthy"},
+		},
+		{
+			name: "failing dependency",
+			checks: []health.Checker{
+				passing("database"),
+				health.NewCheck("external_api", func(ctx context.Context) error { return errors.New("connection refused") }),
+			},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantServices: map[string]string{"database": "healthy", "external_api": "unhealthy"},
+			wantErrors:   map[string]string{"external_api": "connection refused"},
+		},
+		{
+			name: "unconfigured dependency does not fail the check",
+			checks: []health.Checker{
+				passing("database"),
+				health.NewCheck("mq", func(ctx context.Context) error { return health.ErrNotConfigured }),
+			},
+			wantStatus:   http.StatusOK,
+			wantServices: map[string]string{"database": "healthy", "mq": "not_configured"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregator := health.NewAggregator(time.Second)
+			aggregator.Register(tt.checks...)
+
+			e := echo.New()
+			NewHealthHandler(aggregator, health.NewAggregator(time.Second), "1.2.3").RegisterRoutes(e)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+
+			var body HealthResponseDTO
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, "1.2.3", body.Version)
+			assert.Equal(t, tt.wantServices, body.Services)
+			assert.Equal(t, tt.wantErrors, body.Errors)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "healthy", body.Status)
+			} else {
+				assert.Equal(t, "unhealthy", body.Status)
+			}
+		})
+	}
+}
+
+func TestHealthHandlerProbes(t *testing.T) {
+	migrations := health.NewGate("migrations")
+	var connected atomic.Bool
+
+	readiness := health.NewAggregator(time.Second)
+	readiness.Register(migrations, health.NewConnectionCheck("mq", connected.Load))
+
+	e := echo.New()
+	NewHealthHandler(health.NewAggregator(time.Second), readiness, "1.2.3").RegisterRoutes(e)
+
+	probe := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	// Liveness never depends on dependencies
+	assert.Equal(t, http.StatusOK, probe("/healthz").Code)
+
+	rec := probe("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"migrations":"unhealthy"`)
+
+	// Migrated but the producer is not connected yet
+	migrations.Open()
+	rec = probe("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"mq":"unhealthy"`)
+
+	connected.Store(true)
+	assert.Equal(t, http.StatusOK, probe("/readyz").Code)
+
+	// Losing the broker makes the service unready again while staying alive
+	connected.Store(false)
+	assert.Equal(t, http.StatusServiceUnavailable, probe("/readyz").Code)
+	assert.Equal(t, http.StatusOK, probe("/healthz").Code)
+}
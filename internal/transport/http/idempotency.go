@@ -0,0 +1,259 @@
+package http
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"example-api-template/internal/errs"
+	"example-api-template/pkg/ctxkeys"
+	"example-api-template/pkg/logger"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// Idempotency headers
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed" // Set on responses replayed from an earlier request
+)
+
+// maxIdempotencyKeyLength bounds the keys clients may send
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyRecord is what an IdempotencyStore keeps for a key
+type IdempotencyRecord struct {
+	Fingerprint string // Hash of the request body the key was first used with
+	Completed   bool   // False while the first request is still running
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// IdempotencyStore remembers the response of each Idempotency-Key for a while. The in-memory
+// store serves a single instance; a shared store such as Redis maps Reserve to SET NX,
+// Complete to SET XX and Release to DEL.
+type IdempotencyStore interface {
+	// Reserve marks key as in flight for fingerprint. If key is already known, the existing
+	// record is returned instead and nothing changes.
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error)
+	// Complete stores the response of the request that reserved key
+	Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error
+	// Release forgets key, so a request that failed can be retried with it
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key safe to retry: the first
+// successful response is stored and replayed for later requests with the same key and body,
+// without running the handler again. Keys are scoped to the route and authenticated user.
+// Reusing a key with a different body fails with 422, and while the first request is still
+// running with 409. Failed requests are not stored, so they can be retried.
+func IdempotencyMiddleware(store IdempotencyStore, ttl time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				return next(c)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return errs.New(errs.ErrorCodeBadRequest,
+					fmt.Errorf("%s must be at most %d characters", HeaderIdempotencyKey, maxIdempotencyKeyLength), nil)
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return errs.New(errs.ErrorCodeInvalidRequest, err, nil)
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := c.Request().Context()
+			scopedKey := idempotencyScope(c, key)
+			sum := sha256.Sum256(body)
+			fingerprint := hex.EncodeToString(sum[:])
+
+			existing, err := store.Reserve(ctx, scopedKey, fingerprint, ttl)
+			if err != nil {
+				return errs.New(errs.ErrorCodeInternalError, fmt.Errorf("reserve idempotency key: %w", err), nil)
+			}
+			if existing != nil {
+				return replayIdempotent(c, existing, fingerprint)
+			}
+
+			res := c.Response()
+			recorder := &bodyRecorder{ResponseWriter: res.Writer}
+			res.Writer = recorder
+			defer func() { res.Writer = recorder.ResponseWriter }()
+
+			// Store updates outlive a cancelled request, or the key would stay in flight
+			storeCtx := context.WithoutCancel(ctx)
+			err = next(c)
+			if err != nil || res.Status < http.StatusOK || res.Status >= http.StatusMultipleChoices {
+				if releaseErr := store.Release(storeCtx, scopedKey); releaseErr != nil {
+					logger.GetGlobal().WithContext(ctx).Warn("Failed to release idempotency key", zap.Error(releaseErr))
+				}
+				return err
+			}
+
+			record := IdempotencyRecord{
+				Fingerprint: fingerprint,
+				Completed:   true,
+				Status:      res.Status,
+				ContentType: res.Header().Get(echo.HeaderContentType),
+				Body:        recorder.body.Bytes(),
+			}
+			if err := store.Complete(storeCtx, scopedKey, record, ttl); err != nil {
+				logger.GetGlobal().WithContext(ctx).Warn("Failed to store idempotent response", zap.Error(err))
+			}
+			return nil
+		}
+	}
+}
+
+// idempotencyScope qualifies key with the route and authenticated user, so clients
+// can't collide with each other or across endpoints
+func idempotencyScope(c echo.Context, key string) string {
+	return c.Request().Method + " " + c.Request().URL.Path + " " + ctxkeys.UserID(c.Request().Context()) + " " + key
+}
+
+// replayIdempotent answers a request whose key is already known
+func replayIdempotent(c echo.Context, record *IdempotencyRecord, fingerprint string) error {
+	switch {
+	case record.Fingerprint != fingerprint:
+		return errs.New(errs.ErrorCodeIdempotencyKeyReused,
+			fmt.Errorf("%s was used with a different request body", HeaderIdempotencyKey), nil)
+	case !record.Completed:
+		return errs.New(errs.ErrorCodeIdempotencyKeyInProgress,
+			fmt.Errorf("a request with this %s is still running", HeaderIdempotencyKey), nil)
+	}
+
+	c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	return c.Blob(record.Status, record.ContentType, record.Body)
+}
+
+// bodyRecorder copies the response body as it is written
+type bodyRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (r *bodyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// defaultIdempotencyMaxKeys bounds how many keys the in-memory store holds at once
+const defaultIdempotencyMaxKeys = 100000
+
+// errIdempotencyKeyNotReserved is returned when completing a key that expired or was released
+var errIdempotencyKeyNotReserved = errors.New("idempotency key is not reserved")
+
+// InMemoryIdempotencyStore is an IdempotencyStore for a single instance. Keys are kept in
+// expiry order (as long as every call passes the same ttl), so expired keys are swept from
+// the back on every call, and past maxKeys the key closest to expiry is dropped.
+type InMemoryIdempotencyStore struct {
+	maxKeys int
+	now     func() time.Time
+
+	mu    sync.Mutex
+	order *list.List               // Of *idempotencyEntry, latest expiry first
+	keys  map[string]*list.Element // Index into order
+}
+
+// idempotencyEntry holds one key's record until it expires
+type idempotencyEntry struct {
+	key       string
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+// NewInMemoryIdempotencyStore creates an empty in-memory idempotency store
+func NewInMemoryIdempotencyStore() *InMemoryIdempotencyStore {
+	return &InMemoryIdempotencyStore{
+		maxKeys: defaultIdempotencyMaxKeys,
+		now:     time.Now,
+		order:   list.New(),
+		keys:    make(map[string]*list.Element),
+	}
+}
+
+// Reserve marks key as in flight unless it is already known
+func (s *InMemoryIdempotencyStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if elem, exists := s.keys[key]; exists {
+		record := elem.Value.(*idempotencyEntry).record
+		return &record, nil
+	}
+
+	if s.order.Len() >= s.maxKeys {
+		s.remove(s.order.Back())
+	}
+	entry := &idempotencyEntry{
+		key:       key,
+		record:    IdempotencyRecord{Fingerprint: fingerprint},
+		expiresAt: now.Add(ttl),
+	}
+	s.keys[key] = s.order.PushFront(entry)
+	return nil, nil
+}
+
+// Complete stores the response for a reserved key, restarting its ttl
+func (s *InMemoryIdempotencyStore) Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, exists := s.keys[key]
+	if !exists {
+		return errIdempotencyKeyNotReserved
+	}
+	entry := elem.Value.(*idempotencyEntry)
+	entry.record = record
+	entry.expiresAt = s.now().Add(ttl)
+	s.order.MoveToFront(elem)
+	return nil
+}
+
+// Release forgets key
+func (s *InMemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, exists := s.keys[key]; exists {
+		s.remove(elem)
+	}
+	return nil
+}
+
+// sweep drops keys that expired by now
+func (s *InMemoryIdempotencyStore) sweep(now time.Time) {
+	for elem := s.order.Back(); elem != nil; elem = s.order.Back() {
+		if elem.Value.(*idempotencyEntry).expiresAt.After(now) {
+			return
+		}
+		s.remove(elem)
+	}
+}
+
+// remove drops a key's entry
+func (s *InMemoryIdempotencyStore) remove(elem *list.Element) {
+	entry := s.order.Remove(elem).(*idempotencyEntry)
+	delete(s.keys, entry.key)
+}
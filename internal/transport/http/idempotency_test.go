@@ -0,0 +1,182 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example-api-template/internal/repository"
+	"example-api-template/internal/service"
+	"example-api-template/internal/usecase"
+	"example-api-template/pkg/i18n"
+	"example-api-template/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	newServer := func() (*echo.Echo, repository.ExampleRepository, *InMemoryIdempotencyStore) {
+		repo := repository.NewInMemoryExampleRepository()
+		svc := service.NewExampleService(repo, zap.NewNop(), service.DefaultBusinessRules())
+		uc := usecase.NewExampleUseCase(svc, repository.NewMockExternalExampleAPI(false, 0), zap.NewNop())
+		store := NewInMemoryIdempotencyStore()
+
+		e := echo.New()
+		e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+		NewExampleHandler(uc, validator.New(), WithIdempotency(store, time.Hour)).RegisterRoutes(e)
+		return e, repo, store
+	}
+
+	post := func(e *echo.Echo, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	count := func(t *testing.T, repo repository.ExampleRepository) int {
+		n, err := repo.Count(context.Background())
+		require.NoError(t, err)
+		return n
+	}
+
+	const body = `{"name":"John Doe","email":"john@example.com","age":30}`
+
+	t.Run("repeated request replays the first response", func(t *testing.T) {
+		e, repo, _ := newServer()
+
+		first := post(e, "/api/v1/examples", "key-1", body)
+		require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+		assert.Empty(t, first.Header().Get(HeaderIdempotentReplayed))
+
+		second := post(e, "/api/v1/examples", "key-1", body)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, first.Header().Get(echo.HeaderContentType), second.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "true", second.Header().Get(HeaderIdempotentReplayed))
+
+		assert.Equal(t, 1, count(t, repo))
+	})
+
+	t.Run("same key with a different body conflicts", func(t *testing.T) {
+		e, repo, _ := newServer()
+
+		require.Equal(t, http.StatusCreated, post(e, "/api/v1/examples", "key-1", body).Code)
+
+		rec := post(e, "/api/v1/examples", "key-1", `{"name":"Jane Doe","email":"jane@example.com","age":28}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "IDEMPOTENCY_KEY_REUSED")
+		assert.Equal(t, 1, count(t, repo))
+	})
+
+	t.Run("request still running conflicts", func(t *testing.T) {
+		e, repo, store := newServer()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/v1/examples", nil), rec)
+		sum := sha256.Sum256([]byte(body))
+		_, err := store.Reserve(context.Background(), idempotencyScope(c, "key-1"), hex.EncodeToString(sum[:]), time.Hour)
+		require.NoError(t, err)
+
+		rec = post(e, "/api/v1/examples", "key-1", body)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Contains(t, rec.Body.String(), "IDEMPOTENCY_KEY_IN_PROGRESS")
+		assert.Zero(t, count(t, repo))
+	})
+
+	t.Run("failed request can be retried with the same key", func(t *testing.T) {
+		e, repo, _ := newServer()
+
+		failed := post(e, "/api/v1/examples", "key-1", `{"name":"John Doe","email":"not-an-email","age":30}`)
+		require.Equal(t, http.StatusBadRequest, failed.Code)
+
+		rec := post(e, "/api/v1/examples", "key-1", body)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get(HeaderIdempotentReplayed))
+		assert.Equal(t, 1, count(t, repo))
+	})
+
+	t.Run("keys are scoped to the route", func(t *testing.T) {
+		e, _, _ := newServer()
+
+		require.Equal(t, http.StatusCreated, post(e, "/api/v1/examples", "key-1", body).Code)
+
+		rec := post(e, "/api/v1/examples/validate", "key-1", `{"name":"Jane Doe","email":"jane@example.com","age":28}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get(HeaderIdempotentReplayed))
+	})
+
+	t.Run("requests without a key are not deduplicated", func(t *testing.T) {
+		e, _, _ := newServer()
+
+		require.Equal(t, http.StatusCreated, post(e, "/api/v1/examples", "", body).Code)
+		assert.Equal(t, http.StatusConflict, post(e, "/api/v1/examples", "", body).Code)
+	})
+
+	t.Run("overlong key is rejected", func(t *testing.T) {
+		e, _, _ := newServer()
+
+		rec := post(e, "/api/v1/examples", strings.Repeat("k", maxIdempotencyKeyLength+1), body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestInMemoryIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewInMemoryIdempotencyStore()
+	store.now = func() time.Time { return now }
+
+	existing, err := store.Reserve(ctx, "a", "fp", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	existing, err = store.Reserve(ctx, "a", "other", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, "fp", existing.Fingerprint)
+	assert.False(t, existing.Completed)
+
+	require.NoError(t, store.Complete(ctx, "a", IdempotencyRecord{Fingerprint: "fp", Completed: true, Status: http.StatusCreated}, time.Minute))
+	existing, err = store.Reserve(ctx, "a", "fp", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, existing.Completed)
+
+	// Keys expire after their ttl
+	now = now.Add(time.Minute)
+	existing, err = store.Reserve(ctx, "a", "fp", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	require.NoError(t, store.Release(ctx, "a"))
+	assert.Error(t, store.Complete(ctx, "a", IdempotencyRecord{}, time.Minute))
+
+	// Past maxKeys the key closest to expiry is dropped
+	store.maxKeys = 2
+	for _, key := range []string{"x", "y", "z"} {
+		_, err := store.Reserve(ctx, key, "fp", time.Minute)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, store.order.Len())
+	existing, err = store.Reserve(ctx, "x", "fp", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing, "x was evicted")
+}
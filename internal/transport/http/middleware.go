@@ -1,19 +1,41 @@
 package http
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net"
 	"net/http"
+	"runtime/debug"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"example-api-template/internal/errs"
+	"example-api-template/pkg/ctxkeys"
 	"example-api-template/pkg/i18n"
 	"example-api-template/pkg/logger"
+	"example-api-template/pkg/metrics"
+	"example-api-template/pkg/tracing"
+	"example-api-template/pkg/validator"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -69,8 +91,8 @@ func CORSMiddleware() echo.MiddlewareFunc {
 func setCORSHeaders(c echo.Context) {
 	c.Response().Header().Set("Access-Control-Allow-Origin", "*")
 	c.Response().Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	c.Response().Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Language, Accept-Language")
-	c.Response().Header().Set("Access-Control-Expose-Headers", "Content-Language, X-Total-Count")
+	c.Response().Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Encoding, Authorization, X-Language, Accept-Language")
+	c.Response().Header().Set("Access-Control-Expose-Headers", "Content-Language, X-Total-Count, Link")
 	c.Response().Header().Set("Access-Control-Max-Age", "86400")
 	if origin := c.Request().Header.Get("Origin"); origin != "" {
 		c.Logger().Debugf("CORS request from origin: %s", origin)
@@ -85,7 +107,7 @@ func RequestIDMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			requestID := getRequestID(c)
-			ctx := context.WithValue(c.Request().Context(), "request_id", requestID)
+			ctx := ctxkeys.WithRequestID(c.Request().Context(), requestID)
 			c.SetRequest(c.Request().WithContext(ctx))
 			c.Response().Header().Set("X-Request-ID", requestID)
 			return next(c)
@@ -100,10 +122,372 @@ func getRequestID(c echo.Context) string {
 	return uuid.New().String()
 }
 
+// ------------------------
+// Request Timeout Middleware
+// ------------------------
+
+// RequestTimeoutMiddleware puts a deadline on the request context, so database and
+// external API calls made on its behalf are cancelled once it passes. A request that
+// runs out of time without writing a response fails with 504 Gateway Timeout.
+func RequestTimeoutMiddleware(timeout time.Duration, opts ...RequestTimeoutOption) echo.MiddlewareFunc {
+	cfg := &requestTimeoutConfig{skipper: middleware.DefaultSkipper}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.skipper(c) {
+				return next(c)
+			}
+
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Response().Committed {
+				return errs.New(errs.ErrorCodeRequestTimeout,
+					fmt.Errorf("request exceeded %s: %w", timeout, context.DeadlineExceeded), nil)
+			}
+			return err
+		}
+	}
+}
+
+// RequestTimeoutOption configures RequestTimeoutMiddleware
+type RequestTimeoutOption func(*requestTimeoutConfig)
+
+// requestTimeoutConfig holds the settings of RequestTimeoutMiddleware
+type requestTimeoutConfig struct {
+	skipper middleware.Skipper
+}
+
+// WithTimeoutSkipper exempts requests matching skipper from the timeout, such as streaming
+// routes that run for as long as the client keeps up
+func WithTimeoutSkipper(skipper middleware.Skipper) RequestTimeoutOption {
+	return func(cfg *requestTimeoutConfig) {
+		cfg.skipper = skipper
+	}
+}
+
+// ------------------------
+// Recover Middleware
+// ------------------------
+
+// RecoverMiddleware turns a panic in a later handler into an internal error, which the error
+// handler localizes into a 500 response. The panic is logged to log with the request ID, route,
+// method and stack, and counted in m when it isn't nil.
+func RecoverMiddleware(log *zap.Logger, m *metrics.Metrics) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				if r == http.ErrAbortHandler {
+					panic(r) // Aborts the response on purpose, as net/http expects
+				}
+
+				req := c.Request()
+				route := c.Path()
+				if route == "" {
+					route = "unknown"
+				}
+				panicErr, ok := r.(error)
+				if !ok {
+					panicErr = fmt.Errorf("%v", r)
+				}
+
+				logger.ForContext(req.Context(), log).Error("Recovered from panic",
+					zap.String("route", route),
+					zap.String("method", req.Method),
+					zap.Error(panicErr),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				if m != nil {
+					m.ObservePanic(req.Method, route)
+				}
+				err = errs.New(errs.ErrorCodeInternalError, fmt.Errorf("panic: %w", panicErr), nil)
+			}()
+			return next(c)
+		}
+	}
+}
+
+// ------------------------
+// JWT Auth Middleware
+// ------------------------
+
+// JWTAuthOption configures JWTAuthMiddleware
+type JWTAuthOption func(*jwtAuthConfig)
+
+type jwtAuthConfig struct {
+	issuer      string
+	userIDClaim string
+	skipper     middleware.Skipper
+}
+
+// WithIssuer requires tokens to carry the given iss claim
+func WithIssuer(issuer string) JWTAuthOption {
+	return func(cfg *jwtAuthConfig) {
+		cfg.issuer = issuer
+	}
+}
+
+// WithUserIDClaim sets the claim used as the user ID (default: user_id)
+func WithUserIDClaim(claim string) JWTAuthOption {
+	return func(cfg *jwtAuthConfig) {
+		cfg.userIDClaim = claim
+	}
+}
+
+// WithSkipper lets requests matching the skipper bypass authentication
+func WithSkipper(skipper middleware.Skipper) JWTAuthOption {
+	return func(cfg *jwtAuthConfig) {
+		cfg.skipper = skipper
+	}
+}
+
+// JWTAuthMiddleware validates HMAC-signed Bearer tokens and stores the user ID under the user_id context key
+func JWTAuthMiddleware(secret string, opts ...JWTAuthOption) echo.MiddlewareFunc {
+	cfg := &jwtAuthConfig{
+		userIDClaim: "user_id",
+		skipper:     middleware.DefaultSkipper,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// A token without exp would never expire, so it is rejected
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(cfg.issuer))
+	}
+	parser := jwt.NewParser(parserOpts...)
+	keyFunc := func(*jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.skipper(c) {
+				return next(c)
+			}
+
+			tokenString, err := bearerToken(c.Request().Header.Get(echo.HeaderAuthorization))
+			if err != nil {
+				return unauthorized(c, err)
+			}
+
+			claims := jwt.MapClaims{}
+			if _, err := parser.ParseWithClaims(tokenString, claims, keyFunc); err != nil {
+				return unauthorized(c, fmt.Errorf("invalid token: %w", err))
+			}
+
+			userID, ok := claims[cfg.userIDClaim].(string)
+			if !ok || userID == "" {
+				return unauthorized(c, fmt.Errorf("token is missing the %s claim", cfg.userIDClaim))
+			}
+
+			ctx := ctxkeys.WithUserID(c.Request().Context(), userID)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// bearerToken extracts the token from an Authorization header
+func bearerToken(header string) (string, error) {
+	if header == "" {
+		return "", errors.New("missing authorization header")
+	}
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", errors.New("authorization header must use the Bearer scheme")
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// unauthorized builds a 401 error that the error handler localizes
+func unauthorized(c echo.Context, err error) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+	return errs.New(errs.ErrorCodeUnauthorized, err, nil)
+}
+
+// ------------------------
+// Basic Auth Middleware
+// ------------------------
+
+// BasicAuthMiddleware requires HTTP Basic credentials matching username and password, for
+// operational endpoints that are protected separately from the API
+func BasicAuthMiddleware(username, password string) echo.MiddlewareFunc {
+	wantUser := sha256.Sum256([]byte(username))
+	wantPass := sha256.Sum256([]byte(password))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, pass, ok := c.Request().BasicAuth()
+			if !ok {
+				return basicUnauthorized(c, errors.New("missing basic auth credentials"))
+			}
+
+			// Hashing first makes the comparisons constant time whatever the lengths, and both
+			// always run so the timing doesn't tell which one was wrong
+			gotUser := sha256.Sum256([]byte(user))
+			gotPass := sha256.Sum256([]byte(pass))
+			userOK := subtle.ConstantTimeCompare(gotUser[:], wantUser[:])
+			passOK := subtle.ConstantTimeCompare(gotPass[:], wantPass[:])
+			if userOK&passOK != 1 {
+				return basicUnauthorized(c, errors.New("invalid basic auth credentials"))
+			}
+			return next(c)
+		}
+	}
+}
+
+// basicUnauthorized builds a 401 error asking for Basic credentials
+func basicUnauthorized(c echo.Context, err error) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="admin", charset="UTF-8"`)
+	return errs.New(errs.ErrorCodeUnauthorized, err, nil)
+}
+
+// ------------------------
+// Tracing Middleware
+// ------------------------
+
+// TracingMiddleware starts a server span per request and stores its trace ID under the trace_id context key
+func TracingMiddleware(tracerName string) echo.MiddlewareFunc {
+	tracer := tracing.Tracer(tracerName)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			route := c.Path()
+			if route == "" {
+				route = req.URL.Path
+			}
+
+			ctx, span := tracer.Start(ctx, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", req.Method),
+					attribute.String("http.route", route),
+				),
+			)
+			defer span.End()
+
+			if requestID := logger.RequestID(ctx); requestID != "" {
+				span.SetAttributes(attribute.String("request_id", requestID))
+			}
+			if traceID := tracing.TraceID(ctx); traceID != "" {
+				ctx = ctxkeys.WithTraceID(ctx, traceID)
+				c.Response().Header().Set("X-Trace-ID", traceID)
+			}
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+
+			status := responseStatus(c, err)
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if status >= http.StatusInternalServerError {
+				tracing.RecordError(span, err)
+				if err == nil {
+					span.SetStatus(codes.Error, http.StatusText(status))
+				}
+			}
+
+			return err
+		}
+	}
+}
+
+// ------------------------
+// Metrics Middleware
+// ------------------------
+
+// MetricsMiddleware records request count and latency per route and status
+func MetricsMiddleware(m *metrics.Metrics) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			route := c.Path()
+			if route == "" {
+				route = "unknown"
+			}
+			m.ObserveHTTPRequest(c.Request().Method, route, responseStatus(c, err), time.Since(start))
+
+			return err
+		}
+	}
+}
+
+// responseStatus resolves the status that will be sent for a handler result
+func responseStatus(c echo.Context, err error) int {
+	if err == nil {
+		return c.Response().Status
+	}
+
+	var appErr *errs.AppError
+	if errors.As(err, &appErr) {
+		return appErr.GetHTTPStatus()
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+
+	return http.StatusInternalServerError
+}
+
 // ------------------------
 // Security Middleware
 // ------------------------
 
+// JSONSchemaMiddleware validates JSON request bodies against the schema registered for the
+// route, keyed by method and path pattern, e.g. "PUT /api/v1/examples/:id", before the handler
+// binds them. Routes without a schema pass through unchecked; other content types are
+// rejected with 415, since the handler would otherwise bind them without the schema check.
+func JSONSchemaMiddleware(schemas *validator.JSONSchemaValidator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			route := req.Method + " " + c.Path()
+			if !schemas.HasSchema(route) {
+				return next(c)
+			}
+			if contentType := req.Header.Get(echo.HeaderContentType); !strings.HasPrefix(contentType, echo.MIMEApplicationJSON) {
+				return errs.New(errs.ErrorCodeUnsupportedMediaType,
+					fmt.Errorf("unsupported content type %q, expected %s", contentType, echo.MIMEApplicationJSON), nil)
+			}
+
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return errs.New(errs.ErrorCodeInvalidRequest, err, nil)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			fieldErrors, err := schemas.Validate(route, body)
+			if len(fieldErrors) > 0 {
+				return errs.New(errs.ErrorCodeValidationFailed, err, fieldErrors)
+			}
+			if err != nil {
+				return errs.New(errs.ErrorCodeInvalidRequest, err, nil)
+			}
+			return next(c)
+		}
+	}
+}
+
 // InputSanitizationMiddleware sanitizes and validates input data
 func InputSanitizationMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -146,63 +530,239 @@ func sanitizeInput(input string) string {
 	return input
 }
 
-// RequestSizeLimitMiddleware limits the size of incoming requests
-func RequestSizeLimitMiddleware(maxSize int64) echo.MiddlewareFunc {
+// RequestSizeLimitOption configures RequestSizeLimitMiddleware and RequestDecompressionMiddleware
+type RequestSizeLimitOption func(*requestSizeLimits)
+
+// requestSizeLimits resolves the body size limit of a route
+type requestSizeLimits struct {
+	maxSize int64
+	routes  []routeSizeLimit
+}
+
+// routeSizeLimit overrides the default limit for routes under a path prefix
+type routeSizeLimit struct {
+	prefix  string
+	maxSize int64
+}
+
+// WithRouteSizeLimit applies maxSize instead of the default limit to routes whose path
+// starts with prefix. When several prefixes match, the one registered first wins.
+func WithRouteSizeLimit(prefix string, maxSize int64) RequestSizeLimitOption {
+	return func(l *requestSizeLimits) {
+		l.routes = append(l.routes, routeSizeLimit{prefix: prefix, maxSize: maxSize})
+	}
+}
+
+// newRequestSizeLimits applies opts over the default limit
+func newRequestSizeLimits(maxSize int64, opts []RequestSizeLimitOption) *requestSizeLimits {
+	l := &requestSizeLimits{maxSize: maxSize}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// limitFor returns the limit of the route matched for c
+func (l *requestSizeLimits) limitFor(c echo.Context) int64 {
+	for _, route := range l.routes {
+		if strings.HasPrefix(c.Path(), route.prefix) {
+			return route.maxSize
+		}
+	}
+	return l.maxSize
+}
+
+// RequestSizeLimitMiddleware limits the size of incoming requests to maxSize bytes,
+// or the limit of the matching WithRouteSizeLimit route
+func RequestSizeLimitMiddleware(maxSize int64, opts ...RequestSizeLimitOption) echo.MiddlewareFunc {
+	limits := newRequestSizeLimits(maxSize, opts)
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			limit := limits.limitFor(c)
+
 			// Check Content-Length header
-			if contentLength := c.Request().ContentLength; contentLength > maxSize {
-				return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
-					"error":   "Request too large",
-					"message": fmt.Sprintf("Request size exceeds limit of %d bytes", maxSize),
-				})
+			if contentLength := c.Request().ContentLength; contentLength > limit {
+				return requestTooLarge(c, limit)
 			}
 
-			// Limit request body reading
-			c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxSize)
+			// Limit request body reading; bodies without a Content-Length fail on read and
+			// are reported as too large by ErrorHandlerMiddleware
+			c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, limit)
 
 			return next(c)
 		}
 	}
 }
 
-// IPRateLimitMiddleware provides basic rate limiting per IP
-func IPRateLimitMiddleware(requestsPerMinute int) echo.MiddlewareFunc {
-	// Simple in-memory rate limiter (in production, use Redis or similar)
-	rateLimiter := make(map[string][]time.Time)
-	var mu sync.RWMutex
+// RequestDecompressionMiddleware decompresses gzip and deflate request bodies before they
+// are bound, rejecting bodies that decompress to more than maxSize bytes so a small
+// compressed payload cannot expand without bound. Register it after
+// RequestSizeLimitMiddleware, which then limits the compressed size. Route overrides
+// work as in RequestSizeLimitMiddleware.
+func RequestDecompressionMiddleware(maxSize int64, opts ...RequestSizeLimitOption) echo.MiddlewareFunc {
+	limits := newRequestSizeLimits(maxSize, opts)
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			clientIP := c.RealIP()
-			now := time.Now()
-			windowStart := now.Add(-time.Minute)
-
-			mu.Lock()
-			// Clean old entries
-			if requests, exists := rateLimiter[clientIP]; exists {
-				var validRequests []time.Time
-				for _, reqTime := range requests {
-					if reqTime.After(windowStart) {
-						validRequests = append(validRequests, reqTime)
-					}
-				}
-				rateLimiter[clientIP] = validRequests
+			req := c.Request()
+			encoding := strings.ToLower(strings.TrimSpace(req.Header.Get(echo.HeaderContentEncoding)))
+			if encoding == "" || encoding == "identity" {
+				return next(c)
 			}
 
-			// Check rate limit
-			if len(rateLimiter[clientIP]) >= requestsPerMinute {
-				mu.Unlock()
+			var decompressor io.ReadCloser
+			var err error
+			switch encoding {
+			case "gzip", "x-gzip":
+				decompressor, err = gzip.NewReader(req.Body)
+			case "deflate":
+				decompressor, err = zlib.NewReader(req.Body)
+			default:
+				return errs.New(errs.ErrorCodeUnsupportedMediaType,
+					fmt.Errorf("unsupported content encoding %q", encoding), nil)
+			}
+			if err != nil {
+				return decompressionError(c, encoding, err)
+			}
+			defer decompressor.Close()
+
+			// Read one byte past the limit to tell a body of exactly the limit from a larger one
+			limit := limits.limitFor(c)
+			body, err := io.ReadAll(io.LimitReader(decompressor, limit+1))
+			if err != nil {
+				return decompressionError(c, encoding, err)
+			}
+			if int64(len(body)) > limit {
+				return requestTooLarge(c, limit)
+			}
+
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			req.ContentLength = int64(len(body))
+			req.Header.Del(echo.HeaderContentEncoding)
+			req.Header.Set(echo.HeaderContentLength, strconv.Itoa(len(body)))
+
+			return next(c)
+		}
+	}
+}
+
+// decompressionError reports a request body that could not be decompressed; a compressed
+// body cut off by RequestSizeLimitMiddleware is too large rather than corrupt
+func decompressionError(c echo.Context, encoding string, err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return requestTooLarge(c, maxBytesErr.Limit)
+	}
+	return errs.New(errs.ErrorCodeInvalidRequest, fmt.Errorf("invalid %s request body: %w", encoding, err), nil)
+}
+
+// requestTooLarge responds 413 for a request body over maxSize bytes
+func requestTooLarge(c echo.Context, maxSize int64) error {
+	return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+		"error":   "Request too large",
+		"message": fmt.Sprintf("Request size exceeds limit of %d bytes", maxSize),
+	})
+}
+
+// TrustedProxyIPExtractor returns an IPExtractor that honours X-Forwarded-For only when the
+// request comes from one of trusted, walking the header back from the nearest proxy. Without
+// trusted proxies the connection's address is used, so clients can't spoof their IP.
+func TrustedProxyIPExtractor(trusted []*net.IPNet) echo.IPExtractor {
+	if len(trusted) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	// Echo trusts loopback and private addresses by default; only the configured ranges are trusted here
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, ipRange := range trusted {
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// IPRateLimitOption configures IPRateLimitMiddleware
+type IPRateLimitOption func(*ipRateLimitConfig)
+
+// ipRateLimitConfig holds the settings of IPRateLimitMiddleware
+type ipRateLimitConfig struct {
+	skipAuthenticated bool
+}
+
+// WithUserRateLimit leaves requests with a user ID in the context to UserRateLimitMiddleware,
+// so users sharing a proxy aren't held to its limit. Only use it when that middleware is
+// installed, otherwise authenticated requests are not limited at all.
+func WithUserRateLimit() IPRateLimitOption {
+	return func(cfg *ipRateLimitConfig) {
+		cfg.skipAuthenticated = true
+	}
+}
+
+// IPRateLimitMiddleware provides basic rate limiting per IP. With WithUserRateLimit only
+// anonymous requests are limited, so it runs after authentication. Idle IPs are evicted once
+// their window elapses, so clients that stop sending requests are not kept.
+func IPRateLimitMiddleware(requestsPerMinute int, opts ...IPRateLimitOption) echo.MiddlewareFunc {
+	cfg := &ipRateLimitConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// In-memory rate limiter (in production, use Redis or similar)
+	limiter := newSlidingWindowLimiter(requestsPerMinute, time.Minute)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.skipAuthenticated && ctxkeys.UserID(c.Request().Context()) != "" {
+				return next(c)
+			}
+			if ok, _ := limiter.allow(c.RealIP(), time.Now()); !ok {
 				return c.JSON(http.StatusTooManyRequests, map[string]string{
 					"error":   "Rate limit exceeded",
 					"message": fmt.Sprintf("Maximum %d requests per minute allowed", requestsPerMinute),
 				})
 			}
 
-			// Add current request
-			rateLimiter[clientIP] = append(rateLimiter[clientIP], now)
-			mu.Unlock()
+			return next(c)
+		}
+	}
+}
+
+// RateLimitOption configures UserRateLimitMiddleware
+type RateLimitOption func(*slidingWindowLimiter)
+
+// WithRateLimitWindow sets the window the request limit applies to (default: 1 minute)
+func WithRateLimitWindow(window time.Duration) RateLimitOption {
+	return func(l *slidingWindowLimiter) {
+		l.window = window
+	}
+}
+
+// UserRateLimitMiddleware limits each authenticated user, identified by the user_id
+// context key, to requestsPerMinute requests per window. Anonymous requests are limited
+// by IP instead. Requests over the limit fail with 429 and a Retry-After header.
+func UserRateLimitMiddleware(requestsPerMinute int, opts ...RateLimitOption) echo.MiddlewareFunc {
+	limiter := newSlidingWindowLimiter(requestsPerMinute, time.Minute)
+	for _, opt := range opts {
+		opt(limiter)
+	}
 
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := "ip:" + c.RealIP()
+			if userID := ctxkeys.UserID(c.Request().Context()); userID != "" {
+				key = "user:" + userID
+			}
+
+			ok, retryAfter := limiter.allow(key, time.Now())
+			if !ok {
+				c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				return errs.New(errs.ErrorCodeTooManyRequests,
+					fmt.Errorf("rate limit of %d requests per %s exceeded", limiter.limit, limiter.window), nil)
+			}
 			return next(c)
 		}
 	}
@@ -212,13 +772,50 @@ func IPRateLimitMiddleware(requestsPerMinute int) echo.MiddlewareFunc {
 // Error Handler Middleware
 // ------------------------
 
-func ErrorHandlerMiddleware(localizer *i18n.Localizer) echo.HTTPErrorHandler {
+// ErrorHandlerOption configures ErrorHandlerMiddleware
+type ErrorHandlerOption func(*errorHandlerConfig)
+
+// errorHandlerConfig holds ErrorHandlerMiddleware settings
+type errorHandlerConfig struct {
+	exposeStack bool
+}
+
+// WithStackTraces includes the stack of internal errors in responses. Stacks reveal
+// source paths, so only enable it in development.
+func WithStackTraces(expose bool) ErrorHandlerOption {
+	return func(cfg *errorHandlerConfig) {
+		cfg.exposeStack = expose
+	}
+}
+
+// ErrorHandlerMiddleware renders errors as localized responses. Internal errors are
+// logged with the stack where they were created.
+func ErrorHandlerMiddleware(localizer *i18n.Localizer, opts ...ErrorHandlerOption) echo.HTTPErrorHandler {
+	cfg := &errorHandlerConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	return func(err error, c echo.Context) {
-		switch e := err.(type) {
-		case *errs.AppError:
-			handleAppError(e, c, localizer)
-		case *echo.HTTPError:
-			handleEchoError(e, c)
+		// A body cut off by RequestSizeLimitMiddleware surfaces wrapped in a bind error
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			if !c.Response().Committed {
+				if err := requestTooLarge(c, maxBytesErr.Limit); err != nil {
+					c.Logger().Error(err)
+				}
+			}
+			return
+		}
+
+		// Wrapped errors are matched like responseStatus does, so metrics record the status sent
+		var appErr *errs.AppError
+		var httpErr *echo.HTTPError
+		switch {
+		case errors.As(err, &appErr):
+			handleAppError(appErr, c, localizer, cfg)
+		case errors.As(err, &httpErr):
+			handleEchoError(httpErr, c)
 		default:
 			logger.Debug("Unhandled error", zap.Any("error", err))
 			sendErrorResponse(c, http.StatusInternalServerError, "Internal Server Error")
@@ -226,18 +823,31 @@ func ErrorHandlerMiddleware(localizer *i18n.Localizer) echo.HTTPErrorHandler {
 	}
 }
 
-func handleAppError(appErr *errs.AppError, c echo.Context, localizer *i18n.Localizer) {
+func handleAppError(appErr *errs.AppError, c echo.Context, localizer *i18n.Localizer, cfg *errorHandlerConfig) {
 	ctx := c.Request().Context()
+	stack := appErr.StackTrace()
+	if len(stack) > 0 {
+		logger.GetGlobal().WithContext(ctx).Error("Internal error",
+			zap.String("code", string(appErr.Code)),
+			zap.Error(appErr.Err),
+			zap.Strings("stack", stack),
+		)
+	}
+
 	localized := appErr.LocalizeWithContext(localizer, ctx)
-	res := NewErrorResponse(string(localized.Code), localized.Err, localized.Message, localized.Details)
+	res := NewErrorResponse(localized.MachineCode, localized.Err, localized.Message, localized.Details)
+	res.DocURL = localized.DocURL
+	if cfg.exposeStack {
+		res.Stack = stack
+	}
 
 	if !c.Response().Committed {
 		if c.Request().Method == http.MethodHead {
-			if err := c.NoContent(appErr.HTTPStatus); err != nil {
+			if err := c.NoContent(appErr.GetHTTPStatus()); err != nil {
 				c.Logger().Error(err)
 			}
 		} else {
-			if err := c.JSON(appErr.HTTPStatus, res); err != nil {
+			if err := respond(c, appErr.GetHTTPStatus(), res); err != nil {
 				c.Logger().Error(err)
 			}
 		}
@@ -249,6 +859,12 @@ func handleEchoError(he *echo.HTTPError, c echo.Context) {
 	sendErrorResponse(c, he.Code, he.Message)
 }
 
+// genericErrorResponse is the body of errors raised outside the application, e.g. by echo
+type genericErrorResponse struct {
+	XMLName xml.Name    `json:"-" xml:"error"`
+	Error   interface{} `json:"error" xml:"error"`
+}
+
 // sendErrorResponse sends a generic error response
 func sendErrorResponse(c echo.Context, code int, message interface{}) {
 	c.Logger().Errorf("HTTP Error %d: %v", code, message)
@@ -258,7 +874,7 @@ func sendErrorResponse(c echo.Context, code int, message interface{}) {
 				c.Logger().Error(err)
 			}
 		} else {
-			if err := c.JSON(code, map[string]interface{}{"error": message}); err != nil {
+			if err := respond(c, code, &genericErrorResponse{Error: xmlValue(message)}); err != nil {
 				c.Logger().Error(err)
 			}
 		}
@@ -0,0 +1,859 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"example-api-template/internal/errs"
+	"example-api-template/pkg/ctxkeys"
+	"example-api-template/pkg/i18n"
+	"example-api-template/pkg/logger"
+	"example-api-template/pkg/metrics"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	m := metrics.New("test")
+
+	e := echo.New()
+	e.Use(MetricsMiddleware(m))
+	e.GET("/api/v1/examples/:id", func(c echo.Context) error {
+		if c.Param("id") == "missing" {
+			return errs.New(errs.ErrorCodeExampleNotFound, errors.New("not found"), nil)
+		}
+		return c.String(http.StatusOK, "ok")
+	})
+
+	for _, id := range []string{"a", "b", "missing"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples/"+id, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+	}
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.HTTPRequestsTotal.WithLabelValues(http.MethodGet, "/api/v1/examples/:id", "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.HTTPRequestsTotal.WithLabelValues(http.MethodGet, "/api/v1/examples/:id", "404")))
+
+	count, err := testutil.GatherAndCount(m.Registry(), "test_http_requests_total")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	m := metrics.New("test")
+
+	e := echo.New()
+	e.Use(MetricsMiddleware(m))
+	e.GET("/metrics", echo.WrapHandler(m.Handler()))
+	e.GET("/ping", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `test_http_requests_total{method="GET",route="/ping",status="204"} 1`)
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var traceID interface{}
+	e := echo.New()
+	e.Use(TracingMiddleware("test"))
+	e.GET("/ping", func(c echo.Context) error {
+		traceID = ctxkeys.TraceID(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /ping", spans[0].Name)
+	assert.Equal(t, spans[0].SpanContext.TraceID().String(), traceID)
+	assert.Equal(t, traceID, rec.Header().Get("X-Trace-ID"))
+}
+
+func TestJWTAuthMiddleware(t *testing.T) {
+	const secret = "test-secret-that-is-long-enough-123"
+
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	signToken := func(t *testing.T, claims jwt.MapClaims, key string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantUserID    string
+	}{
+		{
+			name:       "missing header",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "malformed token",
+			authorization: "Bearer not-a-jwt",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "wrong scheme",
+			authorization: "Basic dXNlcjpwYXNz",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name: "expired token",
+			authorization: "Bearer " + signToken(t, jwt.MapClaims{
+				"user_id": "user-123",
+				"iss":     "example-api",
+				"exp":     time.Now().Add(-time.Minute).Unix(),
+			}, secret),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "token without expiry",
+			authorization: "Bearer " + signToken(t, jwt.MapClaims{
+				"user_id": "user-123",
+				"iss":     "example-api",
+			}, secret),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong signature",
+			authorization: "Bearer " + signToken(t, jwt.MapClaims{
+				"user_id": "user-123",
+				"iss":     "example-api",
+				"exp":     time.Now().Add(time.Hour).Unix(),
+			}, "another-secret-that-is-long-enough"),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong issuer",
+			authorization: "Bearer " + signToken(t, jwt.MapClaims{
+				"user_id": "user-123",
+				"iss":     "someone-else",
+				"exp":     time.Now().Add(time.Hour).Unix(),
+			}, secret),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "valid token",
+			authorization: "Bearer " + signToken(t, jwt.MapClaims{
+				"user_id": "user-123",
+				"iss":     "example-api",
+				"exp":     time.Now().Add(time.Hour).Unix(),
+			}, secret),
+			wantStatus: http.StatusOK,
+			wantUserID: "user-123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+			e.Use(I18nMiddleware(localizer))
+			e.Use(JWTAuthMiddleware(secret, WithIssuer("example-api")))
+
+			var userID interface{}
+			e.GET("/api/v1/examples", func(c echo.Context) error {
+				userID = ctxkeys.UserID(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/examples", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rec.Header().Get(echo.HeaderWWWAuthenticate))
+				assert.Contains(t, rec.Body.String(), "Authentication required")
+				assert.Nil(t, userID)
+				return
+			}
+			assert.Equal(t, tt.wantUserID, userID)
+		})
+	}
+
+	t.Run("localized message", func(t *testing.T) {
+		e := echo.New()
+		e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+		e.Use(I18nMiddleware(localizer))
+		e.Use(JWTAuthMiddleware(secret))
+		e.GET("/api/v1/examples", func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples?lang=th", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "ต้องมีการยืนยันตัวตน")
+	})
+
+	t.Run("skipper bypasses authentication", func(t *testing.T) {
+		e := echo.New()
+		e.Use(JWTAuthMiddleware(secret, WithSkipper(func(c echo.Context) bool {
+			return c.Path() == "/api/v1/health"
+		})))
+		e.GET("/api/v1/health", func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestBasicAuthMiddleware(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+	NewAdminHandler(&fakeLogLevel{level: "info"},
+		WithAdminMiddleware(BasicAuthMiddleware("ops", "s3cret-password")),
+	).RegisterRoutes(e)
+
+	tests := []struct {
+		name       string
+		setAuth    func(*http.Request)
+		wantStatus int
+	}{
+		{
+			name:       "missing credentials",
+			setAuth:    func(*http.Request) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "bearer token instead of credentials",
+			setAuth:    func(r *http.Request) { r.Header.Set(echo.HeaderAuthorization, "Bearer token") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong username",
+			setAuth:    func(r *http.Request) { r.SetBasicAuth("admin", "s3cret-password") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong password",
+			setAuth:    func(r *http.Request) { r.SetBasicAuth("ops", "s3cret") },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "correct credentials",
+			setAuth:    func(r *http.Request) { r.SetBasicAuth("ops", "s3cret-password") },
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/log-level", nil)
+			tt.setAuth(req)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get(echo.HeaderWWWAuthenticate), "Basic")
+				assert.Contains(t, rec.Body.String(), "UNAUTHORIZED")
+				return
+			}
+			assert.JSONEq(t, `{"level":"info"}`, rec.Body.String())
+		})
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var contextID string
+	e := echo.New()
+	e.Use(RequestIDMiddleware())
+	e.GET("/", func(c echo.Context) error {
+		contextID = logger.RequestID(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	t.Run("incoming ID round-trips", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Request-ID", "req-123")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "req-123", rec.Header().Get("X-Request-ID"))
+		assert.Equal(t, "req-123", contextID)
+	})
+
+	t.Run("missing ID is generated", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		generated := rec.Header().Get("X-Request-ID")
+		assert.NotEmpty(t, generated)
+		assert.Equal(t, generated, contextID)
+	})
+}
+
+func TestUserRateLimitMiddleware(t *testing.T) {
+	const secret = "test-secret-that-is-long-enough-123"
+
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+	e.Use(JWTAuthMiddleware(secret, WithSkipper(func(c echo.Context) bool {
+		return c.Request().Header.Get(echo.HeaderAuthorization) == ""
+	})))
+	e.Use(UserRateLimitMiddleware(2, WithRateLimitWindow(time.Hour)))
+	e.GET("/api/v1/examples", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	tokenFor := func(userID string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": userID, "exp": time.Now().Add(time.Hour).Unix()}).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return token
+	}
+	alice, bob := tokenFor("alice"), tokenFor("bob")
+
+	request := func(token, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/examples", nil)
+		req.RemoteAddr = ip + ":1234"
+		if token != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Both users share one proxy IP but have their own budgets
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, request(alice, "10.0.0.1").Code)
+		assert.Equal(t, http.StatusOK, request(bob, "10.0.0.1").Code)
+	}
+
+	limited := request(alice, "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Contains(t, limited.Body.String(), "TOO_MANY_REQUESTS")
+	retryAfter, err := strconv.Atoi(limited.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, time.Hour.Seconds(), retryAfter, 5)
+
+	assert.Equal(t, http.StatusTooManyRequests, request(bob, "10.0.0.1").Code)
+
+	// A user's budget follows them across IPs
+	assert.Equal(t, http.StatusTooManyRequests, request(alice, "10.0.0.2").Code)
+
+	// Anonymous requests are limited by IP, separately from the users behind it
+	assert.Equal(t, http.StatusOK, request("", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, request("", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, request("", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, request("", "10.0.0.3").Code)
+}
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	limiter := newSlidingWindowLimiter(2, time.Minute)
+	start := time.Now()
+
+	ok, _ := limiter.allow("key", start)
+	assert.True(t, ok)
+	ok, _ = limiter.allow("key", start.Add(10*time.Second))
+	assert.True(t, ok)
+
+	ok, retryAfter := limiter.allow("key", start.Add(20*time.Second))
+	assert.False(t, ok)
+	assert.Equal(t, 40*time.Second, retryAfter)
+
+	// The first request leaves the window, freeing one slot
+	ok, _ = limiter.allow("key", start.Add(time.Minute+time.Second))
+	assert.True(t, ok)
+	ok, _ = limiter.allow("key", start.Add(time.Minute+2*time.Second))
+	assert.False(t, ok)
+}
+
+func TestSlidingWindowLimiterEviction(t *testing.T) {
+	t.Run("idle keys are evicted after the window", func(t *testing.T) {
+		limiter := newSlidingWindowLimiter(5, time.Minute)
+		start := time.Now()
+
+		limiter.allow("10.0.0.1", start)
+		limiter.allow("10.0.0.2", start.Add(30*time.Second))
+		assert.Equal(t, 2, limiter.size())
+
+		// 10.0.0.1 has been idle for a whole window, 10.0.0.2 has not
+		limiter.allow("10.0.0.3", start.Add(time.Minute+time.Second))
+		assert.Equal(t, 2, limiter.size())
+		assert.NotContains(t, limiter.keys, "10.0.0.1")
+
+		limiter.allow("10.0.0.3", start.Add(2*time.Minute))
+		assert.Equal(t, 1, limiter.size())
+		assert.Contains(t, limiter.keys, "10.0.0.3")
+	})
+
+	t.Run("least recently seen key is dropped at capacity", func(t *testing.T) {
+		limiter := newSlidingWindowLimiter(5, time.Minute)
+		limiter.maxKeys = 2
+		now := time.Now()
+
+		limiter.allow("10.0.0.1", now)
+		limiter.allow("10.0.0.2", now)
+		limiter.allow("10.0.0.1", now)
+		limiter.allow("10.0.0.3", now)
+
+		assert.Equal(t, 2, limiter.size())
+		assert.Contains(t, limiter.keys, "10.0.0.1")
+		assert.NotContains(t, limiter.keys, "10.0.0.2")
+	})
+}
+
+func TestIPRateLimitMiddleware(t *testing.T) {
+	e := echo.New()
+	handler := IPRateLimitMiddleware(1)(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, serve().Code)
+	rec := serve()
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Maximum 1 requests per minute allowed")
+
+	serveUser := func(handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.2:1234"
+		req = req.WithContext(ctxkeys.WithUserID(req.Context(), "user-1"))
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler(e.NewContext(req, rec)))
+		return rec
+	}
+
+	t.Run("authenticated requests are left to the user rate limit", func(t *testing.T) {
+		handler := IPRateLimitMiddleware(1, WithUserRateLimit())(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, serveUser(handler).Code)
+		}
+	})
+
+	t.Run("authenticated requests are limited by IP without a user rate limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serveUser(handler).Code)
+		assert.Equal(t, http.StatusTooManyRequests, serveUser(handler).Code)
+	})
+}
+
+func TestIPRateLimitMiddlewareTrustedProxies(t *testing.T) {
+	_, proxies, err := net.ParseCIDR("192.168.1.0/24")
+	require.NoError(t, err)
+
+	newServe := func(trusted []*net.IPNet) func(remoteAddr, forwardedFor string) int {
+		e := echo.New()
+		e.IPExtractor = TrustedProxyIPExtractor(trusted)
+		handler := IPRateLimitMiddleware(1)(func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+		return func(remoteAddr, forwardedFor string) int {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = remoteAddr
+			req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+			rec := httptest.NewRecorder()
+			require.NoError(t, handler(e.NewContext(req, rec)))
+			return rec.Code
+		}
+	}
+
+	t.Run("spoofed header from an untrusted source is ignored", func(t *testing.T) {
+		serve := newServe([]*net.IPNet{proxies})
+
+		assert.Equal(t, http.StatusOK, serve("10.0.0.1:1234", "203.0.113.1"))
+		assert.Equal(t, http.StatusTooManyRequests, serve("10.0.0.1:1234", "203.0.113.2"))
+	})
+
+	t.Run("header is ignored without trusted proxies", func(t *testing.T) {
+		serve := newServe(nil)
+
+		assert.Equal(t, http.StatusOK, serve("192.168.1.10:1234", "203.0.113.1"))
+		assert.Equal(t, http.StatusTooManyRequests, serve("192.168.1.10:1234", "203.0.113.2"))
+	})
+
+	t.Run("clients behind a trusted proxy are limited separately", func(t *testing.T) {
+		serve := newServe([]*net.IPNet{proxies})
+
+		assert.Equal(t, http.StatusOK, serve("192.168.1.10:1234", "203.0.113.1"))
+		assert.Equal(t, http.StatusOK, serve("192.168.1.10:1234", "203.0.113.2"))
+		assert.Equal(t, http.StatusTooManyRequests, serve("192.168.1.10:1234", "203.0.113.1"))
+	})
+
+	t.Run("spoofed entries before the trusted hop are ignored", func(t *testing.T) {
+		serve := newServe([]*net.IPNet{proxies})
+
+		assert.Equal(t, http.StatusOK, serve("192.168.1.10:1234", "198.51.100.1, 203.0.113.1"))
+		assert.Equal(t, http.StatusTooManyRequests, serve("192.168.1.10:1234", "198.51.100.2, 203.0.113.1"))
+	})
+}
+
+func TestRequestSizeLimitMiddleware(t *testing.T) {
+	const (
+		maxSize      = 1024
+		batchMaxSize = 4 * maxSize
+	)
+
+	e, _ := newTestServer(t)
+	e.Use(RequestSizeLimitMiddleware(maxSize, WithRouteSizeLimit("/api/v1/examples/batch", batchMaxSize)))
+
+	// paddedBody pads prefix with an ignored field so the JSON body is exactly size bytes
+	paddedBody := func(prefix string, size int) []byte {
+		body := prefix + `,"padding":"`
+		body += strings.Repeat("a", size-len(body)-len(`"}`)) + `"}`
+		require.Len(t, body, size)
+		return []byte(body)
+	}
+	exampleBody := func(email string, size int) []byte {
+		return paddedBody(`{"name":"John Doe","email":"`+email+`","age":30`, size)
+	}
+	post := func(path string, body []byte, chunked bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if chunked {
+			// Without a Content-Length the limit is only hit while binding the body
+			req.ContentLength = -1
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, chunked := range []bool{false, true} {
+		t.Run(fmt.Sprintf("chunked=%t", chunked), func(t *testing.T) {
+			t.Run("body at the limit is accepted", func(t *testing.T) {
+				rec := post("/api/v1/examples", exampleBody(fmt.Sprintf("under-%t@example.com", chunked), maxSize), chunked)
+				assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+			})
+
+			t.Run("body over the limit is rejected", func(t *testing.T) {
+				rec := post("/api/v1/examples", exampleBody(fmt.Sprintf("over-%t@example.com", chunked), maxSize+1), chunked)
+				assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+				assert.Contains(t, rec.Body.String(), fmt.Sprintf("Request size exceeds limit of %d bytes", maxSize))
+			})
+
+			t.Run("batch route allows a larger body", func(t *testing.T) {
+				rec := post("/api/v1/examples/batch-get", paddedBody(`{"ids":["missing"]`, batchMaxSize), chunked)
+				assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+				rec = post("/api/v1/examples/batch-get", paddedBody(`{"ids":["missing"]`, batchMaxSize+1), chunked)
+				assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+				assert.Contains(t, rec.Body.String(), fmt.Sprintf("Request size exceeds limit of %d bytes", batchMaxSize))
+			})
+		})
+	}
+}
+
+func TestRequestDecompressionMiddleware(t *testing.T) {
+	const maxSize = 1024
+
+	e, _ := newTestServer(t)
+	e.Use(RequestSizeLimitMiddleware(maxSize))
+	e.Use(RequestDecompressionMiddleware(maxSize))
+
+	gzipped := func(body []byte) []byte {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write(body)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+	deflated := func(body []byte) []byte {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		_, err := w.Write(body)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+	post := func(body []byte, encoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/examples", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if encoding != "" {
+			req.Header.Set(echo.HeaderContentEncoding, encoding)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	exampleJSON := func(email string) []byte {
+		age := 30
+		body, err := json.Marshal(CreateExampleRequestDTO{Name: "John Doe", Email: email, Age: &age})
+		require.NoError(t, err)
+		return body
+	}
+
+	t.Run("gzip body is decoded", func(t *testing.T) {
+		rec := post(gzipped(exampleJSON("gzip@example.com")), "gzip")
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+		var response ExampleResponseDTO
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "John Doe", response.Name)
+		assert.Equal(t, "gzip@example.com", response.Email)
+		assert.Equal(t, 30, response.Age)
+	})
+
+	t.Run("deflate body is decoded", func(t *testing.T) {
+		rec := post(deflated(exampleJSON("deflate@example.com")), "deflate")
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		assert.Contains(t, rec.Body.String(), "deflate@example.com")
+	})
+
+	t.Run("uncompressed body is untouched", func(t *testing.T) {
+		rec := post(exampleJSON("plain@example.com"), "")
+		assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	})
+
+	t.Run("body decompressing past the limit is rejected", func(t *testing.T) {
+		// A kilobyte of padding compresses to a few bytes but expands past the limit
+		padded := []byte(`{"name":"John Doe","email":"bomb@example.com","age":30,"padding":"` +
+			strings.Repeat("a", maxSize) + `"}`)
+		compressed := gzipped(padded)
+		require.Less(t, len(compressed), maxSize)
+
+		rec := post(compressed, "gzip")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Request too large")
+	})
+
+	t.Run("corrupt body is a bad request", func(t *testing.T) {
+		rec := post([]byte("not gzip"), "gzip")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("unknown encoding is unsupported", func(t *testing.T) {
+		rec := post(exampleJSON("br@example.com"), "br")
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+}
+
+func TestErrorHandlerMiddlewareStackTraces(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	serve := func(opts ...ErrorHandlerOption) map[string]interface{} {
+		e := echo.New()
+		e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer, opts...)
+		e.GET("/api/v1/examples", func(c echo.Context) error {
+			return errs.New(errs.ErrorCodeDatabaseError, errors.New("connection refused"), nil)
+		})
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/examples", nil))
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return body
+	}
+
+	t.Run("production responses leave the stack out", func(t *testing.T) {
+		body := serve()
+		assert.Equal(t, "DATABASE_ERROR", body["code"])
+		assert.NotContains(t, body, "stack")
+	})
+
+	t.Run("development responses include the stack", func(t *testing.T) {
+		body := serve(WithStackTraces(true))
+		require.Contains(t, body, "stack")
+		stack := body["stack"].([]interface{})
+		require.NotEmpty(t, stack)
+		assert.Contains(t, stack[0], "TestErrorHandlerMiddlewareStackTraces")
+	})
+}
+
+func TestErrorHandlerMiddlewareWrappedErrors(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	m := metrics.New("test")
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+	e.Use(MetricsMiddleware(m))
+	e.GET("/app", func(c echo.Context) error {
+		return fmt.Errorf("loading example: %w", errs.New(errs.ErrorCodeExampleNotFound, errors.New("example not found"), nil))
+	})
+	e.GET("/echo", func(c echo.Context) error {
+		return fmt.Errorf("binding: %w", echo.NewHTTPError(http.StatusMethodNotAllowed, "nope"))
+	})
+
+	for path, want := range map[string]int{"/app": http.StatusNotFound, "/echo": http.StatusMethodNotAllowed} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, want, rec.Code, path)
+
+		// The metrics record the status the client got
+		status := strconv.Itoa(want)
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.HTTPRequestsTotal.WithLabelValues(http.MethodGet, path, status)), path)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/app", nil))
+	assert.Contains(t, rec.Body.String(), "EXAMPLE_NOT_FOUND")
+}
+
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(RequestTimeoutMiddleware(time.Minute, WithTimeoutSkipper(func(c echo.Context) bool {
+		return c.Path() == "/stream"
+	})))
+	hasDeadline := func(c echo.Context) error {
+		_, ok := c.Request().Context().Deadline()
+		return c.String(http.StatusOK, strconv.FormatBool(ok))
+	}
+	e.GET("/bounded", hasDeadline)
+	e.GET("/stream", hasDeadline)
+
+	for path, want := range map[string]string{"/bounded": "true", "/stream": "false"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, want, rec.Body.String(), path)
+	}
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	core, logs := observer.New(zap.ErrorLevel)
+	m := metrics.New("test")
+
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandlerMiddleware(localizer)
+	e.Use(RequestIDMiddleware())
+	e.Use(I18nMiddleware(localizer))
+	e.Use(RecoverMiddleware(zap.New(core), m))
+	e.GET("/api/v1/examples/:id", func(c echo.Context) error {
+		panic("nil map write")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/examples/123?lang=th", nil)
+	req.Header.Set("X-Request-ID", "req-panic")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	var body ErrorResponseDTO
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "INTERNAL_ERROR", body.Code)
+	assert.Equal(t, localizer.LocalizeError("th", "internal_error", nil), body.Message)
+
+	entries := logs.FilterMessage("Recovered from panic").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "req-panic", fields["request_id"])
+	assert.Equal(t, "/api/v1/examples/:id", fields["route"])
+	assert.Equal(t, http.MethodGet, fields["method"])
+	assert.Equal(t, "nil map write", fields["error"])
+	assert.Contains(t, fields["stack"], "TestRecoverMiddleware")
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.HTTPPanicsTotal.WithLabelValues(http.MethodGet, "/api/v1/examples/:id")))
+}
+
+func TestI18nMiddlewareAcceptLanguage(t *testing.T) {
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../../translations",
+	})
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.Use(I18nMiddleware(localizer))
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	language := func(acceptLanguage string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Header().Get("Content-Language")
+	}
+
+	assert.Equal(t, "th", language("fr;q=0.9, th;q=0.8, en;q=0.7"))
+	assert.Equal(t, "en", language("th;q=0.2, en-US"))
+	assert.Equal(t, "en", language("fr, de;q=0.9"), "no supported language falls back to the default")
+	assert.Equal(t, "en", language("th;q=oops"), "a malformed header falls back to the default")
+}
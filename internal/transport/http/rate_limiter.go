@@ -0,0 +1,107 @@
+package http
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// defaultRateLimiterMaxKeys bounds how many clients a limiter tracks at once
+const defaultRateLimiterMaxKeys = 100000
+
+// slidingWindowLimiter allows up to limit requests per key in any window-long period.
+// Keys are kept in least-recently-seen order, so keys idle for a whole window are
+// swept from the back on every call, and past maxKeys the least recent key is dropped.
+// Memory is bounded by maxKeys and by the clients active in the last window.
+type slidingWindowLimiter struct {
+	limit   int
+	window  time.Duration
+	maxKeys int
+
+	mu    sync.Mutex
+	order *list.List               // Of *rateLimitEntry, most recently seen first
+	keys  map[string]*list.Element // Index into order
+}
+
+// rateLimitEntry holds one key's request times within the last window, oldest first
+type rateLimitEntry struct {
+	key      string
+	requests []time.Time
+	lastSeen time.Time
+}
+
+// newSlidingWindowLimiter creates a limiter allowing limit requests per key per window
+func newSlidingWindowLimiter(limit int, window time.Duration) *slidingWindowLimiter {
+	return &slidingWindowLimiter{
+		limit:   limit,
+		window:  window,
+		maxKeys: defaultRateLimiterMaxKeys,
+		order:   list.New(),
+		keys:    make(map[string]*list.Element),
+	}
+}
+
+// allow records a request for key at now. When key is over the limit the request is
+// not recorded, and retryAfter says how long until the oldest request leaves the window.
+func (l *slidingWindowLimiter) allow(key string, now time.Time) (ok bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	windowStart := now.Add(-l.window)
+	l.sweep(windowStart)
+
+	entry := l.touch(key, now)
+	expired := 0
+	for expired < len(entry.requests) && !entry.requests[expired].After(windowStart) {
+		expired++
+	}
+	entry.requests = entry.requests[expired:]
+
+	if len(entry.requests) >= l.limit {
+		return false, entry.requests[0].Add(l.window).Sub(now)
+	}
+
+	entry.requests = append(entry.requests, now)
+	return true, 0
+}
+
+// touch returns the entry for key, creating it if needed, and marks it most recently seen.
+// Adding a key beyond maxKeys drops the least recently seen one.
+func (l *slidingWindowLimiter) touch(key string, now time.Time) *rateLimitEntry {
+	if elem, exists := l.keys[key]; exists {
+		l.order.MoveToFront(elem)
+		entry := elem.Value.(*rateLimitEntry)
+		entry.lastSeen = now
+		return entry
+	}
+
+	if l.order.Len() >= l.maxKeys {
+		l.remove(l.order.Back())
+	}
+	entry := &rateLimitEntry{key: key, lastSeen: now}
+	l.keys[key] = l.order.PushFront(entry)
+	return entry
+}
+
+// sweep drops keys not seen since windowStart; none of their requests count any more
+func (l *slidingWindowLimiter) sweep(windowStart time.Time) {
+	for elem := l.order.Back(); elem != nil; elem = l.order.Back() {
+		if elem.Value.(*rateLimitEntry).lastSeen.After(windowStart) {
+			return
+		}
+		l.remove(elem)
+	}
+}
+
+// remove drops a key's entry
+func (l *slidingWindowLimiter) remove(elem *list.Element) {
+	entry := l.order.Remove(elem).(*rateLimitEntry)
+	delete(l.keys, entry.key)
+}
+
+// size returns the number of keys being tracked
+func (l *slidingWindowLimiter) size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"encoding/xml"
+	"mime"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// respond writes v as XML when the Accept header prefers XML over JSON, and as JSON otherwise
+func respond(c echo.Context, status int, v interface{}) error {
+	if prefersXML(c.Request().Header.Get(echo.HeaderAccept)) {
+		return c.XML(status, v)
+	}
+	return c.JSON(status, v)
+}
+
+// prefersXML reports whether an Accept header ranks XML strictly above JSON. Wildcards
+// count towards JSON, so a missing header, */* or a tie keeps the JSON default.
+func prefersXML(accept string) bool {
+	var xmlQ, jsonQ float64
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+
+		switch mediaType {
+		case echo.MIMEApplicationXML, echo.MIMETextXML:
+			xmlQ = max(xmlQ, q)
+		case echo.MIMEApplicationJSON, "application/*", "*/*":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return xmlQ > jsonQ
+}
+
+// xmlMap encodes a map, which encoding/xml does not support, as one entry element per
+// key in key order, e.g. <entry key="source">api</entry>
+type xmlMap map[string]interface{}
+
+// MarshalXML implements xml.Marshaler
+func (m xmlMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		entry := xml.StartElement{
+			Name: xml.Name{Local: "entry"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+		}
+		if err := e.EncodeElement(xmlValue(m[key]), entry); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// xmlValue wraps string-keyed maps, nested ones included, so they can be encoded as XML
+// and returns other values unchanged
+func xmlValue(v interface{}) interface{} {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String {
+		return v
+	}
+
+	m := make(xmlMap, value.Len())
+	for iter := value.MapRange(); iter.Next(); {
+		m[iter.Key().String()] = iter.Value().Interface()
+	}
+	return m
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+
+	"example-api-template/internal/config"
+	"example-api-template/pkg/buildinfo"
+
+	"github.com/labstack/echo/v4"
+)
+
+// VersionResponseDTO represents the build and deployment metadata of the running service
+type VersionResponseDTO struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Environment string `json:"environment"`
+	GitCommit   string `json:"git_commit"`
+	BuildTime   string `json:"build_time"`
+}
+
+// VersionHandler reports which build of the service is running
+type VersionHandler struct {
+	response VersionResponseDTO
+}
+
+// NewVersionHandler creates a new version handler for app, reading the commit and build time from buildinfo
+func NewVersionHandler(app config.AppConfig) *VersionHandler {
+	return &VersionHandler{
+		response: VersionResponseDTO{
+			Name:        app.Name,
+			Version:     app.Version,
+			Environment: app.Environment,
+			GitCommit:   buildinfo.Commit(),
+			BuildTime:   buildinfo.Time(),
+		},
+	}
+}
+
+// RegisterRoutes registers the version route
+func (h *VersionHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/api/v1/version", h.GetVersion)
+}
+
+// GetVersion returns the build metadata
+// @Summary Get version
+// @Description Get the application name, version, environment, git commit and build time; unset build metadata is reported as unknown
+// @Tags health
+// @Produce json
+// @Success 200 {object} VersionResponseDTO
+// @Router /api/v1/version [get]
+func (h *VersionHandler) GetVersion(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.response)
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example-api-template/internal/config"
+	"example-api-template/pkg/buildinfo"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionHandlerGetVersion(t *testing.T) {
+	commit := buildinfo.GitCommit
+	t.Cleanup(func() { buildinfo.GitCommit = commit })
+	buildinfo.GitCommit = "abc123"
+
+	e := echo.New()
+	NewVersionHandler(config.AppConfig{Name: "example-api", Version: "1.2.3", Environment: "staging"}).RegisterRoutes(e)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/version", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, map[string]string{
+		"name":        "example-api",
+		"version":     "1.2.3",
+		"environment": "staging",
+		"git_commit":  "abc123",
+		"build_time":  buildinfo.Unknown, // Not set by go test
+	}, body)
+}
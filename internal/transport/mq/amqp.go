@@ -0,0 +1,63 @@
+package mq
+
+import (
+	"context"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// amqpChannel is the subset of *amqp.Channel used by the producer and consumer
+type amqpChannel interface {
+	Qos(prefetchCount, prefetchSize int, global bool) error
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Cancel(consumer string, noWait bool) error
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
+	Close() error
+}
+
+// amqpConnection is the subset of *amqp.Connection used by the producer and consumer
+type amqpConnection interface {
+	Channel() (amqpChannel, error)
+	NotifyClose(receiver chan *amqp.Error) chan *amqp.Error
+	Close() error
+}
+
+// amqpDialer opens a connection to the broker
+type amqpDialer func(url string) (amqpConnection, error)
+
+// dialAMQP dials a real RabbitMQ broker
+func dialAMQP(url string) (amqpConnection, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	return &amqpConnectionAdapter{Connection: conn}, nil
+}
+
+// amqpConnectionAdapter exposes *amqp.Connection through amqpConnection
+type amqpConnectionAdapter struct {
+	*amqp.Connection
+}
+
+// Channel opens a new channel on the connection
+func (a *amqpConnectionAdapter) Channel() (amqpChannel, error) {
+	ch, err := a.Connection.Channel()
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// nextBackoff doubles the interval up to max
+func nextBackoff(current, max time.Duration) time.Duration {
+	next := current * 2
+	if next > max {
+		return max
+	}
+	return next
+}
@@ -0,0 +1,96 @@
+package mq
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// DefaultDedupCapacity is the number of event IDs remembered by the default store
+const DefaultDedupCapacity = 10000
+
+// ProcessedEventStore remembers which events have been handled so redeliveries can be skipped.
+// Implementations backed by a shared store such as Redis let several consumers deduplicate together.
+type ProcessedEventStore interface {
+	IsProcessed(ctx context.Context, eventID string) (bool, error)
+	MarkProcessed(ctx context.Context, eventID string) error
+}
+
+// LRUProcessedEventStore is an in-memory ProcessedEventStore that forgets the least recently seen IDs
+type LRUProcessedEventStore struct {
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+	mu       sync.Mutex
+}
+
+// NewLRUProcessedEventStore creates an in-memory store holding up to capacity event IDs
+func NewLRUProcessedEventStore(capacity int) *LRUProcessedEventStore {
+	if capacity <= 0 {
+		capacity = DefaultDedupCapacity
+	}
+	return &LRUProcessedEventStore{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element, capacity),
+	}
+}
+
+// IsProcessed reports whether eventID has been marked as processed
+func (s *LRUProcessedEventStore) IsProcessed(ctx context.Context, eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[eventID]
+	if ok {
+		s.order.MoveToFront(element)
+	}
+	return ok, nil
+}
+
+// MarkProcessed records eventID, evicting the least recently seen ID when full
+func (s *LRUProcessedEventStore) MarkProcessed(ctx context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[eventID]; ok {
+		s.order.MoveToFront(element)
+		return nil
+	}
+
+	s.entries[eventID] = s.order.PushFront(eventID)
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(string))
+	}
+	return nil
+}
+
+// alreadyProcessed reports whether an event was handled before. Lookup failures are
+// logged and treated as unseen, so a store outage never blocks processing.
+func alreadyProcessed(ctx context.Context, store ProcessedEventStore, eventID string, logger *zap.Logger) bool {
+	if eventID == "" {
+		return false
+	}
+
+	processed, err := store.IsProcessed(ctx, eventID)
+	if err != nil {
+		logger.Warn("Failed to check processed events, handling anyway", zap.Error(err))
+		return false
+	}
+	return processed
+}
+
+// markProcessed records a successfully handled event
+func markProcessed(ctx context.Context, store ProcessedEventStore, eventID string, logger *zap.Logger) {
+	if eventID == "" {
+		return
+	}
+
+	if err := store.MarkProcessed(ctx, eventID); err != nil {
+		logger.Warn("Failed to record processed event", zap.Error(err))
+	}
+}
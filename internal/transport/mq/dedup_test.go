@@ -0,0 +1,46 @@
+package mq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUProcessedEventStore(t *testing.T) {
+	ctx := context.Background()
+
+	isProcessed := func(t *testing.T, store *LRUProcessedEventStore, id string) bool {
+		processed, err := store.IsProcessed(ctx, id)
+		require.NoError(t, err)
+		return processed
+	}
+
+	t.Run("remembers marked events", func(t *testing.T) {
+		store := NewLRUProcessedEventStore(10)
+
+		assert.False(t, isProcessed(t, store, "evt-1"))
+		require.NoError(t, store.MarkProcessed(ctx, "evt-1"))
+		assert.True(t, isProcessed(t, store, "evt-1"))
+	})
+
+	t.Run("evicts least recently seen event", func(t *testing.T) {
+		store := NewLRUProcessedEventStore(2)
+		require.NoError(t, store.MarkProcessed(ctx, "evt-1"))
+		require.NoError(t, store.MarkProcessed(ctx, "evt-2"))
+
+		// Touch evt-1 so evt-2 becomes the oldest
+		assert.True(t, isProcessed(t, store, "evt-1"))
+		require.NoError(t, store.MarkProcessed(ctx, "evt-3"))
+
+		assert.True(t, isProcessed(t, store, "evt-1"))
+		assert.False(t, isProcessed(t, store, "evt-2"))
+		assert.True(t, isProcessed(t, store, "evt-3"))
+	})
+
+	t.Run("non-positive capacity uses default", func(t *testing.T) {
+		store := NewLRUProcessedEventStore(0)
+		assert.Equal(t, DefaultDedupCapacity, store.capacity)
+	})
+}
@@ -2,13 +2,17 @@ package mq
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"example-api-template/internal/usecase"
+	"example-api-template/pkg/ctxkeys"
+	"example-api-template/pkg/metrics"
+	"example-api-template/pkg/tracing"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.uber.org/zap"
 )
@@ -28,59 +32,152 @@ type ExampleConsumer interface {
 
 // RabbitMQConsumer implements ExampleConsumer using RabbitMQ
 type RabbitMQConsumer struct {
-	connection   *amqp.Connection
-	channel      *amqp.Channel
-	exchangeName string
-	queueName    string
-	routingKeys  []string
-	handler      ExampleEventHandler
-	logger       *zap.Logger
-	stopChan     chan struct{}
-	wg           sync.WaitGroup
-	mu           sync.RWMutex
-	isRunning    bool
+	config         *RabbitMQConsumerConfig
+	dial           amqpDialer
+	connection     amqpConnection
+	channel        amqpChannel
+	closeNotify    chan *amqp.Error
+	exchangeName   string
+	queueName      string
+	consumerTag    string
+	routingKeys    []string
+	handler        ExampleEventHandler
+	processed      ProcessedEventStore
+	logger         *zap.Logger
+	stopChan       chan struct{}      // Closed by Stop; each Start makes a new one, so a stopped consumer can start again
+	handlerCtx     context.Context    // Passed to handlers; outlives the Start context so Stop can drain
+	cancelHandlers context.CancelFunc // Cancels handlerCtx once the drain timeout expires
+	wg             sync.WaitGroup
+	mu             sync.RWMutex
+	isRunning      bool
+	connected      bool // Cleared as soon as the connection is lost, set again once it is re-established
 }
 
 // RabbitMQConsumerConfig holds configuration for RabbitMQ consumer
 type RabbitMQConsumerConfig struct {
-	URL           string
-	ExchangeName  string
-	QueueName     string
-	RoutingKeys   []string
-	Durable       bool
-	AutoDelete    bool
-	Exclusive     bool
-	NoWait        bool
-	PrefetchCount int
+	URL                string
+	ExchangeName       string
+	QueueName          string
+	RoutingKeys        []string
+	Durable            bool
+	AutoDelete         bool
+	Exclusive          bool
+	NoWait             bool
+	PrefetchCount      int
+	Concurrency        int                      // Workers handling deliveries in parallel (default: 1); keep PrefetchCount at least this high
+	DrainTimeout       time.Duration            // How long Stop waits for in-flight messages before closing the channel
+	ReconnectInterval  time.Duration            // Initial delay between reconnect attempts, doubled up to MaxReconnectInterval
+	DeadLetterExchange string                   // Exchange receiving rejected messages; empty disables dead-lettering
+	DeadLetterQueue    string                   // Queue bound to the dead-letter exchange
+	MaxRetries         int                      // Redeliveries of a retryable failure before it is dead-lettered; 0 disables retries, negative uses DefaultMaxRetries
+	RetryBackoff       time.Duration            // Delay before the first retry, doubled per retry up to MaxRetryBackoff; 0 retries at once. Delayed retries wait in the retry queue
+	ProcessedEvents    ProcessedEventStore      // Remembers handled event IDs to skip redeliveries (default: in-memory LRU)
+	Metrics            *metrics.ConsumerMetrics // Records message outcomes and processing time; nil disables
 }
 
+const (
+	// DefaultReconnectInterval is used when the config leaves ReconnectInterval unset
+	DefaultReconnectInterval = 5 * time.Second
+	// MaxReconnectInterval caps the exponential reconnect backoff
+	MaxReconnectInterval = time.Minute
+	// DefaultMaxRetries is used when the config sets a negative MaxRetries
+	DefaultMaxRetries = 5
+	// MaxRetryBackoff caps the exponential delay between retries
+	MaxRetryBackoff = time.Minute
+	// DefaultConcurrency is used when the config leaves Concurrency unset
+	DefaultConcurrency = 1
+	// DefaultDrainTimeout is used when the config leaves DrainTimeout unset
+	DefaultDrainTimeout = 30 * time.Second
+	// RetryCountHeader tracks how many times a message has been retried
+	RetryCountHeader = "x-retry-count"
+)
+
+// ErrUnknownEventType is returned when an event's type has no handler
+var ErrUnknownEventType = errors.New("unknown event type")
+
 // NewRabbitMQConsumer creates a new RabbitMQ consumer
 func NewRabbitMQConsumer(
 	config *RabbitMQConsumerConfig,
 	handler ExampleEventHandler,
 	logger *zap.Logger,
 ) (*RabbitMQConsumer, error) {
-	conn, err := amqp.Dial(config.URL)
+	return newRabbitMQConsumer(config, handler, logger, dialAMQP)
+}
+
+// newRabbitMQConsumer creates a consumer using the given dialer
+func newRabbitMQConsumer(
+	config *RabbitMQConsumerConfig,
+	handler ExampleEventHandler,
+	logger *zap.Logger,
+	dial amqpDialer,
+) (*RabbitMQConsumer, error) {
+	consumer := &RabbitMQConsumer{
+		config:       config,
+		dial:         dial,
+		exchangeName: config.ExchangeName,
+		consumerTag:  "example-consumer-" + uuid.NewString(),
+		routingKeys:  config.RoutingKeys,
+		handler:      handler,
+		processed:    config.ProcessedEvents,
+		logger:       logger,
+	}
+	if consumer.processed == nil {
+		consumer.processed = NewLRUProcessedEventStore(DefaultDedupCapacity)
+	}
+
+	if err := consumer.connect(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("RabbitMQ consumer initialized",
+		zap.String("exchange", config.ExchangeName),
+		zap.String("queue", consumer.queueName),
+		zap.Strings("routing_keys", config.RoutingKeys),
+	)
+
+	return consumer, nil
+}
+
+// connect dials the broker and declares the exchange, queue and bindings.
+// Callers must hold c.mu once the consumer is shared.
+func (c *RabbitMQConsumer) connect() error {
+	conn, err := c.dial(c.config.URL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
 	}
 
 	ch, err := conn.Channel()
 	if err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("failed to open channel: %w", err)
+		return fmt.Errorf("failed to open channel: %w", err)
 	}
 
-	// Set QoS
-	err = ch.Qos(config.PrefetchCount, 0, false)
+	queueName, err := c.declareTopology(ch)
 	if err != nil {
 		ch.Close()
 		conn.Close()
-		return nil, fmt.Errorf("failed to set QoS: %w", err)
+		return err
+	}
+
+	c.connection = conn
+	c.channel = ch
+	c.queueName = queueName
+	c.closeNotify = conn.NotifyClose(make(chan *amqp.Error, 1))
+	c.connected = true
+	return nil
+}
+
+// declareTopology sets QoS and declares the exchange, queue and bindings on ch
+func (c *RabbitMQConsumer) declareTopology(ch amqpChannel) (string, error) {
+	config := c.config
+
+	// Set QoS
+	if err := ch.Qos(config.PrefetchCount, 0, false); err != nil {
+		return "", fmt.Errorf("failed to set QoS: %w", err)
 	}
 
 	// Declare exchange
-	err = ch.ExchangeDeclare(
+	err := ch.ExchangeDeclare(
 		config.ExchangeName, // name
 		"topic",             // type
 		config.Durable,      // durable
@@ -90,9 +187,19 @@ func NewRabbitMQConsumer(
 		nil,                 // arguments
 	)
 	if err != nil {
-		ch.Close()
-		conn.Close()
-		return nil, fmt.Errorf("failed to declare exchange: %w", err)
+		return "", fmt.Errorf("failed to declare exchange: %w", err)
+	}
+
+	// Declare dead-letter exchange and queue
+	var queueArgs amqp.Table
+	if config.DeadLetterExchange != "" {
+		if err := c.declareDeadLetter(ch); err != nil {
+			return "", err
+		}
+		queueArgs = amqp.Table{
+			"x-dead-letter-exchange":    config.DeadLetterExchange,
+			"x-dead-letter-routing-key": deadLetterRoutingKey(config.QueueName),
+		}
 	}
 
 	// Declare queue
@@ -102,12 +209,17 @@ func NewRabbitMQConsumer(
 		config.AutoDelete, // delete when unused
 		config.Exclusive,  // exclusive
 		config.NoWait,     // no-wait
-		nil,               // arguments
+		queueArgs,         // arguments
 	)
 	if err != nil {
-		ch.Close()
-		conn.Close()
-		return nil, fmt.Errorf("failed to declare queue: %w", err)
+		return "", fmt.Errorf("failed to declare queue: %w", err)
+	}
+
+	// Declare the retry queue, whose expired messages go back to the queue
+	if config.RetryBackoff > 0 {
+		if err := c.declareRetryQueue(ch, queue.Name); err != nil {
+			return "", err
+		}
 	}
 
 	// Bind queue to exchange with routing keys
@@ -120,30 +232,92 @@ func NewRabbitMQConsumer(
 			nil,                 // arguments
 		)
 		if err != nil {
-			ch.Close()
-			conn.Close()
-			return nil, fmt.Errorf("failed to bind queue to routing key %s: %w", routingKey, err)
+			return "", fmt.Errorf("failed to bind queue to routing key %s: %w", routingKey, err)
 		}
 	}
 
-	consumer := &RabbitMQConsumer{
-		connection:   conn,
-		channel:      ch,
-		exchangeName: config.ExchangeName,
-		queueName:    queue.Name,
-		routingKeys:  config.RoutingKeys,
-		handler:      handler,
-		logger:       logger,
-		stopChan:     make(chan struct{}),
+	return queue.Name, nil
+}
+
+// declareDeadLetter declares the dead-letter exchange and queue and binds them
+func (c *RabbitMQConsumer) declareDeadLetter(ch amqpChannel) error {
+	config := c.config
+
+	err := ch.ExchangeDeclare(
+		config.DeadLetterExchange, // name
+		"direct",                  // type
+		true,                      // durable
+		false,                     // auto-deleted
+		false,                     // internal
+		false,                     // no-wait
+		nil,                       // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare dead-letter exchange: %w", err)
 	}
 
-	logger.Info("RabbitMQ consumer initialized",
-		zap.String("exchange", config.ExchangeName),
-		zap.String("queue", queue.Name),
-		zap.Strings("routing_keys", config.RoutingKeys),
+	_, err = ch.QueueDeclare(
+		config.DeadLetterQueue, // name
+		true,                   // durable
+		false,                  // delete when unused
+		false,                  // exclusive
+		false,                  // no-wait
+		nil,                    // arguments
 	)
+	if err != nil {
+		return fmt.Errorf("failed to declare dead-letter queue: %w", err)
+	}
 
-	return consumer, nil
+	err = ch.QueueBind(
+		config.DeadLetterQueue,                 // queue name
+		deadLetterRoutingKey(config.QueueName), // routing key
+		config.DeadLetterExchange,              // exchange
+		false,                                  // no-wait
+		nil,                                    // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to bind dead-letter queue: %w", err)
+	}
+
+	return nil
+}
+
+// declareRetryQueue declares the queue that delayed retries wait in. It has no consumers:
+// each message expires after its own TTL and is dead-lettered through the default exchange
+// back to queueName.
+func (c *RabbitMQConsumer) declareRetryQueue(ch amqpChannel, queueName string) error {
+	_, err := ch.QueueDeclare(
+		retryQueueName(queueName), // name
+		c.config.Durable,          // durable
+		false,                     // delete when unused
+		false,                     // exclusive
+		false,                     // no-wait
+		amqp.Table{
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": queueName,
+		}, // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare retry queue: %w", err)
+	}
+	return nil
+}
+
+// consume registers the consumer on the current channel. Callers must hold c.mu.
+func (c *RabbitMQConsumer) consume() (<-chan amqp.Delivery, error) {
+	msgs, err := c.channel.Consume(
+		c.queueName,   // queue
+		c.consumerTag, // consumer
+		false,         // auto-ack
+		false,         // exclusive
+		false,         // no-local
+		false,         // no-wait
+		nil,           // args
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register consumer: %w", err)
+	}
+	return msgs, nil
 }
 
 // Start starts consuming messages
@@ -155,82 +329,219 @@ func (c *RabbitMQConsumer) Start(ctx context.Context) error {
 		return errors.New("consumer is already running")
 	}
 
+	// Stop closes the connection, so a restarted consumer connects again
+	if c.channel == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
 	// Register consumer
-	msgs, err := c.channel.Consume(
-		c.queueName, // queue
-		"",          // consumer
-		false,       // auto-ack
-		false,       // exclusive
-		false,       // no-local
-		false,       // no-wait
-		nil,         // args
-	)
+	msgs, err := c.consume()
 	if err != nil {
-		return fmt.Errorf("failed to register consumer: %w", err)
+		return err
 	}
 
+	// Cancelling ctx stops consumption but not the handlers already running; Stop gives
+	// them until the drain timeout to settle their messages
+	c.handlerCtx, c.cancelHandlers = context.WithCancel(context.WithoutCancel(ctx))
+	c.stopChan = make(chan struct{})
 	c.isRunning = true
 	c.wg.Add(1)
 
+	go c.run(ctx, c.stopChan, msgs, c.closeNotify)
+
+	c.logger.Info("Consumer started successfully")
+	return nil
+}
+
+// run consumes deliveries and reconnects whenever the connection is lost, until stop is closed
+func (c *RabbitMQConsumer) run(ctx context.Context, stop <-chan struct{}, msgs <-chan amqp.Delivery, closeNotify <-chan *amqp.Error) {
+	defer c.wg.Done()
+	c.logger.Info("Starting message consumption")
+
+	for {
+		if !c.consumeUntilClosed(ctx, stop, msgs, closeNotify) {
+			return
+		}
+
+		c.mu.Lock()
+		c.connected = false
+		c.mu.Unlock()
+
+		var ok bool
+		msgs, closeNotify, ok = c.reconnect(ctx, stop)
+		if !ok {
+			return
+		}
+	}
+}
+
+// consumeUntilClosed handles deliveries with a pool of workers until the consumer is stopped (false)
+// or the connection is lost (true). It returns once every worker has finished its current delivery.
+func (c *RabbitMQConsumer) consumeUntilClosed(ctx context.Context, stop <-chan struct{}, msgs <-chan amqp.Delivery, closeNotify <-chan *amqp.Error) bool {
+	concurrency := c.config.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	done := make(chan struct{})
+	var workers sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			c.work(c.handlerCtx, msgs, done)
+		}()
+	}
+
+	// Workers only return on their own once the delivery channel is closed
+	drained := make(chan struct{})
 	go func() {
-		defer c.wg.Done()
-		c.logger.Info("Starting message consumption")
+		workers.Wait()
+		close(drained)
+	}()
+	defer func() {
+		close(done)
+		<-drained
+	}()
 
-		for {
-			select {
-			case <-c.stopChan:
-				c.logger.Info("Stopping message consumption")
-				return
-			case <-ctx.Done():
-				c.logger.Info("Context cancelled, stopping message consumption")
+	select {
+	case <-stop:
+		c.logger.Info("Stopping message consumption")
+		return false
+	case <-ctx.Done():
+		c.logger.Info("Context cancelled, stopping message consumption")
+		return false
+	case closeErr := <-closeNotify:
+		c.logger.Error("RabbitMQ connection closed unexpectedly", zap.Error(closeErr))
+		return true
+	case <-drained:
+		c.logger.Warn("Message channel closed")
+		return true
+	}
+}
+
+// work handles deliveries one at a time until done is closed or msgs is closed
+func (c *RabbitMQConsumer) work(ctx context.Context, msgs <-chan amqp.Delivery, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case delivery, ok := <-msgs:
+			if !ok {
 				return
-			case delivery, ok := <-msgs:
-				if !ok {
-					c.logger.Warn("Message channel closed")
-					return
-				}
-				c.handleMessage(ctx, delivery)
 			}
+			c.handleMessage(ctx, delivery)
 		}
-	}()
+	}
+}
 
-	// Set up connection close handler
-	go c.handleConnectionClose()
+// reconnect re-dials with exponential backoff until it succeeds or the consumer is stopped
+func (c *RabbitMQConsumer) reconnect(ctx context.Context, stop <-chan struct{}) (<-chan amqp.Delivery, <-chan *amqp.Error, bool) {
+	interval := c.config.ReconnectInterval
+	if interval <= 0 {
+		interval = DefaultReconnectInterval
+	}
 
-	c.logger.Info("Consumer started successfully")
-	return nil
-}
+	for attempt := 1; ; attempt++ {
+		c.logger.Info("Reconnecting to RabbitMQ",
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", interval),
+		)
 
-// Stop stops the consumer
-func (c *RabbitMQConsumer) Stop() error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+		select {
+		case <-stop:
+			return nil, nil, false
+		case <-ctx.Done():
+			return nil, nil, false
+		case <-time.After(interval):
+		}
 
-	if !c.isRunning {
-		return nil
-	}
+		c.mu.Lock()
+		c.closeConnection()
+		err := c.connect()
+		var msgs <-chan amqp.Delivery
+		if err == nil {
+			msgs, err = c.consume()
+		}
+		closeNotify := c.closeNotify
+		c.mu.Unlock()
 
-	c.logger.Info("Stopping consumer...")
+		if err == nil {
+			c.logger.Info("Reconnected to RabbitMQ", zap.Int("attempt", attempt))
+			return msgs, closeNotify, true
+		}
 
-	close(c.stopChan)
-	c.wg.Wait()
+		c.logger.Warn("Failed to reconnect to RabbitMQ", zap.Int("attempt", attempt), zap.Error(err))
+		interval = nextBackoff(interval, MaxReconnectInterval)
+	}
+}
 
+// closeConnection closes the channel and connection, ignoring already-closed errors.
+// Callers must hold c.mu.
+func (c *RabbitMQConsumer) closeConnection() []error {
 	var errs []error
 
 	if c.channel != nil {
-		if err := c.channel.Close(); err != nil {
+		if err := c.channel.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
 			errs = append(errs, fmt.Errorf("failed to close channel: %w", err))
 		}
+		c.channel = nil
 	}
 
 	if c.connection != nil {
-		if err := c.connection.Close(); err != nil {
+		if err := c.connection.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
 			errs = append(errs, fmt.Errorf("failed to close connection: %w", err))
 		}
+		c.connection = nil
 	}
 
+	c.connected = false
+	return errs
+}
+
+// IsConnected reports whether the consumer holds a live connection to the broker; it is
+// false while reconnecting and after Stop
+func (c *RabbitMQConsumer) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connected
+}
+
+// Stop stops the consumer. It cancels the consume so the broker sends no more deliveries,
+// waits up to the drain timeout for in-flight messages to be acked or rejected, and then
+// closes the channel; unsettled messages are redelivered by the broker. Stopping a stopped
+// consumer does nothing, and Start can start it again.
+func (c *RabbitMQConsumer) Stop() error {
+	c.mu.Lock()
+	if !c.isRunning {
+		c.mu.Unlock()
+		return nil
+	}
 	c.isRunning = false
+	if c.channel != nil {
+		if err := c.channel.Cancel(c.consumerTag, false); err != nil && !errors.Is(err, amqp.ErrClosed) {
+			c.logger.Warn("Failed to cancel consumer", zap.Error(err))
+		}
+	}
+	// Only the Stop that clears isRunning closes the channel Start made
+	close(c.stopChan)
+	c.mu.Unlock()
+
+	c.logger.Info("Stopping consumer...")
+
+	// Wait outside the lock so an in-flight reconnect can finish
+	drainErr := c.drain()
+	c.cancelHandlers()
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errs := c.closeConnection()
+	if drainErr != nil {
+		errs = append(errs, drainErr)
+	}
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -239,8 +550,39 @@ func (c *RabbitMQConsumer) Stop() error {
 	return nil
 }
 
-// handleMessage handles incoming messages
+// drain waits for the consume loop and its in-flight handlers to finish, up to the drain timeout
+func (c *RabbitMQConsumer) drain() error {
+	timeout := c.config.DrainTimeout
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		c.logger.Warn("Timed out waiting for in-flight messages", zap.Duration("drain_timeout", timeout))
+		return fmt.Errorf("timed out after %s waiting for in-flight messages", timeout)
+	}
+}
+
+// handleMessage handles incoming messages, recording how each was settled
 func (c *RabbitMQConsumer) handleMessage(ctx context.Context, delivery amqp.Delivery) {
+	start := time.Now()
+	outcome := c.processMessage(ctx, delivery)
+	if c.config.Metrics != nil {
+		c.config.Metrics.ObserveMessage(outcome, time.Since(start))
+	}
+}
+
+// processMessage decodes, dispatches and settles a message, returning its metrics outcome
+func (c *RabbitMQConsumer) processMessage(ctx context.Context, delivery amqp.Delivery) string {
 	logger := c.logger.With(
 		zap.String("message_id", delivery.MessageId),
 		zap.String("routing_key", delivery.RoutingKey),
@@ -249,35 +591,47 @@ func (c *RabbitMQConsumer) handleMessage(ctx context.Context, delivery amqp.Deli
 
 	logger.Debug("Processing message")
 
-	// Parse event
-	var event ExampleEvent
-	if err := json.Unmarshal(delivery.Body, &event); err != nil {
+	// Continue the publisher's trace
+	ctx, span := startProcessSpan(extractTraceContext(ctx, delivery.Headers), messagingSystemRabbitMQ, delivery.RoutingKey)
+	defer span.End()
+
+	// Parse event; versions this consumer cannot decode are dead-lettered like malformed messages
+	event, err := decodeExampleEvent(delivery.Body)
+	if errors.Is(err, ErrUnsupportedSchemaVersion) {
+		logger.Warn("Unsupported event schema version", zap.Error(err))
+		tracing.RecordError(span, err)
+		c.rejectMessage(delivery, false)
+		return metrics.MessageDeadLettered
+	}
+	if err != nil {
 		logger.Error("Failed to unmarshal event", zap.Error(err))
+		tracing.RecordError(span, err)
 		c.rejectMessage(delivery, false)
-		return
+		return metrics.MessageDeadLettered
 	}
 
 	// Add message metadata to context
-	msgCtx := context.WithValue(ctx, "message_id", delivery.MessageId)
-	msgCtx = context.WithValue(msgCtx, "routing_key", delivery.RoutingKey)
-	msgCtx = context.WithValue(msgCtx, "delivery_tag", delivery.DeliveryTag)
+	msgCtx := ctxkeys.WithMessageID(ctx, delivery.MessageId)
+	msgCtx = ctxkeys.WithRoutingKey(msgCtx, delivery.RoutingKey)
+	msgCtx = ctxkeys.WithDeliveryTag(msgCtx, delivery.DeliveryTag)
+
+	// Redelivered events that were already handled are acknowledged without running the handler again
+	if alreadyProcessed(msgCtx, c.processed, event.ID, logger) {
+		logger.Info("Skipping already processed event", zap.String("event_id", event.ID))
+		c.ackMessage(delivery)
+		return metrics.MessageProcessed
+	}
 
 	// Handle event based on type
-	var err error
-	switch event.Type {
-	case EventTypeExampleCreated:
-		err = c.handler.HandleExampleCreated(msgCtx, &event)
-	case EventTypeExampleUpdated:
-		err = c.handler.HandleExampleUpdated(msgCtx, &event)
-	case EventTypeExampleDeleted:
-		err = c.handler.HandleExampleDeleted(msgCtx, &event)
-	default:
+	err = dispatchEvent(msgCtx, c.handler, event)
+	if errors.Is(err, ErrUnknownEventType) {
 		logger.Warn("Unknown event type", zap.String("event_type", string(event.Type)))
 		c.ackMessage(delivery)
-		return
+		return metrics.MessageProcessed
 	}
 
 	if err != nil {
+		tracing.RecordError(span, err)
 		logger.Error("Failed to handle event",
 			zap.Error(err),
 			zap.String("event_type", string(event.Type)),
@@ -285,20 +639,21 @@ func (c *RabbitMQConsumer) handleMessage(ctx context.Context, delivery amqp.Deli
 		)
 
 		// Check if this is a retryable error
-		if c.isRetryableError(err) {
-			c.rejectMessage(delivery, true) // Requeue for retry
-		} else {
-			c.rejectMessage(delivery, false) // Don't requeue
+		if isRetryableError(err) {
+			return c.retryMessage(ctx, delivery, logger)
 		}
-		return
+		c.rejectMessage(delivery, false) // Dead-letter
+		return metrics.MessageDeadLettered
 	}
 
 	// Acknowledge successful processing
+	markProcessed(msgCtx, c.processed, event.ID, logger)
 	c.ackMessage(delivery)
 	logger.Info("Event processed successfully",
 		zap.String("event_type", string(event.Type)),
 		zap.String("event_id", event.ID),
 	)
+	return metrics.MessageProcessed
 }
 
 // ackMessage acknowledges a message
@@ -322,8 +677,64 @@ func (c *RabbitMQConsumer) rejectMessage(delivery amqp.Delivery, requeue bool) {
 	}
 }
 
+// retryMessage republishes a failed message with an incremented retry count, dead-lettering
+// it once the retry limit is reached, and returns the outcome. With a retry backoff the
+// message is parked in the retry queue with the delay as its TTL, so the broker holds it
+// rather than the worker. Messages only leave the retry queue from its head, so a retry can
+// wait behind a longer one, for at most MaxRetryBackoff.
+func (c *RabbitMQConsumer) retryMessage(ctx context.Context, delivery amqp.Delivery, logger *zap.Logger) string {
+	maxRetries := retryLimit(c.config.MaxRetries)
+
+	attempt := retryCount(delivery.Headers) + 1
+	if attempt > maxRetries {
+		logger.Warn("Retry limit reached, dead-lettering message", zap.Int("max_retries", maxRetries))
+		c.rejectMessage(delivery, false)
+		return metrics.MessageDeadLettered
+	}
+
+	c.mu.RLock()
+	ch, queueName := c.channel, c.queueName
+	c.mu.RUnlock()
+
+	if ch == nil {
+		c.rejectMessage(delivery, true)
+		return metrics.MessageRequeued
+	}
+
+	publishing := amqp.Publishing{
+		Headers:      withRetryCount(delivery.Headers, attempt),
+		ContentType:  delivery.ContentType,
+		DeliveryMode: delivery.DeliveryMode,
+		MessageId:    delivery.MessageId,
+		Timestamp:    delivery.Timestamp,
+		Type:         delivery.Type,
+		Body:         delivery.Body,
+	}
+
+	// Publish straight to our queue, or the retry queue, through the default exchange so other
+	// bindings don't see the retry
+	delay := retryDelay(c.config.RetryBackoff, attempt)
+	if delay > 0 {
+		queueName = retryQueueName(queueName)
+		publishing.Expiration = strconv.FormatInt(delay.Milliseconds(), 10)
+	}
+	if err := ch.PublishWithContext(ctx, "", queueName, false, false, publishing); err != nil {
+		logger.Error("Failed to republish message for retry, requeueing", zap.Error(err))
+		c.rejectMessage(delivery, true)
+		return metrics.MessageRequeued
+	}
+
+	logger.Info("Message scheduled for retry",
+		zap.Int("retry", attempt),
+		zap.Int("max_retries", maxRetries),
+		zap.Duration("delay", delay),
+	)
+	c.ackMessage(delivery)
+	return metrics.MessageRetried
+}
+
 // isRetryableError determines if an error is retryable
-func (c *RabbitMQConsumer) isRetryableError(err error) bool {
+func isRetryableError(err error) bool {
 	// Add logic to determine if error is retryable
 	// For example, network errors might be retryable, but validation errors are not
 	retryableErrors := []string{
@@ -343,15 +754,76 @@ func (c *RabbitMQConsumer) isRetryableError(err error) bool {
 	return false
 }
 
-// handleConnectionClose handles connection close events
-func (c *RabbitMQConsumer) handleConnectionClose() {
-	closeError := <-c.connection.NotifyClose(make(chan *amqp.Error))
-	if closeError != nil {
-		c.logger.Error("RabbitMQ connection closed unexpectedly",
-			zap.Error(closeError),
-		)
-		// In production, you might want to implement reconnection logic here
+// dispatchEvent routes an event to the handler method for its type
+func dispatchEvent(ctx context.Context, handler ExampleEventHandler, event *ExampleEvent) error {
+	switch event.Type {
+	case EventTypeExampleCreated:
+		return handler.HandleExampleCreated(ctx, event)
+	case EventTypeExampleUpdated:
+		return handler.HandleExampleUpdated(ctx, event)
+	case EventTypeExampleDeleted:
+		return handler.HandleExampleDeleted(ctx, event)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownEventType, event.Type)
+	}
+}
+
+// deadLetterRoutingKey returns the routing key used to dead-letter messages from queueName
+func deadLetterRoutingKey(queueName string) string {
+	return queueName + ".dead"
+}
+
+// retryQueueName returns the name of the queue delayed retries of queueName wait in
+func retryQueueName(queueName string) string {
+	return queueName + ".retry"
+}
+
+// retryLimit resolves a configured MaxRetries: 0 disables retries and a negative value
+// uses DefaultMaxRetries
+func retryLimit(maxRetries int) int {
+	if maxRetries < 0 {
+		return DefaultMaxRetries
 	}
+	return maxRetries
+}
+
+// retryDelay returns how long to wait before retry attempt, counting from 1: backoff
+// doubled per earlier retry, capped at MaxRetryBackoff
+func retryDelay(backoff time.Duration, attempt int) time.Duration {
+	if backoff <= 0 {
+		return 0
+	}
+	delay := backoff
+	for i := 1; i < attempt && delay < MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, MaxRetryBackoff)
+}
+
+// retryCount reads the retry count header, tolerating the integer types AMQP decodes to
+func retryCount(headers amqp.Table) int {
+	switch v := headers[RetryCountHeader].(type) {
+	case int:
+		return v
+	case int16:
+		return int(v)
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// withRetryCount returns a copy of headers with the retry count set to count
+func withRetryCount(headers amqp.Table, count int) amqp.Table {
+	updated := make(amqp.Table, len(headers)+1)
+	for key, value := range headers {
+		updated[key] = value
+	}
+	updated[RetryCountHeader] = int32(count)
+	return updated
 }
 
 // DefaultExampleEventHandler provides a default implementation of ExampleEventHandler
@@ -463,17 +935,7 @@ func (m *MockConsumer) Stop() error {
 // SimulateEvent simulates receiving an event (for testing)
 func (m *MockConsumer) SimulateEvent(ctx context.Context, event *ExampleEvent) error {
 	m.events = append(m.events, *event)
-
-	switch event.Type {
-	case EventTypeExampleCreated:
-		return m.handler.HandleExampleCreated(ctx, event)
-	case EventTypeExampleUpdated:
-		return m.handler.HandleExampleUpdated(ctx, event)
-	case EventTypeExampleDeleted:
-		return m.handler.HandleExampleDeleted(ctx, event)
-	default:
-		return fmt.Errorf("unknown event type: %s", event.Type)
-	}
+	return dispatchEvent(ctx, m.handler, event)
 }
 
 // GetProcessedEvents returns all processed events (for testing)
@@ -6,11 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"example-api-template/internal/domain"
 	"example-api-template/internal/usecase"
+	"example-api-template/pkg/ctxkeys"
+	"example-api-template/pkg/logger"
+	"example-api-template/pkg/tracing"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 	"go.uber.org/zap"
@@ -20,18 +24,19 @@ import (
 type EventType string
 
 const (
-	EventTypeExampleCreated EventType = "example.created"
-	EventTypeExampleUpdated EventType = "example.updated"
-	EventTypeExampleDeleted EventType = "example.deleted"
+	EventTypeExampleCreated EventType = domain.EventTypeExampleCreated
+	EventTypeExampleUpdated EventType = domain.EventTypeExampleUpdated
+	EventTypeExampleDeleted EventType = domain.EventTypeExampleDeleted
 )
 
 // ExampleEvent represents an event related to an example
 type ExampleEvent struct {
-	ID        string                       `json:"id"`
-	Type      EventType                    `json:"type"`
-	Timestamp time.Time                    `json:"timestamp"`
-	Data      *usecase.ExampleWithMetadata `json:"data,omitempty"`
-	Metadata  map[string]interface{}       `json:"metadata,omitempty"`
+	ID            string                       `json:"id"`
+	SchemaVersion string                       `json:"schema_version"` // Payload shape, see CurrentSchemaVersion
+	Type          EventType                    `json:"type"`
+	Timestamp     time.Time                    `json:"timestamp"`
+	Data          *usecase.ExampleWithMetadata `json:"data,omitempty"`
+	Metadata      map[string]interface{}       `json:"metadata,omitempty"`
 }
 
 // ExampleDeletedEventData represents data for deletion events
@@ -51,11 +56,14 @@ type ExampleProducer interface {
 
 // RabbitMQProducer implements ExampleProducer using RabbitMQ
 type RabbitMQProducer struct {
-	connection    *amqp.Connection
-	channel       *amqp.Channel
+	config        *RabbitMQProducerConfig
+	dial          amqpDialer
+	connection    amqpConnection
+	channel       amqpChannel
 	exchangeName  string
 	routingPrefix string
 	logger        *zap.Logger
+	mu            sync.Mutex
 }
 
 // RabbitMQProducerConfig holds configuration for RabbitMQ producer
@@ -65,121 +73,117 @@ type RabbitMQProducerConfig struct {
 	RoutingPrefix string
 	Durable       bool
 	AutoDelete    bool
+	RetryAttempts int           // Total publish attempts per event, including the first (default: 3)
+	RetryBackoff  time.Duration // Delay before the first retry, doubled for each further retry (default: 200ms)
 }
 
+const (
+	// DefaultPublishRetryAttempts is used when the config leaves RetryAttempts unset
+	DefaultPublishRetryAttempts = 3
+	// DefaultPublishRetryBackoff is used when the config leaves RetryBackoff unset
+	DefaultPublishRetryBackoff = 200 * time.Millisecond
+	// maxPublishRetryBackoff caps the delay between publish attempts
+	maxPublishRetryBackoff = 5 * time.Second
+)
+
+// ErrPublishFailed is returned when an event could not be published after all retries
+var ErrPublishFailed = errors.New("failed to publish event")
+
 // NewRabbitMQProducer creates a new RabbitMQ producer
 func NewRabbitMQProducer(config *RabbitMQProducerConfig, logger *zap.Logger) (*RabbitMQProducer, error) {
-	conn, err := amqp.Dial(config.URL)
+	return newRabbitMQProducer(config, logger, dialAMQP)
+}
+
+// newRabbitMQProducer creates a producer using the given dialer
+func newRabbitMQProducer(config *RabbitMQProducerConfig, logger *zap.Logger, dial amqpDialer) (*RabbitMQProducer, error) {
+	producer := &RabbitMQProducer{
+		config:        config,
+		dial:          dial,
+		exchangeName:  config.ExchangeName,
+		routingPrefix: config.RoutingPrefix,
+		logger:        logger,
+	}
+
+	if err := producer.connect(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("RabbitMQ producer initialized",
+		zap.String("exchange", config.ExchangeName),
+		zap.String("routing_prefix", config.RoutingPrefix),
+	)
+
+	return producer, nil
+}
+
+// connect dials the broker, opens a channel and declares the exchange.
+// Callers must hold p.mu once the producer is shared.
+func (p *RabbitMQProducer) connect() error {
+	conn, err := p.dial(p.config.URL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
 	}
 
 	ch, err := conn.Channel()
 	if err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("failed to open channel: %w", err)
+		return fmt.Errorf("failed to open channel: %w", err)
 	}
 
 	// Declare exchange
 	err = ch.ExchangeDeclare(
-		config.ExchangeName, // name
-		"topic",             // type
-		config.Durable,      // durable
-		config.AutoDelete,   // auto-deleted
-		false,               // internal
-		false,               // no-wait
-		nil,                 // arguments
+		p.config.ExchangeName, // name
+		"topic",               // type
+		p.config.Durable,      // durable
+		p.config.AutoDelete,   // auto-deleted
+		false,                 // internal
+		false,                 // no-wait
+		nil,                   // arguments
 	)
 	if err != nil {
 		ch.Close()
 		conn.Close()
-		return nil, fmt.Errorf("failed to declare exchange: %w", err)
+		return fmt.Errorf("failed to declare exchange: %w", err)
 	}
 
-	producer := &RabbitMQProducer{
-		connection:    conn,
-		channel:       ch,
-		exchangeName:  config.ExchangeName,
-		routingPrefix: config.RoutingPrefix,
-		logger:        logger,
-	}
+	p.connection = conn
+	p.channel = ch
 
 	// Set up connection close handler
-	go producer.handleConnectionClose()
-
-	logger.Info("RabbitMQ producer initialized",
-		zap.String("exchange", config.ExchangeName),
-		zap.String("routing_prefix", config.RoutingPrefix),
-	)
+	go p.handleConnectionClose(conn, conn.NotifyClose(make(chan *amqp.Error, 1)))
 
-	return producer, nil
+	return nil
 }
 
 // PublishExampleCreated publishes an example created event
 func (p *RabbitMQProducer) PublishExampleCreated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
-	event := &ExampleEvent{
-		ID:        generateEventID(),
-		Type:      EventTypeExampleCreated,
-		Timestamp: time.Now(),
-		Data:      example,
-		Metadata: map[string]interface{}{
-			"source":   "example-api",
-			"version":  "1.0",
-			"user_id":  extractUserID(ctx),
-			"trace_id": extractTraceID(ctx),
-		},
-	}
-
+	event := newExampleEvent(ctx, EventTypeExampleCreated, example)
 	routingKey := fmt.Sprintf("%s.%s", p.routingPrefix, EventTypeExampleCreated)
 	return p.publishEvent(ctx, event, routingKey)
 }
 
 // PublishExampleUpdated publishes an example updated event
 func (p *RabbitMQProducer) PublishExampleUpdated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
-	event := &ExampleEvent{
-		ID:        generateEventID(),
-		Type:      EventTypeExampleUpdated,
-		Timestamp: time.Now(),
-		Data:      example,
-		Metadata: map[string]interface{}{
-			"source":   "example-api",
-			"version":  "1.0",
-			"user_id":  extractUserID(ctx),
-			"trace_id": extractTraceID(ctx),
-		},
-	}
-
+	event := newExampleEvent(ctx, EventTypeExampleUpdated, example)
 	routingKey := fmt.Sprintf("%s.%s", p.routingPrefix, EventTypeExampleUpdated)
 	return p.publishEvent(ctx, event, routingKey)
 }
 
 // PublishExampleDeleted publishes an example deleted event
 func (p *RabbitMQProducer) PublishExampleDeleted(ctx context.Context, exampleID, email, name string) error {
-	event := &ExampleEvent{
-		ID:        generateEventID(),
-		Type:      EventTypeExampleDeleted,
-		Timestamp: time.Now(),
-		Data: &usecase.ExampleWithMetadata{
-			Example: &domain.Example{
-				ID:    exampleID,
-				Name:  name,
-				Email: email,
-			},
-		},
-		Metadata: map[string]interface{}{
-			"source":   "example-api",
-			"version":  "1.0",
-			"user_id":  extractUserID(ctx),
-			"trace_id": extractTraceID(ctx),
-		},
-	}
-
+	event := newExampleEvent(ctx, EventTypeExampleDeleted, deletedExampleData(exampleID, email, name))
 	routingKey := fmt.Sprintf("%s.%s", p.routingPrefix, EventTypeExampleDeleted)
 	return p.publishEvent(ctx, event, routingKey)
 }
 
 // publishEvent publishes an event to the message queue
-func (p *RabbitMQProducer) publishEvent(ctx context.Context, event *ExampleEvent, routingKey string) error {
+func (p *RabbitMQProducer) publishEvent(ctx context.Context, event *ExampleEvent, routingKey string) (err error) {
+	ctx, span := startPublishSpan(ctx, messagingSystemRabbitMQ, routingKey)
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	body, err := json.Marshal(event)
 	if err != nil {
 		p.logger.Error("Failed to marshal event", zap.Error(err), zap.String("event_id", event.ID))
@@ -201,12 +205,89 @@ func (p *RabbitMQProducer) publishEvent(ctx context.Context, event *ExampleEvent
 		},
 		Body: body,
 	}
+	if requestID := extractRequestID(ctx); requestID != "" {
+		publishing.Headers["request_id"] = requestID
+	}
+	injectTraceContext(ctx, publishing.Headers)
+
+	logger := p.logger.With(
+		zap.String("event_id", event.ID),
+		zap.String("event_type", string(event.Type)),
+		zap.String("routing_key", routingKey),
+	)
+
+	return publishWithRetry(ctx, p.config.RetryAttempts, p.config.RetryBackoff, logger, func() error {
+		return p.publish(ctx, routingKey, publishing)
+	})
+}
+
+// permanentError marks a publish failure that retrying can't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// permanent wraps err so publishWithRetry gives up on it straight away
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// publishWithRetry calls publish until it succeeds, attempts run out, ctx is done or the
+// error is permanent, doubling the delay between attempts
+func publishWithRetry(ctx context.Context, attempts int, backoff time.Duration, logger *zap.Logger, publish func() error) error {
+	if attempts <= 0 {
+		attempts = DefaultPublishRetryAttempts
+	}
+	if backoff <= 0 {
+		backoff = DefaultPublishRetryBackoff
+	}
+
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		err = publish()
+		if err == nil {
+			logger.Info("Event published successfully", zap.Int("attempt", attempt))
+			return nil
+		}
+
+		logger.Warn("Failed to publish event", zap.Error(err), zap.Int("attempt", attempt))
+		var permanentErr *permanentError
+		if attempt >= attempts || ctx.Err() != nil || errors.As(err, &permanentErr) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+		backoff = nextBackoff(backoff, maxPublishRetryBackoff)
+	}
+
+	logger.Error("Giving up publishing event", zap.Error(err), zap.Int("attempts", attempt))
+	return fmt.Errorf("%w after %d attempt(s): %w", ErrPublishFailed, attempt, err)
+}
+
+// publish sends a single message, reconnecting first if the connection was lost
+func (p *RabbitMQProducer) publish(ctx context.Context, routingKey string, publishing amqp.Publishing) error {
+	p.mu.Lock()
+	if p.channel == nil {
+		if err := p.connect(); err != nil {
+			p.mu.Unlock()
+			return err
+		}
+		p.logger.Info("Reconnected RabbitMQ producer")
+	}
+	ch := p.channel
+	p.mu.Unlock()
 
 	// Set timeout for publishing
 	publishCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	err = p.channel.PublishWithContext(
+	err := ch.PublishWithContext(
 		publishCtx,
 		p.exchangeName, // exchange
 		routingKey,     // routing key
@@ -215,61 +296,83 @@ func (p *RabbitMQProducer) publishEvent(ctx context.Context, event *ExampleEvent
 		publishing,
 	)
 
-	if err != nil {
-		p.logger.Error("Failed to publish event",
-			zap.Error(err),
-			zap.String("event_id", event.ID),
-			zap.String("event_type", string(event.Type)),
-			zap.String("routing_key", routingKey),
-		)
-		return fmt.Errorf("failed to publish event: %w", err)
+	// A broker-level error means the channel is unusable; reconnect on the next attempt
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) {
+		p.mu.Lock()
+		if p.channel == ch {
+			p.closeConnection()
+		}
+		p.mu.Unlock()
 	}
 
-	p.logger.Info("Event published successfully",
-		zap.String("event_id", event.ID),
-		zap.String("event_type", string(event.Type)),
-		zap.String("routing_key", routingKey),
-	)
+	return err
+}
 
-	return nil
+// IsConnected reports whether the producer holds an open channel; after a connection
+// loss it stays false until the next publish reconnects
+func (p *RabbitMQProducer) IsConnected() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.channel != nil
 }
 
 // Close closes the producer connection
 func (p *RabbitMQProducer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if errs := p.closeConnection(); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	p.logger.Info("RabbitMQ producer closed successfully")
+	return nil
+}
+
+// closeConnection closes the channel and connection, ignoring already-closed errors.
+// Callers must hold p.mu.
+func (p *RabbitMQProducer) closeConnection() []error {
 	var errs []error
 
 	if p.channel != nil {
-		if err := p.channel.Close(); err != nil {
+		if err := p.channel.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
 			errs = append(errs, fmt.Errorf("failed to close channel: %w", err))
 		}
+		p.channel = nil
 	}
 
 	if p.connection != nil {
-		if err := p.connection.Close(); err != nil {
+		if err := p.connection.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
 			errs = append(errs, fmt.Errorf("failed to close connection: %w", err))
 		}
+		p.connection = nil
 	}
 
-	if len(errs) > 0 {
-		return errors.Join(errs...)
+	return errs
+}
+
+// handleConnectionClose drops a lost connection so the next publish reconnects
+func (p *RabbitMQProducer) handleConnectionClose(conn amqpConnection, notify <-chan *amqp.Error) {
+	closeError, ok := <-notify
+	if !ok || closeError == nil {
+		return // closed by us
 	}
 
-	p.logger.Info("RabbitMQ producer closed successfully")
-	return nil
-}
+	p.logger.Error("RabbitMQ connection closed unexpectedly",
+		zap.Error(closeError),
+	)
 
-// handleConnectionClose handles connection close events
-func (p *RabbitMQProducer) handleConnectionClose() {
-	closeError := <-p.connection.NotifyClose(make(chan *amqp.Error))
-	if closeError != nil {
-		p.logger.Error("RabbitMQ connection closed unexpectedly",
-			zap.Error(closeError),
-		)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.connection == conn {
+		p.closeConnection()
 	}
 }
 
 // MockProducer is a mock implementation for testing
 type MockProducer struct {
+	mu     sync.Mutex
 	events []ExampleEvent
 	logger *zap.Logger
 }
@@ -284,45 +387,21 @@ func NewMockProducer(logger *zap.Logger) *MockProducer {
 
 // PublishExampleCreated mock implementation
 func (m *MockProducer) PublishExampleCreated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
-	event := ExampleEvent{
-		ID:        generateEventID(),
-		Type:      EventTypeExampleCreated,
-		Timestamp: time.Now(),
-		Data:      example,
-	}
-	m.events = append(m.events, event)
+	m.record(*newExampleEvent(ctx, EventTypeExampleCreated, example))
 	m.logger.Info("Mock: Example created event published", zap.String("example_id", example.ID))
 	return nil
 }
 
 // PublishExampleUpdated mock implementation
 func (m *MockProducer) PublishExampleUpdated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
-	event := ExampleEvent{
-		ID:        generateEventID(),
-		Type:      EventTypeExampleUpdated,
-		Timestamp: time.Now(),
-		Data:      example,
-	}
-	m.events = append(m.events, event)
+	m.record(*newExampleEvent(ctx, EventTypeExampleUpdated, example))
 	m.logger.Info("Mock: Example updated event published", zap.String("example_id", example.ID))
 	return nil
 }
 
 // PublishExampleDeleted mock implementation
 func (m *MockProducer) PublishExampleDeleted(ctx context.Context, exampleID, email, name string) error {
-	event := ExampleEvent{
-		ID:        generateEventID(),
-		Type:      EventTypeExampleDeleted,
-		Timestamp: time.Now(),
-		Data: &usecase.ExampleWithMetadata{
-			Example: &domain.Example{
-				ID:    exampleID,
-				Name:  name,
-				Email: email,
-			},
-		},
-	}
-	m.events = append(m.events, event)
+	m.record(*newExampleEvent(ctx, EventTypeExampleDeleted, deletedExampleData(exampleID, email, name)))
 	m.logger.Info("Mock: Example deleted event published", zap.String("example_id", exampleID))
 	return nil
 }
@@ -335,18 +414,71 @@ func (m *MockProducer) Close() error {
 
 // GetEvents returns all published events (for testing)
 func (m *MockProducer) GetEvents() []ExampleEvent {
-	return m.events
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ExampleEvent(nil), m.events...)
 }
 
 // ClearEvents clears all published events (for testing)
 func (m *MockProducer) ClearEvents() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.events = m.events[:0]
 }
 
+// record stores a published event
+func (m *MockProducer) record(event ExampleEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+}
+
 // Helper functions
 
+// newExampleEvent builds an event carrying the standard metadata taken from ctx
+func newExampleEvent(ctx context.Context, eventType EventType, data *usecase.ExampleWithMetadata) *ExampleEvent {
+	event := &ExampleEvent{
+		ID:            eventID(ctx),
+		SchemaVersion: CurrentSchemaVersion,
+		Type:          eventType,
+		Timestamp:     time.Now(),
+		Data:          data,
+		Metadata: map[string]interface{}{
+			"source":   "example-api",
+			"version":  "1.0",
+			"user_id":  extractUserID(ctx),
+			"trace_id": extractTraceID(ctx),
+		},
+	}
+	// Events raised outside an HTTP request have no request ID to correlate with
+	if requestID := extractRequestID(ctx); requestID != "" {
+		event.Metadata["request_id"] = requestID
+	}
+	return event
+}
+
+// deletedExampleData builds the payload of a deletion event
+func deletedExampleData(exampleID, email, name string) *usecase.ExampleWithMetadata {
+	return &usecase.ExampleWithMetadata{
+		Example: &domain.Example{
+			ID:    exampleID,
+			Name:  name,
+			Email: email,
+		},
+	}
+}
+
 var eventCounter int64
 
+// eventID returns the event ID carried by ctx, e.g. that of the outbox event being relayed,
+// so consumers can deduplicate redeliveries, or a new one
+func eventID(ctx context.Context) string {
+	if id := ctxkeys.EventID(ctx); id != "" {
+		return id
+	}
+	return generateEventID()
+}
+
 // generateEventID generates a unique event ID
 func generateEventID() string {
 	// Use timestamp + counter + random component for uniqueness
@@ -357,20 +489,21 @@ func generateEventID() string {
 
 // extractUserID extracts user ID from context
 func extractUserID(ctx context.Context) string {
-	if userID := ctx.Value("user_id"); userID != nil {
-		if id, ok := userID.(string); ok {
-			return id
-		}
+	if userID := ctxkeys.UserID(ctx); userID != "" {
+		return userID
 	}
 	return "system"
 }
 
+// extractRequestID extracts the HTTP request ID from context, or "" outside a request
+func extractRequestID(ctx context.Context) string {
+	return logger.RequestID(ctx)
+}
+
 // extractTraceID extracts trace ID from context
 func extractTraceID(ctx context.Context) string {
-	if traceID := ctx.Value("trace_id"); traceID != nil {
-		if id, ok := traceID.(string); ok {
-			return id
-		}
+	if traceID := ctxkeys.TraceID(ctx); traceID != "" {
+		return traceID
 	}
-	return ""
+	return tracing.TraceID(ctx)
 }
@@ -0,0 +1,51 @@
+package mq
+
+import (
+	"context"
+	"errors"
+
+	"example-api-template/internal/usecase"
+)
+
+// FanOutProducer implements ExampleProducer by publishing every event to several producers,
+// e.g. the message queue and a webhook. Each producer gets every event even when another
+// fails; the failures are joined into the returned error.
+type FanOutProducer struct {
+	producers []ExampleProducer
+}
+
+// NewFanOutProducer creates a producer publishing to all of producers in order
+func NewFanOutProducer(producers ...ExampleProducer) *FanOutProducer {
+	return &FanOutProducer{producers: producers}
+}
+
+// PublishExampleCreated publishes an example created event to every producer
+func (f *FanOutProducer) PublishExampleCreated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
+	return f.each(func(p ExampleProducer) error { return p.PublishExampleCreated(ctx, example) })
+}
+
+// PublishExampleUpdated publishes an example updated event to every producer
+func (f *FanOutProducer) PublishExampleUpdated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
+	return f.each(func(p ExampleProducer) error { return p.PublishExampleUpdated(ctx, example) })
+}
+
+// PublishExampleDeleted publishes an example deleted event to every producer
+func (f *FanOutProducer) PublishExampleDeleted(ctx context.Context, exampleID, email, name string) error {
+	return f.each(func(p ExampleProducer) error { return p.PublishExampleDeleted(ctx, exampleID, email, name) })
+}
+
+// Close closes every producer
+func (f *FanOutProducer) Close() error {
+	return f.each(ExampleProducer.Close)
+}
+
+// each calls fn for every producer, joining the errors
+func (f *FanOutProducer) each(fn func(ExampleProducer) error) error {
+	var errs []error
+	for _, p := range f.producers {
+		if err := fn(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
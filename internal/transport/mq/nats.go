@@ -0,0 +1,87 @@
+package mq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+)
+
+// exampleEventTypes lists every event type published on the example stream
+var exampleEventTypes = []EventType{
+	EventTypeExampleCreated,
+	EventTypeExampleUpdated,
+	EventTypeExampleDeleted,
+}
+
+// natsSubject maps an event type to its JetStream subject, e.g. "example.example.created"
+func natsSubject(prefix string, eventType EventType) string {
+	return fmt.Sprintf("%s.%s", prefix, eventType)
+}
+
+// natsSubjects returns the subjects of all example event types under prefix
+func natsSubjects(prefix string) []string {
+	subjects := make([]string, 0, len(exampleEventTypes))
+	for _, eventType := range exampleEventTypes {
+		subjects = append(subjects, natsSubject(prefix, eventType))
+	}
+	return subjects
+}
+
+// connectNATS dials the server with unlimited automatic reconnects
+func connectNATS(url, name string, reconnectWait time.Duration, logger *zap.Logger) (*nats.Conn, error) {
+	if reconnectWait <= 0 {
+		reconnectWait = DefaultReconnectInterval
+	}
+
+	conn, err := nats.Connect(url,
+		nats.Name(name),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(reconnectWait),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Error("NATS connection lost", zap.Error(err))
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.Info("Reconnected to NATS", zap.String("url", conn.ConnectedUrlRedacted()))
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	return conn, nil
+}
+
+// ensureStream creates the example event stream or updates it to match the config
+func ensureStream(ctx context.Context, js jetstream.JetStream, name, prefix string, durable bool) error {
+	storage := jetstream.MemoryStorage
+	if durable {
+		storage = jetstream.FileStorage
+	}
+
+	_, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     name,
+		Subjects: natsSubjects(prefix),
+		Storage:  storage,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to declare stream: %w", err)
+	}
+	return nil
+}
+
+// injectNATSTraceContext writes the active trace context from ctx into the message headers
+func injectNATSTraceContext(ctx context.Context, header nats.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// extractNATSTraceContext returns ctx carrying the trace context found in the message headers
+func extractNATSTraceContext(ctx context.Context, header nats.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
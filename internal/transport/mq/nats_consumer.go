@@ -0,0 +1,274 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"example-api-template/pkg/ctxkeys"
+	"example-api-template/pkg/metrics"
+	"example-api-template/pkg/tracing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+// NATSConsumer implements ExampleConsumer using a durable NATS JetStream consumer
+type NATSConsumer struct {
+	config     *NATSConsumerConfig
+	conn       *nats.Conn
+	consumer   jetstream.Consumer
+	consumeCtx jetstream.ConsumeContext
+	handler    ExampleEventHandler
+	processed  ProcessedEventStore
+	maxRetries int
+	logger     *zap.Logger
+	mu         sync.Mutex
+	isRunning  bool
+}
+
+// NATSConsumerConfig holds configuration for NATS consumer
+type NATSConsumerConfig struct {
+	URL               string
+	StreamName        string
+	SubjectPrefix     string
+	DurableName       string                   // Durable consumer name, shared by all replicas of the service
+	Durable           bool                     // Store the stream on disk rather than in memory
+	MaxAckPending     int                      // Unacknowledged messages allowed in flight; 0 uses the server default
+	MaxRetries        int                      // Redeliveries of a retryable failure before it is terminated; 0 disables retries, negative uses DefaultMaxRetries
+	RetryBackoff      time.Duration            // Redelivery delay after the first failure, doubled per retry up to MaxRetryBackoff; 0 redelivers at once
+	AckWait           time.Duration            // Time to process a message before it is redelivered (default: 30s)
+	ReconnectInterval time.Duration            // Delay between reconnect attempts (default: 5s)
+	ProcessedEvents   ProcessedEventStore      // Remembers handled event IDs to skip redeliveries (default: in-memory LRU)
+	Metrics           *metrics.ConsumerMetrics // Records message outcomes and processing time; nil disables
+}
+
+// DefaultNATSAckWait is used when the config leaves AckWait unset
+const DefaultNATSAckWait = 30 * time.Second
+
+// NewNATSConsumer creates a new NATS JetStream consumer, declaring the stream and consumer if needed
+func NewNATSConsumer(
+	config *NATSConsumerConfig,
+	handler ExampleEventHandler,
+	logger *zap.Logger,
+) (*NATSConsumer, error) {
+	conn, err := connectNATS(config.URL, "example-api-consumer", config.ReconnectInterval, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := ensureStream(ctx, js, config.StreamName, config.SubjectPrefix, config.Durable); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	maxRetries := retryLimit(config.MaxRetries)
+	ackWait := config.AckWait
+	if ackWait <= 0 {
+		ackWait = DefaultNATSAckWait
+	}
+
+	consumer, err := js.CreateOrUpdateConsumer(ctx, config.StreamName, jetstream.ConsumerConfig{
+		Durable:        config.DurableName,
+		FilterSubjects: natsSubjects(config.SubjectPrefix),
+		AckPolicy:      jetstream.AckExplicitPolicy,
+		AckWait:        ackWait,
+		MaxDeliver:     maxRetries + 1,
+		MaxAckPending:  config.MaxAckPending,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to declare consumer: %w", err)
+	}
+
+	logger.Info("NATS consumer initialized",
+		zap.String("stream", config.StreamName),
+		zap.String("consumer", config.DurableName),
+		zap.Strings("subjects", natsSubjects(config.SubjectPrefix)),
+	)
+
+	processed := config.ProcessedEvents
+	if processed == nil {
+		processed = NewLRUProcessedEventStore(DefaultDedupCapacity)
+	}
+
+	return &NATSConsumer{
+		config:     config,
+		conn:       conn,
+		consumer:   consumer,
+		handler:    handler,
+		processed:  processed,
+		maxRetries: maxRetries,
+		logger:     logger,
+	}, nil
+}
+
+// Start starts consuming messages; the NATS client reconnects on its own if the connection drops
+func (c *NATSConsumer) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isRunning {
+		return errors.New("consumer is already running")
+	}
+
+	consumeCtx, err := c.consumer.Consume(func(msg jetstream.Msg) {
+		c.handleMessage(ctx, msg)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register consumer: %w", err)
+	}
+
+	c.consumeCtx = consumeCtx
+	c.isRunning = true
+
+	c.logger.Info("Consumer started successfully")
+	return nil
+}
+
+// Stop finishes processing buffered messages and closes the connection
+func (c *NATSConsumer) Stop() error {
+	c.mu.Lock()
+	if !c.isRunning {
+		c.mu.Unlock()
+		return nil
+	}
+	c.isRunning = false
+	consumeCtx := c.consumeCtx
+	c.mu.Unlock()
+
+	c.logger.Info("Stopping consumer...")
+
+	consumeCtx.Drain()
+	<-consumeCtx.Closed()
+	c.conn.Close()
+
+	c.logger.Info("Consumer stopped successfully")
+	return nil
+}
+
+// handleMessage handles incoming messages, recording how each was settled
+func (c *NATSConsumer) handleMessage(ctx context.Context, msg jetstream.Msg) {
+	start := time.Now()
+	outcome := c.processMessage(ctx, msg)
+	if c.config.Metrics != nil {
+		c.config.Metrics.ObserveMessage(outcome, time.Since(start))
+	}
+}
+
+// processMessage decodes, dispatches and settles a message, returning its metrics outcome
+func (c *NATSConsumer) processMessage(ctx context.Context, msg jetstream.Msg) string {
+	messageID := msg.Headers().Get(jetstream.MsgIDHeader)
+	logger := c.logger.With(
+		zap.String("message_id", messageID),
+		zap.String("subject", msg.Subject()),
+	)
+
+	logger.Debug("Processing message")
+
+	// Continue the publisher's trace
+	ctx, span := startProcessSpan(extractNATSTraceContext(ctx, msg.Headers()), messagingSystemNATS, msg.Subject())
+	defer span.End()
+
+	// Parse event; versions this consumer cannot decode are terminated like malformed messages
+	event, err := decodeExampleEvent(msg.Data())
+	if errors.Is(err, ErrUnsupportedSchemaVersion) {
+		logger.Warn("Unsupported event schema version", zap.Error(err))
+		tracing.RecordError(span, err)
+		c.terminateMessage(msg, logger)
+		return metrics.MessageDeadLettered
+	}
+	if err != nil {
+		logger.Error("Failed to unmarshal event", zap.Error(err))
+		tracing.RecordError(span, err)
+		c.terminateMessage(msg, logger)
+		return metrics.MessageDeadLettered
+	}
+
+	// Add message metadata to context
+	msgCtx := ctxkeys.WithMessageID(ctx, messageID)
+	msgCtx = ctxkeys.WithRoutingKey(msgCtx, msg.Subject())
+
+	// Redelivered events that were already handled are acknowledged without running the handler again
+	if alreadyProcessed(msgCtx, c.processed, event.ID, logger) {
+		logger.Info("Skipping already processed event", zap.String("event_id", event.ID))
+		c.ackMessage(msg, logger)
+		return metrics.MessageProcessed
+	}
+
+	err = dispatchEvent(msgCtx, c.handler, event)
+	if errors.Is(err, ErrUnknownEventType) {
+		logger.Warn("Unknown event type", zap.String("event_type", string(event.Type)))
+		c.ackMessage(msg, logger)
+		return metrics.MessageProcessed
+	}
+
+	if err != nil {
+		tracing.RecordError(span, err)
+		logger.Error("Failed to handle event",
+			zap.Error(err),
+			zap.String("event_type", string(event.Type)),
+			zap.String("event_id", event.ID),
+		)
+
+		if isRetryableError(err) {
+			return c.retryMessage(msg, logger)
+		}
+		c.terminateMessage(msg, logger)
+		return metrics.MessageDeadLettered
+	}
+
+	// Acknowledge successful processing
+	markProcessed(msgCtx, c.processed, event.ID, logger)
+	c.ackMessage(msg, logger)
+	logger.Info("Event processed successfully",
+		zap.String("event_type", string(event.Type)),
+		zap.String("event_id", event.ID),
+	)
+	return metrics.MessageProcessed
+}
+
+// ackMessage acknowledges a message
+func (c *NATSConsumer) ackMessage(msg jetstream.Msg, logger *zap.Logger) {
+	if err := msg.Ack(); err != nil {
+		logger.Error("Failed to ack message", zap.Error(err))
+	}
+}
+
+// retryMessage asks JetStream to redeliver a failed message after the retry backoff,
+// terminating it once the retry limit is reached, and returns the outcome
+func (c *NATSConsumer) retryMessage(msg jetstream.Msg, logger *zap.Logger) string {
+	attempt := 1
+	if meta, err := msg.Metadata(); err == nil {
+		attempt = int(meta.NumDelivered)
+	}
+	if attempt > c.maxRetries {
+		logger.Warn("Retry limit reached, terminating message", zap.Int("max_retries", c.maxRetries))
+		c.terminateMessage(msg, logger)
+		return metrics.MessageDeadLettered
+	}
+
+	if err := msg.NakWithDelay(retryDelay(c.config.RetryBackoff, attempt)); err != nil {
+		logger.Error("Failed to nak message", zap.Error(err))
+	}
+	return metrics.MessageRetried
+}
+
+// terminateMessage tells JetStream never to redeliver a message
+func (c *NATSConsumer) terminateMessage(msg jetstream.Msg, logger *zap.Logger) {
+	if err := msg.Term(); err != nil {
+		logger.Error("Failed to terminate message", zap.Error(err))
+	}
+}
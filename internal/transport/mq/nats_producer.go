@@ -0,0 +1,142 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"example-api-template/internal/usecase"
+	"example-api-template/pkg/tracing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+// NATSProducer implements ExampleProducer using NATS JetStream
+type NATSProducer struct {
+	config *NATSProducerConfig
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	logger *zap.Logger
+}
+
+// NATSProducerConfig holds configuration for NATS producer
+type NATSProducerConfig struct {
+	URL               string
+	StreamName        string
+	SubjectPrefix     string
+	Durable           bool          // Store the stream on disk rather than in memory
+	ReconnectInterval time.Duration // Delay between reconnect attempts (default: 5s)
+	RetryAttempts     int           // Total publish attempts per event, including the first (default: 3)
+	RetryBackoff      time.Duration // Delay before the first retry, doubled for each further retry (default: 200ms)
+}
+
+// NewNATSProducer creates a new NATS JetStream producer, declaring the stream if needed
+func NewNATSProducer(config *NATSProducerConfig, logger *zap.Logger) (*NATSProducer, error) {
+	conn, err := connectNATS(config.URL, "example-api-producer", config.ReconnectInterval, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := ensureStream(ctx, js, config.StreamName, config.SubjectPrefix, config.Durable); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	logger.Info("NATS producer initialized",
+		zap.String("stream", config.StreamName),
+		zap.String("subject_prefix", config.SubjectPrefix),
+	)
+
+	return &NATSProducer{
+		config: config,
+		conn:   conn,
+		js:     js,
+		logger: logger,
+	}, nil
+}
+
+// PublishExampleCreated publishes an example created event
+func (p *NATSProducer) PublishExampleCreated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
+	return p.publishEvent(ctx, newExampleEvent(ctx, EventTypeExampleCreated, example))
+}
+
+// PublishExampleUpdated publishes an example updated event
+func (p *NATSProducer) PublishExampleUpdated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
+	return p.publishEvent(ctx, newExampleEvent(ctx, EventTypeExampleUpdated, example))
+}
+
+// PublishExampleDeleted publishes an example deleted event
+func (p *NATSProducer) PublishExampleDeleted(ctx context.Context, exampleID, email, name string) error {
+	return p.publishEvent(ctx, newExampleEvent(ctx, EventTypeExampleDeleted, deletedExampleData(exampleID, email, name)))
+}
+
+// publishEvent publishes an event to the subject for its type
+func (p *NATSProducer) publishEvent(ctx context.Context, event *ExampleEvent) (err error) {
+	subject := natsSubject(p.config.SubjectPrefix, event.Type)
+	ctx, span := startPublishSpan(ctx, messagingSystemNATS, subject)
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		p.logger.Error("Failed to marshal event", zap.Error(err), zap.String("event_id", event.ID))
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	msg := &nats.Msg{
+		Subject: subject,
+		Header:  nats.Header{},
+		Data:    body,
+	}
+	msg.Header.Set("Content-Type", "application/json")
+	msg.Header.Set("Event-Type", string(event.Type))
+	msg.Header.Set("Source", "example-api")
+	msg.Header.Set("User-Id", extractUserID(ctx))
+	msg.Header.Set("Trace-Id", extractTraceID(ctx))
+	if requestID := extractRequestID(ctx); requestID != "" {
+		msg.Header.Set("Request-Id", requestID)
+	}
+	injectNATSTraceContext(ctx, msg.Header)
+
+	logger := p.logger.With(
+		zap.String("event_id", event.ID),
+		zap.String("event_type", string(event.Type)),
+		zap.String("subject", msg.Subject),
+	)
+
+	return publishWithRetry(ctx, p.config.RetryAttempts, p.config.RetryBackoff, logger, func() error {
+		publishCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		// The message ID lets JetStream drop duplicates when a retried publish had in fact been stored
+		_, err := p.js.PublishMsg(publishCtx, msg, jetstream.WithMsgID(event.ID))
+		return err
+	})
+}
+
+// IsConnected reports whether the NATS connection is currently up
+func (p *NATSProducer) IsConnected() bool {
+	return p.conn.IsConnected()
+}
+
+// Close closes the producer connection; publishes are synchronous so nothing is left in flight
+func (p *NATSProducer) Close() error {
+	p.conn.Close()
+
+	p.logger.Info("NATS producer closed successfully")
+	return nil
+}
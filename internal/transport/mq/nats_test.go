@@ -0,0 +1,146 @@
+package mq
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"example-api-template/internal/domain"
+	"example-api-template/internal/usecase"
+	"example-api-template/pkg/ctxkeys"
+	"example-api-template/pkg/metrics"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// runNATSServer starts an embedded JetStream-enabled NATS server for the test
+func runNATSServer(t *testing.T) *server.Server {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	require.NoError(t, err)
+
+	go srv.Start()
+	t.Cleanup(srv.Shutdown)
+	require.True(t, srv.ReadyForConnections(5*time.Second), "NATS server did not start")
+
+	return srv
+}
+
+// recordingEventHandler collects handled events and signals each one
+type recordingEventHandler struct {
+	mu       sync.Mutex
+	events   []ExampleEvent
+	subjects []string
+	handled  chan struct{}
+}
+
+func newRecordingEventHandler() *recordingEventHandler {
+	return &recordingEventHandler{handled: make(chan struct{}, 10)}
+}
+
+func (h *recordingEventHandler) record(ctx context.Context, event *ExampleEvent) error {
+	h.mu.Lock()
+	h.events = append(h.events, *event)
+	h.subjects = append(h.subjects, ctxkeys.RoutingKey(ctx))
+	h.mu.Unlock()
+	h.handled <- struct{}{}
+	return nil
+}
+
+func (h *recordingEventHandler) HandleExampleCreated(ctx context.Context, event *ExampleEvent) error {
+	return h.record(ctx, event)
+}
+
+func (h *recordingEventHandler) HandleExampleUpdated(ctx context.Context, event *ExampleEvent) error {
+	return h.record(ctx, event)
+}
+
+func (h *recordingEventHandler) HandleExampleDeleted(ctx context.Context, event *ExampleEvent) error {
+	return h.record(ctx, event)
+}
+
+func TestNATSSubjects(t *testing.T) {
+	assert.Equal(t, "example.example.created", natsSubject("example", EventTypeExampleCreated))
+	assert.Equal(t, []string{
+		"example.example.created",
+		"example.example.updated",
+		"example.example.deleted",
+	}, natsSubjects("example"))
+}
+
+func TestNATSRoundTrip(t *testing.T) {
+	srv := runNATSServer(t)
+	logger := zap.NewNop()
+
+	producer, err := NewNATSProducer(&NATSProducerConfig{
+		URL:           srv.ClientURL(),
+		StreamName:    "EXAMPLES",
+		SubjectPrefix: "example",
+	}, logger)
+	require.NoError(t, err)
+	defer producer.Close()
+
+	handler := newRecordingEventHandler()
+	consumerMetrics := metrics.NewConsumer("test")
+	consumer, err := NewNATSConsumer(&NATSConsumerConfig{
+		URL:           srv.ClientURL(),
+		StreamName:    "EXAMPLES",
+		SubjectPrefix: "example",
+		DurableName:   "example-events",
+		Metrics:       consumerMetrics,
+	}, handler, logger)
+	require.NoError(t, err)
+	require.NoError(t, consumer.Start(context.Background()))
+	defer consumer.Stop()
+
+	ctx := context.Background()
+	example := &usecase.ExampleWithMetadata{
+		Example: &domain.Example{ID: "ex-1", Name: "John Doe", Email: "john@example.com", Age: 30},
+	}
+	require.NoError(t, producer.PublishExampleCreated(ctx, example))
+	require.NoError(t, producer.PublishExampleUpdated(ctx, example))
+	require.NoError(t, producer.PublishExampleDeleted(ctx, "ex-1", "john@example.com", "John Doe"))
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-handler.handled:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %d", i+1)
+		}
+	}
+
+	// Outcomes are recorded once a message is acknowledged, just after the handler returns
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(consumerMetrics.MessagesProcessedTotal) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+
+	require.Len(t, handler.events, 3)
+	assert.Equal(t, EventTypeExampleCreated, handler.events[0].Type)
+	assert.Equal(t, EventTypeExampleUpdated, handler.events[1].Type)
+	assert.Equal(t, EventTypeExampleDeleted, handler.events[2].Type)
+	assert.Equal(t, natsSubjects("example"), handler.subjects)
+
+	for _, event := range handler.events {
+		require.NotNil(t, event.Data)
+		assert.Equal(t, "ex-1", event.Data.ID)
+		assert.Equal(t, "john@example.com", event.Data.Email)
+		assert.Equal(t, "example-api", event.Metadata["source"])
+	}
+	assert.Equal(t, 30, handler.events[0].Data.Age)
+}
@@ -0,0 +1,50 @@
+package mq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// CurrentSchemaVersion is the schema version of the events this service publishes
+const CurrentSchemaVersion = "1.0"
+
+// ErrUnsupportedSchemaVersion is returned when an event's schema version has no decoder
+var ErrUnsupportedSchemaVersion = errors.New("unsupported event schema version")
+
+// eventDecoder decodes the payload of one schema version into the current ExampleEvent
+type eventDecoder func(body []byte) (*ExampleEvent, error)
+
+// eventDecoders maps each schema version consumers still accept to its decoder.
+// Add an entry here, upgrading the old shape to ExampleEvent, whenever the payload changes.
+var eventDecoders = map[string]eventDecoder{
+	// Events published before versioning have the same shape as 1.0
+	"":    decodeEventV1,
+	"1.0": decodeEventV1,
+}
+
+// decodeExampleEvent decodes body with the decoder for its schema version
+func decodeExampleEvent(body []byte) (*ExampleEvent, error) {
+	var envelope struct {
+		SchemaVersion string `json:"schema_version"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+
+	decode, ok := eventDecoders[envelope.SchemaVersion]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedSchemaVersion, envelope.SchemaVersion)
+	}
+	return decode(body)
+}
+
+// decodeEventV1 decodes a 1.0 payload
+func decodeEventV1(body []byte) (*ExampleEvent, error) {
+	var event ExampleEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	event.SchemaVersion = CurrentSchemaVersion
+	return &event, nil
+}
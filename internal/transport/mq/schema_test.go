@@ -0,0 +1,93 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDecodeExampleEvent(t *testing.T) {
+	t.Run("current version round-trips", func(t *testing.T) {
+		original := newExampleEvent(context.Background(), EventTypeExampleCreated, createTestExampleWithMetadata())
+		assert.Equal(t, CurrentSchemaVersion, original.SchemaVersion)
+
+		body, err := json.Marshal(original)
+		require.NoError(t, err)
+
+		event, err := decodeExampleEvent(body)
+		require.NoError(t, err)
+		assert.Equal(t, original.ID, event.ID)
+		assert.Equal(t, CurrentSchemaVersion, event.SchemaVersion)
+		assert.Equal(t, original.Data.Email, event.Data.Email)
+	})
+
+	t.Run("v1 payload", func(t *testing.T) {
+		body := []byte(`{
+			"id": "evt_v1",
+			"schema_version": "1.0",
+			"type": "example.updated",
+			"timestamp": "2024-01-02T03:04:05Z",
+			"data": {"id": "example-1", "name": "John Doe", "email": "john@example.com", "age": 30},
+			"metadata": {"source": "example-api"}
+		}`)
+
+		event, err := decodeExampleEvent(body)
+		require.NoError(t, err)
+		assert.Equal(t, "evt_v1", event.ID)
+		assert.Equal(t, EventTypeExampleUpdated, event.Type)
+		assert.Equal(t, "example-1", event.Data.ID)
+		assert.Equal(t, "john@example.com", event.Data.Email)
+		assert.Equal(t, 30, event.Data.Age)
+	})
+
+	t.Run("unversioned payload decodes as v1", func(t *testing.T) {
+		body := []byte(`{"id": "evt_legacy", "type": "example.deleted", "data": {"id": "example-1"}}`)
+
+		event, err := decodeExampleEvent(body)
+		require.NoError(t, err)
+		assert.Equal(t, "evt_legacy", event.ID)
+		assert.Equal(t, CurrentSchemaVersion, event.SchemaVersion)
+		assert.Equal(t, "example-1", event.Data.ID)
+	})
+
+	t.Run("unknown version is unsupported", func(t *testing.T) {
+		_, err := decodeExampleEvent([]byte(`{"id": "evt_future", "schema_version": "2.0", "type": "example.created"}`))
+		assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+		assert.Contains(t, err.Error(), `"2.0"`)
+	})
+
+	t.Run("malformed payload", func(t *testing.T) {
+		_, err := decodeExampleEvent([]byte("{"))
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrUnsupportedSchemaVersion)
+	})
+}
+
+func TestRabbitMQConsumerUnsupportedSchemaVersion(t *testing.T) {
+	mockHandler := &MockEventHandler{}
+
+	dialer := &fakeAMQPDialer{}
+	consumer, err := newRabbitMQConsumer(&RabbitMQConsumerConfig{
+		ExchangeName: "examples",
+		QueueName:    "example-events",
+	}, mockHandler, zap.NewNop(), dialer.dial)
+	require.NoError(t, err)
+
+	event := createTestEvent(EventTypeExampleCreated)
+	event.SchemaVersion = "2.0"
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	ack := &fakeAcknowledger{}
+	consumer.handleMessage(context.Background(), amqp.Delivery{Acknowledger: ack, Body: body})
+
+	mockHandler.AssertNotCalled(t, "HandleExampleCreated", mock.Anything, mock.Anything)
+	assert.True(t, ack.rejected)
+	assert.False(t, ack.requeued)
+}
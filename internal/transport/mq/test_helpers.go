@@ -1,11 +1,16 @@
 package mq
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"time"
 
 	"example-api-template/internal/domain"
 	"example-api-template/internal/repository"
 	"example-api-template/internal/usecase"
+
+	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 // Test fixtures shared between consumer and producer tests
@@ -35,10 +40,11 @@ func createTestExampleWithMetadata() *usecase.ExampleWithMetadata {
 // createTestEvent creates a test event of the specified type
 func createTestEvent(eventType EventType) *ExampleEvent {
 	return &ExampleEvent{
-		ID:        "evt_test_123",
-		Type:      eventType,
-		Timestamp: time.Now(),
-		Data:      createTestExampleWithMetadata(),
+		ID:            "evt_test_123",
+		SchemaVersion: CurrentSchemaVersion,
+		Type:          eventType,
+		Timestamp:     time.Now(),
+		Data:          createTestExampleWithMetadata(),
 		Metadata: map[string]interface{}{
 			"source":   "test",
 			"version":  "1.0",
@@ -46,3 +52,243 @@ func createTestEvent(eventType EventType) *ExampleEvent {
 		},
 	}
 }
+
+// fakeAMQPChannel is an in-memory amqpChannel that records declarations and publishes
+type fakeAMQPChannel struct {
+	mu               sync.Mutex
+	exchangeDeclares int
+	queueDeclares    int
+	queueBinds       int
+	consumers        int
+	consumerTags     []string
+	cancelled        []string // consumer tags passed to Cancel
+	exchanges        []string
+	queueArgs        map[string]amqp.Table
+	bindings         []string // "queue <- key @ exchange"
+	deliveries       chan amqp.Delivery
+	published        []amqp.Publishing
+	publishedKeys    []string
+	publishErrs      []error // returned in order by PublishWithContext before succeeding
+	closed           bool
+}
+
+func newFakeAMQPChannel() *fakeAMQPChannel {
+	return &fakeAMQPChannel{
+		queueArgs:  make(map[string]amqp.Table),
+		deliveries: make(chan amqp.Delivery, 10),
+	}
+}
+
+func (f *fakeAMQPChannel) Qos(prefetchCount, prefetchSize int, global bool) error {
+	return nil
+}
+
+func (f *fakeAMQPChannel) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.exchangeDeclares++
+	f.exchanges = append(f.exchanges, name)
+	return nil
+}
+
+func (f *fakeAMQPChannel) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queueDeclares++
+	f.queueArgs[name] = args
+	return amqp.Queue{Name: name}, nil
+}
+
+func (f *fakeAMQPChannel) QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queueBinds++
+	f.bindings = append(f.bindings, name+" <- "+key+" @ "+exchange)
+	return nil
+}
+
+func (f *fakeAMQPChannel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.consumers++
+	f.consumerTags = append(f.consumerTags, consumer)
+	return f.deliveries, nil
+}
+
+func (f *fakeAMQPChannel) Cancel(consumer string, noWait bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return amqp.ErrClosed
+	}
+	f.cancelled = append(f.cancelled, consumer)
+	return nil
+}
+
+func (f *fakeAMQPChannel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return amqp.ErrClosed
+	}
+	if len(f.publishErrs) > 0 {
+		err := f.publishErrs[0]
+		f.publishErrs = f.publishErrs[1:]
+		return err
+	}
+	f.published = append(f.published, msg)
+	f.publishedKeys = append(f.publishedKeys, exchange+"/"+key)
+	return nil
+}
+
+func (f *fakeAMQPChannel) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	return receiver
+}
+
+// Close closes the delivery channel, as the broker does when a channel dies
+func (f *fakeAMQPChannel) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return amqp.ErrClosed
+	}
+	f.closed = true
+	close(f.deliveries)
+	return nil
+}
+
+// isClosed reports whether the channel has been closed
+func (f *fakeAMQPChannel) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+// stats returns the recorded declaration and consumer counts
+func (f *fakeAMQPChannel) stats() (exchangeDeclares, queueDeclares, queueBinds, consumers int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.exchangeDeclares, f.queueDeclares, f.queueBinds, f.consumers
+}
+
+// fakeAMQPConnection is an in-memory amqpConnection handing out fake channels
+type fakeAMQPConnection struct {
+	mu          sync.Mutex
+	channels    []*fakeAMQPChannel
+	closeNotify []chan *amqp.Error
+	closed      bool
+}
+
+func (f *fakeAMQPConnection) Channel() (amqpChannel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil, amqp.ErrClosed
+	}
+	ch := newFakeAMQPChannel()
+	f.channels = append(f.channels, ch)
+	return ch, nil
+}
+
+func (f *fakeAMQPConnection) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closeNotify = append(f.closeNotify, receiver)
+	return receiver
+}
+
+func (f *fakeAMQPConnection) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return amqp.ErrClosed
+	}
+	f.closed = true
+	for _, receiver := range f.closeNotify {
+		close(receiver)
+	}
+	f.closeNotify = nil
+	return nil
+}
+
+// simulateLoss drops the connection the way the broker does, notifying listeners with err
+func (f *fakeAMQPConnection) simulateLoss(err *amqp.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	for _, receiver := range f.closeNotify {
+		receiver <- err
+		close(receiver)
+	}
+	f.closeNotify = nil
+	for _, ch := range f.channels {
+		ch.Close()
+	}
+}
+
+// channel returns the i-th channel opened on the connection
+func (f *fakeAMQPConnection) channel(i int) *fakeAMQPChannel {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.channels[i]
+}
+
+// fakeAMQPDialer hands out fake connections and records every dial
+type fakeAMQPDialer struct {
+	mu          sync.Mutex
+	connections []*fakeAMQPConnection
+	failures    int // number of dials to fail before succeeding
+}
+
+func (d *fakeAMQPDialer) dial(url string) (amqpConnection, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.failures > 0 {
+		d.failures--
+		return nil, errors.New("dial tcp: connection refused")
+	}
+	conn := &fakeAMQPConnection{}
+	d.connections = append(d.connections, conn)
+	return conn, nil
+}
+
+// connection returns the i-th successful connection
+func (d *fakeAMQPDialer) connection(i int) *fakeAMQPConnection {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.connections[i]
+}
+
+// dials returns the number of successful connections
+func (d *fakeAMQPDialer) dials() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.connections)
+}
+
+// fakeAcknowledger records how a delivery was settled
+type fakeAcknowledger struct {
+	mu       sync.Mutex
+	acked    bool
+	rejected bool
+	requeued bool
+}
+
+func (f *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.acked = true
+	return nil
+}
+
+func (f *fakeAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	return f.Reject(tag, requeue)
+}
+
+func (f *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rejected = true
+	f.requeued = requeue
+	return nil
+}
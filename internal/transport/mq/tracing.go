@@ -0,0 +1,74 @@
+package mq
+
+import (
+	"context"
+
+	"example-api-template/pkg/tracing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("example-api-template/internal/transport/mq")
+
+// Messaging systems recorded on publish and process spans
+var (
+	messagingSystemRabbitMQ = semconv.MessagingSystemRabbitmq
+	messagingSystemNATS     = semconv.MessagingSystemKey.String("nats")
+)
+
+// amqpHeaderCarrier adapts AMQP message headers to an OpenTelemetry TextMapCarrier
+type amqpHeaderCarrier amqp.Table
+
+// Get returns the header value for key
+func (c amqpHeaderCarrier) Get(key string) string {
+	if value, ok := c[key].(string); ok {
+		return value
+	}
+	return ""
+}
+
+// Set stores the header value for key
+func (c amqpHeaderCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys lists the header keys
+func (c amqpHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// injectTraceContext writes the active trace context from ctx into the message headers
+func injectTraceContext(ctx context.Context, headers amqp.Table) {
+	otel.GetTextMapPropagator().Inject(ctx, amqpHeaderCarrier(headers))
+}
+
+// extractTraceContext returns ctx carrying the trace context found in the message headers,
+// so spans started from it continue the publisher's trace
+func extractTraceContext(ctx context.Context, headers amqp.Table) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, amqpHeaderCarrier(headers))
+}
+
+// startPublishSpan starts a producer span for publishing to destination. Its context is
+// the one to inject into the message, so consumer spans become its children.
+func startPublishSpan(ctx context.Context, system attribute.KeyValue, destination string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, destination+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(system, semconv.MessagingDestinationName(destination)),
+	)
+}
+
+// startProcessSpan starts a consumer span for processing a message from destination
+func startProcessSpan(ctx context.Context, system attribute.KeyValue, destination string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, destination+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(system, semconv.MessagingDestinationName(destination)),
+	)
+}
@@ -0,0 +1,174 @@
+package mq
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"example-api-template/internal/usecase"
+	"example-api-template/pkg/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.uber.org/zap"
+)
+
+// Webhook request headers
+const (
+	HeaderWebhookSignature = "X-Webhook-Signature" // "sha256=" followed by the hex HMAC, see WebhookSignature
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp" // Unix seconds the request was signed at
+	HeaderWebhookEventID   = "X-Webhook-Event-Id"  // Same for every attempt, so receivers can drop duplicates
+	HeaderWebhookEventType = "X-Webhook-Event-Type"
+)
+
+// DefaultWebhookTimeout is used when the config leaves Timeout unset
+const DefaultWebhookTimeout = 10 * time.Second
+
+// messagingSystemWebhook is recorded on webhook publish spans
+var messagingSystemWebhook = semconv.MessagingSystemKey.String("webhook")
+
+// WebhookProducer implements ExampleProducer by POSTing each event as JSON to a URL
+type WebhookProducer struct {
+	config *WebhookProducerConfig
+	client *http.Client
+	now    func() time.Time
+	logger *zap.Logger
+}
+
+// WebhookProducerConfig holds configuration for the webhook producer
+type WebhookProducerConfig struct {
+	URL           string
+	Secret        string        // Key of the HMAC-SHA256 signature sent with every request
+	Timeout       time.Duration // Time allowed for each attempt (default: 10s)
+	RetryAttempts int           // Total delivery attempts per event, including the first (default: 3)
+	RetryBackoff  time.Duration // Delay before the first retry, doubled for each further retry (default: 200ms)
+}
+
+// NewWebhookProducer creates a producer delivering events to config.URL
+func NewWebhookProducer(config *WebhookProducerConfig, logger *zap.Logger) (*WebhookProducer, error) {
+	parsed, err := url.Parse(config.URL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("invalid webhook URL %q", config.URL)
+	}
+	if config.Secret == "" {
+		return nil, errors.New("webhook secret is required")
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+
+	logger.Info("Webhook producer initialized", zap.String("url", parsed.Redacted()))
+
+	return &WebhookProducer{
+		config: config,
+		client: &http.Client{Timeout: timeout},
+		now:    time.Now,
+		logger: logger,
+	}, nil
+}
+
+// PublishExampleCreated delivers an example created event
+func (p *WebhookProducer) PublishExampleCreated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
+	return p.publishEvent(ctx, newExampleEvent(ctx, EventTypeExampleCreated, example))
+}
+
+// PublishExampleUpdated delivers an example updated event
+func (p *WebhookProducer) PublishExampleUpdated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
+	return p.publishEvent(ctx, newExampleEvent(ctx, EventTypeExampleUpdated, example))
+}
+
+// PublishExampleDeleted delivers an example deleted event
+func (p *WebhookProducer) PublishExampleDeleted(ctx context.Context, exampleID, email, name string) error {
+	return p.publishEvent(ctx, newExampleEvent(ctx, EventTypeExampleDeleted, deletedExampleData(exampleID, email, name)))
+}
+
+// publishEvent POSTs an event, retrying network errors, 429 and 5xx responses
+func (p *WebhookProducer) publishEvent(ctx context.Context, event *ExampleEvent) (err error) {
+	ctx, span := startPublishSpan(ctx, messagingSystemWebhook, string(event.Type))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		p.logger.Error("Failed to marshal event", zap.Error(err), zap.String("event_id", event.ID))
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	logger := p.logger.With(
+		zap.String("event_id", event.ID),
+		zap.String("event_type", string(event.Type)),
+	)
+
+	return publishWithRetry(ctx, p.config.RetryAttempts, p.config.RetryBackoff, logger, func() error {
+		return p.deliver(ctx, event, body)
+	})
+}
+
+// deliver makes a single delivery attempt, signing the body with the current time
+func (p *WebhookProducer) deliver(ctx context.Context, event *ExampleEvent, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return permanent(fmt.Errorf("failed to create webhook request: %w", err))
+	}
+
+	timestamp := p.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "example-api-webhook/1.0")
+	req.Header.Set(HeaderWebhookEventID, event.ID)
+	req.Header.Set(HeaderWebhookEventType, string(event.Type))
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderWebhookSignature, WebhookSignature(p.config.Secret, timestamp, body))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 500, resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	default:
+		// The receiver rejected the event; sending it again won't change that
+		return permanent(fmt.Errorf("webhook responded with status %d", resp.StatusCode))
+	}
+}
+
+// Close releases idle connections
+func (p *WebhookProducer) Close() error {
+	p.client.CloseIdleConnections()
+
+	p.logger.Info("Webhook producer closed successfully")
+	return nil
+}
+
+// WebhookSignature returns the signature header value for body sent at timestamp: the
+// hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret. Receivers recompute it and
+// compare in constant time, rejecting old timestamps to stop replays.
+func WebhookSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,164 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"example-api-template/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// webhookRequest is one request received by a test webhook server
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newWebhookServer starts a server answering with statuses in turn, then 200, and
+// recording every request
+func newWebhookServer(t *testing.T, statuses ...int) (*httptest.Server, func() []webhookRequest) {
+	var mu sync.Mutex
+	var requests []webhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, webhookRequest{header: r.Header.Clone(), body: body})
+		status := http.StatusOK
+		if len(requests) <= len(statuses) {
+			status = statuses[len(requests)-1]
+		}
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []webhookRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]webhookRequest(nil), requests...)
+	}
+}
+
+func newTestWebhookProducer(t *testing.T, url string, attempts int) *WebhookProducer {
+	producer, err := NewWebhookProducer(&WebhookProducerConfig{
+		URL:           url,
+		Secret:        "test-secret",
+		RetryAttempts: attempts,
+		RetryBackoff:  time.Millisecond,
+	}, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { producer.Close() })
+	return producer
+}
+
+func TestWebhookProducer(t *testing.T) {
+	t.Run("event is posted with a valid signature", func(t *testing.T) {
+		server, requests := newWebhookServer(t)
+		producer := newTestWebhookProducer(t, server.URL, 3)
+
+		require.NoError(t, producer.PublishExampleCreated(context.Background(), createTestExampleWithMetadata()))
+
+		received := requests()
+		require.Len(t, received, 1)
+		req := received[0]
+		assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+		assert.Equal(t, string(EventTypeExampleCreated), req.header.Get(HeaderWebhookEventType))
+
+		timestamp, err := strconv.ParseInt(req.header.Get(HeaderWebhookTimestamp), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, WebhookSignature("test-secret", timestamp, req.body), req.header.Get(HeaderWebhookSignature))
+		assert.NotEqual(t, WebhookSignature("other-secret", timestamp, req.body), req.header.Get(HeaderWebhookSignature))
+
+		var event ExampleEvent
+		require.NoError(t, json.Unmarshal(req.body, &event))
+		assert.Equal(t, EventTypeExampleCreated, event.Type)
+		assert.Equal(t, event.ID, req.header.Get(HeaderWebhookEventID))
+		assert.Equal(t, "test-id", event.Data.ID)
+	})
+
+	t.Run("5xx is retried with the same event", func(t *testing.T) {
+		server, requests := newWebhookServer(t, http.StatusServiceUnavailable, http.StatusInternalServerError)
+		producer := newTestWebhookProducer(t, server.URL, 3)
+
+		require.NoError(t, producer.PublishExampleDeleted(context.Background(), "test-id", "john@example.com", "John Doe"))
+
+		received := requests()
+		require.Len(t, received, 3)
+		assert.Equal(t, received[0].body, received[2].body)
+		assert.Equal(t, received[0].header.Get(HeaderWebhookEventID), received[2].header.Get(HeaderWebhookEventID))
+	})
+
+	t.Run("exhausted retries return a wrapped error", func(t *testing.T) {
+		server, requests := newWebhookServer(t, http.StatusBadGateway, http.StatusBadGateway)
+		producer := newTestWebhookProducer(t, server.URL, 2)
+
+		err := producer.PublishExampleUpdated(context.Background(), createTestExampleWithMetadata())
+		assert.ErrorIs(t, err, ErrPublishFailed)
+		assert.Contains(t, err.Error(), "status 502")
+		assert.Len(t, requests(), 2)
+	})
+
+	t.Run("4xx is not retried", func(t *testing.T) {
+		server, requests := newWebhookServer(t, http.StatusBadRequest)
+		producer := newTestWebhookProducer(t, server.URL, 3)
+
+		err := producer.PublishExampleCreated(context.Background(), createTestExampleWithMetadata())
+		assert.ErrorIs(t, err, ErrPublishFailed)
+		assert.Len(t, requests(), 1)
+	})
+
+	t.Run("invalid config is rejected", func(t *testing.T) {
+		_, err := NewWebhookProducer(&WebhookProducerConfig{URL: "ftp://example.com", Secret: "s"}, zap.NewNop())
+		assert.Error(t, err)
+
+		_, err = NewWebhookProducer(&WebhookProducerConfig{URL: "https://example.com"}, zap.NewNop())
+		assert.Error(t, err)
+	})
+}
+
+// failingProducer is an ExampleProducer whose publishes fail
+type failingProducer struct {
+	*MockProducer
+	err    error
+	closed atomic.Bool
+}
+
+func (f *failingProducer) PublishExampleCreated(ctx context.Context, example *usecase.ExampleWithMetadata) error {
+	return f.err
+}
+
+func (f *failingProducer) Close() error {
+	f.closed.Store(true)
+	return nil
+}
+
+func TestFanOutProducer(t *testing.T) {
+	server, requests := newWebhookServer(t)
+	mock := NewMockProducer(zap.NewNop())
+	failing := &failingProducer{MockProducer: NewMockProducer(zap.NewNop()), err: errors.New("broker down")}
+	fanOut := NewFanOutProducer(failing, mock, newTestWebhookProducer(t, server.URL, 1))
+
+	err := fanOut.PublishExampleCreated(context.Background(), createTestExampleWithMetadata())
+	assert.ErrorIs(t, err, failing.err)
+	assert.Len(t, mock.GetEvents(), 1, "a failing producer doesn't stop the others")
+	assert.Len(t, requests(), 1)
+
+	require.NoError(t, fanOut.PublishExampleDeleted(context.Background(), "test-id", "john@example.com", "John Doe"))
+	assert.Len(t, mock.GetEvents(), 2)
+	assert.Len(t, requests(), 2)
+
+	require.NoError(t, fanOut.Close())
+	assert.True(t, failing.closed.Load())
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+)
+
+// BackgroundTasks tracks fire-and-forget work, such as external API notifications,
+// so shutdown can wait for it instead of killing it mid-flight
+type BackgroundTasks struct {
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewBackgroundTasks creates an empty task tracker
+func NewBackgroundTasks() *BackgroundTasks {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BackgroundTasks{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Go runs fn in a new goroutine. fn's context is cancelled if Shutdown gives up waiting for it.
+func (t *BackgroundTasks) Go(fn func(ctx context.Context)) {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		fn(t.ctx)
+	}()
+}
+
+// Shutdown waits for running tasks to finish. Once ctx is done, it cancels the tasks
+// still running, waits for them to return and reports ctx's error.
+func (t *BackgroundTasks) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		t.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
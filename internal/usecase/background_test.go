@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"example-api-template/tests/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBackgroundTasksShutdownWaits(t *testing.T) {
+	tasks := NewBackgroundTasks()
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	tasks.Go(func(ctx context.Context) {
+		<-release
+		close(finished)
+	})
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- tasks.Shutdown(context.Background()) }()
+
+	select {
+	case <-shutdownErr:
+		t.Fatal("Shutdown returned before the task finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-shutdownErr)
+	<-finished
+}
+
+func TestBackgroundTasksShutdownCancelsAtDeadline(t *testing.T) {
+	tasks := NewBackgroundTasks()
+	var taskErr error
+	tasks.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		taskErr = ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := tasks.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, taskErr, context.Canceled) // Shutdown only returns once the task has
+}
+
+func TestExampleUseCase_ShutdownDrainsNotifications(t *testing.T) {
+	tests := []struct {
+		name          string
+		deadline      time.Duration
+		wantErr       error
+		wantNotifyErr error
+	}{
+		{name: "notification completes", deadline: time.Second},
+		{name: "notification cancelled at the deadline", deadline: 20 * time.Millisecond, wantErr: context.DeadlineExceeded, wantNotifyErr: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockExampleService{}
+			mockService.On("CreateExample", mock.Anything, "John Doe", "john.doe@example.com", "", 30).
+				Return(validExample(), nil)
+
+			// The notification finishes 50ms after it starts unless it is cancelled first
+			started := make(chan struct{})
+			var notifyErr error
+			mockExternalAPI := &mocks.MockExternalExampleAPI{}
+			mockExternalAPI.On("NotifyExampleCreated", mock.Anything, mock.AnythingOfType("string"), "john.doe@example.com").
+				Return(nil).
+				Run(func(args mock.Arguments) {
+					close(started)
+					select {
+					case <-time.After(50 * time.Millisecond):
+					case <-args.Get(0).(context.Context).Done():
+						notifyErr = args.Get(0).(context.Context).Err()
+					}
+				})
+
+			tasks := NewBackgroundTasks()
+			useCase := NewExampleUseCase(mockService, mockExternalAPI, zap.NewNop(), WithBackgroundTasks(tasks))
+
+			_, err := useCase.CreateExample(getTestContext(), validCreateExampleRequest())
+			require.NoError(t, err)
+			<-started
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.deadline)
+			defer cancel()
+			err = tasks.Shutdown(ctx)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorIs(t, notifyErr, tt.wantNotifyErr)
+			mockExternalAPI.AssertExpectations(t)
+		})
+	}
+}
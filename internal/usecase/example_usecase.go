@@ -7,43 +7,120 @@ import (
 	"sync"
 	"time"
 
+	"example-api-template/internal/audit"
 	"example-api-template/internal/domain"
+	"example-api-template/internal/errs"
 	"example-api-template/internal/repository"
 	"example-api-template/internal/service"
+	"example-api-template/pkg/logger"
+	"example-api-template/pkg/tracing"
 
+	"github.com/sony/gobreaker"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+var tracer = tracing.Tracer("example-api-template/internal/usecase")
+
+// defaultEnrichmentConcurrency bounds how many examples a list enriches at once
+const defaultEnrichmentConcurrency = 8
+
 var (
-	ErrUseCaseValidation = errors.New("use case validation failed")
-	ErrExternalService   = errors.New("external service error")
+	ErrUseCaseValidation    = errors.New("use case validation failed")
+	ErrExternalService      = errors.New("external service error")
+	ErrTransactionsDisabled = errors.New("transactions are not configured")
+	ErrOutboxUnavailable    = errors.New("transaction repository does not store outbox events")
 )
 
 // CreateExampleRequest represents the input for creating an example
 type CreateExampleRequest struct {
 	Name  string
 	Email string
+	Phone string // Optional, E.164
 	Age   int
 }
 
 // UpdateExampleRequest represents the input for updating an example
 type UpdateExampleRequest struct {
-	Name  string
-	Email string
-	Age   int
+	Name            string
+	Email           string
+	Phone           string // Optional, E.164; empty removes it
+	Age             int
+	ExpectedVersion int // Version the client last read; 0 skips the concurrency check
+}
+
+// PatchExampleRequest represents a partial update; nil fields are left unchanged
+type PatchExampleRequest struct {
+	Name  *string
+	Email *string
+	Phone *string // Empty removes the phone number
+	Age   *int
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (r PatchExampleRequest) IsEmpty() bool {
+	return r.Name == nil && r.Email == nil && r.Phone == nil && r.Age == nil
 }
 
 // ExampleWithMetadata represents an example with additional metadata
 type ExampleWithMetadata struct {
 	*domain.Example
-	ExternalData *repository.ExternalExampleData
-	Enrichment   map[string]interface{}
+	ExternalData     *repository.ExternalExampleData
+	Enrichment       map[string]interface{}
+	EnrichmentStatus EnrichmentStatus // Empty when enrichment wasn't attempted
+}
+
+// EnrichmentStatus reports which of the requested external calls succeeded, so clients can
+// tell missing external data apart from a failed call
+type EnrichmentStatus string
+
+// Enrichment statuses
+const (
+	EnrichmentComplete EnrichmentStatus = "complete" // Every requested call succeeded
+	EnrichmentPartial  EnrichmentStatus = "partial"  // Some requested calls failed
+	EnrichmentSkipped  EnrichmentStatus = "skipped"  // Nothing was requested, the circuit breaker was open or time ran out
+	EnrichmentFailed   EnrichmentStatus = "failed"   // Every requested call failed
+)
+
+// enrichmentStatus derives the status from how many of the requested calls failed
+func enrichmentStatus(requested, failed int) EnrichmentStatus {
+	switch {
+	case requested == 0:
+		return EnrichmentSkipped
+	case failed == 0:
+		return EnrichmentComplete
+	case failed < requested:
+		return EnrichmentPartial
+	default:
+		return EnrichmentFailed
+	}
 }
 
-// ListExamplesRequest represents pagination parameters
+// Include selects the external data an example is enriched with
+type Include uint8
+
+// Parts of the external data that can be included
+const (
+	IncludeExternal   Include = 1 << iota // ExternalData from GetExampleData
+	IncludeEnrichment                     // Enrichment from EnrichExample
+
+	IncludeNone Include = 0
+	IncludeAll          = IncludeExternal | IncludeEnrichment
+)
+
+// Has reports whether i includes part
+func (i Include) Has(part Include) bool {
+	return i&part != 0
+}
+
+// ListExamplesRequest represents pagination, sort and filter parameters
 type ListExamplesRequest struct {
-	Limit  int
-	Offset int
+	Limit        int
+	Offset       int
+	Sort         domain.ExampleSort // Zero value lists the newest first
+	Include      Include            // Zero value lists bare examples without calling the external API
+	CreatedSince time.Time          // Only examples created at or after this time; zero lists all
+	UpdatedSince time.Time          // Only examples updated at or after this time; zero lists all
 }
 
 // ListExamplesResponse represents the paginated response
@@ -54,42 +131,200 @@ type ListExamplesResponse struct {
 	Offset   int
 }
 
+// GetExamplesByIDsResponse represents the examples found for a batch of IDs
+type GetExamplesByIDsResponse struct {
+	Examples []*ExampleWithMetadata
+	NotFound []string // Requested IDs with no example
+}
+
+// DeleteExamplesByIDsResponse represents the outcome of deleting a batch of IDs
+type DeleteExamplesByIDsResponse struct {
+	Deleted  []string // IDs of the deleted examples, in the order requested
+	NotFound []string // Requested IDs with no example
+}
+
 // ExampleUseCase defines the interface for example use cases
 type ExampleUseCase interface {
 	CreateExample(ctx context.Context, req CreateExampleRequest) (*ExampleWithMetadata, error)
 	GetExample(ctx context.Context, id string) (*ExampleWithMetadata, error)
+	GetExamplesByIDs(ctx context.Context, ids []string) (*GetExamplesByIDsResponse, error)
 	GetExampleByEmail(ctx context.Context, email string) (*ExampleWithMetadata, error)
 	UpdateExample(ctx context.Context, id string, req UpdateExampleRequest) (*ExampleWithMetadata, error)
+	PatchExample(ctx context.Context, id string, req PatchExampleRequest) (*ExampleWithMetadata, error)
 	DeleteExample(ctx context.Context, id string) error
+	DeleteExamplesByIDs(ctx context.Context, ids []string) (*DeleteExamplesByIDsResponse, error)
 	ListExamples(ctx context.Context, req ListExamplesRequest) (*ListExamplesResponse, error)
+	ExportExamples(ctx context.Context, fn func(*domain.Example) error) error
 	ValidateAndCreateExample(ctx context.Context, req CreateExampleRequest) (*ExampleWithMetadata, error)
+	CreateExampleTransactional(ctx context.Context, req CreateExampleRequest) (*ExampleWithMetadata, error)
+}
+
+// EventPublisher publishes example lifecycle events (implemented by mq.ExampleProducer)
+type EventPublisher interface {
+	PublishExampleCreated(ctx context.Context, example *ExampleWithMetadata) error
+	PublishExampleUpdated(ctx context.Context, example *ExampleWithMetadata) error
+	PublishExampleDeleted(ctx context.Context, exampleID, email, name string) error
+}
+
+// Auditor records who changed which example (implemented by audit.Auditor)
+type Auditor interface {
+	Audit(ctx context.Context, action, resourceID string, before, after interface{}) error
+	AuditChanges(ctx context.Context, action, resourceID string, before, after interface{}, fields []string) error
 }
 
+// Option configures optional use case dependencies
+type Option func(*exampleUseCase)
+
+// WithEventPublisher publishes an event after every successful create, update and delete
+func WithEventPublisher(publisher EventPublisher) Option {
+	return func(uc *exampleUseCase) {
+		uc.publisher = publisher
+	}
+}
+
+// WithAuditor audits every successful create, update and delete. Updates and deletes load
+// the example first, so the audit entry can record what changed.
+func WithAuditor(auditor Auditor) Option {
+	return func(uc *exampleUseCase) {
+		uc.auditor = auditor
+	}
+}
+
+// WithCircuitBreaker stops calling the external API for openDuration once
+// failureThreshold consecutive calls have failed. While the breaker is open,
+// enrichment is skipped and external validation fails fast.
+func WithCircuitBreaker(failureThreshold int, openDuration time.Duration) Option {
+	return func(uc *exampleUseCase) {
+		uc.breaker = gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:        "external_api",
+			MaxRequests: 1, // A single trial call decides whether a half-open breaker closes
+			Timeout:     openDuration,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures >= uint32(failureThreshold)
+			},
+			OnStateChange: func(name string, from, to gobreaker.State) {
+				uc.logger.Warn("External API circuit breaker changed state",
+					zap.String("breaker", name),
+					zap.String("from", from.String()),
+					zap.String("to", to.String()))
+			},
+		})
+	}
+}
+
+// WithBackgroundTasks runs notifications that outlive their request on tasks, so the
+// caller can wait for them with tasks.Shutdown before exiting
+func WithBackgroundTasks(tasks *BackgroundTasks) Option {
+	return func(uc *exampleUseCase) {
+		uc.background = tasks
+	}
+}
+
+// WithTransactor enables CreateExampleTransactional, which creates examples through a service
+// built by newService on the transaction's repository
+func WithTransactor(transactor repository.Transactor, newService func(repository.ExampleRepository) service.ExampleService) Option {
+	return func(uc *exampleUseCase) {
+		uc.transactor = transactor
+		uc.newTxService = newService
+	}
+}
+
+// WithEnrichmentConcurrency sets how many examples ListExamples enriches in parallel;
+// values below 1 keep the default
+func WithEnrichmentConcurrency(n int) Option {
+	return func(uc *exampleUseCase) {
+		if n > 0 {
+			uc.enrichConcurrency = n
+		}
+	}
+}
+
+// WithEnrichmentDisabled never enriches examples with external data, for deployments
+// without the external API; examples are returned bare with EnrichmentSkipped. External
+// validation by ValidateAndCreateExample is unaffected.
+func WithEnrichmentDisabled() Option {
+	return func(uc *exampleUseCase) {
+		uc.enrichDisabled = true
+	}
+}
+
+// UseCaseConfig holds use case settings taken from configuration
+type UseCaseConfig struct {
+	Timeout    time.Duration     // Bounds each external API validation, enrichment and notification; 0 keeps the default
+	Pagination domain.Pagination // Page sizes of ListExamples; zero keeps domain.DefaultPagination
+}
+
+// defaultTimeout bounds external API calls unless UseCaseConfig.Timeout is set
+const defaultTimeout = 30 * time.Second
+
 // exampleUseCase implements ExampleUseCase
 type exampleUseCase struct {
 	service     service.ExampleService
 	externalAPI repository.ExternalExampleAPI
+	publisher   EventPublisher            // Optional, nil disables event publishing
+	auditor     Auditor                   // Optional, nil disables auditing
+	breaker     *gobreaker.CircuitBreaker // Optional, nil calls the external API unguarded
+	background  *BackgroundTasks          // Runs notifications that outlive the request
+	transactor  repository.Transactor     // Optional, nil disables CreateExampleTransactional
 	logger      *zap.Logger
 	timeout     time.Duration
+	pagination  domain.Pagination
+
+	enrichConcurrency int                                                       // Examples enriched in parallel by ListExamples
+	enrichDisabled    bool                                                      // Never call GetExampleData or EnrichExample
+	newTxService      func(repository.ExampleRepository) service.ExampleService // Builds the service used inside a transaction
 }
 
-// NewExampleUseCase creates a new example use case
+// NewExampleUseCase creates a new example use case with the default settings
 func NewExampleUseCase(
 	service service.ExampleService,
 	externalAPI repository.ExternalExampleAPI,
 	logger *zap.Logger,
+	opts ...Option,
+) ExampleUseCase {
+	return NewExampleUseCaseWithConfig(service, externalAPI, logger, UseCaseConfig{}, opts...)
+}
+
+// NewExampleUseCaseWithConfig creates a new example use case with the settings in cfg
+func NewExampleUseCaseWithConfig(
+	service service.ExampleService,
+	externalAPI repository.ExternalExampleAPI,
+	logger *zap.Logger,
+	cfg UseCaseConfig,
+	opts ...Option,
 ) ExampleUseCase {
-	return &exampleUseCase{
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	pagination := cfg.Pagination
+	if pagination == (domain.Pagination{}) {
+		pagination = domain.DefaultPagination
+	}
+
+	uc := &exampleUseCase{
 		service:     service,
 		externalAPI: externalAPI,
 		logger:      logger,
-		timeout:     30 * time.Second, // Default timeout for external API calls
+		timeout:     timeout,
+		pagination:  pagination,
+		background:  NewBackgroundTasks(),
+
+		enrichConcurrency: defaultEnrichmentConcurrency,
 	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
 // CreateExample creates a new example with external validation
 func (uc *exampleUseCase) CreateExample(ctx context.Context, req CreateExampleRequest) (*ExampleWithMetadata, error) {
-	logger := uc.logger.With(
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.CreateExample")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, uc.logger).With(
 		zap.String("layer", "UseCase"),
 		zap.String("operation", "CreateExample"),
 		zap.String("email", req.Email),
@@ -98,31 +333,33 @@ func (uc *exampleUseCase) CreateExample(ctx context.Context, req CreateExampleRe
 	)
 
 	// Create example using service
-	example, err := uc.service.CreateExample(ctx, req.Name, req.Email, req.Age)
+	example, err := uc.service.CreateExample(ctx, req.Name, req.Email, req.Phone, req.Age)
 	if err != nil {
 		logger.Error("Service failed to create example", zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
+	uc.audit(ctx, audit.ActionCreate, example.ID, nil, example, logger)
+
 	// Notify external API about new example creation (fire and forget)
-	go func() {
-		notifyCtx, cancel := context.WithTimeout(context.Background(), uc.timeout)
-		defer cancel()
+	uc.notifyExampleCreated(ctx, example, logger)
 
-		if err := uc.externalAPI.NotifyExampleCreated(notifyCtx, example.ID, example.Email); err != nil {
-			logger.Warn("Failed to notify external API", zap.Error(err))
-		}
-	}()
+	result := &ExampleWithMetadata{
+		Example: example,
+	}
+	uc.publishCreated(ctx, result, logger)
 
 	// Return example with metadata
-	return &ExampleWithMetadata{
-		Example: example,
-	}, nil
+	return result, nil
 }
 
 // GetExample retrieves an example with external data
 func (uc *exampleUseCase) GetExample(ctx context.Context, id string) (*ExampleWithMetadata, error) {
-	logger := uc.logger.With(
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.GetExample")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, uc.logger).With(
 		zap.String("operation", "GetExample"),
 		zap.String("id", id),
 	)
@@ -131,16 +368,43 @@ func (uc *exampleUseCase) GetExample(ctx context.Context, id string) (*ExampleWi
 	example, err := uc.service.GetExampleByID(ctx, id)
 	if err != nil {
 		logger.Error("Service failed to get example", zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
 	// Enrich with external data
-	return uc.enrichExample(ctx, example, logger)
+	return uc.enrichExample(ctx, example, IncludeAll, logger)
+}
+
+// GetExamplesByIDs retrieves and enriches the examples with the given IDs, reporting missing IDs
+func (uc *exampleUseCase) GetExamplesByIDs(ctx context.Context, ids []string) (*GetExamplesByIDsResponse, error) {
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.GetExamplesByIDs")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, uc.logger).With(
+		zap.String("operation", "GetExamplesByIDs"),
+		zap.Int("ids", len(ids)),
+	)
+
+	examples, notFound, err := uc.service.GetExamplesByIDs(ctx, ids)
+	if err != nil {
+		logger.Error("Service failed to get examples", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	return &GetExamplesByIDsResponse{
+		Examples: uc.enrichAll(ctx, examples, IncludeAll, logger),
+		NotFound: notFound,
+	}, nil
 }
 
 // GetExampleByEmail retrieves an example by email with external data
 func (uc *exampleUseCase) GetExampleByEmail(ctx context.Context, email string) (*ExampleWithMetadata, error) {
-	logger := uc.logger.With(
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.GetExampleByEmail")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, uc.logger).With(
 		zap.String("operation", "GetExampleByEmail"),
 		zap.String("email", email),
 	)
@@ -149,157 +413,387 @@ func (uc *exampleUseCase) GetExampleByEmail(ctx context.Context, email string) (
 	example, err := uc.service.GetExampleByEmail(ctx, email)
 	if err != nil {
 		logger.Error("Service failed to get example by email", zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
 	// Enrich with external data
-	return uc.enrichExample(ctx, example, logger)
+	return uc.enrichExample(ctx, example, IncludeAll, logger)
 }
 
 // UpdateExample updates an example
 func (uc *exampleUseCase) UpdateExample(ctx context.Context, id string, req UpdateExampleRequest) (*ExampleWithMetadata, error) {
-	logger := uc.logger.With(
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.UpdateExample")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, uc.logger).With(
 		zap.String("operation", "UpdateExample"),
 		zap.String("id", id),
 	)
 
 	logger.Info("Updating example via use case")
 
+	before, err := uc.changeSnapshot(ctx, id)
+	if err != nil {
+		logger.Error("Service failed to get example for update", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
 	// Update example using service
-	example, err := uc.service.UpdateExample(ctx, id, req.Name, req.Email, req.Age)
+	example, err := uc.service.UpdateExample(ctx, id, req.Name, req.Email, req.Phone, req.Age, req.ExpectedVersion)
 	if err != nil {
 		logger.Error("Service failed to update example", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	// The service skips an update that changes nothing, so there is nothing to audit or announce
+	changes := changedFields(before, example)
+	if len(changes) > 0 {
+		uc.auditUpdate(ctx, id, before, example, changes, logger)
+	}
+
+	// Enrich with external data
+	enriched, err := uc.enrichExample(ctx, example, IncludeAll, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) > 0 {
+		uc.publishUpdated(ctx, enriched, logger)
+	}
+	return enriched, nil
+}
+
+// PatchExample partially updates an example
+func (uc *exampleUseCase) PatchExample(ctx context.Context, id string, req PatchExampleRequest) (*ExampleWithMetadata, error) {
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.PatchExample")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, uc.logger).With(
+		zap.String("operation", "PatchExample"),
+		zap.String("id", id),
+	)
+
+	logger.Info("Patching example via use case")
+
+	// An empty patch changes nothing, so there is nothing to compare
+	var before *domain.Example
+	if !req.IsEmpty() {
+		var err error
+		if before, err = uc.changeSnapshot(ctx, id); err != nil {
+			logger.Error("Service failed to get example for patch", zap.Error(err))
+			tracing.RecordError(span, err)
+			return nil, err
+		}
+	}
+
+	// Patch example using service
+	example, err := uc.service.PatchExample(ctx, id, req.Name, req.Email, req.Phone, req.Age)
+	if err != nil {
+		logger.Error("Service failed to patch example", zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
+	// Neither an empty patch nor one setting the current values changes anything, so there
+	// is nothing to audit or announce
+	changes := changedFields(before, example)
+	if len(changes) > 0 {
+		uc.auditUpdate(ctx, id, before, example, changes, logger)
+	}
+
 	// Enrich with external data
-	return uc.enrichExample(ctx, example, logger)
+	enriched, err := uc.enrichExample(ctx, example, IncludeAll, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) > 0 {
+		uc.publishUpdated(ctx, enriched, logger)
+	}
+	return enriched, nil
 }
 
 // DeleteExample deletes an example
 func (uc *exampleUseCase) DeleteExample(ctx context.Context, id string) error {
-	logger := uc.logger.With(
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.DeleteExample")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, uc.logger).With(
 		zap.String("operation", "DeleteExample"),
 		zap.String("id", id),
 	)
 
 	logger.Info("Deleting example via use case")
 
+	// Load the example first so the deleted event can carry its email and name, and the
+	// audit entry what was removed
+	var existing *domain.Example
+	if uc.publisher != nil || uc.auditor != nil {
+		var err error
+		existing, err = uc.service.GetExampleByID(ctx, id)
+		if err != nil {
+			logger.Error("Service failed to get example for deletion", zap.Error(err))
+			tracing.RecordError(span, err)
+			return err
+		}
+	}
+
 	if err := uc.service.DeleteExample(ctx, id); err != nil {
 		logger.Error("Service failed to delete example", zap.Error(err))
+		tracing.RecordError(span, err)
 		return err
 	}
 
 	logger.Info("Example deleted successfully")
+	if existing != nil {
+		uc.audit(ctx, audit.ActionDelete, id, existing, nil, logger)
+		uc.publishDeleted(ctx, existing, logger)
+	}
 	return nil
 }
 
-// ListExamples retrieves a paginated list of examples with external data
+// DeleteExamplesByIDs deletes the examples with the given IDs, reporting missing IDs. A deleted
+// event is published and an audit entry recorded for every removed example.
+func (uc *exampleUseCase) DeleteExamplesByIDs(ctx context.Context, ids []string) (*DeleteExamplesByIDsResponse, error) {
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.DeleteExamplesByIDs")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, uc.logger).With(
+		zap.String("operation", "DeleteExamplesByIDs"),
+		zap.Int("ids", len(ids)),
+	)
+
+	deleted, notFound, err := uc.service.DeleteExamplesByIDs(ctx, ids)
+	if err != nil {
+		logger.Error("Service failed to delete examples", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	response := &DeleteExamplesByIDsResponse{
+		Deleted:  make([]string, len(deleted)),
+		NotFound: notFound,
+	}
+	for i, example := range deleted {
+		response.Deleted[i] = example.ID
+		uc.audit(ctx, audit.ActionDelete, example.ID, example, nil, logger)
+		uc.publishDeleted(ctx, example, logger)
+	}
+	return response, nil
+}
+
+// ListExamples retrieves a paginated list of examples, with the external data selected by req.Include
 func (uc *exampleUseCase) ListExamples(ctx context.Context, req ListExamplesRequest) (*ListExamplesResponse, error) {
-	logger := uc.logger.With(
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.ListExamples")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, uc.logger).With(
 		zap.String("operation", "ListExamples"),
 		zap.Int("limit", req.Limit),
 		zap.Int("offset", req.Offset),
 	)
 
-	// Set defaults
-	if req.Limit <= 0 {
-		req.Limit = 10 // Default limit
-	}
-	if req.Limit > 100 {
-		req.Limit = 100 // Max limit
-	}
+	req.Limit = uc.pagination.Limit(req.Limit)
 
 	// Get examples from service
-	examples, total, err := uc.service.ListExamples(ctx, req.Limit, req.Offset)
+	filter := repository.ExampleFilter{CreatedSince: req.CreatedSince, UpdatedSince: req.UpdatedSince}
+	examples, total, err := uc.service.ListExamples(ctx, req.Limit, req.Offset, req.Sort, filter)
 	if err != nil {
 		logger.Error("Service failed to list examples", zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
-	// Enrich examples with external data (with timeout)
-	enrichedExamples := make([]*ExampleWithMetadata, len(examples))
-	for i, example := range examples {
-		enriched, err := uc.enrichExample(ctx, example, logger)
-		if err != nil {
-			// Log error but continue with basic example data
-			logger.Warn("Failed to enrich example", zap.String("id", example.ID), zap.Error(err))
-			enriched = &ExampleWithMetadata{Example: example}
-		}
-		enrichedExamples[i] = enriched
-	}
-
 	return &ListExamplesResponse{
-		Examples: enrichedExamples,
+		Examples: uc.enrichAll(ctx, examples, req.Include, logger),
 		Total:    total,
 		Limit:    req.Limit,
 		Offset:   req.Offset,
 	}, nil
 }
 
-// ValidateAndCreateExample creates an example with external validation
+// ExportExamples calls fn for every example without external data; enriching a full export
+// would make one external API call per example
+func (uc *exampleUseCase) ExportExamples(ctx context.Context, fn func(*domain.Example) error) error {
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.ExportExamples")
+	defer span.End()
+
+	if err := uc.service.ExportExamples(ctx, fn); err != nil {
+		logger.ForContext(ctx, uc.logger).Error("Service failed to export examples",
+			zap.String("operation", "ExportExamples"),
+			zap.Error(err),
+		)
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
+}
+
+// ValidateAndCreateExample creates an example with external validation. With a transactor
+// it goes through CreateExampleTransactional, so the example and its event are saved together.
 func (uc *exampleUseCase) ValidateAndCreateExample(ctx context.Context, req CreateExampleRequest) (*ExampleWithMetadata, error) {
-	logger := uc.logger.With(
+	if uc.transactor != nil {
+		return uc.CreateExampleTransactional(ctx, req)
+	}
+
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.ValidateAndCreateExample")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, uc.logger).With(
 		zap.String("operation", "ValidateAndCreateExample"),
 		zap.String("email", req.Email),
 	)
 
 	logger.Info("Creating example with external validation")
 
-	// Validate with external API first
-	externalCtx, cancel := context.WithTimeout(ctx, uc.timeout)
-	defer cancel()
-
-	isValid, err := uc.externalAPI.ValidateExample(externalCtx, req.Name, req.Email, req.Age)
-	if err != nil {
-		logger.Error("External validation failed",
-			zap.String("name", req.Name),
-			zap.String("email", req.Email),
-			zap.Int("age", req.Age),
-			zap.Error(err))
-		return nil, fmt.Errorf("%w: external validation failed for user %s (%s): %v", ErrExternalService, req.Name, req.Email, err)
-	}
-
-	if !isValid {
-		logger.Warn("External validation rejected example",
-			zap.String("name", req.Name),
-			zap.String("email", req.Email),
-			zap.Int("age", req.Age))
-		return nil, fmt.Errorf("%w: example %s (%s) rejected by external validation", ErrUseCaseValidation, req.Name, req.Email)
+	if err := uc.validateExternally(ctx, req, logger); err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
 	}
 
 	// Create example using service
-	example, err := uc.service.CreateExample(ctx, req.Name, req.Email, req.Age)
+	example, err := uc.service.CreateExample(ctx, req.Name, req.Email, req.Phone, req.Age)
 	if err != nil {
 		logger.Error("Service failed to create example", zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
+	uc.audit(ctx, audit.ActionCreate, example.ID, nil, example, logger)
 
 	// Enrich with external data
-	enriched, err := uc.enrichExample(ctx, example, logger)
+	enriched, err := uc.enrichExample(ctx, example, IncludeAll, logger)
 	if err != nil {
 		// Log error but return basic example
 		logger.Warn("Failed to enrich created example", zap.Error(err))
-		return &ExampleWithMetadata{Example: example}, nil
+		enriched = &ExampleWithMetadata{Example: example, EnrichmentStatus: EnrichmentFailed}
+		uc.publishCreated(ctx, enriched, logger)
+		return enriched, nil
 	}
 
 	// Notify external API about new example creation (fire and forget)
-	go func() {
-		notifyCtx, cancel := context.WithTimeout(context.Background(), uc.timeout)
-		defer cancel()
+	uc.notifyExampleCreated(ctx, example, logger)
 
-		if err := uc.externalAPI.NotifyExampleCreated(notifyCtx, example.ID, example.Email); err != nil {
-			logger.Warn("Failed to notify external API", zap.Error(err))
+	uc.publishCreated(ctx, enriched, logger)
+	return enriched, nil
+}
+
+// CreateExampleTransactional validates req with the external API like ValidateAndCreateExample,
+// then creates the example and stores its created event in the outbox in one transaction, so
+// neither is saved without the other. The outbox publisher relays the event afterwards.
+func (uc *exampleUseCase) CreateExampleTransactional(ctx context.Context, req CreateExampleRequest) (*ExampleWithMetadata, error) {
+	ctx, span := tracer.Start(ctx, "ExampleUseCase.CreateExampleTransactional")
+	defer span.End()
+
+	logger := logger.ForContext(ctx, uc.logger).With(
+		zap.String("operation", "CreateExampleTransactional"),
+		zap.String("email", req.Email),
+	)
+
+	if uc.transactor == nil {
+		return nil, errs.New(errs.ErrorCodeServiceUnavailable, ErrTransactionsDisabled, nil)
+	}
+
+	if err := uc.validateExternally(ctx, req, logger); err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	var example *domain.Example
+	err := uc.transactor.Transaction(ctx, func(txRepo repository.ExampleRepository) error {
+		outboxRepo, ok := txRepo.(repository.OutboxRepository)
+		if !ok {
+			return errs.New(errs.ErrorCodeServiceUnavailable, ErrOutboxUnavailable, nil)
 		}
-	}()
+		// The created event is saved below, so the repository mustn't record a second one
+		if recorder, ok := txRepo.(repository.OutboxRecorder); ok {
+			txRepo = recorder.WithoutOutboxRecording()
+		}
+
+		created, err := uc.newTxService(txRepo).CreateExample(ctx, req.Name, req.Email, req.Phone, req.Age)
+		if err != nil {
+			return err
+		}
+
+		event, err := domain.NewOutboxEvent(domain.EventTypeExampleCreated, created)
+		if err != nil {
+			return err
+		}
+		if err := outboxRepo.SaveOutboxEvent(ctx, event); err != nil {
+			return fmt.Errorf("failed to save outbox event: %w", err)
+		}
+
+		example = created
+		return nil
+	})
+	if err != nil {
+		logger.Error("Transactional create failed, nothing was saved", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	uc.audit(ctx, audit.ActionCreate, example.ID, nil, example, logger)
+
+	// Enrich with external data
+	enriched, err := uc.enrichExample(ctx, example, IncludeAll, logger)
+	if err != nil {
+		logger.Warn("Failed to enrich created example", zap.Error(err))
+		return &ExampleWithMetadata{Example: example, EnrichmentStatus: EnrichmentFailed}, nil
+	}
+
+	// Notify external API about new example creation (fire and forget)
+	uc.notifyExampleCreated(ctx, example, logger)
 
 	return enriched, nil
 }
 
-// enrichExample enriches an example with external data
-func (uc *exampleUseCase) enrichExample(ctx context.Context, example *domain.Example, logger *zap.Logger) (*ExampleWithMetadata, error) {
+// validateExternally asks the external API whether req may be created
+func (uc *exampleUseCase) validateExternally(ctx context.Context, req CreateExampleRequest, logger *zap.Logger) error {
+	externalCtx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	externalCtx, externalSpan := startExternalSpan(externalCtx, "ValidateExample")
+	var isValid bool
+	err := uc.callExternal(func() (err error) {
+		isValid, err = uc.externalAPI.ValidateExample(externalCtx, req.Name, req.Email, req.Age)
+		return err
+	})
+	tracing.RecordError(externalSpan, err)
+	externalSpan.End()
+	if err != nil {
+		logger.Error("External validation failed",
+			zap.String("name", req.Name),
+			zap.String("email", req.Email),
+			zap.Int("age", req.Age),
+			zap.Error(err))
+		return fmt.Errorf("%w: external validation failed for user %s (%s): %w", ErrExternalService, req.Name, req.Email, err)
+	}
+
+	if !isValid {
+		logger.Warn("External validation rejected example",
+			zap.String("name", req.Name),
+			zap.String("email", req.Email),
+			zap.Int("age", req.Age))
+		return fmt.Errorf("%w: example %s (%s) rejected by external validation", ErrUseCaseValidation, req.Name, req.Email)
+	}
+	return nil
+}
+
+// enrichExample enriches an example with the external data selected by include
+func (uc *exampleUseCase) enrichExample(ctx context.Context, example *domain.Example, include Include, logger *zap.Logger) (*ExampleWithMetadata, error) {
 	enriched := &ExampleWithMetadata{
-		Example: example,
+		Example:          example,
+		EnrichmentStatus: EnrichmentSkipped,
+	}
+	if include == IncludeNone || uc.enrichDisabled {
+		return enriched, nil
+	}
+
+	// Enrichment is optional, so don't wait on an API that is known to be down
+	if !uc.externalAPIAvailable() {
+		logger.Debug("Skipping enrichment while the external API circuit breaker is open", zap.String("id", example.ID))
+		return enriched, nil
 	}
 
 	// Create timeout context for external API calls
@@ -312,21 +806,39 @@ func (uc *exampleUseCase) enrichExample(ctx context.Context, example *domain.Exa
 	var enrichmentData map[string]interface{}
 	var extErr, enrichErr error
 
-	wg.Add(2)
-
 	// Get external data in parallel
+	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		externalData, extErr = uc.externalAPI.GetExampleData(externalCtx, example.ID)
+		if !include.Has(IncludeExternal) {
+			return
+		}
+		spanCtx, span := startExternalSpan(externalCtx, "GetExampleData")
+		defer span.End()
+		extErr = uc.callExternal(func() (err error) {
+			externalData, err = uc.externalAPI.GetExampleData(spanCtx, example.ID)
+			return err
+		})
+		tracing.RecordError(span, extErr)
 		if extErr != nil {
 			logger.Warn("Failed to get external data", zap.String("id", example.ID), zap.Error(extErr))
 		}
 	}()
 
 	// Get enrichment data in parallel
+	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		enrichmentData, enrichErr = uc.externalAPI.EnrichExample(externalCtx, example.ID)
+		if !include.Has(IncludeEnrichment) {
+			return
+		}
+		spanCtx, span := startExternalSpan(externalCtx, "EnrichExample")
+		defer span.End()
+		enrichErr = uc.callExternal(func() (err error) {
+			enrichmentData, err = uc.externalAPI.EnrichExample(spanCtx, example.ID)
+			return err
+		})
+		tracing.RecordError(span, enrichErr)
 		if enrichErr != nil {
 			logger.Warn("Failed to get enrichment data", zap.String("id", example.ID), zap.Error(enrichErr))
 		}
@@ -343,5 +855,195 @@ func (uc *exampleUseCase) enrichExample(ctx context.Context, example *domain.Exa
 		enriched.Enrichment = enrichmentData
 	}
 
+	var requested, failed int
+	for _, call := range []struct {
+		part Include
+		err  error
+	}{{IncludeExternal, extErr}, {IncludeEnrichment, enrichErr}} {
+		if !include.Has(call.part) {
+			continue
+		}
+		requested++
+		if call.err != nil {
+			failed++
+		}
+	}
+	enriched.EnrichmentStatus = enrichmentStatus(requested, failed)
+
 	return enriched, nil
 }
+
+// enrichAll enriches examples with include on a bounded pool of workers, keeping their order.
+// An example that fails to enrich, or is not reached before ctx is done, is
+// returned without external data.
+func (uc *exampleUseCase) enrichAll(ctx context.Context, examples []*domain.Example, include Include, logger *zap.Logger) []*ExampleWithMetadata {
+	results := make([]*ExampleWithMetadata, len(examples))
+	if include == IncludeNone || uc.enrichDisabled {
+		for i, example := range examples {
+			results[i] = &ExampleWithMetadata{Example: example, EnrichmentStatus: EnrichmentSkipped}
+		}
+		return results
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(uc.enrichConcurrency, len(examples)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() != nil {
+					continue // Left bare below
+				}
+				enriched, err := uc.enrichExample(ctx, examples[i], include, logger)
+				if err != nil {
+					// Log error but continue with basic example data
+					logger.Warn("Failed to enrich example", zap.String("id", examples[i].ID), zap.Error(err))
+					enriched = &ExampleWithMetadata{Example: examples[i], EnrichmentStatus: EnrichmentFailed}
+				}
+				results[i] = enriched
+			}
+		}()
+	}
+
+feed:
+	for i := range examples {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, result := range results {
+		if result == nil {
+			results[i] = &ExampleWithMetadata{Example: examples[i], EnrichmentStatus: EnrichmentSkipped}
+		}
+	}
+	return results
+}
+
+// notifyExampleCreated notifies the external API as a background task, keeping the caller's trace
+func (uc *exampleUseCase) notifyExampleCreated(ctx context.Context, example *domain.Example, logger *zap.Logger) {
+	parent := trace.SpanContextFromContext(ctx)
+
+	uc.background.Go(func(taskCtx context.Context) {
+		notifyCtx, cancel := context.WithTimeout(trace.ContextWithSpanContext(taskCtx, parent), uc.timeout)
+		defer cancel()
+
+		notifyCtx, span := startExternalSpan(notifyCtx, "NotifyExampleCreated")
+		defer span.End()
+
+		err := uc.callExternal(func() error {
+			return uc.externalAPI.NotifyExampleCreated(notifyCtx, example.ID, example.Email)
+		})
+		if err != nil {
+			tracing.RecordError(span, err)
+			logger.Warn("Failed to notify external API", zap.Error(err))
+		}
+	})
+}
+
+// callExternal runs an external API call through the circuit breaker, if one is configured.
+// While the breaker is open it returns gobreaker.ErrOpenState without calling fn.
+func (uc *exampleUseCase) callExternal(fn func() error) error {
+	if uc.breaker == nil {
+		return fn()
+	}
+	_, err := uc.breaker.Execute(func() (interface{}, error) {
+		return nil, fn()
+	})
+	return err
+}
+
+// externalAPIAvailable reports whether external API calls are currently allowed
+func (uc *exampleUseCase) externalAPIAvailable() bool {
+	return uc.breaker == nil || uc.breaker.State() != gobreaker.StateOpen
+}
+
+// changeSnapshot returns a copy of the example as it is before a change, to audit it and tell
+// whether the change did anything, or nil when neither auditing nor publishing is on
+func (uc *exampleUseCase) changeSnapshot(ctx context.Context, id string) (*domain.Example, error) {
+	if uc.auditor == nil && uc.publisher == nil {
+		return nil, nil
+	}
+	// Read from the primary, so a lagging replica can't misreport what the update changes
+	existing, err := uc.service.GetExampleByID(repository.WithPrimaryReads(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := *existing
+	return &snapshot, nil
+}
+
+// changedFields returns the JSON names of the fields an update changed; none when before is
+// nil, since there is then nothing to audit or publish
+func changedFields(before, after *domain.Example) []string {
+	if before == nil {
+		return nil
+	}
+	var fields []string
+	for field := range before.Diff(after) {
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// auditUpdate records a completed update limited to the changed fields; failures are
+// logged, not returned
+func (uc *exampleUseCase) auditUpdate(ctx context.Context, id string, before, after *domain.Example, fields []string, logger *zap.Logger) {
+	if uc.auditor == nil {
+		return
+	}
+	if err := uc.auditor.AuditChanges(ctx, audit.ActionUpdate, id, before, after, fields); err != nil {
+		logger.Warn("Failed to audit example change", zap.String("action", audit.ActionUpdate), zap.String("id", id), zap.Error(err))
+	}
+}
+
+// audit records a completed change; failures are logged, not returned
+func (uc *exampleUseCase) audit(ctx context.Context, action, id string, before, after *domain.Example, logger *zap.Logger) {
+	if uc.auditor == nil {
+		return
+	}
+	if err := uc.auditor.Audit(ctx, action, id, before, after); err != nil {
+		logger.Warn("Failed to audit example change", zap.String("action", action), zap.String("id", id), zap.Error(err))
+	}
+}
+
+// publishCreated publishes an example created event; failures are logged, not returned
+func (uc *exampleUseCase) publishCreated(ctx context.Context, example *ExampleWithMetadata, logger *zap.Logger) {
+	if uc.publisher == nil {
+		return
+	}
+	if err := uc.publisher.PublishExampleCreated(ctx, example); err != nil {
+		logger.Warn("Failed to publish example created event", zap.String("id", example.ID), zap.Error(err))
+	}
+}
+
+// publishUpdated publishes an example updated event; failures are logged, not returned
+func (uc *exampleUseCase) publishUpdated(ctx context.Context, example *ExampleWithMetadata, logger *zap.Logger) {
+	if uc.publisher == nil {
+		return
+	}
+	if err := uc.publisher.PublishExampleUpdated(ctx, example); err != nil {
+		logger.Warn("Failed to publish example updated event", zap.String("id", example.ID), zap.Error(err))
+	}
+}
+
+// publishDeleted publishes an example deleted event; failures are logged, not returned
+func (uc *exampleUseCase) publishDeleted(ctx context.Context, example *domain.Example, logger *zap.Logger) {
+	if uc.publisher == nil {
+		return
+	}
+	if err := uc.publisher.PublishExampleDeleted(ctx, example.ID, example.Email, example.Name); err != nil {
+		logger.Warn("Failed to publish example deleted event", zap.String("id", example.ID), zap.Error(err))
+	}
+}
+
+// startExternalSpan starts a client span around an external API call
+func startExternalSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "ExternalAPI."+method, trace.WithSpanKind(trace.SpanKindClient))
+}
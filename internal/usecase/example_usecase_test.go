@@ -2,17 +2,33 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"example-api-template/internal/audit"
 	"example-api-template/internal/domain"
+	"example-api-template/internal/errs"
 	"example-api-template/internal/repository"
+	"example-api-template/internal/service"
+	"example-api-template/pkg/ctxkeys"
+	"example-api-template/pkg/logger"
 	"example-api-template/tests/mocks"
 
+	"github.com/sony/gobreaker"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 // Test fixtures for usecase tests
@@ -85,7 +101,10 @@ func validEnrichmentData() map[string]interface{} {
 }
 
 func getTestContext() context.Context {
-	ctx, _ := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Note: In real tests, you should call cancel() when done
+	// For this test helper, we'll let it timeout naturally
+	_ = cancel
 	return ctx
 }
 
@@ -99,6 +118,49 @@ func TestNewExampleUseCase(t *testing.T) {
 	assert.NotNil(t, useCase)
 }
 
+func TestNewExampleUseCaseWithConfig_Timeout(t *testing.T) {
+	mockService := &mocks.MockExampleService{}
+	mockExternalAPI := &mocks.MockExternalExampleAPI{}
+	useCase := NewExampleUseCaseWithConfig(mockService, mockExternalAPI, zap.NewNop(),
+		UseCaseConfig{Timeout: 20 * time.Millisecond})
+
+	// The external API only returns once the configured timeout cancels its context
+	waitForCancel := func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Error("external call was not cancelled")
+		}
+	}
+
+	t.Run("enrichment", func(t *testing.T) {
+		example := validExampleWithCustomData("test-id", "John Doe", "john@example.com", 30)
+		mockService.On("GetExampleByID", mock.Anything, "test-id").Return(example, nil)
+		mockExternalAPI.On("GetExampleData", mock.Anything, "test-id").Run(waitForCancel).Return(nil, context.DeadlineExceeded)
+		mockExternalAPI.On("EnrichExample", mock.Anything, "test-id").Run(waitForCancel).Return(nil, context.DeadlineExceeded)
+
+		start := time.Now()
+		result, err := useCase.GetExample(getTestContext(), "test-id")
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Nil(t, result.ExternalData)
+		assert.Nil(t, result.Enrichment)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		mockExternalAPI.On("ValidateExample", mock.Anything, "John Doe", "john@example.com", 30).
+			Run(waitForCancel).Return(false, context.DeadlineExceeded)
+
+		start := time.Now()
+		_, err := useCase.ValidateAndCreateExample(getTestContext(), CreateExampleRequest{
+			Name: "John Doe", Email: "john@example.com", Age: 30,
+		})
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
 func TestExampleUseCase_CreateExample(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -113,7 +175,7 @@ func TestExampleUseCase_CreateExample(t *testing.T) {
 			request: validCreateExampleRequest(),
 			setupService: func(m *mocks.MockExampleService) {
 				example := validExample()
-				m.On("CreateExample", mock.Anything, "John Doe", "john.doe@example.com", 30).
+				m.On("CreateExample", mock.Anything, "John Doe", "john.doe@example.com", "", 30).
 					Return(example, nil)
 			},
 			setupExternal: func(m *mocks.MockExternalExampleAPI) {
@@ -131,7 +193,7 @@ func TestExampleUseCase_CreateExample(t *testing.T) {
 				Age:   25,
 			},
 			setupService: func(m *mocks.MockExampleService) {
-				m.On("CreateExample", mock.Anything, "Invalid User", "invalid@example.com", 25).
+				m.On("CreateExample", mock.Anything, "Invalid User", "invalid@example.com", "", 25).
 					Return(nil, repository.ErrExampleAlreadyExists)
 			},
 			setupExternal: func(m *mocks.MockExternalExampleAPI) {
@@ -184,6 +246,7 @@ func TestExampleUseCase_GetExample(t *testing.T) {
 		wantErr        bool
 		errContains    string
 		expectEnriched bool
+		wantStatus     EnrichmentStatus
 	}{
 		{
 			name:    "successful get with enrichment",
@@ -200,6 +263,7 @@ func TestExampleUseCase_GetExample(t *testing.T) {
 			},
 			wantErr:        false,
 			expectEnriched: true,
+			wantStatus:     EnrichmentComplete,
 		},
 		{
 			name:    "successful get with partial enrichment failure",
@@ -217,6 +281,24 @@ func TestExampleUseCase_GetExample(t *testing.T) {
 			},
 			wantErr:        false,
 			expectEnriched: false, // Only partial enrichment
+			wantStatus:     EnrichmentPartial,
+		},
+		{
+			name:    "successful get with both external calls failing",
+			inputID: "test-id",
+			setupService: func(m *mocks.MockExampleService) {
+				example := validExampleWithCustomData("test-id", "John Doe", "john@example.com", 30)
+				m.On("GetExampleByID", mock.Anything, "test-id").Return(example, nil)
+			},
+			setupExternal: func(m *mocks.MockExternalExampleAPI) {
+				m.On("GetExampleData", mock.Anything, "test-id").
+					Return(nil, repository.ErrExternalAPIUnavailable)
+				m.On("EnrichExample", mock.Anything, "test-id").
+					Return(nil, repository.ErrExternalAPIUnavailable)
+			},
+			wantErr:        false,
+			expectEnriched: false,
+			wantStatus:     EnrichmentFailed,
 		},
 		{
 			name:    "service fails",
@@ -259,6 +341,7 @@ func TestExampleUseCase_GetExample(t *testing.T) {
 					assert.NotNil(t, result.ExternalData)
 					assert.NotNil(t, result.Enrichment)
 				}
+				assert.Equal(t, tt.wantStatus, result.EnrichmentStatus)
 			}
 
 			mockService.AssertExpectations(t)
@@ -267,6 +350,30 @@ func TestExampleUseCase_GetExample(t *testing.T) {
 	}
 }
 
+func TestExampleUseCase_GetExamplesByIDs(t *testing.T) {
+	mockService := &mocks.MockExampleService{}
+	mockExternalAPI := &mocks.MockExternalExampleAPI{}
+	useCase := NewExampleUseCase(mockService, mockExternalAPI, zap.NewNop())
+
+	ids := []string{"test-id", "missing"}
+	example := validExampleWithCustomData("test-id", "John Doe", "john@example.com", 30)
+	mockService.On("GetExamplesByIDs", mock.Anything, ids).
+		Return([]*domain.Example{example}, []string{"missing"}, nil)
+	mockExternalAPI.On("GetExampleData", mock.Anything, "test-id").Return(validExternalExampleData(), nil)
+	mockExternalAPI.On("EnrichExample", mock.Anything, "test-id").Return(validEnrichmentData(), nil)
+
+	result, err := useCase.GetExamplesByIDs(getTestContext(), ids)
+	require.NoError(t, err)
+	require.Len(t, result.Examples, 1)
+	assert.Equal(t, "test-id", result.Examples[0].ID)
+	assert.NotNil(t, result.Examples[0].ExternalData)
+	assert.NotNil(t, result.Examples[0].Enrichment)
+	assert.Equal(t, []string{"missing"}, result.NotFound)
+
+	mockService.AssertExpectations(t)
+	mockExternalAPI.AssertExpectations(t)
+}
+
 func TestExampleUseCase_ValidateAndCreateExample(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -281,7 +388,7 @@ func TestExampleUseCase_ValidateAndCreateExample(t *testing.T) {
 			request: validCreateExampleRequest(),
 			setupService: func(m *mocks.MockExampleService) {
 				example := validExample()
-				m.On("CreateExample", mock.Anything, "John Doe", "john.doe@example.com", 30).
+				m.On("CreateExample", mock.Anything, "John Doe", "john.doe@example.com", "", 30).
 					Return(example, nil)
 			},
 			setupExternal: func(m *mocks.MockExternalExampleAPI) {
@@ -339,7 +446,7 @@ func TestExampleUseCase_ValidateAndCreateExample(t *testing.T) {
 			name:    "validation succeeds but service fails",
 			request: validCreateExampleRequest(),
 			setupService: func(m *mocks.MockExampleService) {
-				m.On("CreateExample", mock.Anything, "John Doe", "john.doe@example.com", 30).
+				m.On("CreateExample", mock.Anything, "John Doe", "john.doe@example.com", "", 30).
 					Return(nil, repository.ErrExampleAlreadyExists)
 			},
 			setupExternal: func(m *mocks.MockExternalExampleAPI) {
@@ -384,6 +491,27 @@ func TestExampleUseCase_ValidateAndCreateExample(t *testing.T) {
 	}
 }
 
+func TestExampleUseCase_ExportExamples(t *testing.T) {
+	mockService := &mocks.MockExampleService{}
+	mockExternalAPI := &mocks.MockExternalExampleAPI{}
+	useCase := NewExampleUseCase(mockService, mockExternalAPI, zap.NewNop())
+
+	example := validExampleWithCustomData("test-id", "John Doe", "john@example.com", 30)
+	mockService.On("ExportExamples", mock.Anything, mock.Anything).Return([]*domain.Example{example}, nil)
+
+	var exported []*domain.Example
+	err := useCase.ExportExamples(getTestContext(), func(example *domain.Example) error {
+		exported = append(exported, example)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Example{example}, exported)
+
+	// Exports are not enriched
+	mockExternalAPI.AssertNotCalled(t, "GetExampleData", mock.Anything, mock.Anything)
+	mockExternalAPI.AssertNotCalled(t, "EnrichExample", mock.Anything, mock.Anything)
+}
+
 func TestExampleUseCase_ListExamples(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -397,12 +525,13 @@ func TestExampleUseCase_ListExamples(t *testing.T) {
 		{
 			name: "successful list with enrichment",
 			request: ListExamplesRequest{
-				Limit:  5,
-				Offset: 0,
+				Limit:   5,
+				Offset:  0,
+				Include: IncludeAll,
 			},
 			setupService: func(m *mocks.MockExampleService) {
 				examples := multipleValidExamples()[:3]
-				m.On("ListExamples", mock.Anything, 5, 0).Return(examples, 10, nil)
+				m.On("ListExamples", mock.Anything, 5, 0, domain.ExampleSort{}, repository.ExampleFilter{}).Return(examples, 10, nil)
 			},
 			setupExternal: func(m *mocks.MockExternalExampleAPI) {
 				// Each example will be enriched
@@ -419,12 +548,13 @@ func TestExampleUseCase_ListExamples(t *testing.T) {
 		{
 			name: "zero limit uses default",
 			request: ListExamplesRequest{
-				Limit:  0,
-				Offset: 0,
+				Limit:   0,
+				Offset:  0,
+				Include: IncludeAll,
 			},
 			setupService: func(m *mocks.MockExampleService) {
 				examples := multipleValidExamples()[:3]
-				m.On("ListExamples", mock.Anything, 10, 0).Return(examples, 10, nil)
+				m.On("ListExamples", mock.Anything, 10, 0, domain.ExampleSort{}, repository.ExampleFilter{}).Return(examples, 10, nil)
 			},
 			setupExternal: func(m *mocks.MockExternalExampleAPI) {
 				externalData := validExternalExampleData()
@@ -437,6 +567,38 @@ func TestExampleUseCase_ListExamples(t *testing.T) {
 			wantErr:       false,
 			expectedLimit: 10,
 		},
+		{
+			name: "without include the external API is not called",
+			request: ListExamplesRequest{
+				Limit: 5,
+			},
+			setupService: func(m *mocks.MockExampleService) {
+				examples := multipleValidExamples()[:3]
+				m.On("ListExamples", mock.Anything, 5, 0, domain.ExampleSort{}, repository.ExampleFilter{}).Return(examples, 10, nil)
+			},
+			setupExternal: func(m *mocks.MockExternalExampleAPI) {
+				// Any call to the external API fails the test
+			},
+			wantErr:       false,
+			expectedLimit: 5,
+		},
+		{
+			name: "include external skips enrichment",
+			request: ListExamplesRequest{
+				Limit:   5,
+				Include: IncludeExternal,
+			},
+			setupService: func(m *mocks.MockExampleService) {
+				examples := multipleValidExamples()[:3]
+				m.On("ListExamples", mock.Anything, 5, 0, domain.ExampleSort{}, repository.ExampleFilter{}).Return(examples, 10, nil)
+			},
+			setupExternal: func(m *mocks.MockExternalExampleAPI) {
+				m.On("GetExampleData", mock.Anything, mock.AnythingOfType("string")).
+					Return(validExternalExampleData(), nil).Times(3)
+			},
+			wantErr:       false,
+			expectedLimit: 5,
+		},
 		{
 			name: "service fails",
 			request: ListExamplesRequest{
@@ -444,7 +606,7 @@ func TestExampleUseCase_ListExamples(t *testing.T) {
 				Offset: 0,
 			},
 			setupService: func(m *mocks.MockExampleService) {
-				m.On("ListExamples", mock.Anything, 5, 0).
+				m.On("ListExamples", mock.Anything, 5, 0, domain.ExampleSort{}, repository.ExampleFilter{}).
 					Return(nil, 0, repository.ErrExampleNotFound)
 			},
 			setupExternal: func(m *mocks.MockExternalExampleAPI) {
@@ -501,7 +663,7 @@ func TestExampleUseCase_UpdateExample(t *testing.T) {
 			request: validUpdateExampleRequest(),
 			setupService: func(m *mocks.MockExampleService) {
 				example := validExampleWithCustomData("test-id", "John Smith", "john.smith@example.com", 31)
-				m.On("UpdateExample", mock.Anything, "test-id", "John Smith", "john.smith@example.com", 31).
+				m.On("UpdateExample", mock.Anything, "test-id", "John Smith", "john.smith@example.com", "", 31, 0).
 					Return(example, nil)
 			},
 			setupExternal: func(m *mocks.MockExternalExampleAPI) {
@@ -517,7 +679,7 @@ func TestExampleUseCase_UpdateExample(t *testing.T) {
 			inputID: "non-existent",
 			request: validUpdateExampleRequest(),
 			setupService: func(m *mocks.MockExampleService) {
-				m.On("UpdateExample", mock.Anything, "non-existent", "John Smith", "john.smith@example.com", 31).
+				m.On("UpdateExample", mock.Anything, "non-existent", "John Smith", "john.smith@example.com", "", 31, 0).
 					Return(nil, repository.ErrExampleNotFound)
 			},
 			setupExternal: func(m *mocks.MockExternalExampleAPI) {
@@ -679,3 +841,627 @@ func TestExampleUseCase_GetExampleByEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestExampleUseCase_CreateExample_RecordsSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	mockRepo := &mocks.MockExampleRepository{}
+	mockRepo.On("ExistsByEmail", mock.Anything, "john.doe@example.com").Return(false, nil)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Example")).Return(nil)
+
+	mockExternalAPI := &mocks.MockExternalExampleAPI{}
+	notified := make(chan struct{})
+	mockExternalAPI.On("NotifyExampleCreated", mock.Anything, mock.AnythingOfType("string"), "john.doe@example.com").
+		Return(nil).Run(func(mock.Arguments) { close(notified) })
+
+	svc := service.NewExampleService(mockRepo, zap.NewNop(), service.DefaultBusinessRules())
+	useCase := NewExampleUseCase(svc, mockExternalAPI, zap.NewNop())
+
+	result, err := useCase.CreateExample(getTestContext(), validCreateExampleRequest())
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		t.Fatal("external API was not notified")
+	}
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := make(map[string]tracetest.SpanStub)
+	require.Eventually(t, func() bool {
+		for _, span := range exporter.GetSpans() {
+			spans[span.Name] = span
+		}
+		return len(spans) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	root, ok := spans["ExampleUseCase.CreateExample"]
+	require.True(t, ok)
+	serviceSpan, ok := spans["ExampleService.CreateExample"]
+	require.True(t, ok)
+	notifySpan, ok := spans["ExternalAPI.NotifyExampleCreated"]
+	require.True(t, ok)
+
+	assert.Equal(t, root.SpanContext.TraceID(), serviceSpan.SpanContext.TraceID())
+	assert.Equal(t, root.SpanContext.SpanID(), serviceSpan.Parent.SpanID())
+	assert.Equal(t, root.SpanContext.TraceID(), notifySpan.SpanContext.TraceID())
+}
+
+// MockProducer is a mock implementation of EventPublisher
+type MockProducer struct {
+	mock.Mock
+}
+
+func (m *MockProducer) PublishExampleCreated(ctx context.Context, example *ExampleWithMetadata) error {
+	args := m.Called(ctx, example)
+	return args.Error(0)
+}
+
+func (m *MockProducer) PublishExampleUpdated(ctx context.Context, example *ExampleWithMetadata) error {
+	args := m.Called(ctx, example)
+	return args.Error(0)
+}
+
+func (m *MockProducer) PublishExampleDeleted(ctx context.Context, exampleID, email, name string) error {
+	args := m.Called(ctx, exampleID, email, name)
+	return args.Error(0)
+}
+
+func TestExampleUseCase_PublishesEvents(t *testing.T) {
+	newUseCase := func() (ExampleUseCase, *mocks.MockExampleService, *mocks.MockExternalExampleAPI, *MockProducer) {
+		mockService := &mocks.MockExampleService{}
+		mockExternalAPI := &mocks.MockExternalExampleAPI{}
+		mockProducer := &MockProducer{}
+		useCase := NewExampleUseCase(mockService, mockExternalAPI, zap.NewNop(), WithEventPublisher(mockProducer))
+		return useCase, mockService, mockExternalAPI, mockProducer
+	}
+
+	t.Run("create publishes created event", func(t *testing.T) {
+		useCase, mockService, mockExternalAPI, mockProducer := newUseCase()
+		example := validExample()
+
+		mockService.On("CreateExample", mock.Anything, "John Doe", "john.doe@example.com", "", 30).Return(example, nil)
+		mockExternalAPI.On("NotifyExampleCreated", mock.Anything, example.ID, example.Email).Return(nil).Maybe()
+		mockProducer.On("PublishExampleCreated", mock.Anything, mock.MatchedBy(func(e *ExampleWithMetadata) bool {
+			return e.Example == example
+		})).Return(nil).Once()
+
+		result, err := useCase.CreateExample(getTestContext(), validCreateExampleRequest())
+		require.NoError(t, err)
+		assert.Equal(t, example, result.Example)
+
+		mockProducer.AssertExpectations(t)
+	})
+
+	t.Run("update publishes updated event with enriched payload", func(t *testing.T) {
+		useCase, mockService, mockExternalAPI, mockProducer := newUseCase()
+		example := validExampleWithCustomData("test-id", "John Smith", "john.smith@example.com", 31)
+		externalData := validExternalExampleData()
+
+		mockService.On("GetExampleByID", mock.Anything, "test-id").
+			Return(validExampleWithCustomData("test-id", "John Doe", "john.doe@example.com", 30), nil)
+		mockService.On("UpdateExample", mock.Anything, "test-id", "John Smith", "john.smith@example.com", "", 31, 0).Return(example, nil)
+		mockExternalAPI.On("GetExampleData", mock.Anything, "test-id").Return(externalData, nil)
+		mockExternalAPI.On("EnrichExample", mock.Anything, "test-id").Return(validEnrichmentData(), nil)
+		mockProducer.On("PublishExampleUpdated", mock.Anything, mock.MatchedBy(func(e *ExampleWithMetadata) bool {
+			return e.Example == example && e.ExternalData == externalData
+		})).Return(nil).Once()
+
+		_, err := useCase.UpdateExample(getTestContext(), "test-id", validUpdateExampleRequest())
+		require.NoError(t, err)
+
+		mockProducer.AssertExpectations(t)
+	})
+
+	t.Run("patch publishes updated event unless empty", func(t *testing.T) {
+		useCase, mockService, mockExternalAPI, mockProducer := newUseCase()
+		example := validExampleWithCustomData("test-id", "John Doe", "john.doe@example.com", 31)
+		age := 31
+
+		mockService.On("GetExampleByID", mock.Anything, "test-id").
+			Return(validExampleWithCustomData("test-id", "John Doe", "john.doe@example.com", 30), nil)
+		mockService.On("PatchExample", mock.Anything, "test-id", (*string)(nil), (*string)(nil), (*string)(nil), &age).Return(example, nil)
+		mockService.On("PatchExample", mock.Anything, "test-id", (*string)(nil), (*string)(nil), (*string)(nil), (*int)(nil)).Return(example, nil)
+		mockExternalAPI.On("GetExampleData", mock.Anything, "test-id").Return(validExternalExampleData(), nil)
+		mockExternalAPI.On("EnrichExample", mock.Anything, "test-id").Return(validEnrichmentData(), nil)
+		mockProducer.On("PublishExampleUpdated", mock.Anything, mock.Anything).Return(nil).Once()
+
+		_, err := useCase.PatchExample(getTestContext(), "test-id", PatchExampleRequest{Age: &age})
+		require.NoError(t, err)
+		_, err = useCase.PatchExample(getTestContext(), "test-id", PatchExampleRequest{})
+		require.NoError(t, err)
+
+		mockProducer.AssertExpectations(t)
+		mockProducer.AssertNumberOfCalls(t, "PublishExampleUpdated", 1)
+	})
+
+	t.Run("update that changes nothing publishes nothing", func(t *testing.T) {
+		useCase, mockService, mockExternalAPI, mockProducer := newUseCase()
+		example := validExampleWithCustomData("test-id", "John Smith", "john.smith@example.com", 31)
+		age := 31
+
+		mockService.On("GetExampleByID", mock.Anything, "test-id").Return(example, nil)
+		mockService.On("UpdateExample", mock.Anything, "test-id", "John Smith", "john.smith@example.com", "", 31, 0).Return(example, nil)
+		mockService.On("PatchExample", mock.Anything, "test-id", (*string)(nil), (*string)(nil), (*string)(nil), &age).Return(example, nil)
+		mockExternalAPI.On("GetExampleData", mock.Anything, "test-id").Return(validExternalExampleData(), nil)
+		mockExternalAPI.On("EnrichExample", mock.Anything, "test-id").Return(validEnrichmentData(), nil)
+
+		_, err := useCase.UpdateExample(getTestContext(), "test-id", validUpdateExampleRequest())
+		require.NoError(t, err)
+		_, err = useCase.PatchExample(getTestContext(), "test-id", PatchExampleRequest{Age: &age})
+		require.NoError(t, err)
+
+		mockProducer.AssertNotCalled(t, "PublishExampleUpdated", mock.Anything, mock.Anything)
+	})
+
+	t.Run("delete publishes deleted event with identifying fields", func(t *testing.T) {
+		useCase, mockService, _, mockProducer := newUseCase()
+		example := validExample()
+
+		mockService.On("GetExampleByID", mock.Anything, example.ID).Return(example, nil)
+		mockService.On("DeleteExample", mock.Anything, example.ID).Return(nil)
+		mockProducer.On("PublishExampleDeleted", mock.Anything, example.ID, example.Email, example.Name).Return(nil).Once()
+
+		require.NoError(t, useCase.DeleteExample(getTestContext(), example.ID))
+
+		mockProducer.AssertExpectations(t)
+	})
+
+	t.Run("bulk delete publishes a deleted event per removed example", func(t *testing.T) {
+		useCase, mockService, _, mockProducer := newUseCase()
+		examples := multipleValidExamples()[:2]
+		ids := []string{examples[0].ID, "missing", examples[1].ID}
+
+		mockService.On("DeleteExamplesByIDs", mock.Anything, ids).Return(examples, []string{"missing"}, nil)
+		for _, example := range examples {
+			mockProducer.On("PublishExampleDeleted", mock.Anything, example.ID, example.Email, example.Name).Return(nil).Once()
+		}
+
+		result, err := useCase.DeleteExamplesByIDs(getTestContext(), ids)
+		require.NoError(t, err)
+		assert.Equal(t, []string{examples[0].ID, examples[1].ID}, result.Deleted)
+		assert.Equal(t, []string{"missing"}, result.NotFound)
+
+		mockProducer.AssertExpectations(t)
+	})
+
+	t.Run("failed write publishes nothing", func(t *testing.T) {
+		useCase, mockService, _, mockProducer := newUseCase()
+		example := validExample()
+
+		mockService.On("GetExampleByID", mock.Anything, example.ID).Return(example, nil)
+		mockService.On("DeleteExample", mock.Anything, example.ID).Return(repository.ErrDatabaseConnection)
+
+		assert.Error(t, useCase.DeleteExample(getTestContext(), example.ID))
+
+		mockProducer.AssertNotCalled(t, "PublishExampleDeleted", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("publish failure is not fatal", func(t *testing.T) {
+		useCase, mockService, mockExternalAPI, mockProducer := newUseCase()
+		example := validExample()
+
+		mockService.On("CreateExample", mock.Anything, "John Doe", "john.doe@example.com", "", 30).Return(example, nil)
+		mockExternalAPI.On("NotifyExampleCreated", mock.Anything, example.ID, example.Email).Return(nil).Maybe()
+		mockProducer.On("PublishExampleCreated", mock.Anything, mock.Anything).Return(errors.New("broker unavailable"))
+
+		result, err := useCase.CreateExample(getTestContext(), validCreateExampleRequest())
+		require.NoError(t, err)
+		assert.NotNil(t, result)
+	})
+}
+
+// auditRecorder keeps every audit entry written to it
+type auditRecorder struct {
+	entries []audit.Entry
+}
+
+func (r *auditRecorder) Write(ctx context.Context, entry audit.Entry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func TestExampleUseCase_AuditsChanges(t *testing.T) {
+	newUseCase := func() (ExampleUseCase, *mocks.MockExampleService, *mocks.MockExternalExampleAPI, *auditRecorder) {
+		mockService := &mocks.MockExampleService{}
+		mockExternalAPI := &mocks.MockExternalExampleAPI{}
+		recorder := &auditRecorder{}
+		useCase := NewExampleUseCase(mockService, mockExternalAPI, zap.NewNop(), WithAuditor(audit.New(recorder)))
+		return useCase, mockService, mockExternalAPI, recorder
+	}
+	ctx := ctxkeys.WithUserID(getTestContext(), "user-42")
+
+	t.Run("update records before, after and the acting user", func(t *testing.T) {
+		useCase, mockService, mockExternalAPI, recorder := newUseCase()
+		before := validExampleWithCustomData("test-id", "John Doe", "john.doe@example.com", 30)
+		after := validExampleWithCustomData("test-id", "John Smith", "john.smith@example.com", 31)
+
+		mockService.On("GetExampleByID", mock.Anything, "test-id").Return(before, nil)
+		mockService.On("UpdateExample", mock.Anything, "test-id", "John Smith", "john.smith@example.com", "", 31, 0).Return(after, nil)
+		mockExternalAPI.On("GetExampleData", mock.Anything, "test-id").Return(validExternalExampleData(), nil)
+		mockExternalAPI.On("EnrichExample", mock.Anything, "test-id").Return(validEnrichmentData(), nil)
+
+		_, err := useCase.UpdateExample(ctx, "test-id", validUpdateExampleRequest())
+		require.NoError(t, err)
+
+		require.Len(t, recorder.entries, 1)
+		entry := recorder.entries[0]
+		assert.Equal(t, audit.ActionUpdate, entry.Action)
+		assert.Equal(t, "test-id", entry.ResourceID)
+		assert.Equal(t, "user-42", entry.UserID)
+		assert.Equal(t, audit.Change{From: "John Doe", To: "John Smith"}, entry.Changes["name"])
+		assert.Equal(t, audit.Change{From: "john.doe@example.com", To: "john.smith@example.com"}, entry.Changes["email"])
+		assert.Equal(t, audit.Change{From: float64(30), To: float64(31)}, entry.Changes["age"])
+		assert.NotContains(t, entry.Changes, "updated_at", "bookkeeping fields are left out of the changes")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("update that changes nothing is not audited", func(t *testing.T) {
+		useCase, mockService, mockExternalAPI, recorder := newUseCase()
+		example := validExampleWithCustomData("test-id", "John Smith", "john.smith@example.com", 31)
+
+		mockService.On("GetExampleByID", mock.Anything, "test-id").Return(example, nil)
+		mockService.On("UpdateExample", mock.Anything, "test-id", "John Smith", "john.smith@example.com", "", 31, 0).Return(example, nil)
+		mockExternalAPI.On("GetExampleData", mock.Anything, "test-id").Return(validExternalExampleData(), nil)
+		mockExternalAPI.On("EnrichExample", mock.Anything, "test-id").Return(validEnrichmentData(), nil)
+
+		_, err := useCase.UpdateExample(ctx, "test-id", validUpdateExampleRequest())
+		require.NoError(t, err)
+		assert.Empty(t, recorder.entries)
+	})
+
+	t.Run("create and delete are audited", func(t *testing.T) {
+		useCase, mockService, mockExternalAPI, recorder := newUseCase()
+		example := validExample()
+
+		mockService.On("CreateExample", mock.Anything, "John Doe", "john.doe@example.com", "", 30).Return(example, nil)
+		mockExternalAPI.On("NotifyExampleCreated", mock.Anything, example.ID, example.Email).Return(nil).Maybe()
+		mockService.On("GetExampleByID", mock.Anything, example.ID).Return(example, nil)
+		mockService.On("DeleteExample", mock.Anything, example.ID).Return(nil)
+
+		_, err := useCase.CreateExample(ctx, validCreateExampleRequest())
+		require.NoError(t, err)
+		require.NoError(t, useCase.DeleteExample(ctx, example.ID))
+
+		require.Len(t, recorder.entries, 2)
+		assert.Equal(t, audit.ActionCreate, recorder.entries[0].Action)
+		assert.Nil(t, recorder.entries[0].Before)
+		assert.Equal(t, audit.ActionDelete, recorder.entries[1].Action)
+		assert.Nil(t, recorder.entries[1].After)
+	})
+
+	t.Run("failed write is not audited", func(t *testing.T) {
+		useCase, mockService, _, recorder := newUseCase()
+		before := validExampleWithCustomData("test-id", "John Doe", "john.doe@example.com", 30)
+
+		mockService.On("GetExampleByID", mock.Anything, "test-id").Return(before, nil)
+		mockService.On("UpdateExample", mock.Anything, "test-id", "John Smith", "john.smith@example.com", "", 31, 0).
+			Return(nil, repository.ErrVersionConflict)
+
+		_, err := useCase.UpdateExample(ctx, "test-id", validUpdateExampleRequest())
+		require.Error(t, err)
+		assert.Empty(t, recorder.entries)
+	})
+}
+
+func TestExampleUseCase_PropagatesRequestID(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	mockService := &mocks.MockExampleService{}
+	mockExternalAPI := &mocks.MockExternalExampleAPI{}
+	mockProducer := &MockProducer{}
+	useCase := NewExampleUseCase(mockService, mockExternalAPI, zap.New(core), WithEventPublisher(mockProducer))
+
+	example := validExampleWithCustomData("test-id", "John Smith", "john.smith@example.com", 31)
+	mockService.On("GetExampleByID", mock.Anything, "test-id").
+		Return(validExampleWithCustomData("test-id", "John Doe", "john.doe@example.com", 30), nil)
+	mockService.On("UpdateExample", mock.Anything, "test-id", "John Smith", "john.smith@example.com", "", 31, 0).Return(example, nil)
+	mockExternalAPI.On("GetExampleData", mock.Anything, "test-id").Return(validExternalExampleData(), nil)
+	mockExternalAPI.On("EnrichExample", mock.Anything, "test-id").Return(validEnrichmentData(), nil)
+	mockProducer.On("PublishExampleUpdated", mock.MatchedBy(func(ctx context.Context) bool {
+		return logger.RequestID(ctx) == "req-123"
+	}), mock.Anything).Return(nil).Once()
+
+	ctx := ctxkeys.WithRequestID(getTestContext(), "req-123")
+	_, err := useCase.UpdateExample(ctx, "test-id", validUpdateExampleRequest())
+	require.NoError(t, err)
+	mockProducer.AssertExpectations(t)
+
+	entries := logs.FilterMessage("Updating example via use case").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "req-123", entries[0].ContextMap()["request_id"])
+}
+
+func TestExampleUseCase_CircuitBreaker(t *testing.T) {
+	t.Run("opens after consecutive failures and fails fast", func(t *testing.T) {
+		mockService := &mocks.MockExampleService{}
+		mockExternalAPI := &mocks.MockExternalExampleAPI{}
+		useCase := NewExampleUseCase(mockService, mockExternalAPI, zap.NewNop(), WithCircuitBreaker(3, time.Minute))
+
+		mockExternalAPI.On("ValidateExample", mock.Anything, "John Doe", "john.doe@example.com", 30).
+			Return(false, repository.ErrExternalAPIUnavailable)
+
+		for i := 0; i < 3; i++ {
+			_, err := useCase.ValidateAndCreateExample(getTestContext(), validCreateExampleRequest())
+			require.ErrorIs(t, err, ErrExternalService)
+			assert.NotErrorIs(t, err, gobreaker.ErrOpenState)
+		}
+
+		// The breaker is open: validation fails without calling the external API
+		_, err := useCase.ValidateAndCreateExample(getTestContext(), validCreateExampleRequest())
+		require.ErrorIs(t, err, ErrExternalService)
+		assert.ErrorIs(t, err, gobreaker.ErrOpenState)
+		mockExternalAPI.AssertNumberOfCalls(t, "ValidateExample", 3)
+
+		// Reads still succeed, just without enrichment
+		example := validExampleWithCustomData("test-id", "John Doe", "john@example.com", 30)
+		mockService.On("GetExampleByID", mock.Anything, "test-id").Return(example, nil)
+
+		result, err := useCase.GetExample(getTestContext(), "test-id")
+		require.NoError(t, err)
+		assert.Equal(t, example, result.Example)
+		assert.Nil(t, result.ExternalData)
+		assert.Nil(t, result.Enrichment)
+		assert.Equal(t, EnrichmentSkipped, result.EnrichmentStatus)
+		mockExternalAPI.AssertNotCalled(t, "GetExampleData", mock.Anything, mock.Anything)
+		mockExternalAPI.AssertNotCalled(t, "EnrichExample", mock.Anything, mock.Anything)
+	})
+
+	t.Run("closes again after a successful trial call", func(t *testing.T) {
+		mockService := &mocks.MockExampleService{}
+		mockExternalAPI := &mocks.MockExternalExampleAPI{}
+		useCase := NewExampleUseCase(mockService, mockExternalAPI, zap.NewNop(), WithCircuitBreaker(1, 20*time.Millisecond))
+
+		mockExternalAPI.On("ValidateExample", mock.Anything, "John Doe", "john.doe@example.com", 30).
+			Return(false, repository.ErrExternalAPIUnavailable).Once()
+		mockExternalAPI.On("ValidateExample", mock.Anything, "John Doe", "john.doe@example.com", 30).
+			Return(false, nil)
+
+		_, err := useCase.ValidateAndCreateExample(getTestContext(), validCreateExampleRequest())
+		require.ErrorIs(t, err, ErrExternalService)
+
+		_, err = useCase.ValidateAndCreateExample(getTestContext(), validCreateExampleRequest())
+		require.ErrorIs(t, err, gobreaker.ErrOpenState)
+
+		// After the open duration the next call is let through and its outcome is used
+		time.Sleep(50 * time.Millisecond)
+		_, err = useCase.ValidateAndCreateExample(getTestContext(), validCreateExampleRequest())
+		require.ErrorIs(t, err, ErrUseCaseValidation)
+		mockExternalAPI.AssertNumberOfCalls(t, "ValidateExample", 2)
+	})
+}
+
+func TestExampleUseCase_EnrichmentDisabled(t *testing.T) {
+	mockService := &mocks.MockExampleService{}
+	mockExternalAPI := &mocks.MockExternalExampleAPI{}
+	useCase := NewExampleUseCase(mockService, mockExternalAPI, zap.NewNop(), WithEnrichmentDisabled())
+
+	example := validExample()
+	mockService.On("GetExampleByID", mock.Anything, example.ID).Return(example, nil)
+	mockService.On("ListExamples", mock.Anything, 10, 0, domain.ExampleSort{}, repository.ExampleFilter{}).
+		Return(multipleValidExamples(), 3, nil)
+	mockService.On("CreateExample", mock.Anything, "John Doe", "john.doe@example.com", "", 30).Return(example, nil)
+	mockExternalAPI.On("ValidateExample", mock.Anything, "John Doe", "john.doe@example.com", 30).Return(true, nil)
+	mockExternalAPI.On("NotifyExampleCreated", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	result, err := useCase.GetExample(getTestContext(), example.ID)
+	require.NoError(t, err)
+	assert.Equal(t, example, result.Example)
+	assert.Nil(t, result.ExternalData)
+	assert.Nil(t, result.Enrichment)
+	assert.Equal(t, EnrichmentSkipped, result.EnrichmentStatus)
+
+	list, err := useCase.ListExamples(getTestContext(), ListExamplesRequest{Limit: 10, Include: IncludeAll})
+	require.NoError(t, err)
+	require.Len(t, list.Examples, 3)
+	for _, item := range list.Examples {
+		assert.Equal(t, EnrichmentSkipped, item.EnrichmentStatus)
+	}
+
+	// External validation still runs
+	created, err := useCase.ValidateAndCreateExample(getTestContext(), validCreateExampleRequest())
+	require.NoError(t, err)
+	assert.Equal(t, EnrichmentSkipped, created.EnrichmentStatus)
+	mockExternalAPI.AssertNumberOfCalls(t, "ValidateExample", 1)
+
+	mockExternalAPI.AssertNotCalled(t, "GetExampleData", mock.Anything, mock.Anything)
+	mockExternalAPI.AssertNotCalled(t, "EnrichExample", mock.Anything, mock.Anything)
+}
+
+func TestExampleUseCase_ListExamples_BoundedConcurrentEnrichment(t *testing.T) {
+	const concurrency = 4
+
+	examples := make([]*domain.Example, 20)
+	for i := range examples {
+		examples[i] = validExampleWithCustomData(
+			fmt.Sprintf("ex_%03d", i), "User", fmt.Sprintf("user%d@example.com", i), 30)
+	}
+
+	mockService := &mocks.MockExampleService{}
+	mockExternalAPI := &mocks.MockExternalExampleAPI{}
+	useCase := NewExampleUseCase(mockService, mockExternalAPI, zap.NewNop(), WithEnrichmentConcurrency(concurrency))
+
+	mockService.On("ListExamples", mock.Anything, 20, 0, domain.ExampleSort{}, repository.ExampleFilter{}).Return(examples, len(examples), nil)
+
+	// Each enrichment makes one GetExampleData call, so in-flight calls show how many examples are enriched at once
+	var inFlight, maxInFlight atomic.Int32
+	mockExternalAPI.On("GetExampleData", mock.Anything, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				observed := maxInFlight.Load()
+				if current <= observed || maxInFlight.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}).
+		Return(validExternalExampleData(), nil)
+	// Every fifth example fails enrichment and falls back to the bare example
+	mockExternalAPI.On("EnrichExample", mock.Anything, mock.MatchedBy(func(id string) bool {
+		return strings.HasSuffix(id, "0") || strings.HasSuffix(id, "5")
+	})).Return(nil, repository.ErrExternalAPIUnavailable)
+	mockExternalAPI.On("EnrichExample", mock.Anything, mock.AnythingOfType("string")).Return(validEnrichmentData(), nil)
+
+	result, err := useCase.ListExamples(getTestContext(), ListExamplesRequest{Limit: 20, Include: IncludeAll})
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(concurrency))
+	assert.Greater(t, maxInFlight.Load(), int32(1), "enrichment should run in parallel")
+
+	require.Len(t, result.Examples, len(examples))
+	for i, enriched := range result.Examples {
+		assert.Equal(t, examples[i], enriched.Example, "results must keep the listing order")
+		assert.NotNil(t, enriched.ExternalData)
+		if i%5 == 0 {
+			assert.Nil(t, enriched.Enrichment)
+		} else {
+			assert.NotNil(t, enriched.Enrichment)
+		}
+	}
+	mockExternalAPI.AssertNumberOfCalls(t, "GetExampleData", len(examples))
+}
+
+func TestExampleUseCase_ListExamples_StopsEnrichingWhenContextDone(t *testing.T) {
+	examples := multipleValidExamples()
+
+	mockService := &mocks.MockExampleService{}
+	mockExternalAPI := &mocks.MockExternalExampleAPI{}
+	useCase := NewExampleUseCase(mockService, mockExternalAPI, zap.NewNop(), WithEnrichmentConcurrency(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockService.On("ListExamples", mock.Anything, 10, 0, domain.ExampleSort{}, repository.ExampleFilter{}).Return(examples, len(examples), nil)
+	// The first enrichment cancels the request, so the remaining examples are never sent out
+	mockExternalAPI.On("GetExampleData", mock.Anything, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { cancel() }).
+		Return(nil, context.Canceled)
+	mockExternalAPI.On("EnrichExample", mock.Anything, mock.AnythingOfType("string")).
+		Return(nil, context.Canceled)
+
+	result, err := useCase.ListExamples(ctx, ListExamplesRequest{Include: IncludeAll})
+	require.NoError(t, err)
+
+	require.Len(t, result.Examples, len(examples))
+	for i, enriched := range result.Examples {
+		assert.Equal(t, examples[i], enriched.Example)
+		assert.Nil(t, enriched.ExternalData)
+	}
+	mockExternalAPI.AssertNumberOfCalls(t, "GetExampleData", 1)
+}
+
+func TestExampleUseCase_ListExamplesPagination(t *testing.T) {
+	mockService := &mocks.MockExampleService{}
+	useCase := NewExampleUseCaseWithConfig(mockService, &mocks.MockExternalExampleAPI{}, zap.NewNop(),
+		UseCaseConfig{Pagination: domain.Pagination{DefaultLimit: 20, MaxLimit: 50}})
+
+	mockService.On("ListExamples", mock.Anything, 50, 0, domain.ExampleSort{}, repository.ExampleFilter{}).
+		Return(multipleValidExamples(), 3, nil)
+
+	result, err := useCase.ListExamples(getTestContext(), ListExamplesRequest{Limit: 200})
+	require.NoError(t, err)
+	assert.Equal(t, 50, result.Limit)
+	mockService.AssertExpectations(t)
+}
+
+func TestExampleUseCase_CreateExampleTransactional(t *testing.T) {
+	// newTransactionalUseCase returns a use case creating examples in an SQLite database with
+	// an outbox, along with the database
+	newTransactionalUseCase := func(t *testing.T, opts ...Option) (ExampleUseCase, *repository.PostgreSQLExampleRepository, *gorm.DB) {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		require.NoError(t, err)
+
+		// Every connection to ":memory:" opens a fresh database, so keep to one
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		sqlDB.SetMaxOpenConns(1)
+		t.Cleanup(func() { sqlDB.Close() })
+
+		repo := repository.NewPostgreSQLExampleRepository(db).WithOutbox()
+		require.NoError(t, repo.AutoMigrate())
+
+		newService := func(repo repository.ExampleRepository) service.ExampleService {
+			return service.NewExampleService(repo, zap.NewNop(), service.DefaultBusinessRules())
+		}
+		useCase := NewExampleUseCase(newService(repo), repository.NewMockExternalExampleAPI(false, 0), zap.NewNop(),
+			append([]Option{WithTransactor(repo, newService)}, opts...)...)
+		return useCase, repo, db
+	}
+
+	t.Run("example and event are saved together", func(t *testing.T) {
+		useCase, repo, _ := newTransactionalUseCase(t)
+
+		result, err := useCase.CreateExampleTransactional(getTestContext(), validCreateExampleRequest())
+		require.NoError(t, err)
+
+		stored, err := repo.GetByID(context.Background(), result.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "john.doe@example.com", stored.Email)
+
+		events, err := repo.UnpublishedOutboxEvents(context.Background(), 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1, "the repository doesn't record a second event inside the transaction")
+		assert.Equal(t, domain.EventTypeExampleCreated, events[0].Type)
+		eventExample, err := events[0].Example()
+		require.NoError(t, err)
+		assert.Equal(t, result.ID, eventExample.ID)
+	})
+
+	t.Run("failed outbox insert rolls back the example", func(t *testing.T) {
+		useCase, repo, db := newTransactionalUseCase(t)
+		require.NoError(t, db.Migrator().DropTable(&domain.OutboxEvent{}))
+
+		result, err := useCase.CreateExampleTransactional(getTestContext(), validCreateExampleRequest())
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "failed to save outbox event")
+
+		count, err := repo.Count(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("failed create saves no event", func(t *testing.T) {
+		useCase, repo, _ := newTransactionalUseCase(t)
+		_, err := useCase.CreateExampleTransactional(getTestContext(), validCreateExampleRequest())
+		require.NoError(t, err)
+
+		_, err = useCase.CreateExampleTransactional(getTestContext(), validCreateExampleRequest())
+		require.Error(t, err)
+
+		events, err := repo.UnpublishedOutboxEvents(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+
+	t.Run("validated create goes through the transaction", func(t *testing.T) {
+		mockProducer := &MockProducer{}
+		useCase, repo, _ := newTransactionalUseCase(t, WithEventPublisher(mockProducer))
+
+		result, err := useCase.ValidateAndCreateExample(getTestContext(), validCreateExampleRequest())
+		require.NoError(t, err)
+
+		events, err := repo.UnpublishedOutboxEvents(context.Background(), 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, domain.EventTypeExampleCreated, events[0].Type)
+		eventExample, err := events[0].Example()
+		require.NoError(t, err)
+		assert.Equal(t, result.ID, eventExample.ID)
+		mockProducer.AssertNotCalled(t, "PublishExampleCreated", mock.Anything, mock.Anything)
+	})
+
+	t.Run("without a transactor", func(t *testing.T) {
+		useCase := NewExampleUseCase(&mocks.MockExampleService{}, &mocks.MockExternalExampleAPI{}, zap.NewNop())
+
+		_, err := useCase.CreateExampleTransactional(getTestContext(), validCreateExampleRequest())
+		assert.ErrorIs(t, err, ErrTransactionsDisabled)
+		var appErr *errs.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, errs.ErrorCodeServiceUnavailable, appErr.Code)
+	})
+}
@@ -0,0 +1,32 @@
+// Package buildinfo holds build metadata injected at link time, for example:
+//
+//	go build -ldflags "-X example-api-template/pkg/buildinfo.GitCommit=$(git rev-parse HEAD) \
+//	  -X example-api-template/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+package buildinfo
+
+// Unknown is reported for metadata that was not set at build time
+const Unknown = "unknown"
+
+// Set with -ldflags "-X"; empty in builds that do not set them, such as go run
+var (
+	GitCommit string
+	BuildTime string
+)
+
+// Commit returns the git commit the binary was built from, or Unknown
+func Commit() string {
+	return orUnknown(GitCommit)
+}
+
+// Time returns when the binary was built, or Unknown
+func Time() string {
+	return orUnknown(BuildTime)
+}
+
+// orUnknown returns value, or Unknown when it is empty
+func orUnknown(value string) string {
+	if value == "" {
+		return Unknown
+	}
+	return value
+}
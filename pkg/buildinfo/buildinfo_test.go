@@ -0,0 +1,20 @@
+package buildinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildInfo(t *testing.T) {
+	commit, buildTime := GitCommit, BuildTime
+	t.Cleanup(func() { GitCommit, BuildTime = commit, buildTime })
+
+	GitCommit, BuildTime = "", ""
+	assert.Equal(t, Unknown, Commit())
+	assert.Equal(t, Unknown, Time())
+
+	GitCommit, BuildTime = "abc123", "2024-01-02T03:04:05Z"
+	assert.Equal(t, "abc123", Commit())
+	assert.Equal(t, "2024-01-02T03:04:05Z", Time())
+}
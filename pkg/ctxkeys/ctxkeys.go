@@ -0,0 +1,108 @@
+// Package ctxkeys stores request and message scoped values in a context.Context under
+// unexported typed keys, so packages can't collide on them. Each value has a With setter
+// and a getter returning the zero value when the context doesn't carry it.
+package ctxkeys
+
+import "context"
+
+// key is the type of every context key in this package
+type key int
+
+const (
+	userIDKey key = iota
+	requestIDKey
+	traceIDKey
+	messageIDKey
+	routingKeyKey
+	deliveryTagKey
+	languageKey
+	eventIDKey
+)
+
+// WithUserID returns ctx carrying the authenticated user ID
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserID returns the authenticated user ID carried by ctx, or ""
+func UserID(ctx context.Context) string {
+	return stringValue(ctx, userIDKey)
+}
+
+// WithRequestID returns ctx carrying the HTTP request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the HTTP request ID carried by ctx, or ""
+func RequestID(ctx context.Context) string {
+	return stringValue(ctx, requestIDKey)
+}
+
+// WithTraceID returns ctx carrying the trace ID of the request
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey, traceID)
+}
+
+// TraceID returns the trace ID carried by ctx, or ""
+func TraceID(ctx context.Context) string {
+	return stringValue(ctx, traceIDKey)
+}
+
+// WithMessageID returns ctx carrying the ID of the message being consumed
+func WithMessageID(ctx context.Context, messageID string) context.Context {
+	return context.WithValue(ctx, messageIDKey, messageID)
+}
+
+// MessageID returns the ID of the message being consumed, or ""
+func MessageID(ctx context.Context) string {
+	return stringValue(ctx, messageIDKey)
+}
+
+// WithRoutingKey returns ctx carrying the routing key or subject of the message being consumed
+func WithRoutingKey(ctx context.Context, routingKey string) context.Context {
+	return context.WithValue(ctx, routingKeyKey, routingKey)
+}
+
+// RoutingKey returns the routing key or subject of the message being consumed, or ""
+func RoutingKey(ctx context.Context) string {
+	return stringValue(ctx, routingKeyKey)
+}
+
+// WithDeliveryTag returns ctx carrying the AMQP delivery tag of the message being consumed
+func WithDeliveryTag(ctx context.Context, tag uint64) context.Context {
+	return context.WithValue(ctx, deliveryTagKey, tag)
+}
+
+// DeliveryTag returns the AMQP delivery tag carried by ctx and whether there is one
+func DeliveryTag(ctx context.Context) (uint64, bool) {
+	tag, ok := ctx.Value(deliveryTagKey).(uint64)
+	return tag, ok
+}
+
+// WithLanguage returns ctx carrying the language responses are localized to
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey, lang)
+}
+
+// Language returns the language carried by ctx, or ""
+func Language(ctx context.Context) string {
+	return stringValue(ctx, languageKey)
+}
+
+// WithEventID returns ctx carrying the ID an event published under it must use, e.g. the
+// ID of the outbox event it relays, so every publish of that event carries the same ID
+func WithEventID(ctx context.Context, eventID string) context.Context {
+	return context.WithValue(ctx, eventIDKey, eventID)
+}
+
+// EventID returns the event ID carried by ctx, or ""
+func EventID(ctx context.Context) string {
+	return stringValue(ctx, eventIDKey)
+}
+
+// stringValue returns the string stored under k, or ""
+func stringValue(ctx context.Context, k key) string {
+	value, _ := ctx.Value(k).(string)
+	return value
+}
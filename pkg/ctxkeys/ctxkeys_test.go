@@ -0,0 +1,54 @@
+package ctxkeys
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringValues(t *testing.T) {
+	tests := []struct {
+		name string
+		with func(context.Context, string) context.Context
+		get  func(context.Context) string
+	}{
+		{"user ID", WithUserID, UserID},
+		{"request ID", WithRequestID, RequestID},
+		{"trace ID", WithTraceID, TraceID},
+		{"message ID", WithMessageID, MessageID},
+		{"routing key", WithRoutingKey, RoutingKey},
+		{"language", WithLanguage, Language},
+		{"event ID", WithEventID, EventID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Empty(t, tt.get(context.Background()), "missing value")
+			assert.Equal(t, "value", tt.get(tt.with(context.Background(), "value")))
+		})
+	}
+
+	t.Run("keys don't collide", func(t *testing.T) {
+		ctx := WithUserID(context.Background(), "user-42")
+		ctx = WithRequestID(ctx, "req-123")
+
+		assert.Equal(t, "user-42", UserID(ctx))
+		assert.Equal(t, "req-123", RequestID(ctx))
+		assert.Empty(t, TraceID(ctx))
+	})
+
+	t.Run("untyped string keys are ignored", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), "user_id", "user-42")
+		assert.Empty(t, UserID(ctx))
+	})
+}
+
+func TestDeliveryTag(t *testing.T) {
+	_, ok := DeliveryTag(context.Background())
+	assert.False(t, ok)
+
+	tag, ok := DeliveryTag(WithDeliveryTag(context.Background(), 7))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7), tag)
+}
@@ -0,0 +1,23 @@
+package database
+
+import (
+	"example-api-template/internal/config"
+	"example-api-template/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Migrator creates or updates a database schema
+type Migrator interface {
+	AutoMigrate() error
+}
+
+// MigrateSchema migrates the schema of m, named by what, unless cfg.AutoMigrate is off
+// because migrations are managed externally
+func MigrateSchema(cfg *config.DatabaseConfig, m Migrator, what string, logger *logger.Logger) error {
+	if !cfg.AutoMigrate {
+		logger.Info("Skipping database migration, the schema is managed externally", zap.String("schema", what))
+		return nil
+	}
+	return m.AutoMigrate()
+}
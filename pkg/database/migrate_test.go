@@ -0,0 +1,42 @@
+package database
+
+import (
+	"testing"
+
+	"example-api-template/internal/config"
+	"example-api-template/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// countingMigrator counts the migrations it is asked to run
+type countingMigrator struct {
+	runs int
+}
+
+func (m *countingMigrator) AutoMigrate() error {
+	m.runs++
+	return nil
+}
+
+func TestMigrateSchema(t *testing.T) {
+	log := &logger.Logger{Logger: zap.NewNop()}
+
+	t.Run("migrates by default", func(t *testing.T) {
+		m := &countingMigrator{}
+		cfg := &config.DatabaseConfig{AutoMigrate: true}
+
+		require.NoError(t, MigrateSchema(cfg, m, "examples", log))
+		assert.Equal(t, 1, m.runs)
+	})
+
+	t.Run("skips when migrations are managed externally", func(t *testing.T) {
+		m := &countingMigrator{}
+		cfg := &config.DatabaseConfig{AutoMigrate: false}
+
+		require.NoError(t, MigrateSchema(cfg, m, "examples", log))
+		assert.Zero(t, m.runs)
+	})
+}
@@ -1,23 +1,30 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"example-api-template/internal/config"
 	"example-api-template/pkg/logger"
+	"example-api-template/pkg/metrics"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
 // PostgreSQLConnection holds the database connection and configuration
 type PostgreSQLConnection struct {
-	DB     *gorm.DB
-	Config *config.DatabaseConfig
-	Logger *logger.Logger
+	DB       *gorm.DB
+	Replicas []*sql.DB // Read replica pools that DB routes reads to
+	Config   *config.DatabaseConfig
+	Logger   *logger.Logger
 }
 
 // NewPostgreSQLConnection creates a new PostgreSQL database connection
@@ -33,14 +40,9 @@ func NewPostgreSQLConnection(cfg *config.DatabaseConfig, logger *logger.Logger)
 	// Build DSN (Data Source Name)
 	dsn := buildPostgresDSN(cfg)
 
-	// Configure GORM logger
-	gormLogLevel := gormlogger.Silent
-	// Enable GORM logging for debug builds or when explicitly requested
-	// We'll keep it simple and use Silent by default for production
-	gormLogLevel = gormlogger.Warn
-
 	gormConfig := &gorm.Config{
-		Logger: gormlogger.Default.LogMode(gormLogLevel),
+		// Route GORM logs through zap and report slow queries
+		Logger: NewQueryLogger(logger, cfg.SlowQueryThreshold, nil),
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
@@ -64,6 +66,18 @@ func NewPostgreSQLConnection(cfg *config.DatabaseConfig, logger *logger.Logger)
 	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
+	// Send reads to replicas when configured; writes and transactions stay on the primary
+	replicaPools, replicas, err := openReplicas(cfg.Replicas)
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	if err := RegisterReplicas(db, replicas...); err != nil {
+		sqlDB.Close()
+		closePools(replicaPools)
+		return nil, err
+	}
+
 	logger.Info("Successfully connected to PostgreSQL database",
 		zap.String("host", cfg.Host),
 		zap.Int("port", cfg.Port),
@@ -72,15 +86,97 @@ func NewPostgreSQLConnection(cfg *config.DatabaseConfig, logger *logger.Logger)
 		zap.Int("max_connections", cfg.MaxConnections),
 		zap.Int("max_idle_conns", cfg.MaxIdleConns),
 		zap.Duration("conn_max_lifetime", cfg.ConnMaxLifetime),
+		zap.Int("replicas", len(replicas)),
+		zap.Duration("slow_query_threshold", cfg.SlowQueryThreshold),
 	)
 
 	return &PostgreSQLConnection{
-		DB:     db,
-		Config: cfg,
-		Logger: logger,
+		DB:       db,
+		Replicas: replicaPools,
+		Config:   cfg,
+		Logger:   logger,
 	}, nil
 }
 
+// InstrumentQueries records the duration of every query on m from now on
+func (c *PostgreSQLConnection) InstrumentQueries(m *metrics.Metrics) {
+	c.DB.Logger = NewQueryLogger(c.Logger, c.Config.SlowQueryThreshold, m)
+}
+
+// WarmUp opens up to MaxIdleConns connections and returns them to the pool, so the first
+// requests after startup don't pay for connecting. It returns how many connections it opened.
+func (c *PostgreSQLConnection) WarmUp(ctx context.Context) (int, error) {
+	sqlDB, err := c.DB.DB()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	opened, err := warmUpPool(ctx, sqlDB, c.Config.MaxIdleConns)
+	if err != nil {
+		return opened, fmt.Errorf("failed to warm up connection pool: %w", err)
+	}
+
+	c.Logger.Info("Database connection pool warmed up", zap.Int("connections", opened))
+	return opened, nil
+}
+
+// warmUpPool holds n connections open at once, so the pool has to dial each of them, then
+// releases them to the idle pool
+func warmUpPool(ctx context.Context, sqlDB *sql.DB, n int) (int, error) {
+	conns := make([]*sql.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	for len(conns) < n {
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			return len(conns), err
+		}
+		conns = append(conns, conn)
+		if err := conn.PingContext(ctx); err != nil {
+			return len(conns) - 1, err
+		}
+	}
+	return len(conns), nil
+}
+
+// ReportPoolStats records the statistics of the primary and replica connection pools on m
+// every interval until ctx is done
+func (c *PostgreSQLConnection) ReportPoolStats(ctx context.Context, m *metrics.Metrics, interval time.Duration) {
+	sqlDB, err := c.DB.DB()
+	if err != nil {
+		c.Logger.Error("Failed to get underlying sql.DB for pool stats", zap.Error(err))
+		return
+	}
+
+	pools := map[string]*sql.DB{metrics.PoolPrimary: sqlDB}
+	for i, replica := range c.Replicas {
+		pools[metrics.ReplicaPool(i)] = replica
+	}
+	// Wait counts and durations are totals, so only the growth since the last snapshot is added
+	previous := make(map[string]sql.DBStats, len(pools))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for name, pool := range pools {
+			stats := pool.Stats()
+			m.ObserveDatabasePool(name, stats, previous[name])
+			previous[name] = stats
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // Close closes the database connection
 func (c *PostgreSQLConnection) Close() error {
 	if c.DB != nil {
@@ -89,6 +185,7 @@ func (c *PostgreSQLConnection) Close() error {
 			return fmt.Errorf("failed to get underlying sql.DB: %w", err)
 		}
 
+		closePools(c.Replicas)
 		if err := sqlDB.Close(); err != nil {
 			return fmt.Errorf("failed to close database connection: %w", err)
 		}
@@ -174,7 +271,7 @@ func (c *PostgreSQLConnection) Migrate(models ...interface{}) error {
 
 	c.Logger.Info("Starting database migration", zap.Int("models_count", len(models)))
 
-	if err := c.DB.AutoMigrate(models...); err != nil {
+	if err := c.DB.Clauses(dbresolver.Write).AutoMigrate(models...); err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
 
@@ -196,20 +293,62 @@ func buildPostgresDSN(cfg *config.DatabaseConfig) string {
 	return dsn
 }
 
+// ConnectionError is returned by TestConnection when it gives up. Fatal errors, such as bad
+// credentials or a missing database, stop the retries at once because retrying can't fix them.
+type ConnectionError struct {
+	Fatal    bool
+	Attempts int
+	Err      error
+}
+
+func (e *ConnectionError) Error() string {
+	if e.Fatal {
+		return fmt.Sprintf("failed to connect to database (fatal, after %d attempts): %v", e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("failed to connect to database after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// isFatalConnectionError reports whether err is a server answer that retrying can't change:
+// invalid authorization (SQLSTATE class 28) or a database that does not exist (3D000).
+// Anything else, such as refused connections or timeouts, is assumed transient.
+func isFatalConnectionError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return strings.HasPrefix(pgErr.Code, "28") || pgErr.Code == "3D000"
+}
+
 // TestConnection tests the database connection with retry logic
 func TestConnection(cfg *config.DatabaseConfig, logger *logger.Logger, maxRetries int, retryDelay time.Duration) (*PostgreSQLConnection, error) {
-	var conn *PostgreSQLConnection
+	return connectWithRetry(func() (*PostgreSQLConnection, error) {
+		return NewPostgreSQLConnection(cfg, logger)
+	}, logger, maxRetries, retryDelay)
+}
+
+// connectWithRetry calls connect until it returns a healthy connection, a fatal error, or
+// maxRetries attempts have failed
+func connectWithRetry(connect func() (*PostgreSQLConnection, error), logger *logger.Logger, maxRetries int, retryDelay time.Duration) (*PostgreSQLConnection, error) {
 	var err error
 
 	for i := 0; i < maxRetries; i++ {
-		conn, err = NewPostgreSQLConnection(cfg, logger)
+		var conn *PostgreSQLConnection
+		conn, err = connect()
 		if err == nil {
-			if err := conn.HealthCheck(); err == nil {
+			if err = conn.HealthCheck(); err == nil {
 				return conn, nil
 			}
 			conn.Close()
 		}
 
+		if isFatalConnectionError(err) {
+			return nil, &ConnectionError{Fatal: true, Attempts: i + 1, Err: err}
+		}
+
 		if i < maxRetries-1 {
 			logger.Warn("Database connection attempt failed, retrying",
 				zap.Int("attempt", i+1),
@@ -221,5 +360,5 @@ func TestConnection(cfg *config.DatabaseConfig, logger *logger.Logger, maxRetrie
 		}
 	}
 
-	return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", maxRetries, err)
+	return nil, &ConnectionError{Attempts: maxRetries, Err: err}
 }
@@ -1,16 +1,27 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"example-api-template/internal/config"
 	"example-api-template/internal/domain"
 	"example-api-template/pkg/logger"
+	"example-api-template/pkg/metrics"
 
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 // TestBuildPostgresDSN tests the DSN building function
@@ -134,10 +145,118 @@ func TestTestConnection(t *testing.T) {
 	assert.Nil(t, conn)
 	assert.Contains(t, err.Error(), "failed to connect to database after 2 attempts")
 
+	// An unknown host may resolve later, so it is not fatal
+	var connErr *ConnectionError
+	require.ErrorAs(t, err, &connErr)
+	assert.False(t, connErr.Fatal)
+
 	// Should have waited at least one retry delay
 	assert.GreaterOrEqual(t, elapsed, 100*time.Millisecond)
 }
 
+// TestWarmUpAndPoolStats tests that warm-up fills the idle pool and the metrics follow Stats()
+// for the primary and replica pools
+func TestWarmUpAndPoolStats(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "pool.db")), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	replica, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "replica.db")), &gorm.Config{})
+	require.NoError(t, err)
+	replicaDB, err := replica.DB()
+	require.NoError(t, err)
+	defer replicaDB.Close()
+	replicaDB.SetMaxOpenConns(3)
+
+	cfg := &config.DatabaseConfig{MaxConnections: 10, MaxIdleConns: 4}
+	sqlDB.SetMaxOpenConns(cfg.MaxConnections)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	conn := &PostgreSQLConnection{DB: db, Replicas: []*sql.DB{replicaDB}, Config: cfg, Logger: &logger.Logger{Logger: zap.NewNop()}}
+
+	before := sqlDB.Stats().Idle
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	opened, err := conn.WarmUp(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.MaxIdleConns, opened)
+
+	stats := sqlDB.Stats()
+	assert.Greater(t, stats.Idle, before)
+	assert.Equal(t, cfg.MaxIdleConns, stats.Idle)
+
+	// A cancelled context records one snapshot and stops
+	m := metrics.New("test")
+	reportCtx, stopReport := context.WithCancel(context.Background())
+	stopReport()
+	conn.ReportPoolStats(reportCtx, m, time.Minute)
+
+	primary := metrics.PoolPrimary
+	assert.Equal(t, float64(stats.Idle), testutil.ToFloat64(m.DatabasePoolConnections.WithLabelValues(primary, metrics.PoolStateIdle)))
+	assert.Equal(t, float64(stats.OpenConnections), testutil.ToFloat64(m.DatabasePoolConnections.WithLabelValues(primary, metrics.PoolStateOpen)))
+	assert.Equal(t, float64(stats.InUse), testutil.ToFloat64(m.DatabasePoolConnections.WithLabelValues(primary, metrics.PoolStateInUse)))
+	assert.Equal(t, float64(cfg.MaxConnections), testutil.ToFloat64(m.DatabasePoolConnections.WithLabelValues(primary, metrics.PoolStateMaxOpen)))
+	assert.Equal(t, float64(3), testutil.ToFloat64(m.DatabasePoolConnections.WithLabelValues(metrics.ReplicaPool(0), metrics.PoolStateMaxOpen)))
+
+	// The wait counters only grow by the waits since the previous snapshot
+	m.ObserveDatabasePool(primary, sql.DBStats{WaitCount: 3, WaitDuration: time.Second}, sql.DBStats{})
+	m.ObserveDatabasePool(primary, sql.DBStats{WaitCount: 5, WaitDuration: 3 * time.Second}, sql.DBStats{WaitCount: 3, WaitDuration: time.Second})
+	assert.Equal(t, float64(stats.WaitCount+5), testutil.ToFloat64(m.DatabasePoolWaitCount.WithLabelValues(primary)))
+	assert.Equal(t, stats.WaitDuration.Seconds()+3, testutil.ToFloat64(m.DatabasePoolWaitDuration.WithLabelValues(primary)))
+}
+
+// TestConnectWithRetry tests that transient errors are retried and fatal ones are not
+func TestConnectWithRetry(t *testing.T) {
+	log := &logger.Logger{Logger: zap.NewNop()}
+
+	tests := []struct {
+		name         string
+		err          error
+		wantFatal    bool
+		wantAttempts int
+	}{
+		{
+			name:         "host unreachable is retried",
+			err:          errors.New("failed to connect to PostgreSQL database: dial tcp 10.0.0.1:5432: connect: no route to host"),
+			wantAttempts: 3,
+		},
+		{
+			name: "authentication failure fails fast",
+			err: fmt.Errorf("failed to connect to PostgreSQL database: %w",
+				&pgconn.PgError{Severity: "FATAL", Code: "28P01", Message: "password authentication failed for user \"app\""}),
+			wantFatal:    true,
+			wantAttempts: 1,
+		},
+		{
+			name: "missing database fails fast",
+			err: fmt.Errorf("failed to connect to PostgreSQL database: %w",
+				&pgconn.PgError{Severity: "FATAL", Code: "3D000", Message: "database \"nope\" does not exist"}),
+			wantFatal:    true,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			conn, err := connectWithRetry(func() (*PostgreSQLConnection, error) {
+				attempts++
+				return nil, tt.err
+			}, log, 3, time.Millisecond)
+
+			assert.Nil(t, conn)
+			assert.Equal(t, tt.wantAttempts, attempts)
+
+			var connErr *ConnectionError
+			require.ErrorAs(t, err, &connErr)
+			assert.Equal(t, tt.wantFatal, connErr.Fatal)
+			assert.Equal(t, tt.wantAttempts, connErr.Attempts)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
 // Integration tests that require a real PostgreSQL database
 func TestPostgreSQLIntegration(t *testing.T) {
 	if testing.Short() {
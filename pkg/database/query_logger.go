@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"example-api-template/pkg/logger"
+	"example-api-template/pkg/metrics"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// QueryLogger is a GORM logger that writes through zap, reports queries slower
+// than a threshold and optionally records query durations in Prometheus
+type QueryLogger struct {
+	logger        *logger.Logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+	metrics       *metrics.Metrics
+}
+
+// NewQueryLogger creates a query logger at warn level. Queries taking at least
+// slowThreshold are logged as slow; m may be nil to skip metrics.
+func NewQueryLogger(log *logger.Logger, slowThreshold time.Duration, m *metrics.Metrics) *QueryLogger {
+	return &QueryLogger{
+		logger:        log,
+		level:         gormlogger.Warn,
+		slowThreshold: slowThreshold,
+		metrics:       m,
+	}
+}
+
+// LogMode returns a copy of the logger at the given level
+func (l *QueryLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+// Info logs a GORM info message
+func (l *QueryLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.logger.Info(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Warn logs a GORM warning
+func (l *QueryLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.logger.Warn(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Error logs a GORM error
+func (l *QueryLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.logger.Error(fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace is called by GORM after every query
+func (l *QueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	sql, rows := fc()
+
+	// Not found is an expected outcome, not a failed query
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+
+	if l.metrics != nil {
+		l.metrics.ObserveDatabaseQuery(queryOperation(sql), elapsed, err)
+	}
+
+	fields := []zap.Field{
+		zap.String("sql", sql),
+		zap.Duration("duration", elapsed),
+		zap.Int64("rows_affected", rows),
+	}
+
+	switch {
+	case err != nil && l.level >= gormlogger.Error:
+		l.logger.Error("Database query failed", append(fields, zap.Error(err))...)
+	case elapsed >= l.slowThreshold && l.level >= gormlogger.Warn:
+		l.logger.Warn("Slow database query", append(fields, zap.Duration("threshold", l.slowThreshold))...)
+	case l.level >= gormlogger.Info:
+		l.logger.Debug("Database query", fields...)
+	}
+}
+
+// queryOperation labels a query by its leading SQL keyword, keeping the label set small
+func queryOperation(sql string) string {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	switch keyword = strings.ToLower(keyword); keyword {
+	case "select", "insert", "update", "delete":
+		return keyword
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"example-api-template/internal/domain"
+	"example-api-template/pkg/logger"
+	"example-api-template/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// openObservedDB opens an in-memory SQLite database whose queries are logged to the returned observer
+func openObservedDB(t *testing.T, slowThreshold time.Duration, m *metrics.Metrics) (*gorm.DB, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := &logger.Logger{Logger: zap.New(core)}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: NewQueryLogger(log, slowThreshold, m),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	require.NoError(t, db.AutoMigrate(&domain.Example{}))
+
+	return db, logs
+}
+
+func TestQueryLoggerSlowQuery(t *testing.T) {
+	m := metrics.New("test")
+	db, logs := openObservedDB(t, 0, m)
+
+	var count int64
+	require.NoError(t, db.Model(&domain.Example{}).Count(&count).Error)
+
+	slow := logs.FilterMessage("Slow database query").All()
+	require.NotEmpty(t, slow)
+
+	entry := slow[len(slow)-1]
+	assert.Equal(t, zapcore.WarnLevel, entry.Level)
+	fields := entry.ContextMap()
+	assert.Contains(t, fields["sql"], "SELECT count(*) FROM `examples`")
+	assert.Contains(t, fields, "duration")
+	assert.Equal(t, int64(1), fields["rows_affected"])
+
+	// AutoMigrate and Count both ran select queries
+	assert.Positive(t, testutil.CollectAndCount(m.DatabaseQueryDuration, "test_database_query_duration_seconds"))
+	assert.Positive(t, sampleCount(t, m, "select", metrics.StatusSuccess))
+}
+
+// sampleCount returns how many queries were observed with the given labels
+func sampleCount(t *testing.T, m *metrics.Metrics, operation, status string) uint64 {
+	families, err := m.Registry().Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "test_database_query_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["operation"] == operation && labels["status"] == status {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
+func TestQueryLoggerFastQuery(t *testing.T) {
+	db, logs := openObservedDB(t, time.Hour, nil)
+
+	var count int64
+	require.NoError(t, db.Model(&domain.Example{}).Count(&count).Error)
+
+	assert.Empty(t, logs.FilterMessage("Slow database query").All())
+}
+
+func TestQueryLoggerFailedQuery(t *testing.T) {
+	db, logs := openObservedDB(t, time.Hour, nil)
+
+	err := db.Exec("SELECT * FROM missing_table").Error
+	require.Error(t, err)
+
+	failed := logs.FilterMessage("Database query failed").All()
+	require.Len(t, failed, 1)
+	assert.Equal(t, zapcore.ErrorLevel, failed[0].Level)
+
+	// Not found is not a failure
+	var example domain.Example
+	require.ErrorIs(t, db.First(&example, "id = ?", "missing").Error, gorm.ErrRecordNotFound)
+	assert.Len(t, logs.FilterMessage("Database query failed").All(), 1)
+}
+
+func TestQueryOperation(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM examples":           "select",
+		"  insert INTO examples VALUES ()": "insert",
+		"UPDATE examples SET name = 'a'":   "update",
+		"DELETE FROM examples":             "delete",
+		"CREATE TABLE examples (id text)":  "other",
+		"":                                 "other",
+	}
+
+	for sql, expected := range tests {
+		assert.Equal(t, expected, queryOperation(sql), sql)
+	}
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// RegisterReplicas routes reads on db to the given replicas while writes and
+// transactions stay on the primary. It is a no-op when no replicas are given.
+func RegisterReplicas(db *gorm.DB, replicas ...gorm.Dialector) error {
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	})
+	if err := db.Use(resolver); err != nil {
+		return fmt.Errorf("failed to register read replicas: %w", err)
+	}
+
+	return nil
+}
+
+// openReplicas opens a pool for each configured replica DSN, skipping blank entries.
+// The pools are returned alongside their dialectors so their statistics can be reported.
+func openReplicas(dsns []string) ([]*sql.DB, []gorm.Dialector, error) {
+	var (
+		pools      []*sql.DB
+		dialectors []gorm.Dialector
+	)
+	for _, dsn := range dsns {
+		if dsn = strings.TrimSpace(dsn); dsn == "" {
+			continue
+		}
+		connConfig, err := pgx.ParseConfig(dsn)
+		if err != nil {
+			closePools(pools)
+			return nil, nil, fmt.Errorf("invalid read replica DSN: %w", err)
+		}
+		pool := stdlib.OpenDB(*connConfig)
+		pools = append(pools, pool)
+		dialectors = append(dialectors, postgres.New(postgres.Config{Conn: pool}))
+	}
+	return pools, dialectors, nil
+}
+
+// closePools closes every pool, ignoring errors
+func closePools(pools []*sql.DB) {
+	for _, pool := range pools {
+		pool.Close()
+	}
+}
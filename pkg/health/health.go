@@ -0,0 +1,198 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status values reported for each check and for the overall report
+type Status string
+
+const (
+	StatusHealthy       Status = "healthy"
+	StatusUnhealthy     Status = "unhealthy"
+	StatusNotConfigured Status = "not_configured"
+	StatusDegraded      Status = "degraded"
+)
+
+// DefaultTimeout bounds a full health run when the aggregator is given no timeout
+const DefaultTimeout = 2 * time.Second
+
+// ErrNotConfigured is returned by a check whose dependency is intentionally absent.
+// It is reported as not_configured and does not make the overall report unhealthy.
+var ErrNotConfigured = errors.New("not configured")
+
+// ErrDegraded is wrapped by a check whose dependency is replaced by a fallback. It is reported
+// as degraded, with the error, and makes the overall report degraded but not unhealthy.
+var ErrDegraded = errors.New("degraded")
+
+// Checker probes a single dependency
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// checkFunc adapts a function to the Checker interface
+type checkFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (c *checkFunc) Name() string                    { return c.name }
+func (c *checkFunc) Check(ctx context.Context) error { return c.fn(ctx) }
+
+// NewCheck creates a Checker named name that runs fn
+func NewCheck(name string, fn func(ctx context.Context) error) Checker {
+	return &checkFunc{name: name, fn: fn}
+}
+
+// NewConnectionCheck creates a Checker that fails while isConnected reports false
+func NewConnectionCheck(name string, isConnected func() bool) Checker {
+	return NewCheck(name, func(ctx context.Context) error {
+		if !isConnected() {
+			return errors.New("not connected")
+		}
+		return nil
+	})
+}
+
+// Gate is a Checker that fails until Open is called, for one-off startup steps such as migrations
+type Gate struct {
+	name string
+	open atomic.Bool
+}
+
+// NewGate creates a closed gate
+func NewGate(name string) *Gate {
+	return &Gate{name: name}
+}
+
+// Name returns the gate name
+func (g *Gate) Name() string {
+	return g.name
+}
+
+// Open marks the step as complete
+func (g *Gate) Open() {
+	g.open.Store(true)
+}
+
+// Check fails while the gate is closed
+func (g *Gate) Check(ctx context.Context) error {
+	if !g.open.Load() {
+		return errors.New("not complete")
+	}
+	return nil
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the outcome of running every registered check
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Healthy reports whether no check failed; a degraded report is still healthy
+func (r *Report) Healthy() bool {
+	return r.Status != StatusUnhealthy
+}
+
+// Aggregator runs registered checks in parallel and combines their results
+type Aggregator struct {
+	mu      sync.RWMutex
+	checks  []Checker
+	timeout time.Duration
+}
+
+// NewAggregator creates an aggregator that gives all checks timeout to finish
+func NewAggregator(timeout time.Duration) *Aggregator {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Aggregator{timeout: timeout}
+}
+
+// Register adds checks to the aggregator
+func (a *Aggregator) Register(checks ...Checker) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.checks = append(a.checks, checks...)
+}
+
+// Run executes all checks concurrently. A check still running when the timeout
+// expires is reported as unhealthy; the report is unhealthy if any check is, and
+// otherwise degraded if any check is.
+func (a *Aggregator) Run(ctx context.Context) *Report {
+	a.mu.RLock()
+	checks := make([]Checker, len(a.checks))
+	copy(checks, a.checks)
+	a.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	report := &Report{
+		Status: StatusHealthy,
+		Checks: make(map[string]Result, len(checks)),
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check Checker) {
+			defer wg.Done()
+
+			result := runCheck(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name()] = result
+			switch {
+			case result.Status == StatusUnhealthy:
+				report.Status = StatusUnhealthy
+			case result.Status == StatusDegraded && report.Status == StatusHealthy:
+				report.Status = StatusDegraded
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// runCheck runs a single check, giving up when ctx is done
+func runCheck(ctx context.Context, check Checker) Result {
+	// Buffered so a check that ignores ctx can still finish after we stop waiting
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		switch {
+		case err == nil:
+			return Result{Status: StatusHealthy}
+		case errors.Is(err, ErrNotConfigured):
+			return Result{Status: StatusNotConfigured}
+		case errors.Is(err, ErrDegraded):
+			return Result{Status: StatusDegraded, Error: err.Error()}
+		default:
+			return Result{Status: StatusUnhealthy, Error: err.Error()}
+		}
+	case <-ctx.Done():
+		return Result{Status: StatusUnhealthy, Error: fmt.Sprintf("check did not complete: %v", ctx.Err())}
+	}
+}
@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregatorRun(t *testing.T) {
+	healthy := NewCheck("database", func(ctx context.Context) error { return nil })
+	failing := NewCheck("external_api", func(ctx context.Context) error { return errors.New("connection refused") })
+	absent := NewCheck("mq", func(ctx context.Context) error { return ErrNotConfigured })
+	fallback := NewCheck("cache", func(ctx context.Context) error { return fmt.Errorf("%w: using in-memory fallback", ErrDegraded) })
+
+	t.Run("all checks pass", func(t *testing.T) {
+		aggregator := NewAggregator(time.Second)
+		aggregator.Register(healthy, absent)
+
+		report := aggregator.Run(context.Background())
+
+		assert.True(t, report.Healthy())
+		assert.Equal(t, Result{Status: StatusHealthy}, report.Checks["database"])
+		assert.Equal(t, Result{Status: StatusNotConfigured}, report.Checks["mq"])
+	})
+
+	t.Run("one failing check makes the report unhealthy", func(t *testing.T) {
+		aggregator := NewAggregator(time.Second)
+		aggregator.Register(healthy, failing)
+
+		report := aggregator.Run(context.Background())
+
+		assert.False(t, report.Healthy())
+		assert.Equal(t, StatusUnhealthy, report.Status)
+		assert.Equal(t, StatusHealthy, report.Checks["database"].Status)
+		assert.Equal(t, Result{Status: StatusUnhealthy, Error: "connection refused"}, report.Checks["external_api"])
+	})
+
+	t.Run("degraded check degrades the report without failing it", func(t *testing.T) {
+		aggregator := NewAggregator(time.Second)
+		aggregator.Register(healthy, fallback)
+
+		report := aggregator.Run(context.Background())
+
+		assert.True(t, report.Healthy())
+		assert.Equal(t, StatusDegraded, report.Status)
+		assert.Equal(t, Result{Status: StatusDegraded, Error: "degraded: using in-memory fallback"}, report.Checks["cache"])
+
+		aggregator.Register(failing)
+		assert.Equal(t, StatusUnhealthy, aggregator.Run(context.Background()).Status)
+	})
+
+	t.Run("no checks is healthy", func(t *testing.T) {
+		report := NewAggregator(0).Run(context.Background())
+
+		assert.True(t, report.Healthy())
+		assert.Empty(t, report.Checks)
+	})
+}
+
+func TestAggregatorRunTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	aggregator := NewAggregator(50 * time.Millisecond)
+	aggregator.Register(
+		// Ignores its context, so only the aggregator timeout can end the wait
+		NewCheck("stuck", func(ctx context.Context) error { <-block; return nil }),
+		NewCheck("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+
+	start := time.Now()
+	report := aggregator.Run(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, report.Healthy())
+	assert.Equal(t, StatusUnhealthy, report.Checks["stuck"].Status)
+	assert.Equal(t, StatusUnhealthy, report.Checks["slow"].Status)
+}
+
+func TestAggregatorRunsChecksInParallel(t *testing.T) {
+	const checks = 5
+	started := make(chan struct{}, checks)
+	release := make(chan struct{})
+
+	aggregator := NewAggregator(time.Second)
+	for i := 0; i < checks; i++ {
+		aggregator.Register(NewCheck(string(rune('a'+i)), func(ctx context.Context) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}))
+	}
+
+	done := make(chan *Report)
+	go func() { done <- aggregator.Run(context.Background()) }()
+
+	// Every check must be running before any is allowed to finish
+	for i := 0; i < checks; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatalf("only %d of %d checks started", i, checks)
+		}
+	}
+	close(release)
+
+	report := <-done
+	require.Len(t, report.Checks, checks)
+	assert.True(t, report.Healthy())
+}
+
+func TestNewConnectionCheck(t *testing.T) {
+	connected := false
+	check := NewConnectionCheck("mq", func() bool { return connected })
+
+	assert.Equal(t, "mq", check.Name())
+	assert.Error(t, check.Check(context.Background()))
+
+	connected = true
+	assert.NoError(t, check.Check(context.Background()))
+}
+
+func TestGate(t *testing.T) {
+	gate := NewGate("migrations")
+	aggregator := NewAggregator(time.Second)
+	aggregator.Register(gate)
+
+	report := aggregator.Run(context.Background())
+	assert.False(t, report.Healthy())
+	assert.Equal(t, Result{Status: StatusUnhealthy, Error: "not complete"}, report.Checks["migrations"])
+
+	gate.Open()
+
+	report = aggregator.Run(context.Background())
+	assert.True(t, report.Healthy())
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
+	"example-api-template/pkg/ctxkeys"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -61,17 +65,110 @@ func (l *Localizer) DefaultLanguage() string {
 	return l.defaultLanguage
 }
 
+// ParseAcceptLanguage returns the supported language the Accept-Language header prefers
+// most, e.g. "th" for "fr;q=0.9, th;q=0.8, en;q=0.1" when French is not supported. Entries
+// are ranked by quality, ties keeping their order, and a region such as en-US matches its
+// base language. Unsupported languages, q=0 and malformed entries are skipped, and a header
+// with no supported language returns "" so the caller can fall back.
 func (l *Localizer) ParseAcceptLanguage(acceptLanguage string) string {
-	lang := strings.Split(acceptLanguage, ",")[0]
-	return strings.Split(lang, ";")[0]
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(entry, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+
+		quality, ok := parseQuality(params)
+		if !ok || quality == 0 || tag == "" {
+			continue
+		}
+
+		lang := tag
+		if !l.IsLanguageSupported(lang) {
+			lang, _, _ = strings.Cut(tag, "-")
+		}
+		if l.IsLanguageSupported(lang) {
+			candidates = append(candidates, candidate{lang: lang, quality: quality})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0].lang
+}
+
+// parseQuality returns the q parameter of an Accept-Language entry's params, 1 when it has
+// none, and false when it is not a number between 0 and 1
+func parseQuality(params string) (float64, bool) {
+	params = strings.TrimSpace(params)
+	if params == "" {
+		return 1, true
+	}
+	name, value, ok := strings.Cut(params, "=")
+	if !ok || strings.TrimSpace(name) != "q" {
+		return 0, false
+	}
+	quality, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || quality < 0 || quality > 1 {
+		return 0, false
+	}
+	return quality, true
 }
 
 // LocalizeError returns localized message using template data
 func (l *Localizer) LocalizeError(lang, key string, data map[string]interface{}) string {
-	trans, ok := l.locales[lang][key]
+	trans, ok := l.lookup(lang, key)
 	if !ok {
 		trans = key
 	}
+	return render(trans, data)
+}
+
+// Localize returns the message for key in the language from ctx, filling named
+// placeholders such as {{.Name}} from data
+func (l *Localizer) Localize(ctx context.Context, key string, data map[string]interface{}) string {
+	return l.LocalizeError(l.GetLanguageFromContext(ctx), key, data)
+}
+
+// LocalizePlural returns the plural form of key for count in the language from ctx.
+// Forms are stored as key_one, key_other and so on, per CLDR plural category, and
+// fall back to key_other and then key. The count is available to the message as {{.Count}}.
+func (l *Localizer) LocalizePlural(ctx context.Context, key string, count int, data map[string]interface{}) string {
+	lang := l.GetLanguageFromContext(ctx)
+
+	values := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		values[k] = v
+	}
+	values["Count"] = count
+
+	for _, form := range []string{key + "_" + PluralCategory(lang, count), key + "_" + PluralOther, key} {
+		if trans, ok := l.lookup(lang, form); ok {
+			return render(trans, values)
+		}
+	}
+	return key
+}
+
+// lookup finds the translation of key in lang, falling back to the default language
+func (l *Localizer) lookup(lang, key string) (string, bool) {
+	if trans, ok := l.locales[lang][key]; ok {
+		return trans, true
+	}
+	trans, ok := l.locales[l.defaultLanguage][key]
+	return trans, ok
+}
+
+// render fills the named placeholders in trans from data, returning trans unchanged
+// when it is not a valid template
+func render(trans string, data map[string]interface{}) string {
 	tmpl, err := template.New("").Parse(trans)
 	if err != nil {
 		return trans
@@ -85,7 +182,7 @@ func (l *Localizer) LocalizeError(lang, key string, data map[string]interface{})
 }
 
 func (l *Localizer) SetLanguageInContext(ctx context.Context, lang string) context.Context {
-	return context.WithValue(ctx, "lang", lang)
+	return ctxkeys.WithLanguage(ctx, lang)
 }
 
 // Get language from context
@@ -93,7 +190,7 @@ func (l *Localizer) GetLanguageFromContext(ctx context.Context) string {
 	if ctx == nil {
 		return "en"
 	}
-	if lang, ok := ctx.Value("lang").(string); ok && lang != "" {
+	if lang := ctxkeys.Language(ctx); lang != "" {
 		return lang
 	}
 	return "en"
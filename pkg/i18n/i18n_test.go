@@ -0,0 +1,102 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalizer(t *testing.T) *Localizer {
+	t.Helper()
+
+	localizer, err := NewLocalizer(&Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../translations",
+	})
+	require.NoError(t, err)
+	return localizer
+}
+
+func TestLocalizePlural(t *testing.T) {
+	localizer := newTestLocalizer(t)
+	en := localizer.SetLanguageInContext(context.Background(), "en")
+	th := localizer.SetLanguageInContext(context.Background(), "th")
+
+	t.Run("English has singular and plural forms", func(t *testing.T) {
+		assert.Equal(t, "1 example found", localizer.LocalizePlural(en, "examples_found", 1, nil))
+		assert.Equal(t, "5 examples found", localizer.LocalizePlural(en, "examples_found", 5, nil))
+		assert.Equal(t, "0 examples found", localizer.LocalizePlural(en, "examples_found", 0, nil))
+	})
+
+	t.Run("Thai uses one form for every count", func(t *testing.T) {
+		assert.Equal(t, "พบตัวอย่าง 1 รายการ", localizer.LocalizePlural(th, "examples_found", 1, nil))
+		assert.Equal(t, "พบตัวอย่าง 5 รายการ", localizer.LocalizePlural(th, "examples_found", 5, nil))
+	})
+
+	t.Run("unsupported language falls back to the default", func(t *testing.T) {
+		de := localizer.SetLanguageInContext(context.Background(), "de")
+		assert.Equal(t, "5 examples found", localizer.LocalizePlural(de, "examples_found", 5, nil))
+	})
+
+	t.Run("falls back to the plain key", func(t *testing.T) {
+		data := map[string]interface{}{"ID": "ex_1"}
+		assert.Equal(t, "Example with ID 'ex_1' not found", localizer.LocalizePlural(en, "example_not_found", 2, data))
+		assert.NotContains(t, data, "Count", "caller's data is not modified")
+		assert.Equal(t, "missing_key", localizer.LocalizePlural(en, "missing_key", 2, nil))
+	})
+}
+
+func TestLocalize(t *testing.T) {
+	localizer := newTestLocalizer(t)
+	data := map[string]interface{}{"ID": "ex_1"}
+
+	en := localizer.SetLanguageInContext(context.Background(), "en")
+	assert.Equal(t, "Example with ID 'ex_1' not found", localizer.Localize(en, "example_not_found", data))
+
+	th := localizer.SetLanguageInContext(context.Background(), "th")
+	assert.Equal(t, "ไม่พบตัวอย่างที่มี ID 'ex_1'", localizer.Localize(th, "example_not_found", data))
+
+	assert.Equal(t, "missing_key", localizer.Localize(en, "missing_key", nil))
+}
+
+func TestPluralCategory(t *testing.T) {
+	assert.Equal(t, PluralOne, PluralCategory("en", 1))
+	assert.Equal(t, PluralOne, PluralCategory("en", -1))
+	assert.Equal(t, PluralOther, PluralCategory("en", 0))
+	assert.Equal(t, PluralOther, PluralCategory("en", 5))
+	assert.Equal(t, PluralOther, PluralCategory("th", 1))
+	assert.Equal(t, PluralOne, PluralCategory("fr", 0))
+	assert.Equal(t, PluralOther, PluralCategory("fr", 2))
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	localizer := newTestLocalizer(t)
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"single language", "th", "th"},
+		{"highest quality wins regardless of order", "en;q=0.5, th;q=0.9", "th"},
+		{"unsupported preferred language is skipped", "fr;q=0.9, th;q=0.8, en;q=0.1", "th"},
+		{"missing quality counts as 1", "en;q=0.8, th", "th"},
+		{"ties keep header order", "th, en", "th"},
+		{"region matches its base language", "th-TH, en;q=0.5", "th"},
+		{"case-insensitive tags", "TH;q=0.9, EN;q=0.1", "th"},
+		{"q=0 is not acceptable", "th;q=0, en;q=0.1", "en"},
+		{"all unsupported", "fr, de;q=0.9, *;q=0.1", ""},
+		{"empty header", "", ""},
+		{"malformed entries are skipped", "th;q=abc, en;q=2, ;q=0.5, fr-CA;foo, en-GB;q=0.3", "en"},
+		{"garbage", ";;;,,,", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, localizer.ParseAcceptLanguage(tt.header))
+		})
+	}
+}
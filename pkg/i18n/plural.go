@@ -0,0 +1,43 @@
+package i18n
+
+// CLDR plural categories used as translation key suffixes
+const (
+	PluralOne   = "one"
+	PluralOther = "other"
+)
+
+// pluralRules maps a language to its CLDR plural rule for whole numbers. Languages
+// not listed follow English, where only 1 is singular.
+var pluralRules = map[string]func(n int) string{
+	"th": pluralAlwaysOther,
+	"ja": pluralAlwaysOther,
+	"ko": pluralAlwaysOther,
+	"zh": pluralAlwaysOther,
+	"vi": pluralAlwaysOther,
+	"id": pluralAlwaysOther,
+	"fr": func(n int) string {
+		if n == 0 || n == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	},
+}
+
+// PluralCategory returns the CLDR plural category of count in lang
+func PluralCategory(lang string, count int) string {
+	if count < 0 {
+		count = -count
+	}
+	if rule, ok := pluralRules[lang]; ok {
+		return rule(count)
+	}
+	if count == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// pluralAlwaysOther is the rule for languages without grammatical plurals
+func pluralAlwaysOther(int) string {
+	return PluralOther
+}
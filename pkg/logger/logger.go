@@ -1,18 +1,23 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"example-api-template/internal/config"
+	"example-api-template/pkg/ctxkeys"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // Logger wraps zap logger with additional functionality
 type Logger struct {
 	*zap.Logger
+	level zap.AtomicLevel // Shared by derived loggers so level changes apply everywhere
 }
 
 // New creates a new logger instance based on configuration
@@ -52,8 +57,7 @@ func New(cfg *config.LoggerConfig) (*Logger, error) {
 		} else if path == "stderr" {
 			ws = zapcore.AddSync(os.Stderr)
 		} else {
-			// For file paths, open the file
-			file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+			file, err := newRotatingFile(path, cfg.Rotation)
 			if err != nil {
 				return nil, fmt.Errorf("failed to open log file %s: %w", path, err)
 			}
@@ -65,8 +69,10 @@ func New(cfg *config.LoggerConfig) (*Logger, error) {
 	// Combine all write syncers
 	writeSyncer := zapcore.NewMultiWriteSyncer(writeSyncers...)
 
-	// Create core
-	core := zapcore.NewCore(encoder, writeSyncer, level)
+	// Create core with a level that can be changed at runtime
+	atomicLevel := zap.NewAtomicLevelAt(level)
+	core := zapcore.NewCore(encoder, writeSyncer, atomicLevel)
+	core = newSampler(core, cfg.Sampling)
 
 	// Create logger options
 	options := []zap.Option{
@@ -82,7 +88,42 @@ func New(cfg *config.LoggerConfig) (*Logger, error) {
 	// Create logger
 	logger := zap.New(core, options...)
 
-	return &Logger{Logger: logger}, nil
+	return &Logger{Logger: logger, level: atomicLevel}, nil
+}
+
+// defaultMaxSizeMB is the size log files are rotated at when the config leaves it unset
+const defaultMaxSizeMB = 100
+
+// newRotatingFile opens the file at path for appending, rotating it as configured
+func newRotatingFile(path string, cfg config.LogRotationConfig) (*lumberjack.Logger, error) {
+	maxSize := cfg.MaxSizeMB
+	if maxSize <= 0 {
+		maxSize = defaultMaxSizeMB
+	}
+	file := &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSize,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAgeDays,
+		Compress:   cfg.Compress,
+	}
+	// lumberjack opens the file on the first write; do it now so a bad path fails here
+	if _, err := file.Write(nil); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// samplingTick is the interval log sampling counts entries over
+const samplingTick = time.Second
+
+// newSampler wraps core to drop repeated entries as configured; it returns core as is
+// when sampling is disabled
+func newSampler(core zapcore.Core, cfg config.LogSamplingConfig) zapcore.Core {
+	if !cfg.Enabled {
+		return core
+	}
+	return zapcore.NewSamplerWithOptions(core, samplingTick, cfg.Initial, cfg.Thereafter)
 }
 
 // NewDevelopment creates a development logger with sensible defaults
@@ -142,32 +183,66 @@ func createEncoderConfig(cfg *config.LoggerConfig) zapcore.EncoderConfig {
 
 // WithFields adds fields to the logger context
 func (l *Logger) WithFields(fields ...zap.Field) *Logger {
-	return &Logger{Logger: l.Logger.With(fields...)}
+	return &Logger{Logger: l.Logger.With(fields...), level: l.level}
 }
 
 // WithError adds an error field to the logger
 func (l *Logger) WithError(err error) *Logger {
-	return &Logger{Logger: l.Logger.With(zap.Error(err))}
+	return &Logger{Logger: l.Logger.With(zap.Error(err)), level: l.level}
 }
 
 // WithRequestID adds a request ID field to the logger
 func (l *Logger) WithRequestID(requestID string) *Logger {
-	return &Logger{Logger: l.Logger.With(zap.String("request_id", requestID))}
+	return &Logger{Logger: l.Logger.With(zap.String("request_id", requestID)), level: l.level}
 }
 
 // WithUserID adds a user ID field to the logger
 func (l *Logger) WithUserID(userID string) *Logger {
-	return &Logger{Logger: l.Logger.With(zap.String("user_id", userID))}
+	return &Logger{Logger: l.Logger.With(zap.String("user_id", userID)), level: l.level}
 }
 
 // WithComponent adds a component field to the logger
 func (l *Logger) WithComponent(component string) *Logger {
-	return &Logger{Logger: l.Logger.With(zap.String("component", component))}
+	return &Logger{Logger: l.Logger.With(zap.String("component", component)), level: l.level}
 }
 
 // WithOperation adds an operation field to the logger
 func (l *Logger) WithOperation(operation string) *Logger {
-	return &Logger{Logger: l.Logger.With(zap.String("operation", operation))}
+	return &Logger{Logger: l.Logger.With(zap.String("operation", operation)), level: l.level}
+}
+
+// WithContext adds the request ID carried by ctx, if any, to the logger
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	if requestID := RequestID(ctx); requestID != "" {
+		return l.WithRequestID(requestID)
+	}
+	return l
+}
+
+// RequestID returns the request ID carried by ctx, or "" outside an HTTP request
+func RequestID(ctx context.Context) string {
+	return ctxkeys.RequestID(ctx)
+}
+
+// ForContext is WithContext for a plain zap logger, as held by the service and use case layers
+func ForContext(ctx context.Context, l *zap.Logger) *zap.Logger {
+	return (&Logger{Logger: l}).WithContext(ctx).Logger
+}
+
+// Level returns the current minimum log level, e.g. "info"
+func (l *Logger) Level() string {
+	return l.level.String()
+}
+
+// SetLevel changes the minimum log level at runtime for this logger and all loggers derived from it
+func (l *Logger) SetLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level: %s", level)
+	}
+
+	l.level.SetLevel(parsed)
+	return nil
 }
 
 // LogHTTPRequest logs HTTP request details
@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"example-api-template/internal/config"
+	"example-api-template/pkg/ctxkeys"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newFileLogger(t *testing.T, level string) (*Logger, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := New(&config.LoggerConfig{
+		Level:       level,
+		Format:      "json",
+		OutputPaths: []string{path},
+	})
+	require.NoError(t, err)
+	return log, path
+}
+
+func readLog(t *testing.T, log *Logger, path string) string {
+	t.Helper()
+
+	_ = log.Sync()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestLoggerSetLevel(t *testing.T) {
+	t.Run("debug entries appear after raising verbosity", func(t *testing.T) {
+		log, path := newFileLogger(t, "info")
+		assert.Equal(t, "info", log.Level())
+
+		log.Debug("suppressed debug entry")
+		assert.NotContains(t, readLog(t, log, path), "suppressed debug entry")
+
+		require.NoError(t, log.SetLevel("debug"))
+		assert.Equal(t, "debug", log.Level())
+
+		log.Debug("emitted debug entry")
+		assert.Contains(t, readLog(t, log, path), "emitted debug entry")
+	})
+
+	t.Run("derived loggers follow level changes", func(t *testing.T) {
+		log, path := newFileLogger(t, "info")
+		component := log.WithComponent("worker")
+
+		require.NoError(t, log.SetLevel("debug"))
+		component.Debug("component debug entry")
+
+		assert.Contains(t, readLog(t, log, path), "component debug entry")
+	})
+
+	t.Run("invalid level is rejected", func(t *testing.T) {
+		log, _ := newFileLogger(t, "warn")
+
+		assert.Error(t, log.SetLevel("verbose"))
+		assert.Equal(t, "warn", log.Level())
+	})
+}
+
+func TestLoggerWithContext(t *testing.T) {
+	log, path := newFileLogger(t, "info")
+	ctx := ctxkeys.WithRequestID(context.Background(), "req-123")
+
+	log.WithContext(ctx).Info("entry with request")
+	ForContext(ctx, log.Logger).Info("zap entry with request")
+	log.WithContext(context.Background()).Info("entry without request")
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, log, path)), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"request_id":"req-123"`)
+	assert.Contains(t, lines[1], `"request_id":"req-123"`)
+	assert.NotContains(t, lines[2], "request_id")
+}
+
+func TestNewSampler(t *testing.T) {
+	t.Run("repeated entries are dropped past the initial ones", func(t *testing.T) {
+		core, logs := observer.New(zapcore.InfoLevel)
+		log := zap.New(newSampler(core, config.LogSamplingConfig{Enabled: true, Initial: 3, Thereafter: 5}))
+
+		for i := 0; i < 20; i++ {
+			log.Info("request handled", zap.Int("i", i))
+		}
+		log.Info("other message")
+
+		handled := logs.FilterMessage("request handled").All()
+		// The first 3 pass, then every 5th: the 8th, 13th and 18th
+		require.Len(t, handled, 6)
+		for i, want := range []int64{0, 1, 2, 7, 12, 17} {
+			assert.Equal(t, want, handled[i].ContextMap()["i"])
+		}
+		assert.Equal(t, 1, logs.FilterMessage("other message").Len(), "messages are sampled separately")
+	})
+
+	t.Run("disabled sampling keeps every entry", func(t *testing.T) {
+		core, logs := observer.New(zapcore.InfoLevel)
+		log := zap.New(newSampler(core, config.LogSamplingConfig{Initial: 3, Thereafter: 5}))
+
+		for i := 0; i < 20; i++ {
+			log.Info("request handled")
+		}
+
+		assert.Equal(t, 20, logs.Len())
+	})
+}
+
+func TestLoggerFileRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	log, err := New(&config.LoggerConfig{
+		Level:       "info",
+		Format:      "json",
+		OutputPaths: []string{path},
+		Rotation:    config.LogRotationConfig{MaxSizeMB: 1, MaxBackups: 2},
+	})
+	require.NoError(t, err)
+
+	// Write a little over 1MB so the file rotates once
+	payload := strings.Repeat("x", 1024)
+	for i := 0; i < 1100; i++ {
+		log.Info("filler", zap.String("payload", payload))
+	}
+	require.NoError(t, log.Sync())
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Less(t, info.Size(), int64(1024*1024), "writing continues in a fresh file")
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Message outcomes recorded by ConsumerMetrics
+const (
+	MessageProcessed    = "processed"     // Acknowledged, including skipped duplicates and unknown event types
+	MessageRetried      = "retried"       // Republished, or handed back for redelivery, for another attempt
+	MessageRequeued     = "requeued"      // Returned to the queue because it could not be republished
+	MessageDeadLettered = "dead_lettered" // Rejected without requeueing
+)
+
+// ConsumerMetrics holds the Prometheus collectors used by the message queue consumer
+type ConsumerMetrics struct {
+	registry *prometheus.Registry
+
+	MessagesProcessedTotal    prometheus.Counter
+	MessagesFailedTotal       prometheus.Counter
+	MessagesRetriedTotal      prometheus.Counter
+	MessagesDeadLetteredTotal prometheus.Counter
+	MessageProcessingDuration *prometheus.HistogramVec
+}
+
+// NewConsumer creates a new ConsumerMetrics instance backed by its own registry
+func NewConsumer(namespace string) *ConsumerMetrics {
+	registry := prometheus.NewRegistry()
+
+	m := &ConsumerMetrics{
+		registry: registry,
+		MessagesProcessedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consumer",
+			Name:      "messages_processed_total",
+			Help:      "Total number of messages handled and acknowledged.",
+		}),
+		MessagesFailedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consumer",
+			Name:      "messages_failed_total",
+			Help:      "Total number of messages that could not be handled, whether retried, requeued or dead-lettered.",
+		}),
+		MessagesRetriedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consumer",
+			Name:      "messages_retried_total",
+			Help:      "Total number of messages republished for another attempt.",
+		}),
+		MessagesDeadLetteredTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "consumer",
+			Name:      "messages_dead_lettered_total",
+			Help:      "Total number of messages rejected to the dead-letter queue.",
+		}),
+		MessageProcessingDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "consumer",
+			Name:      "message_processing_duration_seconds",
+			Help:      "Message processing latency by outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"outcome"}),
+	}
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.MessagesProcessedTotal,
+		m.MessagesFailedTotal,
+		m.MessagesRetriedTotal,
+		m.MessagesDeadLetteredTotal,
+		m.MessageProcessingDuration,
+	)
+
+	return m
+}
+
+// Registry returns the underlying Prometheus registry
+func (m *ConsumerMetrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler returns an HTTP handler exposing the registered metrics
+func (m *ConsumerMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveMessage records a message settled with outcome after being processed for duration
+func (m *ConsumerMetrics) ObserveMessage(outcome string, duration time.Duration) {
+	switch outcome {
+	case MessageProcessed:
+		m.MessagesProcessedTotal.Inc()
+	case MessageRetried:
+		m.MessagesFailedTotal.Inc()
+		m.MessagesRetriedTotal.Inc()
+	case MessageDeadLettered:
+		m.MessagesFailedTotal.Inc()
+		m.MessagesDeadLetteredTotal.Inc()
+	default:
+		m.MessagesFailedTotal.Inc()
+	}
+	m.MessageProcessingDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+}
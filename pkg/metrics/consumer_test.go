@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerMetricsObserveMessage(t *testing.T) {
+	m := NewConsumer("test")
+
+	m.ObserveMessage(MessageProcessed, 10*time.Millisecond)
+	m.ObserveMessage(MessageProcessed, 20*time.Millisecond)
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.MessagesProcessedTotal))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.MessagesFailedTotal), "acked messages are not failures")
+
+	m.ObserveMessage(MessageRetried, time.Millisecond)
+	m.ObserveMessage(MessageDeadLettered, time.Millisecond)
+	m.ObserveMessage(MessageRequeued, time.Millisecond)
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.MessagesProcessedTotal))
+	assert.Equal(t, float64(3), testutil.ToFloat64(m.MessagesFailedTotal))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.MessagesRetriedTotal))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.MessagesDeadLetteredTotal))
+
+	count, err := testutil.GatherAndCount(m.Registry(), "test_consumer_message_processing_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 4, count, "one histogram per outcome")
+}
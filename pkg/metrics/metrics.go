@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Status labels used for non-HTTP operations
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
+// Metrics holds the Prometheus collectors used by the application
+type Metrics struct {
+	registry *prometheus.Registry
+
+	HTTPRequestsTotal           *prometheus.CounterVec
+	HTTPRequestDuration         *prometheus.HistogramVec
+	HTTPPanicsTotal             *prometheus.CounterVec
+	RepositoryOperationDuration *prometheus.HistogramVec
+	DatabaseQueryDuration       *prometheus.HistogramVec
+	ExternalAPICallDuration     *prometheus.HistogramVec
+	DatabasePoolConnections     *prometheus.GaugeVec
+	DatabasePoolWaitCount       *prometheus.CounterVec
+	DatabasePoolWaitDuration    *prometheus.CounterVec
+}
+
+// Connection pool states reported by DatabasePoolConnections
+const (
+	PoolStateMaxOpen = "max_open"
+	PoolStateOpen    = "open"
+	PoolStateInUse   = "in_use"
+	PoolStateIdle    = "idle"
+)
+
+// PoolPrimary labels the primary database connection pool
+const PoolPrimary = "primary"
+
+// ReplicaPool returns the pool label of the i-th read replica, counting from zero
+func ReplicaPool(i int) string {
+	return "replica_" + strconv.Itoa(i+1)
+}
+
+// New creates a new Metrics instance backed by its own registry
+func New(namespace string) *Metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &Metrics{
+		registry: registry,
+		HTTPRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Total number of HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		HTTPRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		HTTPPanicsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "panics_total",
+			Help:      "Total number of panics recovered from HTTP handlers by method and route.",
+		}, []string{"method", "route"}),
+		RepositoryOperationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "operation_duration_seconds",
+			Help:      "Repository operation latency by operation and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "status"}),
+		DatabaseQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "database",
+			Name:      "query_duration_seconds",
+			Help:      "SQL query latency by operation and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "status"}),
+		ExternalAPICallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "external_api",
+			Name:      "call_duration_seconds",
+			Help:      "External API call latency by method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "status"}),
+		DatabasePoolConnections: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "database",
+			Name:      "pool_connections",
+			Help:      "Database connections in the pool by pool and state.",
+		}, []string{"pool", "state"}),
+		DatabasePoolWaitCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "database",
+			Name:      "pool_waits_total",
+			Help:      "Total number of times a query waited for a free pool connection by pool.",
+		}, []string{"pool"}),
+		DatabasePoolWaitDuration: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "database",
+			Name:      "pool_wait_duration_seconds_total",
+			Help:      "Total time queries spent waiting for a free pool connection by pool.",
+		}, []string{"pool"}),
+	}
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequestsTotal,
+		m.HTTPRequestDuration,
+		m.HTTPPanicsTotal,
+		m.RepositoryOperationDuration,
+		m.DatabaseQueryDuration,
+		m.ExternalAPICallDuration,
+		m.DatabasePoolConnections,
+		m.DatabasePoolWaitCount,
+		m.DatabasePoolWaitDuration,
+	)
+
+	return m
+}
+
+// Registry returns the underlying Prometheus registry
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler returns an HTTP handler exposing the registered metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records a completed HTTP request
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	m.HTTPRequestsTotal.WithLabelValues(method, route, statusLabel).Inc()
+	m.HTTPRequestDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+// ObservePanic records a panic recovered from the handler of route
+func (m *Metrics) ObservePanic(method, route string) {
+	m.HTTPPanicsTotal.WithLabelValues(method, route).Inc()
+}
+
+// ObserveRepositoryOperation records the duration of a repository operation
+func (m *Metrics) ObserveRepositoryOperation(operation string, start time.Time, err error) {
+	m.RepositoryOperationDuration.WithLabelValues(operation, statusFromError(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDatabaseQuery records the duration of a single SQL query
+func (m *Metrics) ObserveDatabaseQuery(operation string, duration time.Duration, err error) {
+	m.DatabaseQueryDuration.WithLabelValues(operation, statusFromError(err)).Observe(duration.Seconds())
+}
+
+// ObserveExternalAPICall records the duration of an external API call
+func (m *Metrics) ObserveExternalAPICall(method string, start time.Time, err error) {
+	m.ExternalAPICallDuration.WithLabelValues(method, statusFromError(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDatabasePool records a snapshot of the named connection pool; the wait counters
+// grow by how much the pool's waits grew since the previous snapshot
+func (m *Metrics) ObserveDatabasePool(pool string, stats, previous sql.DBStats) {
+	m.DatabasePoolConnections.WithLabelValues(pool, PoolStateMaxOpen).Set(float64(stats.MaxOpenConnections))
+	m.DatabasePoolConnections.WithLabelValues(pool, PoolStateOpen).Set(float64(stats.OpenConnections))
+	m.DatabasePoolConnections.WithLabelValues(pool, PoolStateInUse).Set(float64(stats.InUse))
+	m.DatabasePoolConnections.WithLabelValues(pool, PoolStateIdle).Set(float64(stats.Idle))
+	if waits := stats.WaitCount - previous.WaitCount; waits > 0 {
+		m.DatabasePoolWaitCount.WithLabelValues(pool).Add(float64(waits))
+	}
+	if waited := stats.WaitDuration - previous.WaitDuration; waited > 0 {
+		m.DatabasePoolWaitDuration.WithLabelValues(pool).Add(waited.Seconds())
+	}
+}
+
+// statusFromError maps an error to a status label
+func statusFromError(err error) string {
+	if err != nil {
+		return StatusError
+	}
+	return StatusSuccess
+}
@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// Limits from RFC 5321
+const (
+	maxEmailLength       = 254
+	maxEmailLocalLength  = 64
+	maxEmailDomainLabel  = 63
+	minEmailTopLevelName = 2
+)
+
+// IsValidEmail reports whether email is a plain address such as "user.name+tag@sub.example.co.uk".
+// The address is parsed with net/mail (RFC 5322), display names and comments are rejected, and the
+// domain must be a dotted hostname ending in an alphabetic top-level domain.
+func IsValidEmail(email string) bool {
+	if email == "" || len(email) > maxEmailLength {
+		return false
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return false
+	}
+
+	at := strings.LastIndex(email, "@")
+	local, domain := email[:at], email[at+1:]
+	if len(local) > maxEmailLocalLength {
+		return false
+	}
+
+	return isValidEmailDomain(domain)
+}
+
+// isValidEmailDomain checks that domain is a hostname with at least two labels
+func isValidEmailDomain(domain string) bool {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > maxEmailDomainLabel {
+			return false
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, char := range label {
+			if !isASCIILetter(char) && !(char >= '0' && char <= '9') && char != '-' {
+				return false
+			}
+		}
+	}
+
+	tld := labels[len(labels)-1]
+	if len(tld) < minEmailTopLevelName {
+		return false
+	}
+	for _, char := range tld {
+		if !isASCIILetter(char) {
+			return false
+		}
+	}
+	return true
+}
+
+// isASCIILetter reports whether char is an ASCII letter
+func isASCIILetter(char rune) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
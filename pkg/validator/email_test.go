@@ -0,0 +1,73 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidEmail(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		want  bool
+	}{
+		{name: "valid email", email: "test@example.com", want: true},
+		{name: "valid email with subdomain", email: "user@mail.example.com", want: true},
+		{name: "valid email with numbers", email: "user123@example.com", want: true},
+		{name: "valid email with dots", email: "user.name@example.com", want: true},
+		{name: "valid email with tag and multi-level domain", email: "user.name+tag@sub.example.co.uk", want: true},
+		{name: "valid email with several dots in local part", email: "first.middle.last@example.com", want: true},
+		{name: "valid email with hyphenated domain", email: "user@my-company.com", want: true},
+		{name: "invalid email no @", email: "userexample.com", want: false},
+		{name: "invalid email no domain", email: "user@", want: false},
+		{name: "invalid email no user", email: "@example.com", want: false},
+		{name: "invalid email no TLD", email: "user@example", want: false},
+		{name: "invalid email single-letter TLD", email: "a@b.c", want: false},
+		{name: "invalid email numeric TLD", email: "user@example.123", want: false},
+		{name: "invalid email multiple @", email: "user@@x.com", want: false},
+		{name: "invalid email consecutive dots in local part", email: "user..name@example.com", want: false},
+		{name: "invalid email empty domain label", email: "user@example..com", want: false},
+		{name: "invalid email hyphen at label edge", email: "user@-example.com", want: false},
+		{name: "invalid email with display name", email: "John <john@example.com>", want: false},
+		{name: "invalid email with surrounding spaces", email: " john@example.com ", want: false},
+		{name: "invalid email local part too long", email: strings.Repeat("a", 65) + "@example.com", want: false},
+		{name: "empty email", email: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsValidEmail(tt.email))
+		})
+	}
+}
+
+func TestEmailTagUsesSharedRules(t *testing.T) {
+	type request struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	v := New()
+
+	_, err := v.ValidateStruct(&request{Email: "user.name+tag@sub.example.co.uk"})
+	assert.NoError(t, err)
+
+	validationErrors, err := v.ValidateStruct(&request{Email: "a@b.c"})
+	assert.Error(t, err)
+	if assert.Len(t, validationErrors, 1) {
+		assert.Equal(t, "email", validationErrors[0].Tag)
+	}
+
+	assert.NoError(t, ValidateEmail("user.name+tag@sub.example.co.uk"))
+	assert.Error(t, ValidateEmail("user@@x.com"))
+}
+
+func BenchmarkIsValidEmail(b *testing.B) {
+	email := "test@example.com"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = IsValidEmail(email)
+	}
+}
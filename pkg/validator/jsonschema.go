@@ -0,0 +1,140 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ErrSchemaViolation is returned when a document doesn't match its route's schema
+var ErrSchemaViolation = errors.New("request body does not match its JSON schema")
+
+// JSONSchemaValidator validates raw JSON request bodies against a JSON Schema registered
+// per route, before they are bound to structs. Routes are keyed by method and path
+// pattern, e.g. "POST /api/v1/examples".
+type JSONSchemaValidator struct {
+	mu      sync.RWMutex
+	schemas map[string]*jsonschema.Schema
+}
+
+// NewJSONSchemaValidator creates a validator with no schemas
+func NewJSONSchemaValidator() *JSONSchemaValidator {
+	return &JSONSchemaValidator{schemas: make(map[string]*jsonschema.Schema)}
+}
+
+// AddSchema compiles schema and uses it for route, replacing any schema it had
+func (v *JSONSchemaValidator) AddSchema(route string, schema []byte) error {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return fmt.Errorf("schema for %s: %w", route, err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	const url = "mem:///request.json"
+	if err := compiler.AddResource(url, doc); err != nil {
+		return fmt.Errorf("schema for %s: %w", route, err)
+	}
+	compiled, err := compiler.Compile(url)
+	if err != nil {
+		return fmt.Errorf("schema for %s: %w", route, err)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.schemas[route] = compiled
+	return nil
+}
+
+// AddSchemaFile loads the schema in file and uses it for route
+func (v *JSONSchemaValidator) AddSchemaFile(route, file string) error {
+	schema, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("schema for %s: %w", route, err)
+	}
+	return v.AddSchema(route, schema)
+}
+
+// HasSchema reports whether a schema is registered for route
+func (v *JSONSchemaValidator) HasSchema(route string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	_, ok := v.schemas[route]
+	return ok
+}
+
+// Validate checks body against the schema of route. Violations are returned as field errors
+// whose Field is the JSON pointer of the offending value, along with ErrSchemaViolation.
+// Bodies of routes without a schema pass, and a body that isn't JSON returns only an error.
+func (v *JSONSchemaValidator) Validate(route string, body []byte) ([]ValidationFieldErrorDTO, error) {
+	v.mu.RLock()
+	schema, ok := v.schemas[route]
+	v.mu.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	err = schema.Validate(doc)
+	if err == nil {
+		return nil, nil
+	}
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	var fieldErrors []ValidationFieldErrorDTO
+	for _, unit := range validationErr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		fieldError := ValidationFieldErrorDTO{
+			Field:   unit.InstanceLocation,
+			Message: unit.Error.String(),
+			Tag:     path.Base(unit.KeywordLocation),
+		}
+		if fieldError.Field == "" {
+			fieldError.Field = "/" // The document itself, e.g. for a missing or unexpected property
+		} else if value, ok := lookupPointer(doc, fieldError.Field); ok {
+			fieldError.Value = fmt.Sprintf("%v", value)
+		}
+		fieldErrors = append(fieldErrors, fieldError)
+	}
+	return fieldErrors, fmt.Errorf("%w: %d violation(s)", ErrSchemaViolation, len(fieldErrors))
+}
+
+// lookupPointer returns the value at the JSON pointer ptr within doc
+func lookupPointer(doc interface{}, ptr string) (interface{}, bool) {
+	value := doc
+	for _, token := range strings.Split(strings.TrimPrefix(ptr, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+	"type": "object",
+	"additionalProperties": false,
+	"required": ["name"],
+	"properties": {
+		"name": {"type": "string"},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"age": {"type": "integer", "minimum": 0}
+	}
+}`
+
+func TestJSONSchemaValidator(t *testing.T) {
+	v := NewJSONSchemaValidator()
+	require.NoError(t, v.AddSchema("POST /examples", []byte(testSchema)))
+
+	t.Run("matching body passes", func(t *testing.T) {
+		fields, err := v.Validate("POST /examples", []byte(`{"name":"John Doe","age":30,"tags":["a"]}`))
+		assert.NoError(t, err)
+		assert.Empty(t, fields)
+	})
+
+	t.Run("unknown property violates additionalProperties", func(t *testing.T) {
+		fields, err := v.Validate("POST /examples", []byte(`{"name":"John Doe","nickname":"JD"}`))
+		assert.ErrorIs(t, err, ErrSchemaViolation)
+		require.Len(t, fields, 1)
+		assert.Equal(t, "/", fields[0].Field)
+		assert.Equal(t, "additionalProperties", fields[0].Tag)
+		assert.Contains(t, fields[0].Message, "nickname")
+	})
+
+	t.Run("fields are JSON pointers to the offending values", func(t *testing.T) {
+		fields, err := v.Validate("POST /examples", []byte(`{"name":"John Doe","age":-1,"tags":["a",2]}`))
+		assert.ErrorIs(t, err, ErrSchemaViolation)
+		require.Len(t, fields, 2)
+		byField := map[string]ValidationFieldErrorDTO{}
+		for _, f := range fields {
+			byField[f.Field] = f
+		}
+		assert.Equal(t, "minimum", byField["/age"].Tag)
+		assert.Equal(t, "-1", byField["/age"].Value)
+		assert.Equal(t, "type", byField["/tags/1"].Tag)
+		assert.Equal(t, "2", byField["/tags/1"].Value)
+	})
+
+	t.Run("body that isn't JSON is an error", func(t *testing.T) {
+		fields, err := v.Validate("POST /examples", []byte(`{"name":`))
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrSchemaViolation)
+		assert.Empty(t, fields)
+	})
+
+	t.Run("routes without a schema pass", func(t *testing.T) {
+		assert.False(t, v.HasSchema("PUT /examples/:id"))
+		fields, err := v.Validate("PUT /examples/:id", []byte(`not json`))
+		assert.NoError(t, err)
+		assert.Empty(t, fields)
+	})
+
+	t.Run("invalid schema is rejected", func(t *testing.T) {
+		assert.Error(t, v.AddSchema("POST /broken", []byte(`{"type":"no-such-type"}`)))
+	})
+}
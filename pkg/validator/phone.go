@@ -0,0 +1,27 @@
+package validator
+
+// Limits from ITU-T E.164
+const (
+	minPhoneDigits = 2
+	maxPhoneDigits = 15
+)
+
+// IsValidPhone reports whether phone is an E.164 number such as "+14155552671": a plus
+// sign followed by up to 15 digits, the first of which (the country code) is not zero.
+// Spaces, dashes and other formatting are rejected.
+func IsValidPhone(phone string) bool {
+	if len(phone) < 1+minPhoneDigits || len(phone) > 1+maxPhoneDigits || phone[0] != '+' {
+		return false
+	}
+
+	digits := phone[1:]
+	if digits[0] == '0' {
+		return false
+	}
+	for _, char := range digits {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidPhone(t *testing.T) {
+	tests := []struct {
+		name  string
+		phone string
+		want  bool
+	}{
+		{name: "valid US number", phone: "+14155552671", want: true},
+		{name: "valid Thai number", phone: "+66812345678", want: true},
+		{name: "valid 15 digit number", phone: "+123456789012345", want: true},
+		{name: "invalid without plus", phone: "12345", want: false},
+		{name: "invalid national format", phone: "0812345678", want: false},
+		{name: "invalid country code zero", phone: "+0812345678", want: false},
+		{name: "invalid with spaces", phone: "+1 415 555 2671", want: false},
+		{name: "invalid with dashes", phone: "+1-415-555-2671", want: false},
+		{name: "invalid with letters", phone: "+1415CALLNOW", want: false},
+		{name: "invalid too long", phone: "+1234567890123456", want: false},
+		{name: "invalid plus only", phone: "+", want: false},
+		{name: "empty phone", phone: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsValidPhone(tt.phone))
+		})
+	}
+}
+
+func TestPhoneTag(t *testing.T) {
+	type request struct {
+		Phone string `json:"phone" validate:"omitempty,phone"`
+	}
+
+	v := New()
+
+	_, err := v.ValidateStruct(&request{Phone: "+14155552671"})
+	assert.NoError(t, err)
+
+	_, err = v.ValidateStruct(&request{})
+	assert.NoError(t, err, "phone is optional")
+
+	validationErrors, err := v.ValidateStruct(&request{Phone: "12345"})
+	assert.Error(t, err)
+	if assert.Len(t, validationErrors, 1) {
+		assert.Equal(t, "phone", validationErrors[0].Field)
+		assert.Equal(t, "phone", validationErrors[0].Tag)
+		assert.Contains(t, validationErrors[0].Message, "E.164")
+	}
+
+	assert.NoError(t, ValidatePhone("+14155552671"))
+	assert.Error(t, ValidatePhone("12345"))
+}
@@ -1,36 +1,56 @@
 package validator
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 
+	"example-api-template/pkg/ctxkeys"
+	"example-api-template/pkg/i18n"
+
 	"github.com/go-playground/validator/v10"
 )
 
 // ValidationFieldErrorDTO represents a field validation error
 type ValidationFieldErrorDTO struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
-	Tag     string `json:"tag"`
-	Value   string `json:"value"`
+	Field   string `json:"field" xml:"field"`
+	Message string `json:"message" xml:"message"`
+	Tag     string `json:"tag" xml:"tag"`
+	Value   string `json:"value" xml:"value"`
 }
 
 // Validator wraps the go-playground validator with additional functionality
 type Validator interface {
 	ValidateStruct(s interface{}) ([]ValidationFieldErrorDTO, error)
+	ValidateStructCtx(ctx context.Context, s interface{}) ([]ValidationFieldErrorDTO, error)
 	ValidateVar(field interface{}, tag string) error
 	RegisterValidation(tag string, fn validator.Func) error
+	RegisterStructValidationCtx(fn validator.StructLevelFuncCtx, types ...interface{})
 }
 
 // customValidator implements the Validator interface
 type customValidator struct {
 	validator *validator.Validate
+	localizer *i18n.Localizer
+}
+
+// Option configures the validator
+type Option func(*customValidator)
+
+// WithLocalizer translates field error messages into the language carried by the context
+// passed to ValidateStructCtx. Messages are looked up as "validation_<tag>", with the field
+// name and tag parameter available as {{.Field}} and {{.Param}}; tags without a translation
+// keep the built-in English message.
+func WithLocalizer(localizer *i18n.Localizer) Option {
+	return func(cv *customValidator) {
+		cv.localizer = localizer
+	}
 }
 
 // New creates a new validator instance
-func New() Validator {
+func New(opts ...Option) Validator {
 	validate := validator.New()
 
 	// Use JSON tag names for validation errors
@@ -44,25 +64,34 @@ func New() Validator {
 
 	// Register custom validations
 	cv := &customValidator{validator: validate}
+	for _, opt := range opts {
+		opt(cv)
+	}
 	cv.registerCustomValidations()
 
 	return cv
 }
 
-// ValidateStruct validates a struct and returns validation errors
+// ValidateStruct validates a struct and returns validation errors in the default language
 func (cv *customValidator) ValidateStruct(s interface{}) ([]ValidationFieldErrorDTO, error) {
+	return cv.ValidateStructCtx(ctxkeys.WithLanguage(context.Background(), cv.defaultLanguage()), s)
+}
+
+// ValidateStructCtx validates a struct and returns validation errors with messages in the
+// language from ctx. The context is also passed on to struct-level validations.
+func (cv *customValidator) ValidateStructCtx(ctx context.Context, s interface{}) ([]ValidationFieldErrorDTO, error) {
 	var validationErrors []ValidationFieldErrorDTO
 
-	err := cv.validator.Struct(s)
+	err := cv.validator.StructCtx(ctx, s)
 	if err != nil {
 		var ve validator.ValidationErrors
 		if errors.As(err, &ve) {
 			for _, fe := range ve {
 				validationErrors = append(validationErrors, ValidationFieldErrorDTO{
 					Field:   fe.Field(),
-					Message: cv.getErrorMessage(fe),
+					Message: cv.localizeMessage(ctx, fe),
 					Tag:     fe.Tag(),
-					Value:   fmt.Sprintf("%v", fe.Value()),
+					Value:   formatValue(fe.Value()),
 				})
 			}
 		}
@@ -71,6 +100,14 @@ func (cv *customValidator) ValidateStruct(s interface{}) ([]ValidationFieldError
 	return validationErrors, err
 }
 
+// formatValue formats a rejected value; a nil pointer, such as a missing required field, has none
+func formatValue(value interface{}) string {
+	if v := reflect.ValueOf(value); !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
 // ValidateVar validates a single variable
 func (cv *customValidator) ValidateVar(field interface{}, tag string) error {
 	return cv.validator.Var(field, tag)
@@ -81,8 +118,17 @@ func (cv *customValidator) RegisterValidation(tag string, fn validator.Func) err
 	return cv.validator.RegisterValidation(tag, fn)
 }
 
+// RegisterStructValidationCtx registers a validation run on whole structs of the given types,
+// for rules spanning several fields or needing outside state such as a repository
+func (cv *customValidator) RegisterStructValidationCtx(fn validator.StructLevelFuncCtx, types ...interface{}) {
+	cv.validator.RegisterStructValidationCtx(fn, types...)
+}
+
 // registerCustomValidations registers custom validation functions
 func (cv *customValidator) registerCustomValidations() {
+	// Replace the built-in email check so every layer shares IsValidEmail
+	cv.validator.RegisterValidation("email", validateEmail)
+
 	// Register custom email validation (stricter than default)
 	cv.validator.RegisterValidation("strict_email", validateStrictEmail)
 
@@ -94,6 +140,35 @@ func (cv *customValidator) registerCustomValidations() {
 
 	// Register no profanity validation
 	cv.validator.RegisterValidation("no_profanity", validateNoProfanity)
+
+	// Register E.164 phone number validation
+	cv.validator.RegisterValidation("phone", validatePhone)
+}
+
+// defaultLanguage returns the language of messages when the context carries none
+func (cv *customValidator) defaultLanguage() string {
+	if cv.localizer != nil && cv.localizer.DefaultLanguage() != "" {
+		return cv.localizer.DefaultLanguage()
+	}
+	return "en"
+}
+
+// localizeMessage returns the message for fe in the language from ctx, falling back to
+// getErrorMessage when there is no localizer or no translation for the tag
+func (cv *customValidator) localizeMessage(ctx context.Context, fe validator.FieldError) string {
+	if cv.localizer == nil {
+		return cv.getErrorMessage(fe)
+	}
+
+	key := "validation_" + fe.Tag()
+	message := cv.localizer.Localize(ctx, key, map[string]interface{}{
+		"Field": fe.Field(),
+		"Param": fe.Param(),
+	})
+	if message == key {
+		return cv.getErrorMessage(fe)
+	}
+	return message
 }
 
 // getErrorMessage returns a human-readable error message for validation errors
@@ -135,6 +210,10 @@ func (cv *customValidator) getErrorMessage(fe validator.FieldError) string {
 		return fmt.Sprintf("%s must be between 0 and 150", fe.Field())
 	case "no_profanity":
 		return fmt.Sprintf("%s contains inappropriate content", fe.Field())
+	case "phone":
+		return fmt.Sprintf("%s must be a phone number in E.164 format, e.g. +14155552671", fe.Field())
+	case "unique_email":
+		return fmt.Sprintf("%s is already taken", fe.Field())
 	case "oneof":
 		return fmt.Sprintf("%s must be one of: %s", fe.Field(), fe.Param())
 	case "uuid":
@@ -148,12 +227,17 @@ func (cv *customValidator) getErrorMessage(fe validator.FieldError) string {
 
 // Custom validation functions
 
+// validateEmail validates email format with IsValidEmail
+func validateEmail(fl validator.FieldLevel) bool {
+	return IsValidEmail(fl.Field().String())
+}
+
 // validateStrictEmail validates email with stricter rules
 func validateStrictEmail(fl validator.FieldLevel) bool {
 	email := fl.Field().String()
 
 	// Basic email validation
-	if err := validator.New().Var(email, "email"); err != nil {
+	if !IsValidEmail(email) {
 		return false
 	}
 
@@ -219,6 +303,11 @@ func validateAge(fl validator.FieldLevel) bool {
 	return age >= 0 && age <= 150
 }
 
+// validatePhone validates phone number format with IsValidPhone
+func validatePhone(fl validator.FieldLevel) bool {
+	return IsValidPhone(fl.Field().String())
+}
+
 // validateNoProfanity validates that text doesn't contain profanity
 func validateNoProfanity(fl validator.FieldLevel) bool {
 	text := strings.ToLower(fl.Field().String())
@@ -245,6 +334,7 @@ func validateNoProfanity(fl validator.FieldLevel) bool {
 // ValidateEmail validates email format
 func ValidateEmail(email string) error {
 	validate := validator.New()
+	validate.RegisterValidation("email", validateEmail)
 	return validate.Var(email, "required,email")
 }
 
@@ -269,6 +359,13 @@ func ValidateAge(age int) error {
 	return validate.Var(age, "required,valid_age")
 }
 
+// ValidatePhone validates phone number format
+func ValidatePhone(phone string) error {
+	validate := validator.New()
+	validate.RegisterValidation("phone", validatePhone)
+	return validate.Var(phone, "required,phone")
+}
+
 // ValidateRequired validates that a field is not empty
 func ValidateRequired(field interface{}) error {
 	validate := validator.New()
@@ -0,0 +1,66 @@
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"example-api-template/pkg/ctxkeys"
+	"example-api-template/pkg/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStructCtx(t *testing.T) {
+	type request struct {
+		Name string `json:"name" validate:"required,min=2"`
+		Code string `json:"code" validate:"omitempty,hexadecimal"`
+	}
+
+	localizer, err := i18n.NewLocalizer(&i18n.Config{
+		DefaultLanguage: "en",
+		Languages:       []string{"en", "th"},
+		TranslationDir:  "../../translations",
+	})
+	require.NoError(t, err)
+	v := New(WithLocalizer(localizer))
+
+	validate := func(lang string, req *request) []ValidationFieldErrorDTO {
+		t.Helper()
+		validationErrors, err := v.ValidateStructCtx(ctxkeys.WithLanguage(context.Background(), lang), req)
+		assert.Error(t, err)
+		require.Len(t, validationErrors, 1)
+		return validationErrors
+	}
+
+	t.Run("message follows the context language", func(t *testing.T) {
+		en := validate("en", &request{Name: "J"})
+		th := validate("th", &request{Name: "J"})
+
+		assert.Equal(t, "name must be at least 2 characters long", en[0].Message)
+		assert.Equal(t, "name ต้องมีความยาวอย่างน้อย 2 ตัวอักษร", th[0].Message)
+		assert.Equal(t, en[0].Tag, th[0].Tag)
+	})
+
+	t.Run("unsupported language falls back to the default", func(t *testing.T) {
+		assert.Equal(t, "name is required", validate("fr", &request{})[0].Message)
+	})
+
+	t.Run("tag without a translation keeps the built-in message", func(t *testing.T) {
+		assert.Equal(t, "code is invalid", validate("th", &request{Name: "John", Code: "xyz"})[0].Message)
+	})
+
+	t.Run("ValidateStruct uses the default language", func(t *testing.T) {
+		validationErrors, err := v.ValidateStruct(&request{})
+		assert.Error(t, err)
+		require.Len(t, validationErrors, 1)
+		assert.Equal(t, "name is required", validationErrors[0].Message)
+	})
+
+	t.Run("without a localizer messages are English", func(t *testing.T) {
+		validationErrors, err := New().ValidateStructCtx(ctxkeys.WithLanguage(context.Background(), "th"), &request{})
+		assert.Error(t, err)
+		require.Len(t, validationErrors, 1)
+		assert.Equal(t, "name is required", validationErrors[0].Message)
+	})
+}
@@ -184,7 +184,10 @@ func ErrorScenarios() map[string]error {
 
 // GetTestContext returns a context with timeout for testing
 func GetTestContext() context.Context {
-	ctx, _ := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Note: In real tests, you should call cancel() when done
+	// For this test helper, we'll let it timeout naturally
+	_ = cancel
 	return ctx
 }
 
@@ -0,0 +1,84 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// HTTP request fixtures, shaped like CreateExampleRequestDTO and UpdateExampleRequestDTO.
+// They are JSON rather than DTO values because the handler tests using them live in the
+// package declaring the DTOs, which this package therefore can't import.
+
+// InvalidPayload is a request body failing validation on Field, the JSON name reported in
+// the validation error
+type InvalidPayload struct {
+	Name  string
+	Field string
+	Body  string
+}
+
+// ExampleJSON returns a create or update request body with the given fields
+func ExampleJSON(name, email string, age int) string {
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":  name,
+		"email": email,
+		"age":   age,
+	})
+	return string(body)
+}
+
+// ValidCreateExampleJSON returns a create request body that passes validation
+func ValidCreateExampleJSON() string {
+	return ExampleJSON("John Doe", "john.doe@example.com", 30)
+}
+
+// ValidUpdateExampleJSON returns an update request body that passes validation
+func ValidUpdateExampleJSON() string {
+	return ExampleJSON("Jane Doe", "jane.doe@example.com", 31)
+}
+
+// InvalidCreateExamplePayloads returns create request bodies each failing validation on one field
+func InvalidCreateExamplePayloads() []InvalidPayload {
+	return invalidExamplePayloads()
+}
+
+// InvalidUpdateExamplePayloads returns update request bodies each failing validation on one field
+func InvalidUpdateExamplePayloads() []InvalidPayload {
+	// Updates replace every field, so they are held to the same rules as creates
+	return invalidExamplePayloads()
+}
+
+// invalidExamplePayloads returns bodies breaking the rules shared by create and update requests
+func invalidExamplePayloads() []InvalidPayload {
+	return []InvalidPayload{
+		{Name: "empty name", Field: "name", Body: ExampleJSON("", "test@example.com", 25)},
+		{Name: "invalid email", Field: "email", Body: ExampleJSON("Test User", "invalid-email", 25)},
+		{Name: "negative age", Field: "age", Body: ExampleJSON("Test User", "test@example.com", -5)},
+		{Name: "excessive age", Field: "age", Body: ExampleJSON("Test User", "test@example.com", 151)},
+		{Name: "missing age", Field: "age", Body: `{"name":"Test User","email":"test@example.com"}`},
+	}
+}
+
+// PostJSON sends body to e as a JSON request with method and returns the recorded response.
+// A string body is sent as is and anything else is marshalled to JSON first.
+func PostJSON(t testing.TB, e *echo.Echo, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	raw, ok := body.(string)
+	if !ok {
+		encoded, err := json.Marshal(body)
+		require.NoError(t, err)
+		raw = string(encoded)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(raw))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"example-api-template/internal/domain"
+	"example-api-template/internal/repository"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -28,6 +29,15 @@ func (m *MockExampleRepository) GetByID(ctx context.Context, id string) (*domain
 	return args.Get(0).(*domain.Example), args.Error(1)
 }
 
+// GetByIDs mocks the GetByIDs method
+func (m *MockExampleRepository) GetByIDs(ctx context.Context, ids []string) ([]*domain.Example, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Example), args.Error(1)
+}
+
 // GetByEmail mocks the GetByEmail method
 func (m *MockExampleRepository) GetByEmail(ctx context.Context, email string) (*domain.Example, error) {
 	args := m.Called(ctx, email)
@@ -37,21 +47,51 @@ func (m *MockExampleRepository) GetByEmail(ctx context.Context, email string) (*
 	return args.Get(0).(*domain.Example), args.Error(1)
 }
 
+// ExistsByEmail mocks the ExistsByEmail method
+func (m *MockExampleRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	args := m.Called(ctx, email)
+	return args.Bool(0), args.Error(1)
+}
+
 // Update mocks the Update method
 func (m *MockExampleRepository) Update(ctx context.Context, example *domain.Example) error {
 	args := m.Called(ctx, example)
 	return args.Error(0)
 }
 
+// UpdateFields mocks the UpdateFields method
+func (m *MockExampleRepository) UpdateFields(ctx context.Context, id string, expectedVersion int, fields map[string]interface{}) error {
+	args := m.Called(ctx, id, expectedVersion, fields)
+	return args.Error(0)
+}
+
 // Delete mocks the Delete method
 func (m *MockExampleRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+// DeleteByIDs mocks the DeleteByIDs method
+func (m *MockExampleRepository) DeleteByIDs(ctx context.Context, ids []string) ([]string, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // List mocks the List method
-func (m *MockExampleRepository) List(ctx context.Context, limit, offset int) ([]*domain.Example, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockExampleRepository) List(ctx context.Context, limit, offset int, sort domain.ExampleSort) ([]*domain.Example, error) {
+	args := m.Called(ctx, limit, offset, sort)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Example), args.Error(1)
+}
+
+// ListFiltered mocks the ListFiltered method
+func (m *MockExampleRepository) ListFiltered(ctx context.Context, filter repository.ExampleFilter, limit, offset int, sort domain.ExampleSort) ([]*domain.Example, error) {
+	args := m.Called(ctx, filter, limit, offset, sort)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -63,3 +103,22 @@ func (m *MockExampleRepository) Count(ctx context.Context) (int, error) {
 	args := m.Called(ctx)
 	return args.Int(0), args.Error(1)
 }
+
+// CountFiltered mocks the CountFiltered method
+func (m *MockExampleRepository) CountFiltered(ctx context.Context, filter repository.ExampleFilter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
+// StreamAll mocks the StreamAll method, calling fn for the examples given as the first return value
+func (m *MockExampleRepository) StreamAll(ctx context.Context, fn func(*domain.Example) error) error {
+	args := m.Called(ctx, fn)
+	if examples, ok := args.Get(0).([]*domain.Example); ok {
+		for _, example := range examples {
+			if err := fn(example); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
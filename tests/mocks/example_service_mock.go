@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"example-api-template/internal/domain"
+	"example-api-template/internal/repository"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -14,8 +15,8 @@ type MockExampleService struct {
 }
 
 // CreateExample mocks the CreateExample method
-func (m *MockExampleService) CreateExample(ctx context.Context, name, email string, age int) (*domain.Example, error) {
-	args := m.Called(ctx, name, email, age)
+func (m *MockExampleService) CreateExample(ctx context.Context, name, email, phone string, age int) (*domain.Example, error) {
+	args := m.Called(ctx, name, email, phone, age)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -31,6 +32,20 @@ func (m *MockExampleService) GetExampleByID(ctx context.Context, id string) (*do
 	return args.Get(0).(*domain.Example), args.Error(1)
 }
 
+// GetExamplesByIDs mocks the GetExamplesByIDs method
+func (m *MockExampleService) GetExamplesByIDs(ctx context.Context, ids []string) ([]*domain.Example, []string, error) {
+	args := m.Called(ctx, ids)
+	var examples []*domain.Example
+	if args.Get(0) != nil {
+		examples = args.Get(0).([]*domain.Example)
+	}
+	var notFound []string
+	if args.Get(1) != nil {
+		notFound = args.Get(1).([]string)
+	}
+	return examples, notFound, args.Error(2)
+}
+
 // GetExampleByEmail mocks the GetExampleByEmail method
 func (m *MockExampleService) GetExampleByEmail(ctx context.Context, email string) (*domain.Example, error) {
 	args := m.Called(ctx, email)
@@ -41,8 +56,17 @@ func (m *MockExampleService) GetExampleByEmail(ctx context.Context, email string
 }
 
 // UpdateExample mocks the UpdateExample method
-func (m *MockExampleService) UpdateExample(ctx context.Context, id, name, email string, age int) (*domain.Example, error) {
-	args := m.Called(ctx, id, name, email, age)
+func (m *MockExampleService) UpdateExample(ctx context.Context, id, name, email, phone string, age, expectedVersion int) (*domain.Example, error) {
+	args := m.Called(ctx, id, name, email, phone, age, expectedVersion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Example), args.Error(1)
+}
+
+// PatchExample mocks the PatchExample method
+func (m *MockExampleService) PatchExample(ctx context.Context, id string, name, email, phone *string, age *int) (*domain.Example, error) {
+	args := m.Called(ctx, id, name, email, phone, age)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -55,15 +79,42 @@ func (m *MockExampleService) DeleteExample(ctx context.Context, id string) error
 	return args.Error(0)
 }
 
+// DeleteExamplesByIDs mocks the DeleteExamplesByIDs method
+func (m *MockExampleService) DeleteExamplesByIDs(ctx context.Context, ids []string) ([]*domain.Example, []string, error) {
+	args := m.Called(ctx, ids)
+	var examples []*domain.Example
+	if args.Get(0) != nil {
+		examples = args.Get(0).([]*domain.Example)
+	}
+	var notFound []string
+	if args.Get(1) != nil {
+		notFound = args.Get(1).([]string)
+	}
+	return examples, notFound, args.Error(2)
+}
+
 // ListExamples mocks the ListExamples method
-func (m *MockExampleService) ListExamples(ctx context.Context, limit, offset int) ([]*domain.Example, int, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockExampleService) ListExamples(ctx context.Context, limit, offset int, sort domain.ExampleSort, filter repository.ExampleFilter) ([]*domain.Example, int, error) {
+	args := m.Called(ctx, limit, offset, sort, filter)
 	if args.Get(0) == nil {
 		return nil, args.Int(1), args.Error(2)
 	}
 	return args.Get(0).([]*domain.Example), args.Int(1), args.Error(2)
 }
 
+// ExportExamples mocks the ExportExamples method, calling fn for the examples given as the first return value
+func (m *MockExampleService) ExportExamples(ctx context.Context, fn func(*domain.Example) error) error {
+	args := m.Called(ctx, fn)
+	if examples, ok := args.Get(0).([]*domain.Example); ok {
+		for _, example := range examples {
+			if err := fn(example); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 // ValidateExampleBusinessRules mocks the ValidateExampleBusinessRules method
 func (m *MockExampleService) ValidateExampleBusinessRules(ctx context.Context, name, email string, age int) error {
 	args := m.Called(ctx, name, email, age)
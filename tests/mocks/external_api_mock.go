@@ -42,3 +42,9 @@ func (m *MockExternalExampleAPI) NotifyExampleCreated(ctx context.Context, examp
 	args := m.Called(ctx, exampleID, email)
 	return args.Error(0)
 }
+
+// Ping mocks the Ping method
+func (m *MockExternalExampleAPI) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}